@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== AGENT INTERFACE ====================
+
+// Agent is a trading strategy driven by Holodeck.Run, which reads the
+// ticks, paces them, submits the agent's orders and reports fills back,
+// so a strategy needs no loop or wiring of its own
+type Agent interface {
+	// OnTick returns the orders to submit for a tick (nil for none)
+	OnTick(tick *types.Tick, state *State) []*types.Order
+
+	// OnFill is called with every execution report that filled some size,
+	// including working orders filled on later ticks
+	// Fills are delivered after the call that produced them returns and
+	// the Holodeck's lock is released (before the next OnTick), so OnFill
+	// may call Holodeck methods
+	OnFill(exec *types.ExecutionReport)
+
+	// OnSessionEnd is called once with the final state when the run ends
+	OnSessionEnd(state *State)
+}
+
+// Rejecter is implemented by agents that want to hear about their orders
+// that were rejected or failed; Run calls OnReject with the order and the
+// reason, outside the Holodeck's lock, before the next OnTick
+type Rejecter interface {
+	OnReject(order *types.Order, err error)
+}
+
+// ==================== STATE ====================
+
+// State is the account as of a tick; it is a copy the agent may keep
+type State struct {
+	SimTime      time.Time                  // Timestamp of the latest tick
+	TickCount    int64                      // Ticks processed so far
+	Balance      *types.Balance             // Account balance and P&L
+	Position     *types.Position            // Position in the primary symbol
+	Positions    map[string]*types.Position // Every symbol's position
+	AccountBlown bool                       // Account hit max drawdown
+}
+
+// Equity returns the balance plus unrealized P&L
+func (s *State) Equity() float64 {
+	if s.Balance == nil {
+		return 0
+	}
+	return s.Balance.CurrentBalance + s.Balance.TotalUnrealizedPnL
+}
+
+// IsFlat returns whether the primary symbol has no open position
+func (s *State) IsFlat() bool {
+	return s.Position == nil || s.Position.Size == 0
+}
+
+// PositionFor returns a symbol's position (nil if it never traded)
+func (s *State) PositionFor(symbol string) *types.Position {
+	return s.Positions[symbol]
+}
+
+// ==================== ADAPTERS ====================
+
+// Base implements OnFill and OnSessionEnd as no-ops; embed it in agents
+// that only trade on ticks
+type Base struct{}
+
+// OnFill does nothing
+func (Base) OnFill(exec *types.ExecutionReport) {}
+
+// OnSessionEnd does nothing
+func (Base) OnSessionEnd(state *State) {}
+
+// Func adapts an OnTick function to an Agent
+type Func func(tick *types.Tick, state *State) []*types.Order
+
+// OnTick calls the function
+func (f Func) OnTick(tick *types.Tick, state *State) []*types.Order {
+	return f(tick, state)
+}
+
+// OnFill does nothing
+func (f Func) OnFill(exec *types.ExecutionReport) {}
+
+// OnSessionEnd does nothing
+func (f Func) OnSessionEnd(state *State) {}
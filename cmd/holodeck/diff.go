@@ -0,0 +1,217 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== SESSION DIFF ====================
+
+// diffPriceTolerance is the largest fill price difference treated as equal
+const diffPriceTolerance = 1e-9
+
+// DiffSession is one side of a session comparison
+type DiffSession struct {
+	Path       string
+	Metrics    map[string]interface{}
+	Executions []*types.ExecutionReport
+}
+
+// loadDiffSession loads a session directory or an export-session archive
+func loadDiffSession(path string) (*DiffSession, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := path
+	if !info.IsDir() {
+		dir, err = os.MkdirTemp("", "holodeck-diff-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		if err := extractSessionArchive(path, dir); err != nil {
+			return nil, err
+		}
+		if _, err := verifySessionDir(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	session := &DiffSession{
+		Path:       path,
+		Metrics:    make(map[string]interface{}),
+		Executions: make([]*types.ExecutionReport, 0),
+	}
+	if err := readJSONFile(filepath.Join(dir, SessionMetricsFile), &session.Metrics); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := readJSONFile(filepath.Join(dir, SessionExecutionsFile), &session.Executions); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return session, nil
+}
+
+// MetricDelta is the change of one numeric metric between two sessions
+type MetricDelta struct {
+	Name  string
+	A     float64
+	B     float64
+	Delta float64
+}
+
+// diffMetrics returns the numeric metrics of both sessions with their deltas,
+// sorted by name
+func diffMetrics(a, b map[string]interface{}) []MetricDelta {
+	deltas := make([]MetricDelta, 0)
+	for name, rawA := range a {
+		valueA, okA := rawA.(float64)
+		valueB, okB := b[name].(float64)
+		if !okA || !okB {
+			continue
+		}
+		deltas = append(deltas, MetricDelta{
+			Name:  name,
+			A:     valueA,
+			B:     valueB,
+			Delta: valueB - valueA,
+		})
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].Name < deltas[j].Name
+	})
+	return deltas
+}
+
+// tradeKey identifies a trade independently of its order ID
+func tradeKey(exec *types.ExecutionReport) string {
+	return fmt.Sprintf("%s %s %.8f", exec.Timestamp.Format(time.RFC3339Nano), exec.Action, exec.FilledSize)
+}
+
+// sameExecution checks if two executions are the same trade at the same price
+func sameExecution(a, b *types.ExecutionReport) bool {
+	return tradeKey(a) == tradeKey(b) && math.Abs(a.FillPrice-b.FillPrice) <= diffPriceTolerance
+}
+
+// findDivergence returns the index of the first execution that differs
+// Returns -1 if both histories are identical
+func findDivergence(a, b []*types.ExecutionReport) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		if !sameExecution(a[i], b[i]) {
+			return i
+		}
+	}
+	if len(a) != len(b) {
+		return n
+	}
+	return -1
+}
+
+// uniqueTrades returns the trades of a that have no counterpart in b
+// Repeated identical trades are matched one for one
+func uniqueTrades(a, b []*types.ExecutionReport) []*types.ExecutionReport {
+	counts := make(map[string]int)
+	for _, exec := range b {
+		counts[tradeKey(exec)]++
+	}
+
+	unique := make([]*types.ExecutionReport, 0)
+	for _, exec := range a {
+		key := tradeKey(exec)
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		unique = append(unique, exec)
+	}
+	return unique
+}
+
+// runDiff implements `holodeck diff runA runB`
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	all := fs.Bool("all", false, "Show unchanged metrics too")
+	maxTrades := fs.Int("max-trades", 20, "Maximum unmatched trades listed per session (0 = all)")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: holodeck diff [-all] [-max-trades N] <runA> <runB>")
+	}
+
+	a, err := loadDiffSession(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	b, err := loadDiffSession(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("A: %s\nB: %s\n", a.Path, b.Path)
+
+	// Metrics
+	fmt.Println("\n" + strings.Repeat("=", 79))
+	fmt.Println("METRICS")
+	fmt.Println(strings.Repeat("=", 79))
+	changed := 0
+	for _, d := range diffMetrics(a.Metrics, b.Metrics) {
+		if d.Delta == 0 && !*all {
+			continue
+		}
+		changed++
+		fmt.Printf("  %-28s %16.4f %16.4f %+16.4f\n", d.Name, d.A, d.B, d.Delta)
+	}
+	if changed == 0 {
+		fmt.Println("  No metric changes")
+	}
+
+	// Divergence
+	fmt.Println("\n" + strings.Repeat("=", 79))
+	fmt.Println("EXECUTION HISTORY")
+	fmt.Println(strings.Repeat("=", 79))
+	fmt.Printf("  Executions: A=%d B=%d\n", len(a.Executions), len(b.Executions))
+	divergence := findDivergence(a.Executions, b.Executions)
+	if divergence < 0 {
+		fmt.Println("  Identical execution histories")
+		return nil
+	}
+	fmt.Printf("  Diverges at execution #%d\n", divergence+1)
+	if divergence < len(a.Executions) {
+		fmt.Printf("    A: %s\n", a.Executions[divergence])
+	}
+	if divergence < len(b.Executions) {
+		fmt.Printf("    B: %s\n", b.Executions[divergence])
+	}
+
+	// Unmatched trades
+	printUnmatchedTrades("Only in A", uniqueTrades(a.Executions, b.Executions), *maxTrades)
+	printUnmatchedTrades("Only in B", uniqueTrades(b.Executions, a.Executions), *maxTrades)
+
+	return nil
+}
+
+// printUnmatchedTrades lists trades present in only one session
+func printUnmatchedTrades(title string, trades []*types.ExecutionReport, limit int) {
+	fmt.Printf("\n  %s: %d trades\n", title, len(trades))
+	for i, exec := range trades {
+		if limit > 0 && i >= limit {
+			fmt.Printf("    ... %d more\n", len(trades)-limit)
+			return
+		}
+		fmt.Printf("    %s\n", exec)
+	}
+}
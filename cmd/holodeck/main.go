@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
+	"time"
 
+	"holodeck/reader"
+	"holodeck/reports"
 	"holodeck/simulator"
+	"holodeck/speed"
 	"holodeck/types"
 )
 
@@ -22,12 +28,40 @@ const (
 // ==================== MAIN ====================
 
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 {
+		var run func([]string) error
+		switch os.Args[1] {
+		case "export-session":
+			run = runExportSession
+		case "import-session":
+			run = runImportSession
+		case "diff":
+			run = runDiff
+		}
+		if run != nil {
+			if err := run(os.Args[2:]); err != nil {
+				log.Fatalf("[ERROR] %s: %v", os.Args[1], err)
+			}
+			os.Exit(0)
+		}
+	}
+
 	// Define command-line flags
 	configFile := flag.String("config", "", "Path to configuration JSON file (REQUIRED)")
-	speed := flag.Float64("speed", 100.0, "Simulation speed multiplier (default 100.0)")
+	speedFlag := flag.String("speed", "100", "Simulation speed multiplier, or \"max\" for unthrottled (default 100.0)")
+	tpsFlag := flag.Float64("tps", 0, "Throttle to this many ticks per second instead of the speed multiplier")
+	adaptive := flag.Bool("adaptive", false, "Lower the speed automatically while ticks overrun their time budget")
+	controlAddr := flag.String("control", "", "Accept speed/pause/resume/status commands on this socket (path, tcp:host:port or http:host:port)")
 	verbose := flag.Bool("verbose", false, "Enable verbose output")
 	showHelp := flag.Bool("help", false, "Show help message")
 	showVersion := flag.Bool("version", false, "Show version information")
+	statusFile := flag.String("status-file", "", "Periodically rewrite session status JSON to this file")
+	statusInterval := flag.Int("status-interval", 5, "Seconds between status file updates (default 5)")
+	sessionDir := flag.String("session-dir", "", "Save config, executions, metrics and report to this directory")
+	auditFile := flag.String("audit-file", "", "Export the hash-chained execution audit trail to this file")
+	reportDir := flag.String("report-dir", "", "Write an HTML report with equity, drawdown and trade charts to this directory")
+	resultFile := flag.String("result-file", "", "Write the versioned JSON simulation result to this file")
 
 	flag.Parse()
 
@@ -47,7 +81,7 @@ func main() {
 	// Validate required config flag
 	if *configFile == "" {
 		fmt.Println("Error: -config flag is required")
-		fmt.Println("\nUsage: holodeck -config <file.json> [-speed <multiplier>] [-verbose]")
+		fmt.Println("\nUsage: holodeck -config <file.json> [-speed <multiplier|max>] [-verbose]")
 		fmt.Println("       holodeck -help")
 		fmt.Println("       holodeck -version")
 		os.Exit(1)
@@ -74,25 +108,137 @@ func main() {
 		fmt.Println("[INFO] Initializing Holodeck simulator...")
 	}
 
-	holodeck, err := config.NewHolodeck()
+	// Tick count for the ETAs in the status file and verbose batch stats
+	var totalTicks int64
+	if *statusFile != "" || *verbose {
+		count, err := reader.CountTicks(config.CSV.FilePath, true)
+		if err != nil && *verbose {
+			fmt.Printf("[WARN] Could not count ticks for ETA: %v\n", err)
+		}
+		totalTicks = count
+	}
+
+	// Optional heartbeat status file for external monitors
+	var statusWriter *StatusWriter
+	if *statusFile != "" {
+		statusWriter = NewStatusWriter(*statusFile, time.Duration(*statusInterval)*time.Second, totalTicks)
+	}
+
+	// From here on, exits leave a failed status for monitors and close the
+	// Holodeck so its log is flushed
+	var holodeck *simulator.Holodeck
+	fatalf := func(format string, args ...interface{}) {
+		if statusWriter != nil {
+			statusWriter.Stop()
+			writeStatus(statusWriter, holodeck, "failed", *verbose)
+		}
+		if holodeck != nil {
+			holodeck.Close()
+		}
+		log.Fatalf(format, args...)
+	}
+
+	holodeck, err = config.NewHolodeck()
 	if err != nil {
-		log.Fatalf("[ERROR] Failed to initialize Holodeck: %v", err)
+		fatalf("[ERROR] Failed to initialize Holodeck: %v", err)
 	}
 
 	// Step 3: Override speed if specified
-	if *speed > 0 {
-		if err := holodeck.SetSpeed(*speed); err != nil {
-			log.Fatalf("[ERROR] Failed to set speed: %v", err)
+	multiplier, maxSpeed, err := speed.ParseSpeed(*speedFlag)
+	if err != nil {
+		fatalf("[ERROR] Failed to set speed: %v", err)
+	}
+	if maxSpeed {
+		holodeck.SetMaxSpeed(true)
+	} else if multiplier > 0 {
+		if err := holodeck.SetSpeed(multiplier); err != nil {
+			fatalf("[ERROR] Failed to set speed: %v", err)
+		}
+	}
+
+	// A tick rate (flag or config) takes over from the multiplier
+	ticksPerSecond := *tpsFlag
+	if ticksPerSecond == 0 {
+		ticksPerSecond = config.Speed.TicksPerSecond
+	}
+	if ticksPerSecond < 0 {
+		fatalf("[ERROR] Failed to set tick rate: ticks per second cannot be negative")
+	}
+	if ticksPerSecond > 0 && !maxSpeed {
+		if err := holodeck.SetTicksPerSecond(ticksPerSecond); err != nil {
+			fatalf("[ERROR] Failed to set tick rate: %v", err)
+		}
+	}
+
+	if *adaptive {
+		if err := holodeck.SetAdaptiveSpeed(true); err != nil {
+			fatalf("[ERROR] Failed to enable adaptive speed: %v", err)
+		}
+	}
+
+	// Print progress with ETA, every 10000 ticks unless configured
+	if *verbose {
+		if !config.Logging.Progress.IsEnabled() {
+			holodeck.WithProgress(simulator.NewProgressTracker(simulator.ProgressConfig{EveryTicks: 10000}))
 		}
+		holodeck.WithCallbacks(simulator.HolodeckCallbacks{
+			OnProgress: func(progress *simulator.Progress) {
+				fmt.Printf("[PROGRESS] %s | Balance: $%.2f\n", progress, progress.Balance)
+			},
+		})
 	}
 
 	// Step 4: Start simulation
 	if *verbose {
-		fmt.Printf("[INFO] Starting simulation at %.1fx speed\n", *speed)
+		if maxSpeed {
+			fmt.Println("[INFO] Starting simulation at max speed")
+		} else if ticksPerSecond > 0 {
+			fmt.Printf("[INFO] Starting simulation at %.0f ticks/s\n", ticksPerSecond)
+		} else {
+			fmt.Printf("[INFO] Starting simulation at %.1fx speed\n", multiplier)
+		}
+	}
+
+	// Ctrl-C ends the run cleanly: the loop stops and results are printed
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stopSignals()
+
+	if statusWriter != nil {
+		writeStatus(statusWriter, holodeck, "starting", *verbose)
+	}
+
+	if err := holodeck.StartContext(ctx); err != nil {
+		fatalf("[ERROR] Failed to start simulation: %v", err)
+	}
+
+	// Optional control socket for changing speed or pausing mid-run
+	var control *simulator.ControlServer
+	if *controlAddr != "" {
+		control = simulator.NewControlServer(holodeck)
+		if err := control.Listen(*controlAddr); err != nil {
+			fatalf("[ERROR] Failed to start control server: %v", err)
+		}
+		if *verbose {
+			fmt.Printf("[INFO] Control server listening on %s\n", control.Addr())
+		}
+	}
+
+	// Heartbeat updates come from a ticker, so they continue while the
+	// reader is stalled
+	if statusWriter != nil {
+		writeStatus(statusWriter, holodeck, "running", *verbose)
+		statusWriter.Start(holodeck, func(err error) {
+			if *verbose {
+				fmt.Printf("[WARN] Failed to write status file: %v\n", err)
+			}
+		})
 	}
 
-	if err := holodeck.Start(); err != nil {
-		log.Fatalf("[ERROR] Failed to start simulation: %v", err)
+	// Verbose runs time the loop in batches for periodic stats and ETA
+	var sessionTimer *speed.SessionTimer
+	if *verbose {
+		sessionTimer = speed.NewSessionTimer(holodeck.GetSpeedController(), holodeck.GetStatus().SessionID)
+		sessionTimer.StartBatch(batchStatsTicks)
 	}
 
 	// Step 5: Main simulation loop
@@ -103,20 +249,22 @@ func main() {
 		// Get next tick from data source
 		tick, err := holodeck.GetNextTick()
 		if err != nil {
-			// No more ticks available
+			// No more ticks available, or interrupted
+			if ctx.Err() != nil && *verbose {
+				fmt.Println("[INFO] Interrupted")
+			}
 			break
 		}
 
 		tickCount++
+		tickStart := time.Now()
 
-		// Print progress every 10000 ticks
-		if *verbose && tickCount%10000 == 0 {
-			balance := holodeck.GetBalance()
-			fmt.Printf("[PROGRESS] Processed %d ticks | Balance: $%.2f\n",
-				tickCount, balance.CurrentBalance)
+		if statusWriter != nil {
+			statusWriter.SetTicks(int64(tickCount))
 		}
 
-		// TODO: Add agent decision logic here
+		// TODO: Add agent decision logic here, or drive the session with
+		// holodeck.Run(agent) (see package agent) instead of this loop
 		// Example:
 		// if shouldExecuteOrder(tick) {
 		//     order := createOrder(tick)
@@ -127,6 +275,10 @@ func main() {
 		// }
 
 		_ = tick // Placeholder to use tick variable
+
+		if sessionTimer != nil {
+			recordBatchTick(sessionTimer, time.Since(tickStart), totalTicks)
+		}
 	}
 
 	// Step 6: Stop simulation
@@ -134,17 +286,93 @@ func main() {
 		fmt.Println("[INFO] Stopping simulation...")
 	}
 
+	if control != nil {
+		control.Close()
+	}
+
 	if err := holodeck.Stop(); err != nil {
-		log.Fatalf("[ERROR] Failed to stop simulation: %v", err)
+		fatalf("[ERROR] Failed to stop simulation: %v", err)
+	}
+
+	if statusWriter != nil {
+		statusWriter.Stop()
+		writeStatus(statusWriter, holodeck, "completed", *verbose)
+	}
+
+	if sessionTimer != nil {
+		fmt.Print("\n" + sessionTimer.EndSession().String())
+	}
+
+	if *verbose && holodeck.IsAdaptiveSpeed() {
+		fmt.Printf("[INFO] Adaptive speed: ended at %.1fx, %s\n",
+			holodeck.GetEffectiveSpeed(), holodeck.GetSpeedController().GetGovernor())
 	}
 
 	// Step 7: Retrieve final metrics
-	metrics := holodeck.GetMetrics()
+	metrics := holodeck.GetPerformanceMetrics()
 	balance := holodeck.GetBalance()
-	position := holodeck.GetPosition()
+	position := holodeck.GetPosition("")
 
 	// Step 8: Print results
-	printResults(metrics, balance, position, tickCount, tradeCount)
+	account := holodeck.GetSymbolReport()
+	report := holodeck.GetFinalReport()
+	money := config.GetMoneyDecimals()
+	printResults(metrics, balance, position, tickCount, tradeCount, money)
+	printBenchmark(account.Benchmark)
+	printDailyPnL(holodeck.GetDailyPnL(), money)
+	printTimeBreakdown(account, money)
+	printCostAttribution(holodeck.GetCostAttribution().Total, money)
+	if report.IsMultiSymbol() {
+		printSymbolReport(report, money)
+	}
+	if symbols := holodeck.GetSymbolMetrics(); len(symbols) > 1 {
+		printSymbolMetrics(symbols, money)
+	}
+
+	// Step 9: Save session artifacts for export-session
+	if *sessionDir != "" {
+		if err := writeSessionDir(*sessionDir, *configFile, config, holodeck); err != nil {
+			fatalf("[ERROR] Failed to save session: %v", err)
+		}
+		if *verbose {
+			fmt.Printf("[INFO] Session saved to %s\n", *sessionDir)
+		}
+	}
+
+	// Step 10: Render the HTML report
+	if *reportDir != "" {
+		path, err := reports.NewHTMLReport(holodeck, money).WriteFile(*reportDir)
+		if err != nil {
+			fatalf("[ERROR] Failed to write report: %v", err)
+		}
+		if *verbose {
+			fmt.Printf("[INFO] Report written to %s\n", path)
+		}
+	}
+
+	// Step 11: Write the machine-readable result
+	if *resultFile != "" {
+		if err := holodeck.WriteSimulationResult(*resultFile); err != nil {
+			fatalf("[ERROR] Failed to write result: %v", err)
+		}
+		if *verbose {
+			fmt.Printf("[INFO] Result written to %s\n", *resultFile)
+		}
+	}
+
+	// Step 12: Export the audit trail
+	if *auditFile != "" {
+		if err := holodeck.ExportAuditTrail(*auditFile); err != nil {
+			fatalf("[ERROR] Failed to export audit trail: %v", err)
+		}
+		if *verbose {
+			fmt.Printf("[INFO] Audit trail exported to %s\n", *auditFile)
+		}
+	}
+
+	if err := holodeck.Close(); err != nil {
+		log.Fatalf("[ERROR] Failed to close Holodeck: %v", err)
+	}
 }
 
 // loadConfigFromFile loads configuration from a JSON file
@@ -164,8 +392,16 @@ func loadConfigFromFile(filePath string) (*simulator.Config, error) {
 	return config, nil
 }
 
+// writeStatus writes the status file, warning on failure in verbose mode
+func writeStatus(sw *StatusWriter, h *simulator.Holodeck, state string, verbose bool) {
+	if err := sw.Write(h, state); err != nil && verbose {
+		fmt.Printf("[WARN] Failed to write status file: %v\n", err)
+	}
+}
+
 // printResults prints the simulation results in a formatted way
-func printResults(metrics map[string]interface{}, balance *types.Balance, position *types.Position, ticks int, trades int) {
+// money is the number of decimals for monetary values
+func printResults(metrics *simulator.Metrics, balance *types.Balance, position *types.Position, ticks int, trades int, money int) {
 	fmt.Println("\n" + strings.Repeat("=", 63))
 	fmt.Println(strings.Repeat(" ", 15) + "SIMULATION RESULTS")
 	fmt.Println(strings.Repeat("=", 63) + "\n")
@@ -175,8 +411,8 @@ func printResults(metrics map[string]interface{}, balance *types.Balance, positi
 	if ticks > 0 {
 		fmt.Printf("  Ticks Processed:           %d\n", ticks)
 	}
-	if v, ok := metrics["total_ticks_available"]; ok {
-		fmt.Printf("  Total Available Ticks:     %v\n", v)
+	if metrics.TotalTicksAvailable > 0 {
+		fmt.Printf("  Total Available Ticks:     %d\n", metrics.TotalTicksAvailable)
 	}
 
 	// Trades
@@ -186,30 +422,33 @@ func printResults(metrics map[string]interface{}, balance *types.Balance, positi
 	} else {
 		fmt.Printf("  Trades Executed:           0 (Demo mode)\n")
 	}
-	if v, ok := metrics["trades_executed"]; ok {
-		fmt.Printf("  Total Executed:            %v\n", v)
+	fmt.Printf("  Total Executed:            %d\n", metrics.TradesExecuted)
+	if metrics.OrdersGated > 0 {
+		fmt.Printf("  Gated (spread/stale):      %d\n", metrics.OrdersGated)
 	}
 
 	// Account information
 	fmt.Println("\nACCOUNT:")
 	if balance != nil && balance.InitialBalance > 0 {
-		fmt.Printf("  Initial Balance:           $%.2f\n", balance.InitialBalance)
-		fmt.Printf("  Final Balance:             $%.2f\n", balance.CurrentBalance)
+		fmt.Printf("  Initial Balance:           $%.*f\n", money, balance.InitialBalance)
+		fmt.Printf("  Final Balance:             $%.*f\n", money, balance.CurrentBalance)
 		netPnL := balance.CurrentBalance - balance.InitialBalance
-		fmt.Printf("  Net P&L:                   $%.2f\n", netPnL)
-		fmt.Printf("  Commission Paid:           $%.2f\n", balance.CommissionPaid)
+		fmt.Printf("  Net P&L:                   $%.*f\n", money, netPnL)
+		fmt.Printf("  Commission Paid:           $%.*f\n", money, balance.CommissionPaid)
 	}
 
 	// Performance metrics
 	fmt.Println("\nPERFORMANCE:")
-	if v, ok := metrics["return_percent"]; ok {
-		fmt.Printf("  Return %%:                   %.2f%%\n", v)
+	if account := metrics.AccountMetrics; account != nil {
+		fmt.Printf("  Return %%:                   %.2f%%\n", account.ReturnPercent)
+		fmt.Printf("  Max Drawdown %%:            %.2f%%\n", account.DrawdownPercent)
+		fmt.Printf("  Win Rate:                  %.2f%%\n", account.WinRate)
 	}
-	if v, ok := metrics["drawdown_percent"]; ok {
-		fmt.Printf("  Max Drawdown %%:            %.2f%%\n", v)
-	}
-	if v, ok := metrics["win_rate"]; ok {
-		fmt.Printf("  Win Rate:                  %.2f%%\n", v)
+	if equity := metrics.EquityCurve; equity != nil {
+		if v, ok := equity["var_95"]; ok {
+			fmt.Printf("  Daily VaR 95%% / 99%%:       %.2f%% / %.2f%%\n", v, equity["var_99"])
+			fmt.Printf("  Daily CVaR 95%% / 99%%:      %.2f%% / %.2f%%\n", equity["cvar_95"], equity["cvar_99"])
+		}
 	}
 
 	// Position information
@@ -217,18 +456,188 @@ func printResults(metrics map[string]interface{}, balance *types.Balance, positi
 	if position != nil {
 		fmt.Printf("  Size:                      %.2f\n", position.Size)
 		fmt.Printf("  Entry Price:               %.4f\n", position.EntryPrice)
-		fmt.Printf("  Unrealized P&L:            $%.2f\n", position.UnrealizedPnL)
-		fmt.Printf("  Realized P&L:              $%.2f\n", position.RealizedPnL)
+		fmt.Printf("  Unrealized P&L:            $%.*f\n", money, position.UnrealizedPnL)
+		fmt.Printf("  Realized P&L:              $%.*f\n", money, position.RealizedPnL)
 	}
 
+	// Agent vs engine wall time
+	fmt.Println("\nTIMING:")
+	fmt.Printf("  Agent Time:                %v (%.1f%%)\n", metrics.AgentTime, metrics.AgentTimePercent)
+	fmt.Printf("  Engine Time:               %v\n", metrics.EngineTime)
+
 	// Session duration
-	if v, ok := metrics["session_duration"]; ok {
-		fmt.Printf("\nSession Duration:           %v\n", v)
-	}
+	fmt.Printf("\nSession Duration:           %v\n", metrics.SessionDuration)
 
 	fmt.Println("\n" + strings.Repeat("=", 63) + "\n")
 }
 
+// printBenchmark prints the strategy against buying and holding the
+// instrument
+func printBenchmark(benchmark *types.BenchmarkComparison) {
+	if benchmark == nil {
+		return
+	}
+
+	fmt.Println(strings.Repeat(" ", 16) + "BUY-AND-HOLD BENCHMARK")
+	fmt.Println(strings.Repeat("=", 63))
+	fmt.Printf("  Strategy Return:           %.2f%%\n", benchmark.StrategyReturnPercent)
+	fmt.Printf("  Benchmark Return:          %.2f%%\n", benchmark.BenchmarkReturnPercent)
+	fmt.Printf("  Relative Return:           %.2f%%\n", benchmark.RelativeReturnPercent)
+	fmt.Printf("  Benchmark Max Drawdown:    %.2f%%\n", benchmark.BenchmarkMaxDrawdown)
+	fmt.Printf("  Alpha (annualized):        %.2f%%\n", benchmark.Alpha)
+	fmt.Printf("  Beta:                      %.2f\n", benchmark.Beta)
+	fmt.Printf("  Correlation:               %.2f\n", benchmark.Correlation)
+	fmt.Println(strings.Repeat("=", 63) + "\n")
+}
+
+// printCostAttribution splits the closed trades' gross P&L into the
+// friction terms (skipped when nothing has closed)
+func printCostAttribution(total types.CostBreakdown, money int) {
+	if total.Trades == 0 {
+		return
+	}
+
+	share := total.GetCostShare()
+	fmt.Println(strings.Repeat(" ", 19) + "COST ATTRIBUTION")
+	fmt.Println(strings.Repeat("=", 63))
+	fmt.Printf("  Gross P&L (at mid):        %.*f\n", money, total.GrossPnL)
+	fmt.Printf("  Spread Cost:              -%.*f  (%.1f%% of gross)\n", money, total.SpreadCost, share["spread"])
+	fmt.Printf("  Slippage Cost:            -%.*f  (%.1f%% of gross)\n", money, total.SlippageCost, share["slippage"])
+	fmt.Printf("  Commission:               -%.*f  (%.1f%% of gross)\n", money, total.Commission, share["commission"])
+	fmt.Printf("  Swap:                      %+.*f\n", money, total.Swap)
+	fmt.Printf("  Net P&L:                   %.*f  (%d trades)\n", money, total.NetPnL, total.Trades)
+	fmt.Println(strings.Repeat("=", 63) + "\n")
+}
+
+// printDailyPnL prints the per-day P&L rollup (skipped for single-day runs)
+func printDailyPnL(days []types.DailyRecord, money int) {
+	if len(days) < 2 {
+		return
+	}
+
+	fmt.Println(strings.Repeat(" ", 20) + "DAILY P&L")
+	fmt.Println(strings.Repeat("=", 63))
+	fmt.Printf("%-12s %12s %12s %10s %7s %9s\n",
+		"DATE", "NET P&L", "REALIZED", "COMMISSION", "TRADES", "MAX DD%")
+	fmt.Println(strings.Repeat("-", 63))
+	for _, day := range days {
+		fmt.Printf("%-12s %12.*f %12.*f %10.*f %7d %8.2f%%\n",
+			day.Date,
+			money, day.GetNetPnL(),
+			money, day.RealizedPnL,
+			money, day.Commission,
+			day.Trades,
+			day.MaxIntradayDrawdownPercent,
+		)
+	}
+	fmt.Println(strings.Repeat("=", 63) + "\n")
+}
+
+// printTimeBreakdown prints closing-trade performance per trading session
+// and per UTC hour (skipped when nothing closed)
+func printTimeBreakdown(report *simulator.SymbolReport, money int) {
+	if len(report.Hours) == 0 {
+		return
+	}
+
+	fmt.Println(strings.Repeat(" ", 14) + "PERFORMANCE BY SESSION / HOUR")
+	fmt.Println(strings.Repeat("=", 63))
+	fmt.Printf("%-12s %8s %10s %14s %14s\n", "BUCKET", "TRADES", "WIN RATE", "AVG P&L", "TOTAL P&L")
+	fmt.Println(strings.Repeat("-", 63))
+	rows := func(buckets []types.PerformanceBucket) {
+		for _, bucket := range buckets {
+			fmt.Printf("%-12s %8d %9.1f%% %14.*f %14.*f\n",
+				bucket.Label, bucket.Trades, bucket.GetWinRate(),
+				money, bucket.GetAveragePnL(), money, bucket.TotalPnL)
+		}
+	}
+	rows(report.Sessions)
+	fmt.Println(strings.Repeat("-", 63))
+	rows(report.Hours)
+	fmt.Println(strings.Repeat("=", 63) + "\n")
+}
+
+// printSymbolReport prints per-symbol tables and the consolidated portfolio
+func printSymbolReport(report *simulator.FinalReport, money int) {
+	fmt.Println(strings.Repeat(" ", 17) + "PER-SYMBOL BREAKDOWN")
+	fmt.Println(strings.Repeat("=", 79))
+	fmt.Printf("%-10s %8s %14s %12s %12s %9s %9s\n",
+		"SYMBOL", "TRADES", "NET P&L", "COMMISSION", "SLIPPAGE", "MAX DD%", "DD SHARE")
+	fmt.Println(strings.Repeat("-", 79))
+
+	rows := make([]*simulator.SymbolReport, 0, len(report.Symbols)+1)
+	rows = append(rows, report.Symbols...)
+	rows = append(rows, report.Portfolio)
+	for i, r := range rows {
+		if i == len(rows)-1 {
+			fmt.Println(strings.Repeat("-", 79))
+		}
+		fmt.Printf("%-10s %8d %14.*f %12.*f %12.2f %8.2f%% %8.1f%%\n",
+			r.Symbol, r.Trades, money, r.NetPnL, money, r.Commission, r.SlippageUnits,
+			r.MaxDrawdownPercent, r.DrawdownContribution)
+	}
+
+	if len(report.Portfolio.ClosedTrades) > 0 {
+		fmt.Printf("\nClosed trades: %d | Avg MFE: %.*f | Avg MAE: %.*f\n",
+			len(report.Portfolio.ClosedTrades), money, report.Portfolio.AverageMFE, money, report.Portfolio.AverageMAE)
+	}
+	if r := report.Portfolio.RMultiples; r != nil && r.Trades > 0 {
+		fmt.Printf("R-multiples: %d trades | Avg: %.2fR | Median: %.2fR | >=1R: %.1f%% | >=2R: %.1f%%\n",
+			r.Trades, r.AverageR, r.MedianR, r.Above1RPercent, r.Above2RPercent)
+		for _, bucket := range r.Distribution {
+			fmt.Printf("  %-12s %6d\n", bucket.Label, bucket.Trades)
+		}
+	}
+
+	fmt.Println("\n" + strings.Repeat("=", 79) + "\n")
+}
+
+// printSymbolMetrics prints the account's performance per traded symbol
+func printSymbolMetrics(symbols []types.SymbolMetrics, money int) {
+	fmt.Println(strings.Repeat(" ", 19) + "PER-SYMBOL METRICS")
+	fmt.Println(strings.Repeat("=", 79))
+	fmt.Printf("%-10s %8s %9s %14s %12s %12s %9s\n",
+		"SYMBOL", "TRADES", "WIN RATE", "NET P&L", "COMMISSION", "MAX DD", "DD SHARE")
+	fmt.Println(strings.Repeat("-", 79))
+
+	for _, s := range symbols {
+		fmt.Printf("%-10s %8d %8.1f%% %14.*f %12.*f %12.*f %8.1f%%\n",
+			s.Symbol, s.Trades, s.GetWinRate(), money, s.GetNetPnL(), money, s.Commission,
+			money, s.MaxDrawdown, s.DrawdownContribution)
+	}
+
+	fmt.Println("\n" + strings.Repeat("=", 79) + "\n")
+}
+
+// ==================== BATCH STATS ====================
+
+// batchStatsTicks is the number of ticks per batch in verbose batch stats
+const batchStatsTicks = 10000
+
+// recordBatchTick records a tick's processing time and, at the end of each
+// batch, prints its stats with the estimated time remaining
+func recordBatchTick(timer *speed.SessionTimer, processing time.Duration, totalTicks int64) {
+	timer.RecordTick(processing)
+	if timer.GetTicksProcessed()%batchStatsTicks != 0 {
+		return
+	}
+
+	stats := timer.EndBatch()
+	line := fmt.Sprintf("[BATCH] %d ticks in %s (%.0f ticks/s, avg tick %s, max %s)",
+		stats.TicksProcessed,
+		speed.FormatDuration(stats.WallClockTime),
+		stats.TicksPerSecond,
+		speed.FormatDuration(stats.AverageTickTime),
+		speed.FormatDuration(stats.MaxTickTime),
+	)
+	if eta := timer.GetEstimatedTimeRemaining(totalTicks); eta > 0 {
+		line += " | ETA " + speed.FormatDuration(eta)
+	}
+	fmt.Println(line)
+
+	timer.StartBatch(batchStatsTicks)
+}
+
 // ==================== USAGE ====================
 
 func printUsage() {
@@ -237,11 +646,35 @@ Version %s
 
 USAGE:
     holodeck -config <file.json> [options]
+    holodeck export-session -session <dir> [-out session.tar.gz]
+    holodeck import-session -in <session.tar.gz> [-out <dir>] [-replay]
+    holodeck diff [-all] [-max-trades N] <runA> <runB>
 
 OPTIONS:
     -config <file>      Configuration file (JSON) - REQUIRED
-    -speed <multiplier> Simulation speed multiplier (default: 100.0)
+    -speed <multiplier> Simulation speed multiplier (default: 100.0), or
+                        "max" to run unthrottled with no timing bookkeeping
+    -tps <rate>         Throttle to <rate> ticks per second instead of the
+                        speed multiplier (overrides speed.ticks_per_second)
+    -adaptive           Lower the speed while ticks overrun their time budget
+                        and raise it again when headroom returns
+                        (speed.governor in config)
+    -control <addr>     Accept commands while running on a Unix socket
+                        (path), tcp:host:port or http:host:port:
+                        status, metrics, speed <x|max>, tps <rate>, pause,
+                        resume, step [on|off], help
     -verbose            Enable verbose output
+    -status-file <file> Atomically rewrite session status, progress and ETA
+                        as JSON to <file> while running
+    -status-interval <s> Seconds between status file updates (default: 5)
+    -session-dir <dir>  Save config, executions, metrics and report to <dir>
+                        after the run (input for export-session)
+    -audit-file <file>  Export the hash-chained execution audit trail
+                        (JSON lines) to <file> after the run
+    -report-dir <dir>   Write report.html with equity, drawdown and trade
+                        distribution charts to <dir> after the run
+    -result-file <file> Write the versioned JSON SimulationResult (config,
+                        metrics, trades, errors) to <file> after the run
     -help               Show this help message
     -version            Show version information
 
@@ -252,6 +685,27 @@ EXAMPLES:
     # Simulation at 1000x speed with verbose output
     holodeck -config config.json -speed 1000.0 -verbose
 
+    # Batch backtest as fast as possible
+    holodeck -config config.json -speed max
+
+    # Fixed 500 ticks per second, whatever the tick density
+    holodeck -config config.json -tps 500
+
+    # Long run that can be slowed down or paused from another terminal
+    holodeck -config config.json -speed 10000 -control /tmp/holodeck.sock
+    echo "speed 10" | nc -U /tmp/holodeck.sock
+
+    # Long run monitored by an external scheduler
+    holodeck -config config.json -status-file status.json -status-interval 10
+
+    # Bundle a run for sharing, then inspect and replay it elsewhere
+    holodeck -config config.json -session-dir runs/eurusd
+    holodeck export-session -session runs/eurusd -out eurusd.tar.gz
+    holodeck import-session -in eurusd.tar.gz -replay
+
+    # Compare two runs (session directories or archives)
+    holodeck diff runs/baseline runs/candidate.tar.gz
+
     # Show version
     holodeck -version
 
@@ -277,7 +731,7 @@ CONFIGURATION FILE:
         "commission": true,
         "commission_value": 0.0001,
         "slippage": true,
-        "slippage_model": "fixed",
+        "slippage_model": "depth",
         "latency": false
       },
       "speed": {
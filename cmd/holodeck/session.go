@@ -0,0 +1,519 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"holodeck/reports"
+	"holodeck/simulator"
+)
+
+// ==================== SESSION DIRECTORY ====================
+//
+// A session directory holds everything a finished run produced:
+//
+//	config.json       the configuration the run used
+//	executions.json   every applied execution report
+//	metrics.json      final metrics
+//	report.json       per-symbol final report
+//	costs.csv         closed trades' P&L split into spread, slippage,
+//	                  commission and swap
+//	heatmap.csv       closing trades by weekday and hour, for heatmaps
+//	report.html       shareable report with equity, drawdown and trade charts
+//	result.json       versioned SimulationResult for programmatic comparison
+//	logs/             session log files (if logging was enabled)
+//	checkpoint.json   end-of-session state checkpoint (netting mode only;
+//	                  hedging tickets are not carried over)
+//
+// export-session bundles a session directory plus a checksum manifest into
+// a tar.gz archive; import-session extracts, verifies and optionally replays it
+
+// Session file names
+const (
+	SessionConfigFile     = "config.json"
+	SessionExecutionsFile = "executions.json"
+	SessionMetricsFile    = "metrics.json"
+	SessionReportFile     = "report.json"
+	SessionCheckpointFile = "checkpoint.json"
+	SessionManifestFile   = "manifest.json"
+	SessionAuditFile      = "audit.jsonl"
+	SessionEquityFile     = "equity_curve.json"
+	SessionStatementCSV   = "statement.csv"
+	SessionStatementText  = "statement.txt"
+	SessionTaxLotsFile    = "tax_lots.csv"
+	SessionCostsFile      = "costs.csv"
+	SessionHeatmapFile    = "heatmap.csv"
+	SessionLogsDir        = "logs"
+)
+
+// writeSessionDir saves the artifacts of a finished run to dir
+func writeSessionDir(dir, configFile string, config *simulator.Config, h *simulator.Holodeck) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	configData, err := os.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, SessionConfigFile), configData, 0644); err != nil {
+		return err
+	}
+
+	artifacts := map[string]interface{}{
+		SessionExecutionsFile: h.GetExecutionHistory(),
+		SessionMetricsFile:    h.GetPerformanceMetrics(),
+		SessionReportFile:     h.GetSymbolReport(),
+		SessionEquityFile:     h.GetEquityCurve(),
+	}
+	for name, value := range artifacts {
+		if err := writeJSONFile(filepath.Join(dir, name), value); err != nil {
+			return err
+		}
+	}
+	if err := h.ExportAuditTrail(filepath.Join(dir, SessionAuditFile)); err != nil {
+		return err
+	}
+	for _, name := range []string{SessionStatementCSV, SessionStatementText} {
+		if err := h.ExportStatement(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	if err := h.ExportTaxLots(filepath.Join(dir, SessionTaxLotsFile)); err != nil {
+		return err
+	}
+	if err := h.ExportCostAttribution(filepath.Join(dir, SessionCostsFile)); err != nil {
+		return err
+	}
+	if err := h.ExportHeatmap(filepath.Join(dir, SessionHeatmapFile)); err != nil {
+		return err
+	}
+	if _, err := reports.NewHTMLReport(h, config.GetMoneyDecimals()).WriteFile(dir); err != nil {
+		return err
+	}
+	if err := h.WriteSimulationResult(filepath.Join(dir, simulator.SimulationResultFile)); err != nil {
+		return err
+	}
+
+	// The carryover state doubles as the session checkpoint
+	if !config.IsHedging() {
+		if err := h.SaveState(filepath.Join(dir, SessionCheckpointFile)); err != nil {
+			return err
+		}
+	}
+
+	return copySessionLogs(dir, config, h.GetSessionID())
+}
+
+// copySessionLogs copies the session's log files (named
+// <session id>_<timestamp>_*, including rotated ones) into the logs/
+// subdirectory of dir
+func copySessionLogs(dir string, config *simulator.Config, sessionID string) error {
+	logDir := config.GetLogDir()
+	if logDir == "" {
+		return nil
+	}
+
+	logFiles, err := filepath.Glob(filepath.Join(logDir, sessionID+"_*"))
+	if err != nil {
+		return err
+	}
+
+	logsDir := filepath.Join(dir, SessionLogsDir)
+	for _, logFile := range logFiles {
+		info, err := os.Stat(logFile)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if err := os.MkdirAll(logsDir, 0755); err != nil {
+			return err
+		}
+		if err := copyFile(logFile, filepath.Join(logsDir, filepath.Base(logFile))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ==================== MANIFEST ====================
+
+// SessionManifest lists the archive contents with checksums
+type SessionManifest struct {
+	Version   string          `json:"version"`
+	CreatedAt time.Time       `json:"created_at"`
+	DataFile  *ManifestEntry  `json:"data_file,omitempty"`
+	Files     []ManifestEntry `json:"files"`
+}
+
+// ManifestEntry is one checksummed file
+type ManifestEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// newManifestEntry checksums a file
+func newManifestEntry(path, name string) (ManifestEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+
+	return ManifestEntry{
+		Path:   name,
+		Size:   size,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// ==================== EXPORT ====================
+
+// runExportSession implements `holodeck export-session`
+func runExportSession(args []string) error {
+	fs := flag.NewFlagSet("export-session", flag.ExitOnError)
+	sessionDir := fs.String("session", "", "Session directory written by -session-dir (REQUIRED)")
+	out := fs.String("out", "session.tar.gz", "Archive to write")
+	fs.Parse(args)
+
+	if *sessionDir == "" {
+		return fmt.Errorf("-session is required")
+	}
+
+	files, err := listSessionFiles(*sessionDir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("session directory %s is empty", *sessionDir)
+	}
+
+	manifest := &SessionManifest{
+		Version:   Version,
+		CreatedAt: time.Now(),
+		Files:     make([]ManifestEntry, 0, len(files)),
+	}
+	for _, name := range files {
+		entry, err := newManifestEntry(filepath.Join(*sessionDir, name), name)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, entry)
+	}
+
+	// Checksum the market data so a replay can prove it uses the same ticks
+	if config, err := loadConfigFromFile(filepath.Join(*sessionDir, SessionConfigFile)); err == nil && config.CSV.FilePath != "" {
+		if entry, err := newManifestEntry(config.CSV.FilePath, config.CSV.FilePath); err == nil {
+			manifest.DataFile = &entry
+		}
+	}
+
+	if err := writeSessionArchive(*out, *sessionDir, files, manifest); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d files from %s to %s\n", len(files), *sessionDir, *out)
+	if manifest.DataFile == nil {
+		fmt.Println("Warning: market data file not found; archive has no data checksum")
+	}
+	return nil
+}
+
+// listSessionFiles returns session files relative to dir, sorted
+func listSessionFiles(dir string) ([]string, error) {
+	files := make([]string, 0)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if name != SessionManifestFile {
+			files = append(files, filepath.ToSlash(name))
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// writeSessionArchive writes the manifest and session files to a tar.gz
+func writeSessionArchive(out, dir string, files []string, manifest *SessionManifest) error {
+	f, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeTarEntry(tw, SessionManifestFile, manifestData); err != nil {
+		return err
+	}
+
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+		if err := writeTarEntry(tw, name, data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// writeTarEntry adds one file to a tar archive
+func writeTarEntry(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// ==================== IMPORT ====================
+
+// runImportSession implements `holodeck import-session`
+func runImportSession(args []string) error {
+	fs := flag.NewFlagSet("import-session", flag.ExitOnError)
+	in := fs.String("in", "", "Archive written by export-session (REQUIRED)")
+	out := fs.String("out", "", "Directory to extract into (default: archive name)")
+	replay := fs.Bool("replay", false, "Re-run the session's config after verifying the data checksum")
+	fs.Parse(args)
+
+	if *in == "" {
+		return fmt.Errorf("-in is required")
+	}
+	if *out == "" {
+		*out = strings.TrimSuffix(strings.TrimSuffix(filepath.Base(*in), ".gz"), ".tar")
+	}
+
+	if err := extractSessionArchive(*in, *out); err != nil {
+		return err
+	}
+
+	manifest, err := verifySessionDir(*out)
+	if err != nil {
+		return err
+	}
+
+	printSessionSummary(*out, manifest)
+
+	if *replay {
+		return replaySession(*out, manifest)
+	}
+	return nil
+}
+
+// extractSessionArchive extracts a tar.gz into dir
+func extractSessionArchive(archive, dir string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("invalid archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid archive: %w", err)
+		}
+
+		// Refuse entries that would escape the target directory
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %q escapes the target directory", header.Name)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		target := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		outFile, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(outFile, tr); err != nil {
+			outFile.Close()
+			return err
+		}
+		if err := outFile.Close(); err != nil {
+			return err
+		}
+	}
+}
+
+// verifySessionDir checks every file against the manifest
+func verifySessionDir(dir string) (*SessionManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, SessionManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("archive has no manifest: %w", err)
+	}
+
+	manifest := &SessionManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	for _, expected := range manifest.Files {
+		actual, err := newManifestEntry(filepath.Join(dir, filepath.FromSlash(expected.Path)), expected.Path)
+		if err != nil {
+			return nil, fmt.Errorf("missing %s: %w", expected.Path, err)
+		}
+		if actual.SHA256 != expected.SHA256 {
+			return nil, fmt.Errorf("checksum mismatch for %s", expected.Path)
+		}
+	}
+
+	// Sessions saved before the audit trail existed have no audit file
+	if f, err := os.Open(filepath.Join(dir, SessionAuditFile)); err == nil {
+		defer f.Close()
+		entries, err := simulator.ReadAuditTrail(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit trail: %w", err)
+		}
+		if err := simulator.VerifyAuditEntries(entries); err != nil {
+			return nil, fmt.Errorf("audit trail verification failed: %w", err)
+		}
+	}
+
+	return manifest, nil
+}
+
+// printSessionSummary prints what an imported session contains
+func printSessionSummary(dir string, manifest *SessionManifest) {
+	fmt.Printf("Session:   %s\n", dir)
+	fmt.Printf("Exported:  %s (holodeck %s)\n", manifest.CreatedAt.Format(time.RFC3339), manifest.Version)
+	if manifest.DataFile != nil {
+		fmt.Printf("Data:      %s (sha256 %s...)\n", manifest.DataFile.Path, manifest.DataFile.SHA256[:12])
+	}
+	fmt.Println("Files:")
+	for _, entry := range manifest.Files {
+		fmt.Printf("  %-40s %10d bytes\n", entry.Path, entry.Size)
+	}
+
+	report := &simulator.SymbolReport{}
+	if err := readJSONFile(filepath.Join(dir, SessionReportFile), report); err == nil {
+		fmt.Printf("\nResult:    %s\n", report)
+	}
+}
+
+// replaySession re-runs the session config once its data is verified
+func replaySession(dir string, manifest *SessionManifest) error {
+	if manifest.DataFile == nil {
+		return fmt.Errorf("cannot replay: archive has no data checksum")
+	}
+
+	actual, err := newManifestEntry(manifest.DataFile.Path, manifest.DataFile.Path)
+	if err != nil {
+		return fmt.Errorf("cannot replay: data file %s: %w", manifest.DataFile.Path, err)
+	}
+	if actual.SHA256 != manifest.DataFile.SHA256 {
+		return fmt.Errorf("cannot replay: data file %s differs from the exported session", manifest.DataFile.Path)
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\nReplaying session...")
+	cmd := exec.Command(self, "-config", filepath.Join(dir, SessionConfigFile))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// ==================== HELPERS ====================
+
+// writeJSONFile writes a value as indented JSON
+func writeJSONFile(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// readJSONFile reads JSON into value
+func readJSONFile(path string, value interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+// copyFile copies a file
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
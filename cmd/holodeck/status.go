@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"holodeck/simulator"
+)
+
+// ==================== STATUS FILE ====================
+
+// StatusReport is the heartbeat document written to the status file
+type StatusReport struct {
+	State           string                   `json:"state"` // starting, running, paused, completed, failed
+	UpdatedAt       time.Time                `json:"updated_at"`
+	Session         *simulator.SessionStatus `json:"session"`
+	TicksProcessed  int64                    `json:"ticks_processed"`
+	TotalTicks      int64                    `json:"total_ticks"`
+	ProgressPercent float64                  `json:"progress_percent"`
+	ElapsedSeconds  float64                  `json:"elapsed_seconds"`
+	ETASeconds      float64                  `json:"eta_seconds"`
+	ETA             string                   `json:"eta"`
+}
+
+// StatusWriter periodically rewrites a status file for external monitors
+type StatusWriter struct {
+	path       string
+	interval   time.Duration
+	totalTicks int64
+	startTime  time.Time
+
+	// Ticks processed so far, set by the main loop (atomic)
+	ticks int64
+
+	// Serializes writes from the ticker and the main loop
+	mu sync.Mutex
+
+	// Periodic updates (nil when not started)
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewStatusWriter creates a status writer
+// totalTicks may be 0 when the size of the data set is unknown
+func NewStatusWriter(path string, interval time.Duration, totalTicks int64) *StatusWriter {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	return &StatusWriter{
+		path:       path,
+		interval:   interval,
+		totalTicks: totalTicks,
+		startTime:  time.Now(),
+	}
+}
+
+// SetTicks records how many ticks have been processed
+func (sw *StatusWriter) SetTicks(ticks int64) {
+	atomic.StoreInt64(&sw.ticks, ticks)
+}
+
+// Start rewrites the status file every interval from a ticker until Stop,
+// so monitors keep getting updates while no tick arrives
+// onError, if set, is called with each failed write
+func (sw *StatusWriter) Start(h *simulator.Holodeck, onError func(error)) {
+	if sw.stop != nil {
+		return
+	}
+	sw.stop = make(chan struct{})
+	sw.done = make(chan struct{})
+
+	go func() {
+		defer close(sw.done)
+
+		ticker := time.NewTicker(sw.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-sw.stop:
+				return
+			case <-ticker.C:
+				state := "running"
+				if h.IsPaused() {
+					state = "paused"
+				}
+				if err := sw.Write(h, state); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the periodic updates and waits for an in-flight write
+// Safe to call when the writer was never started
+func (sw *StatusWriter) Stop() {
+	if sw.stop == nil {
+		return
+	}
+	close(sw.stop)
+	<-sw.done
+	sw.stop = nil
+}
+
+// Write builds a status report and atomically replaces the status file
+// The report is written to a temp file in the same directory and renamed,
+// so readers never observe a partially written document
+// h may be nil before the Holodeck is created
+func (sw *StatusWriter) Write(h *simulator.Holodeck, state string) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	report := sw.buildReport(h, atomic.LoadInt64(&sw.ticks), state)
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode status: %w", err)
+	}
+
+	dir := filepath.Dir(sw.path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(sw.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create status temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write status: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to close status temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpName, sw.path); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to replace status file: %w", err)
+	}
+
+	return nil
+}
+
+// buildReport assembles the current status report
+func (sw *StatusWriter) buildReport(h *simulator.Holodeck, ticks int64, state string) *StatusReport {
+	elapsed := time.Since(sw.startTime)
+
+	report := &StatusReport{
+		State:          state,
+		UpdatedAt:      time.Now(),
+		TicksProcessed: ticks,
+		TotalTicks:     sw.totalTicks,
+		ElapsedSeconds: elapsed.Seconds(),
+		ETASeconds:     -1,
+		ETA:            "unknown",
+	}
+
+	if h != nil {
+		report.Session = h.GetStatus()
+	}
+
+	if sw.totalTicks > 0 {
+		report.ProgressPercent = float64(ticks) / float64(sw.totalTicks) * 100
+	}
+
+	if state == "completed" {
+		report.ETASeconds = 0
+		report.ETA = "0s"
+	} else if sw.totalTicks > 0 && ticks > 0 {
+		remaining := sw.totalTicks - ticks
+		if remaining < 0 {
+			remaining = 0
+		}
+		eta := time.Duration(float64(elapsed) / float64(ticks) * float64(remaining))
+		report.ETASeconds = eta.Seconds()
+		report.ETA = eta.Round(time.Second).String()
+	}
+
+	return report
+}
@@ -0,0 +1,225 @@
+package commission
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== COMMISSION SCHEDULE ====================
+
+// CommissionTier is one volume bracket of a tiered schedule
+// A tier applies once the month's traded notional reaches MinVolume
+type CommissionTier struct {
+	// MinVolume is the monthly notional at which this tier starts
+	MinVolume float64 `json:"min_volume"`
+
+	// Rate is the taker rate, in the schedule's commission type units
+	Rate float64 `json:"rate"`
+
+	// MakerRate is charged to limit orders (nil uses Rate)
+	// Negative values are rebates
+	MakerRate *float64 `json:"maker_rate,omitempty"`
+}
+
+// ScheduleConfig defines a tiered commission schedule
+type ScheduleConfig struct {
+	// Type is how Rate is applied: per_million, per_share, per_lot or percentage
+	Type string `json:"type"`
+
+	// Tiers are the volume brackets; the lowest must start at 0
+	Tiers []CommissionTier `json:"tiers"`
+
+	// MinCommission is the smallest fee charged per fill (0 = no minimum)
+	MinCommission float64 `json:"min_commission"`
+}
+
+// CommissionSchedule computes commissions from a tiered schedule
+// Traded notional accumulates per calendar month (of the fill timestamps)
+// and resets when a fill falls in a new month
+type CommissionSchedule struct {
+	config ScheduleConfig
+
+	// Current month's volume
+	month         time.Time
+	monthlyVolume float64
+
+	// Statistics
+	totalCommission float64
+	totalNotional   float64
+	commissionCount int64
+	makerCount      int64
+}
+
+// NewCommissionSchedule creates a schedule, sorting tiers by volume
+func NewCommissionSchedule(config ScheduleConfig) (*CommissionSchedule, error) {
+	if err := ValidateScheduleConfig(config); err != nil {
+		return nil, err
+	}
+
+	tiers := make([]CommissionTier, len(config.Tiers))
+	copy(tiers, config.Tiers)
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].MinVolume < tiers[j].MinVolume
+	})
+	config.Tiers = tiers
+
+	return &CommissionSchedule{config: config}, nil
+}
+
+// ValidateScheduleConfig checks a schedule definition
+func ValidateScheduleConfig(config ScheduleConfig) error {
+	switch config.Type {
+	case types.CommissionTypePerMillion, types.CommissionTypePerShare,
+		types.CommissionTypePerLot, types.CommissionTypePercentage:
+	default:
+		return fmt.Errorf("invalid commission schedule type: %s", config.Type)
+	}
+
+	if len(config.Tiers) == 0 {
+		return fmt.Errorf("commission schedule needs at least one tier")
+	}
+
+	lowest := math.Inf(1)
+	for _, tier := range config.Tiers {
+		if tier.MinVolume < 0 {
+			return fmt.Errorf("tier min_volume cannot be negative")
+		}
+		if tier.Rate < 0 {
+			return fmt.Errorf("tier rate cannot be negative")
+		}
+		lowest = math.Min(lowest, tier.MinVolume)
+	}
+	if lowest != 0 {
+		return fmt.Errorf("lowest tier must start at min_volume 0")
+	}
+
+	if config.MinCommission < 0 {
+		return fmt.Errorf("min_commission cannot be negative")
+	}
+	return nil
+}
+
+// ==================== CORE CALCULATION ====================
+
+// Calculate returns the commission for a fill and adds its notional to the
+// month's volume; maker fills use the tier's maker rate
+// The tier is chosen from the volume traded before this fill
+func (cs *CommissionSchedule) Calculate(
+	price float64,
+	size float64,
+	instrument types.Instrument,
+	maker bool,
+	timestamp time.Time,
+) float64 {
+
+	cs.rollMonth(timestamp)
+
+	contractSize := 1.0
+	if instrument != nil && instrument.GetContractSize() > 0 {
+		contractSize = float64(instrument.GetContractSize())
+	}
+	notional := math.Abs(price * size * contractSize)
+
+	tier := cs.GetCurrentTier()
+	rate := tier.Rate
+	if maker && tier.MakerRate != nil {
+		rate = *tier.MakerRate
+		cs.makerCount++
+	}
+
+	var commission float64
+	switch cs.config.Type {
+	case types.CommissionTypePerMillion:
+		commission = notional / 1000000.0 * rate
+	case types.CommissionTypePerShare, types.CommissionTypePerLot:
+		commission = math.Abs(size) * rate
+	case types.CommissionTypePercentage:
+		commission = notional * rate
+	}
+
+	// Rebates are exempt from the minimum
+	if commission >= 0 && commission < cs.config.MinCommission {
+		commission = cs.config.MinCommission
+	}
+
+	cs.monthlyVolume += notional
+	cs.totalNotional += notional
+	cs.totalCommission += commission
+	cs.commissionCount++
+
+	return commission
+}
+
+// rollMonth resets the monthly volume when a fill starts a new month
+func (cs *CommissionSchedule) rollMonth(timestamp time.Time) {
+	month := time.Date(timestamp.Year(), timestamp.Month(), 1, 0, 0, 0, 0, timestamp.Location())
+	if !month.Equal(cs.month) {
+		cs.month = month
+		cs.monthlyVolume = 0
+	}
+}
+
+// GetCurrentTier returns the tier for the month's volume so far
+func (cs *CommissionSchedule) GetCurrentTier() CommissionTier {
+	current := cs.config.Tiers[0]
+	for _, tier := range cs.config.Tiers {
+		if cs.monthlyVolume >= tier.MinVolume {
+			current = tier
+		}
+	}
+	return current
+}
+
+// ==================== STATISTICS ====================
+
+// GetMonthlyVolume returns the notional traded in the current month
+func (cs *CommissionSchedule) GetMonthlyVolume() float64 {
+	return cs.monthlyVolume
+}
+
+// GetTotalCommission returns total commission charged
+func (cs *CommissionSchedule) GetTotalCommission() float64 {
+	return cs.totalCommission
+}
+
+// GetStatistics returns schedule statistics
+func (cs *CommissionSchedule) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"type":             cs.config.Type,
+		"tiers":            len(cs.config.Tiers),
+		"current_rate":     cs.GetCurrentTier().Rate,
+		"monthly_volume":   cs.monthlyVolume,
+		"total_notional":   cs.totalNotional,
+		"total_commission": cs.totalCommission,
+		"commission_count": cs.commissionCount,
+		"maker_fills":      cs.makerCount,
+	}
+}
+
+// Reset clears volume and statistics
+func (cs *CommissionSchedule) Reset() {
+	cs.month = time.Time{}
+	cs.monthlyVolume = 0
+	cs.totalCommission = 0
+	cs.totalNotional = 0
+	cs.commissionCount = 0
+	cs.makerCount = 0
+}
+
+// ==================== DEBUG ====================
+
+// String returns a human-readable representation
+func (cs *CommissionSchedule) String() string {
+	return fmt.Sprintf(
+		"CommissionSchedule[%s, Tiers:%d, Rate:%.6f, Volume:%.2f, Total:$%.2f]",
+		cs.config.Type,
+		len(cs.config.Tiers),
+		cs.GetCurrentTier().Rate,
+		cs.monthlyVolume,
+		cs.totalCommission,
+	)
+}
@@ -0,0 +1,221 @@
+package corporate
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ==================== CONFIGURATION ====================
+
+// Corporate action types
+const (
+	ActionTypeDividend = "dividend"
+	ActionTypeSplit    = "split"
+)
+
+// CorporateAction is one calendar entry
+// Dividends pay Amount per share to longs (and charge shorts); splits
+// multiply position size by Ratio and divide entry prices by it (2 is a
+// 2-for-1 split, 0.1 a 1-for-10 reverse split)
+// Both take effect at the start of the ex-date (UTC) unless ExDate carries
+// a time
+type CorporateAction struct {
+	Symbol string  `json:"symbol"`
+	Type   string  `json:"type"`
+	ExDate string  `json:"ex_date"` // YYYY-MM-DD or RFC3339
+	Amount float64 `json:"amount"`
+	Ratio  float64 `json:"ratio"`
+
+	effective time.Time
+}
+
+// GetEffectiveTime returns when the action applies
+func (ca *CorporateAction) GetEffectiveTime() time.Time {
+	return ca.effective
+}
+
+// IsDividend returns true for a cash dividend
+func (ca *CorporateAction) IsDividend() bool {
+	return ca.Type == ActionTypeDividend
+}
+
+// IsSplit returns true for a split or reverse split
+func (ca *CorporateAction) IsSplit() bool {
+	return ca.Type == ActionTypeSplit
+}
+
+// GetCashAmount returns the dividend cash for a signed position size
+// (positive = credit to a long, negative = debit to a short)
+func (ca *CorporateAction) GetCashAmount(positionSize float64) float64 {
+	if !ca.IsDividend() {
+		return 0
+	}
+	return ca.Amount * positionSize
+}
+
+// String returns a human-readable representation
+func (ca *CorporateAction) String() string {
+	if ca.IsSplit() {
+		return fmt.Sprintf("Split[%s %s x%.4g]", ca.Symbol, ca.ExDate, ca.Ratio)
+	}
+	return fmt.Sprintf("Dividend[%s %s %.4f/share]", ca.Symbol, ca.ExDate, ca.Amount)
+}
+
+// parse validates the action and resolves its effective time
+func (ca *CorporateAction) parse() error {
+	if ca.Symbol == "" {
+		return fmt.Errorf("corporate action requires a symbol")
+	}
+
+	effective, err := time.Parse(time.RFC3339, ca.ExDate)
+	if err != nil {
+		effective, err = time.Parse("2006-01-02", ca.ExDate)
+		if err != nil {
+			return fmt.Errorf("%s %s: invalid ex_date %q (want YYYY-MM-DD or RFC3339)", ca.Symbol, ca.Type, ca.ExDate)
+		}
+	}
+	ca.effective = effective
+
+	switch ca.Type {
+	case ActionTypeDividend:
+		if ca.Amount <= 0 {
+			return fmt.Errorf("%s dividend on %s: amount must be positive", ca.Symbol, ca.ExDate)
+		}
+	case ActionTypeSplit:
+		if ca.Ratio <= 0 || ca.Ratio == 1 {
+			return fmt.Errorf("%s split on %s: ratio must be positive and not 1", ca.Symbol, ca.ExDate)
+		}
+	default:
+		return fmt.Errorf("%s: action type must be %s or %s", ca.Symbol, ActionTypeDividend, ActionTypeSplit)
+	}
+	return nil
+}
+
+// CorporateActionsConfig configures the corporate actions calendar
+type CorporateActionsConfig struct {
+	Enabled bool              `json:"enabled"`
+	Actions []CorporateAction `json:"actions"`
+}
+
+// ==================== ENGINE ====================
+
+// CorporateActionsEngine releases calendar entries as the simulated clock
+// passes their effective times, per symbol
+// As with financing, the first tick of a symbol only starts its clock, so
+// actions dated before the data are not applied
+type CorporateActionsEngine struct {
+	actions  map[string][]*CorporateAction // By symbol, in effective order
+	lastTime map[string]time.Time
+
+	// Statistics
+	dividendsPaid    float64
+	dividendsCharged float64
+	dividendsApplied int64
+	splitsApplied    int64
+}
+
+// NewCorporateActionsEngine validates the calendar and creates an engine
+func NewCorporateActionsEngine(config CorporateActionsConfig) (*CorporateActionsEngine, error) {
+	engine := &CorporateActionsEngine{
+		actions:  make(map[string][]*CorporateAction),
+		lastTime: make(map[string]time.Time),
+	}
+
+	for i := range config.Actions {
+		action := config.Actions[i]
+		if err := action.parse(); err != nil {
+			return nil, err
+		}
+		engine.actions[action.Symbol] = append(engine.actions[action.Symbol], &action)
+	}
+
+	for _, actions := range engine.actions {
+		sort.SliceStable(actions, func(i, j int) bool {
+			return actions[i].effective.Before(actions[j].effective)
+		})
+	}
+
+	return engine, nil
+}
+
+// ProcessTick returns the symbol's actions that took effect since its
+// previous tick, oldest first
+func (ce *CorporateActionsEngine) ProcessTick(now time.Time, symbol string) []*CorporateAction {
+	last, started := ce.lastTime[symbol]
+	if !started || !now.After(last) {
+		if !started {
+			ce.lastTime[symbol] = now
+		}
+		return nil
+	}
+	ce.lastTime[symbol] = now
+
+	due := make([]*CorporateAction, 0)
+	for _, action := range ce.actions[symbol] {
+		if action.effective.After(last) && !action.effective.After(now) {
+			due = append(due, action)
+		}
+	}
+	return due
+}
+
+// RecordDividend adds an applied dividend to the statistics
+func (ce *CorporateActionsEngine) RecordDividend(amount float64) {
+	ce.dividendsApplied++
+	if amount > 0 {
+		ce.dividendsPaid += amount
+	} else {
+		ce.dividendsCharged -= amount
+	}
+}
+
+// RecordSplit adds an applied split to the statistics
+func (ce *CorporateActionsEngine) RecordSplit() {
+	ce.splitsApplied++
+}
+
+// GetActions returns the calendar for a symbol, in effective order
+func (ce *CorporateActionsEngine) GetActions(symbol string) []CorporateAction {
+	actions := make([]CorporateAction, len(ce.actions[symbol]))
+	for i, action := range ce.actions[symbol] {
+		actions[i] = *action
+	}
+	return actions
+}
+
+// ==================== STATISTICS ====================
+
+// GetStatistics returns corporate action statistics
+func (ce *CorporateActionsEngine) GetStatistics() map[string]interface{} {
+	scheduled := 0
+	for _, actions := range ce.actions {
+		scheduled += len(actions)
+	}
+	return map[string]interface{}{
+		"scheduled":         scheduled,
+		"dividends_applied": ce.dividendsApplied,
+		"splits_applied":    ce.splitsApplied,
+		"dividends_paid":    ce.dividendsPaid,
+		"dividends_charged": ce.dividendsCharged,
+	}
+}
+
+// Reset clears the clocks and statistics
+func (ce *CorporateActionsEngine) Reset() {
+	ce.lastTime = make(map[string]time.Time)
+	ce.dividendsPaid = 0
+	ce.dividendsCharged = 0
+	ce.dividendsApplied = 0
+	ce.splitsApplied = 0
+}
+
+// String returns a human-readable representation
+func (ce *CorporateActionsEngine) String() string {
+	return fmt.Sprintf(
+		"CorporateActionsEngine[Symbols:%d, Dividends:%d, Splits:%d]",
+		len(ce.actions),
+		ce.dividendsApplied,
+		ce.splitsApplied,
+	)
+}
@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -366,8 +367,11 @@ func (ple *PositionLimitError) Error() string {
 // ==================== ERROR CONVERTERS ====================
 
 // ConvertToHolodeckError converts an executor error to HolodeckError
+// Errors that do not wrap one (e.g. from a custom Instrument) become a
+// generic order rejection
 func ConvertToHolodeckError(err error) *types.HolodeckError {
-	if herr, ok := err.(*types.HolodeckError); ok {
+	var herr *types.HolodeckError
+	if errors.As(err, &herr) {
 		return herr
 	}
 
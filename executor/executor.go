@@ -1,8 +1,13 @@
 package executor
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"time"
 
+	"holodeck/commission"
+	"holodeck/slippage"
 	"holodeck/types"
 )
 
@@ -17,6 +22,32 @@ type OrderExecutor struct {
 	ordersExecuted   int64
 	ordersRejected   int64
 	executionHistory []*types.ExecutionReport
+
+	// Remainders of depth-limited fills still working
+	workingOrders *WorkingOrderBook
+
+	// Slippage applied to market fills when enabled
+	slippageCalc *slippage.SlippageCalculator
+
+	// Spread/staleness gate checked before routing
+	guard *ExecutionGuard
+
+	// Account state used by the margin check (set by the simulator)
+	balance  *types.Balance
+	position *types.Position
+
+	// Open positions of every symbol, used by the exposure limits
+	positions map[string]*types.Position
+
+	// Commission actually charged vs. what the configured commission
+	// would have been without overrides or commission-free mode
+	commissionCharged    float64
+	commissionConfigured float64
+
+	// Tracer and the order's span context (set by the simulator; nil
+	// tracer disables tracing)
+	tracer   types.Tracer
+	traceCtx context.Context
 }
 
 // ExecutorConfig holds executor configuration
@@ -27,6 +58,56 @@ type ExecutorConfig struct {
 	LatencyEnabled      bool
 	PartialFillsEnabled bool
 
+	// PartialFillModel selects how partial fill sizes are computed
+	// ("participation" caps fills at ParticipationRate of tick volume;
+	// anything else limits fills by depth)
+	PartialFillModel  string
+	ParticipationRate float64
+
+	// ContinuePartialFills keeps the unfilled remainder of a partial fill
+	// working against depth on subsequent ticks instead of dropping it
+	ContinuePartialFills bool
+
+	// LimitQueueModeling rests unfilled limit orders in a queue at their
+	// price; they fill only once traded volume works through the queue
+	LimitQueueModeling bool
+
+	// CommissionSchedules are tiered schedules keyed by symbol or
+	// instrument type, consulted when commission is enabled
+	CommissionSchedules map[string]*commission.CommissionSchedule
+
+	// SlippageModel selects the slippage model (depth, impact, none, ...)
+	SlippageModel string
+
+	// SpreadMarkupType widens the quoted spread like a retail broker
+	// ("pips" or "percent"; empty disables the markup)
+	SpreadMarkupType  string
+	SpreadMarkupValue float64
+
+	// SyntheticBook expands L1 ticks into depth levels that market orders
+	// walk; Levels <= 1 keeps the single top-of-book depth
+	SyntheticBook BookConfig
+
+	// Execution guard: reject when the spread is wider than MaxSpreadPips
+	// or the tick is older than MaxTickAge (simulated time); 0 disables
+	MaxSpreadPips float64
+	MaxTickAge    time.Duration
+
+	// MarginCheck rejects orders whose required margin (notional /
+	// leverage, converted to the account currency) exceeds the account's
+	// available margin
+	MarginCheck bool
+
+	// Exposure limits across all open positions, in notional (size x
+	// price x contract size); 0 disables each
+	MaxGrossExposure float64
+	MaxNetExposure   float64
+
+	// LeverageTiers margin position notional band by band, keyed by
+	// symbol or instrument type; instruments without tiers use the
+	// account's flat leverage
+	LeverageTiers map[string]types.LeverageTiers
+
 	// Order limits
 	MaxOrderSize     float64
 	MaxPositionSize  float64
@@ -35,12 +116,36 @@ type ExecutorConfig struct {
 
 // ==================== EXECUTOR CREATION ====================
 
+// newWorkingOrderBookForConfig creates the working order book, applying the
+// participation cap to remainders when that fill model is selected
+func newWorkingOrderBookForConfig(config ExecutorConfig) *WorkingOrderBook {
+	book := NewWorkingOrderBook()
+	if config.PartialFillModel == types.PartialFillByParticipation {
+		book.SetParticipationRate(config.ParticipationRate)
+	}
+	return book
+}
+
 // NewOrderExecutor creates a new order executor
-func NewOrderExecutor(config ExecutorConfig) *OrderExecutor {
+// Returns an error if slippage is enabled with an unsupported model
+func NewOrderExecutor(config ExecutorConfig) (*OrderExecutor, error) {
+	var slippageCalc *slippage.SlippageCalculator
+	if config.SlippageEnabled {
+		var err error
+		slippageCalc, err = slippage.NewSlippageCalculatorForModel(config.SlippageModel)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &OrderExecutor{
 		config:           config,
 		executionHistory: make([]*types.ExecutionReport, 0),
-	}
+		workingOrders:    newWorkingOrderBookForConfig(config),
+		slippageCalc:     slippageCalc,
+		guard:            NewExecutionGuard(config.MaxSpreadPips, config.MaxTickAge),
+		traceCtx:         context.Background(),
+	}, nil
 }
 
 // ==================== CORE EXECUTION ====================
@@ -80,26 +185,33 @@ func (oe *OrderExecutor) Execute(
 		}, nil
 	}
 
+	// An order that expired before reaching the market never trades
+	if order.IsExpired(tick.Timestamp) {
+		exec := &types.ExecutionReport{
+			OrderID:       order.OrderID,
+			Timestamp:     tick.Timestamp,
+			Action:        order.Action,
+			RequestedSize: order.Size,
+			FillPrice:     order.LimitPrice,
+			Status:        types.OrderStatusExpired,
+		}
+		oe.recordExecution(exec)
+		return exec, nil
+	}
+
 	// Validate order
-	validator := NewOrderValidator()
-	if err := validator.ValidateOrder(
-		order,
-		instrument,
-		10000000, // Default available balance
-		oe.config.MinimumOrderSize,
-		oe.config.MaxOrderSize,
-		oe.config.MaxPositionSize,
-	); err != nil {
+	_, span := types.StartSpan(oe.traceCtx, oe.tracer, types.SpanOrderValidate)
+	rejected, record := oe.validate(order, tick, instrument)
+	if rejected != nil {
+		span.SetAttribute("error_code", rejected.ErrorCode)
+	}
+	span.End()
+	if rejected != nil {
 		oe.ordersRejected++
-		herr := err.(*types.HolodeckError)
-		return types.NewRejectedExecution(
-			order.OrderID,
-			tick.Timestamp,
-			order.Action,
-			order.Size,
-			herr.Code,
-			herr.Message,
-		), nil
+		if record {
+			oe.recordExecution(rejected)
+		}
+		return rejected, nil
 	}
 
 	// Route to appropriate executor
@@ -128,21 +240,85 @@ func (oe *OrderExecutor) Execute(
 		return nil, err
 	}
 
+	// Walk the synthetic book for market fills, or apply slippage if enabled
+	var walk *BookWalk
+	if oe.config.SyntheticBook.Levels > 1 && order.IsMarket() && exec.IsFilled() {
+		_, span := types.StartSpan(oe.traceCtx, oe.tracer, types.SpanOrderSlippage)
+		walk = oe.applyBookWalk(exec, tick, instrument)
+		span.End()
+	} else if oe.config.SlippageEnabled && order.IsMarket() && exec.IsFilled() {
+		_, span := types.StartSpan(oe.traceCtx, oe.tracer, types.SpanOrderSlippage)
+		err := oe.applySlippage(exec, tick, instrument)
+		span.SetAttribute("slippage_units", exec.SlippageUnits)
+		if err != nil {
+			span.RecordError(err)
+			span.End()
+			oe.ordersRejected++
+			return nil, err
+		}
+		span.End()
+	}
+
+	// Apply broker spread markup to every fill
+	if oe.config.SpreadMarkupType != "" && exec.IsFilled() {
+		oe.applySpreadMarkup(order, exec, tick, instrument)
+	}
+
 	// Handle partial fills if enabled
+	keepWorking := false
 	if oe.config.PartialFillsEnabled && exec.IsFilled() {
-		pfc := NewPartialFillCalculator()
-		filledSize := pfc.CalculateFilledSize(
-			exec.RequestedSize,
-			int64(tick.GetAvailableDepth()),
-			tick.Volume,
-		)
+		var filledSize float64
+		if walk != nil {
+			// The book walk already knows how much depth there was
+			filledSize = walk.FilledSize
+		} else {
+			pfc := NewPartialFillCalculator()
+			filledSize = pfc.CalculateFilledSize(
+				exec.RequestedSize,
+				int64(tick.GetAvailableDepth()),
+				tick.Volume,
+			)
+		}
+
+		// Participation caps the fill at a share of this tick's volume
+		if oe.config.PartialFillModel == types.PartialFillByParticipation {
+			pfc := NewPartialFillCalculator()
+			filledSize = pfc.CalculateParticipationFill(filledSize, tick.Volume, oe.config.ParticipationRate)
+		}
 
-		if filledSize < exec.RequestedSize {
+		if filledSize <= 0 {
+			// Nothing on the book; a later tick may have depth
+			exec = types.NewRejectedExecution(
+				order.OrderID,
+				tick.Timestamp,
+				order.Action,
+				order.Size,
+				types.ErrorCodeNoLiquidity,
+				"no liquidity available",
+			)
+		} else if filledSize < exec.RequestedSize {
 			exec.FilledSize = filledSize
 			exec.Status = types.OrderStatusPartial
+
+			// Keep the remainder working on following ticks
+			keepWorking = oe.config.ContinuePartialFills
 		}
 	}
 
+	// Charge commission on the filled size
+	if exec.FilledSize > 0 && !exec.IsRejected() {
+		oe.applyCommission(order, exec, instrument)
+	}
+
+	if keepWorking {
+		oe.workingOrders.Add(order, exec)
+	}
+
+	// Rest unfilled limit orders in the queue at their price
+	if oe.config.LimitQueueModeling && order.IsLimit() && exec.Status == types.OrderStatusPending {
+		oe.workingOrders.AddResting(order, tick)
+	}
+
 	// Record execution
 	oe.recordExecution(exec)
 	if !exec.IsRejected() {
@@ -154,6 +330,408 @@ func (oe *OrderExecutor) Execute(
 	return exec, nil
 }
 
+// validate runs the order, limit price, margin, exposure and guard checks
+// Returns the rejection, if any, and whether it is recorded in the
+// execution history
+func (oe *OrderExecutor) validate(
+	order *types.Order,
+	tick *types.Tick,
+	instrument types.Instrument,
+) (*types.ExecutionReport, bool) {
+	// Liquidations must close whatever the market or the limits say
+	if order.Forced {
+		return nil, false
+	}
+
+	validator := NewOrderValidator()
+	if err := validator.ValidateOrder(
+		order,
+		instrument,
+		10000000, // Default available balance
+		oe.config.MinimumOrderSize,
+		oe.config.MaxOrderSize,
+		oe.config.MaxPositionSize,
+	); err != nil {
+		herr := ConvertToHolodeckError(err)
+		return types.NewRejectedExecution(
+			order.OrderID,
+			tick.Timestamp,
+			order.Action,
+			order.Size,
+			herr.Code,
+			herr.Message,
+		), false
+	}
+
+	// Limit orders rest on their own side of the market unless the
+	// order explicitly allows crossing the spread
+	if order.IsLimit() && !order.AllowMarketable {
+		currentPrice := tick.GetBuyPrice()
+		if order.IsSell() {
+			currentPrice = tick.GetSellPrice()
+		}
+		if err := validator.ValidateLimitPrice(order.LimitPrice, currentPrice, order.Action, instrument); err != nil {
+			herr := ConvertToHolodeckError(err)
+			rejected := types.NewRejectedExecution(
+				order.OrderID,
+				tick.Timestamp,
+				order.Action,
+				order.Size,
+				herr.Code,
+				herr.Message,
+			)
+			return rejected, true
+		}
+	}
+
+	// Reject orders the account cannot margin
+	if herr := oe.checkMargin(order, tick, instrument); herr != nil {
+		rejected := types.NewRejectedExecution(
+			order.OrderID,
+			tick.Timestamp,
+			order.Action,
+			order.Size,
+			herr.Code,
+			herr.Message,
+		)
+		return rejected, true
+	}
+
+	// Reject orders that would take exposure across positions past a limit
+	if herr := oe.checkExposure(order, tick, instrument); herr != nil {
+		rejected := types.NewRejectedExecution(
+			order.OrderID,
+			tick.Timestamp,
+			order.Action,
+			order.Size,
+			herr.Code,
+			herr.Message,
+		)
+		return rejected, true
+	}
+
+	// Gate on spread and tick staleness
+	if rejected := oe.guard.Check(order, tick, instrument); rejected != nil {
+		return rejected, true
+	}
+
+	return nil, false
+}
+
+// ==================== SLIPPAGE ====================
+
+// applySlippage moves a market fill price against the order by the
+// slippage from the configured model
+func (oe *OrderExecutor) applySlippage(
+	exec *types.ExecutionReport,
+	tick *types.Tick,
+	instrument types.Instrument,
+) error {
+
+	depth := tick.AskQty
+	if exec.IsSell() {
+		depth = tick.BidQty
+	}
+
+	volatility := 0.0
+	if cfg := instrument.GetConfig(); cfg != nil {
+		volatility = cfg.TypicalVolatility
+	}
+
+	units, err := oe.slippageCalc.CalculateSlippage(
+		exec.RequestedSize,
+		float64(depth),
+		volatility,
+		1.0, // Neutral momentum
+		tick,
+		instrument,
+	)
+	if err != nil {
+		return err
+	}
+
+	fillPrice, err := oe.slippageCalc.CalculateFillPrice(exec.FillPrice, units, exec.Action, instrument)
+	if err != nil {
+		return err
+	}
+
+	exec.SlippageUnits = units
+	exec.FillPrice = fillPrice
+	exec.AverageFillPrice = fillPrice
+	exec.AvailableDepth = depth
+	return nil
+}
+
+// CalculateSlippage estimates the slippage of an order from the
+// instrument's own model (0 when slippage is disabled)
+func (oe *OrderExecutor) CalculateSlippage(size float64, availableDepth int64, momentum int, instrument types.Instrument) float64 {
+	if !oe.config.SlippageEnabled || instrument == nil {
+		return 0
+	}
+	return instrument.CalculateSlippage(size, availableDepth, momentum)
+}
+
+// ==================== BOOK WALK ====================
+
+// applyBookWalk prices a market fill by sweeping a synthetic order book
+// With partial fills disabled, any size beyond the book fills at the
+// deepest level's price
+func (oe *OrderExecutor) applyBookWalk(
+	exec *types.ExecutionReport,
+	tick *types.Tick,
+	instrument types.Instrument,
+) *BookWalk {
+
+	book := NewSyntheticOrderBook(tick, instrument, oe.config.SyntheticBook)
+	walk := book.Walk(exec.Action, exec.RequestedSize)
+	if walk.FilledSize <= 0 {
+		return walk
+	}
+
+	fillPrice := walk.AveragePrice
+	if walk.Exhausted && !oe.config.PartialFillsEnabled {
+		remainder := exec.RequestedSize - walk.FilledSize
+		fillPrice = (walk.AveragePrice*walk.FilledSize + walk.WorstPrice*remainder) / exec.RequestedSize
+	}
+
+	if pip := instrument.GetPipValue(); pip > 0 {
+		exec.SlippageUnits = math.Abs(fillPrice-exec.FillPrice) / pip
+	}
+	exec.FillPrice = fillPrice
+	exec.AverageFillPrice = fillPrice
+	exec.AvailableDepth = book.GetTotalQty(exec.Action)
+	return walk
+}
+
+// ==================== SPREAD MARKUP ====================
+
+// applySpreadMarkup moves a fill price outward by half the configured
+// markup, so the effective spread widens by the full markup
+// A limit fill is never marked up through its limit price
+func (oe *OrderExecutor) applySpreadMarkup(
+	order *types.Order,
+	exec *types.ExecutionReport,
+	tick *types.Tick,
+	instrument types.Instrument,
+) {
+
+	halfMarkup := oe.GetSpreadMarkup(tick, instrument) / 2
+	if halfMarkup <= 0 {
+		return
+	}
+
+	fillPrice := exec.FillPrice
+	if exec.IsSell() {
+		fillPrice -= halfMarkup
+		if order.IsLimit() && fillPrice < order.LimitPrice {
+			fillPrice = order.LimitPrice
+		}
+	} else {
+		fillPrice += halfMarkup
+		if order.IsLimit() && fillPrice > order.LimitPrice {
+			fillPrice = order.LimitPrice
+		}
+	}
+
+	markup := math.Abs(fillPrice - exec.FillPrice)
+	if markup <= 0 {
+		return
+	}
+	exec.FillPrice = fillPrice
+	exec.AverageFillPrice = fillPrice
+	exec.SpreadMarkup = markup
+}
+
+// GetSpreadMarkup returns the full spread markup in price units for a tick
+func (oe *OrderExecutor) GetSpreadMarkup(tick *types.Tick, instrument types.Instrument) float64 {
+	switch oe.config.SpreadMarkupType {
+	case types.SpreadMarkupPips:
+		return oe.config.SpreadMarkupValue * instrument.GetPipValue()
+	case types.SpreadMarkupPercent:
+		return tick.GetMidPrice() * oe.config.SpreadMarkupValue / 100
+	default:
+		return 0
+	}
+}
+
+// GetExecutionGuard returns the spread/staleness guard
+func (oe *OrderExecutor) GetExecutionGuard() *ExecutionGuard {
+	return oe.guard
+}
+
+// GetSlippageCalculator returns the slippage calculator (nil when slippage
+// is disabled)
+func (oe *OrderExecutor) GetSlippageCalculator() *slippage.SlippageCalculator {
+	return oe.slippageCalc
+}
+
+// ObserveTick feeds every market tick to the slippage models, so their
+// market averages are not limited to the ticks that carried an order
+func (oe *OrderExecutor) ObserveTick(tick *types.Tick) {
+	if oe.slippageCalc != nil {
+		oe.slippageCalc.ObserveTick(tick)
+	}
+}
+
+// ==================== TRACING ====================
+
+// SetTrace gives the executor the tracer and the context of the order's
+// span; validation and slippage spans are started as its children
+// The simulator calls this before each order
+func (oe *OrderExecutor) SetTrace(ctx context.Context, tracer types.Tracer) {
+	oe.traceCtx = ctx
+	oe.tracer = tracer
+}
+
+// ==================== MARGIN ====================
+
+// SetAccount gives the executor the account state the margin check uses
+// The simulator calls this before each order
+func (oe *OrderExecutor) SetAccount(balance *types.Balance, position *types.Position) {
+	oe.balance = balance
+	oe.position = position
+}
+
+// checkMargin returns an INSUFFICIENT_BALANCE error if the exposure an
+// order adds needs more margin than is available
+// Orders that only reduce the position need no margin
+func (oe *OrderExecutor) checkMargin(
+	order *types.Order,
+	tick *types.Tick,
+	instrument types.Instrument,
+) *types.HolodeckError {
+
+	if !oe.config.MarginCheck || oe.balance == nil {
+		return nil
+	}
+
+	current := 0.0
+	if oe.position != nil {
+		current = oe.position.Size
+	}
+	after := current + float64(order.GetDirection())*order.Size
+	added := math.Abs(after) - math.Abs(current)
+	if added <= 0 {
+		return nil
+	}
+
+	price := tick.GetBuyPrice()
+	if order.IsSell() {
+		price = tick.GetSellPrice()
+	}
+	if order.IsLimit() {
+		price = order.LimitPrice
+	}
+
+	// Tiered margin is not linear in size, so charge the difference
+	// between the position's margin after and before the order
+	tiers := types.GetLeverageTiers(oe.config.LeverageTiers, instrument)
+	required := types.CalculateTieredMargin(after, price, instrument, tiers, oe.balance.Leverage) -
+		types.CalculateTieredMargin(current, price, instrument, tiers, oe.balance.Leverage)
+	required = oe.balance.ConvertToAccount(order.Symbol, required)
+	if required > oe.balance.AvailableMargin {
+		return types.NewInsufficientBalanceError(required, oe.balance.AvailableMargin)
+	}
+	return nil
+}
+
+// ==================== COMMISSION ====================
+
+// applyCommission sets the commission of a fill
+// The configured commission comes from the instrument's schedule (limit
+// orders pay the maker rate) or else the instrument's own commission; an
+// order override replaces it pro rata to the filled size, and
+// commission-free mode charges nothing
+func (oe *OrderExecutor) applyCommission(
+	order *types.Order,
+	exec *types.ExecutionReport,
+	instrument types.Instrument,
+) {
+	var configured float64
+	if schedule := oe.GetCommissionSchedule(instrument); schedule != nil {
+		configured = schedule.Calculate(
+			exec.FillPrice,
+			exec.FilledSize,
+			instrument,
+			order.IsLimit(),
+			exec.Timestamp,
+		)
+	} else {
+		configured = instrument.CalculateCommission(exec.FillPrice, exec.FilledSize, order.Action)
+	}
+
+	charged := configured
+	if order.HasCommissionOverride() && order.Size > 0 {
+		charged = *order.CommissionOverride * exec.FilledSize / order.Size
+	}
+	if !oe.config.CommissionEnabled {
+		charged = 0
+	}
+
+	exec.Commission = charged
+	oe.commissionConfigured += configured
+	oe.commissionCharged += charged
+}
+
+// GetCommissionSchedule returns the schedule for an instrument, looked up
+// by symbol first and then by instrument type
+// Returns nil if no schedule is configured
+func (oe *OrderExecutor) GetCommissionSchedule(instrument types.Instrument) *commission.CommissionSchedule {
+	if instrument == nil || len(oe.config.CommissionSchedules) == 0 {
+		return nil
+	}
+	if schedule, ok := oe.config.CommissionSchedules[instrument.GetSymbol()]; ok {
+		return schedule
+	}
+	return oe.config.CommissionSchedules[instrument.GetType()]
+}
+
+// CalculateCommission estimates the commission for an order from the
+// instrument's own commission (0 in commission-free mode)
+// Tiered schedules are applied to actual fills only, since they track the
+// month's volume
+func (oe *OrderExecutor) CalculateCommission(price, size float64, instrument types.Instrument, side string) float64 {
+	if !oe.config.CommissionEnabled || instrument == nil {
+		return 0
+	}
+	return instrument.CalculateCommission(price, size, side)
+}
+
+// ==================== WORKING ORDERS ====================
+
+// ProcessWorkingOrders fills working remainders against a new tick
+// Returns the incremental fills for this tick and the consolidated
+// reports of orders that are now completely filled
+func (oe *OrderExecutor) ProcessWorkingOrders(
+	tick *types.Tick,
+	instrument types.Instrument,
+) ([]*types.ExecutionReport, []*types.ExecutionReport) {
+
+	oe.workingOrders.SetFillHook(func(order *types.Order, fill *types.ExecutionReport) {
+		if oe.config.SpreadMarkupType != "" {
+			oe.applySpreadMarkup(order, fill, tick, instrument)
+		}
+		oe.applyCommission(order, fill, instrument)
+	})
+	fills, completed := oe.workingOrders.ProcessTick(tick)
+	for _, fill := range fills {
+		oe.recordExecution(fill)
+	}
+
+	return fills, completed
+}
+
+// CancelWorkingOrders stops working all outstanding remainders
+// Returns consolidated reports for whatever was filled before cancellation
+func (oe *OrderExecutor) CancelWorkingOrders() []*types.ExecutionReport {
+	return oe.workingOrders.CancelAll()
+}
+
+// GetWorkingOrders returns the orders currently being worked
+func (oe *OrderExecutor) GetWorkingOrders() []*WorkingOrder {
+	return oe.workingOrders.GetWorkingOrders()
+}
+
 // ==================== VALIDATION ====================
 
 // ValidateOrder validates an order before execution
@@ -174,6 +752,29 @@ func (oe *OrderExecutor) ValidateOrder(
 	)
 }
 
+// CheckOrder runs an order through the checks Execute makes before
+// filling it (order fields, limit side, margin, exposure, spread and tick
+// age) without executing, recording or counting it
+// Returns the rejection Execute would report, or nil if the order passes
+func (oe *OrderExecutor) CheckOrder(
+	order *types.Order,
+	tick *types.Tick,
+	instrument types.Instrument,
+) *types.ExecutionReport {
+	rejected, _ := oe.validate(order, tick, instrument)
+	return rejected
+}
+
+// Validate validates an order before execution
+// Satisfies simulator.OrderExecutor; same as ValidateOrder
+func (oe *OrderExecutor) Validate(
+	order *types.Order,
+	instrument types.Instrument,
+	availableBalance float64,
+) error {
+	return oe.ValidateOrder(order, instrument, availableBalance)
+}
+
 // ==================== STATISTICS ====================
 
 // GetOrdersReceived returns total orders received
@@ -212,9 +813,23 @@ func (oe *OrderExecutor) GetStatistics() map[string]interface{} {
 		"orders_rejected":        oe.ordersRejected,
 		"execution_rate":         oe.GetExecutionRate(),
 		"execution_history_size": int64(len(oe.executionHistory)),
+		"working_orders":         oe.workingOrders.GetStatistics(),
+		"execution_guard":        oe.guard.GetStatistics(),
+		"commission_charged":     oe.commissionCharged,
+		"commission_configured":  oe.commissionConfigured,
+		"commission_schedules":   oe.getScheduleStatistics(),
 	}
 }
 
+// getScheduleStatistics returns statistics of each commission schedule
+func (oe *OrderExecutor) getScheduleStatistics() map[string]interface{} {
+	stats := make(map[string]interface{}, len(oe.config.CommissionSchedules))
+	for key, schedule := range oe.config.CommissionSchedules {
+		stats[key] = schedule.GetStatistics()
+	}
+	return stats
+}
+
 // recordExecution records execution details
 func (oe *OrderExecutor) recordExecution(exec *types.ExecutionReport) {
 	oe.executionHistory = append(oe.executionHistory, exec)
@@ -251,9 +866,15 @@ func (oe *OrderExecutor) DebugString() string {
 			"\n"+
 			"  Configuration:\n"+
 			"    Commission:         %v\n"+
+			"    Margin Check:       %v\n"+
 			"    Slippage:           %v\n"+
 			"    Latency:            %v\n"+
 			"    Partial Fills:      %v\n"+
+			"    Partial Fill Model: %s (participation %.2f)\n"+
+			"    Continue Partials:  %v\n"+
+			"    Limit Queue:        %v\n"+
+			"    Spread Markup:      %s (%.4f)\n"+
+			"    Book Levels:        %d (decay %.2f)\n"+
 			"    Min Order Size:     %.6f\n"+
 			"    Max Order Size:     %.6f\n"+
 			"    Max Position Size:  %.6f",
@@ -263,9 +884,15 @@ func (oe *OrderExecutor) DebugString() string {
 		oe.GetExecutionRate(),
 		len(oe.executionHistory),
 		oe.config.CommissionEnabled,
+		oe.config.MarginCheck,
 		oe.config.SlippageEnabled,
 		oe.config.LatencyEnabled,
 		oe.config.PartialFillsEnabled,
+		oe.config.PartialFillModel, oe.config.ParticipationRate,
+		oe.config.ContinuePartialFills,
+		oe.config.LimitQueueModeling,
+		oe.config.SpreadMarkupType, oe.config.SpreadMarkupValue,
+		oe.config.SyntheticBook.Levels, oe.config.SyntheticBook.Decay,
 		oe.config.MinimumOrderSize,
 		oe.config.MaxOrderSize,
 		oe.config.MaxPositionSize,
@@ -278,4 +905,14 @@ func (oe *OrderExecutor) Reset() {
 	oe.ordersExecuted = 0
 	oe.ordersRejected = 0
 	oe.executionHistory = make([]*types.ExecutionReport, 0)
+	oe.workingOrders.Reset()
+	if oe.slippageCalc != nil {
+		oe.slippageCalc.Reset()
+	}
+	oe.guard.Reset()
+	oe.commissionCharged = 0
+	oe.commissionConfigured = 0
+	for _, schedule := range oe.config.CommissionSchedules {
+		schedule.Reset()
+	}
 }
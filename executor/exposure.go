@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"math"
+
+	"holodeck/types"
+)
+
+// ==================== EXPOSURE LIMITS ====================
+
+// SetPositions gives the executor every open position for the exposure
+// limits; the order's own symbol is the position passed to SetAccount
+// The simulator calls this before each order
+func (oe *OrderExecutor) SetPositions(positions map[string]*types.Position) {
+	oe.positions = positions
+}
+
+// GetExposure returns the gross (sum of absolute) and net (signed sum)
+// notional of the open positions, valued at their last marked price
+func (oe *OrderExecutor) GetExposure(instrument types.Instrument) (gross, net float64) {
+	for _, pos := range oe.positions {
+		notional := positionNotional(pos.Size, markedPrice(pos), instrument)
+		gross += math.Abs(notional)
+		net += notional
+	}
+	return gross, net
+}
+
+// checkExposure returns an EXPOSURE_LIMIT_EXCEEDED error if an order would
+// take gross or net exposure past its limit
+// The order's symbol is valued at the order's price and the other symbols
+// at their last marked price; orders that reduce an exposure already over
+// its limit are allowed
+func (oe *OrderExecutor) checkExposure(
+	order *types.Order,
+	tick *types.Tick,
+	instrument types.Instrument,
+) *types.HolodeckError {
+
+	if oe.config.MaxGrossExposure <= 0 && oe.config.MaxNetExposure <= 0 {
+		return nil
+	}
+
+	price := tick.GetBuyPrice()
+	if order.IsSell() {
+		price = tick.GetSellPrice()
+	}
+	if order.IsLimit() {
+		price = order.LimitPrice
+	}
+
+	current := 0.0
+	if oe.position != nil {
+		current = oe.position.Size
+	}
+	after := current + float64(order.GetDirection())*order.Size
+
+	// Exposure of the other symbols, unchanged by the order
+	otherGross, otherNet := 0.0, 0.0
+	for _, pos := range oe.positions {
+		if pos == oe.position {
+			continue
+		}
+		notional := positionNotional(pos.Size, markedPrice(pos), instrument)
+		otherGross += math.Abs(notional)
+		otherNet += notional
+	}
+
+	before := positionNotional(current, price, instrument)
+	notional := positionNotional(after, price, instrument)
+
+	grossBefore, grossAfter := otherGross+math.Abs(before), otherGross+math.Abs(notional)
+	if limit := oe.config.MaxGrossExposure; limit > 0 && grossAfter > limit && grossAfter > grossBefore {
+		return types.NewExposureLimitError("gross", grossAfter, limit)
+	}
+
+	netBefore, netAfter := math.Abs(otherNet+before), math.Abs(otherNet+notional)
+	if limit := oe.config.MaxNetExposure; limit > 0 && netAfter > limit && netAfter > netBefore {
+		return types.NewExposureLimitError("net", netAfter, limit)
+	}
+
+	return nil
+}
+
+// positionNotional returns the signed notional of a size at price
+func positionNotional(size, price float64, instrument types.Instrument) float64 {
+	contractSize := 1.0
+	if instrument != nil && instrument.GetContractSize() > 0 {
+		contractSize = float64(instrument.GetContractSize())
+	}
+	return size * price * contractSize
+}
+
+// markedPrice returns the price a position was last marked at, falling
+// back to its entry price before the first mark
+func markedPrice(pos *types.Position) float64 {
+	if pos.CurrentPrice > 0 {
+		return pos.CurrentPrice
+	}
+	return pos.EntryPrice
+}
@@ -0,0 +1,111 @@
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== EXECUTION GUARD ====================
+
+// ExecutionGuard rejects orders when market conditions are unsafe to trade:
+// the spread is wider than allowed or the tick is too old for the order
+type ExecutionGuard struct {
+	// MaxSpreadPips is the widest spread accepted (0 = no limit)
+	MaxSpreadPips float64
+
+	// MaxTickAge is the oldest tick accepted, measured in simulated time
+	// from the tick's timestamp to the order's (0 = no limit)
+	MaxTickAge time.Duration
+
+	// Statistics
+	gatedSpread int64
+	gatedStale  int64
+}
+
+// NewExecutionGuard creates a guard; zero limits disable each check
+func NewExecutionGuard(maxSpreadPips float64, maxTickAge time.Duration) *ExecutionGuard {
+	return &ExecutionGuard{
+		MaxSpreadPips: maxSpreadPips,
+		MaxTickAge:    maxTickAge,
+	}
+}
+
+// IsEnabled checks if any guard limit is configured
+func (eg *ExecutionGuard) IsEnabled() bool {
+	return eg.MaxSpreadPips > 0 || eg.MaxTickAge > 0
+}
+
+// Check returns a rejected execution if the order must not trade on this tick
+// Returns nil if the order may proceed
+func (eg *ExecutionGuard) Check(
+	order *types.Order,
+	tick *types.Tick,
+	instrument types.Instrument,
+) *types.ExecutionReport {
+
+	if eg.MaxSpreadPips > 0 && instrument.GetPipValue() > 0 {
+		spreadPips := tick.GetSpread() / instrument.GetPipValue()
+		if spreadPips > eg.MaxSpreadPips {
+			eg.gatedSpread++
+			return types.NewRejectedExecution(
+				order.OrderID,
+				tick.Timestamp,
+				order.Action,
+				order.Size,
+				types.ErrorCodeSpreadTooWide,
+				fmt.Sprintf("spread %.2f pips exceeds maximum %.2f", spreadPips, eg.MaxSpreadPips),
+			)
+		}
+	}
+
+	if eg.MaxTickAge > 0 && !order.Timestamp.IsZero() {
+		age := order.Timestamp.Sub(tick.Timestamp)
+		if age > eg.MaxTickAge {
+			eg.gatedStale++
+			return types.NewRejectedExecution(
+				order.OrderID,
+				tick.Timestamp,
+				order.Action,
+				order.Size,
+				types.ErrorCodeStaleTick,
+				fmt.Sprintf("tick is %v old, maximum is %v", age, eg.MaxTickAge),
+			)
+		}
+	}
+
+	return nil
+}
+
+// GetGatedCount returns the total number of gated orders
+func (eg *ExecutionGuard) GetGatedCount() int64 {
+	return eg.gatedSpread + eg.gatedStale
+}
+
+// GetStatistics returns guard statistics
+func (eg *ExecutionGuard) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"max_spread_pips": eg.MaxSpreadPips,
+		"max_tick_age":    eg.MaxTickAge,
+		"gated_spread":    eg.gatedSpread,
+		"gated_stale":     eg.gatedStale,
+		"gated_total":     eg.GetGatedCount(),
+	}
+}
+
+// Reset clears guard statistics
+func (eg *ExecutionGuard) Reset() {
+	eg.gatedSpread = 0
+	eg.gatedStale = 0
+}
+
+// String returns a human-readable representation
+func (eg *ExecutionGuard) String() string {
+	return fmt.Sprintf(
+		"ExecutionGuard[MaxSpread:%.2f pips, MaxAge:%v, Gated:%d]",
+		eg.MaxSpreadPips,
+		eg.MaxTickAge,
+		eg.GetGatedCount(),
+	)
+}
@@ -236,11 +236,17 @@ func (lot *LimitOrderTracker) AddPending(order *types.Order) {
 }
 
 // CheckFills checks all pending orders for fills
+// Orders whose ExpiresAt has passed (in tick time) move to expired first
 func (lot *LimitOrderTracker) CheckFills(tick *types.Tick) []string {
 	executor := NewLimitOrderExecutor()
 	filled := make([]string, 0)
 
 	for orderID, order := range lot.pendingOrders {
+		if order.IsExpired(tick.Timestamp) {
+			lot.expiredOrders[orderID] = order
+			delete(lot.pendingOrders, orderID)
+			continue
+		}
 		if exec, _ := executor.Execute(order, tick, nil); exec != nil {
 			if exec.IsFilled() {
 				filled = append(filled, orderID)
@@ -258,6 +264,11 @@ func (lot *LimitOrderTracker) GetPendingCount() int {
 	return len(lot.pendingOrders)
 }
 
+// GetExpiredCount returns number of expired orders
+func (lot *LimitOrderTracker) GetExpiredCount() int {
+	return len(lot.expiredOrders)
+}
+
 // GetFilledCount returns number of filled orders
 func (lot *LimitOrderTracker) GetFilledCount() int {
 	return len(lot.filledOrders)
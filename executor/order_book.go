@@ -0,0 +1,178 @@
+package executor
+
+import (
+	"fmt"
+	"math"
+
+	"holodeck/types"
+)
+
+// ==================== SYNTHETIC ORDER BOOK ====================
+
+// BookConfig configures synthetic depth generation from L1 ticks
+type BookConfig struct {
+	// Levels is the number of price levels per side (including the top)
+	Levels int
+
+	// Decay scales quantity from one level to the next (level n = top × decay^n)
+	// Values below 1 thin the book out, values above 1 deepen it
+	Decay float64
+
+	// LevelSpacingPips is the price gap between levels in pips
+	// 0 uses the tick's spread (minimum one pip)
+	LevelSpacingPips float64
+}
+
+// DefaultBookConfig returns a five-level book with 70% decay
+func DefaultBookConfig() BookConfig {
+	return BookConfig{
+		Levels: 5,
+		Decay:  0.7,
+	}
+}
+
+// BookLevel is a single price level
+type BookLevel struct {
+	Price float64
+	Qty   int64
+}
+
+// SyntheticOrderBook expands a tick's top of book into N depth levels
+type SyntheticOrderBook struct {
+	Bids []BookLevel // Best (highest) first
+	Asks []BookLevel // Best (lowest) first
+}
+
+// NewSyntheticOrderBook builds a book from a tick's bid/ask and quantities
+func NewSyntheticOrderBook(tick *types.Tick, instrument types.Instrument, config BookConfig) *SyntheticOrderBook {
+	if config.Levels <= 0 {
+		config.Levels = 1
+	}
+	if config.Decay <= 0 {
+		config.Decay = DefaultBookConfig().Decay
+	}
+
+	pip := instrument.GetPipValue()
+	spacing := config.LevelSpacingPips * pip
+	if spacing <= 0 {
+		spacing = math.Max(tick.GetSpread(), pip)
+	}
+
+	book := &SyntheticOrderBook{
+		Bids: make([]BookLevel, 0, config.Levels),
+		Asks: make([]BookLevel, 0, config.Levels),
+	}
+
+	for i := 0; i < config.Levels; i++ {
+		scale := math.Pow(config.Decay, float64(i))
+		offset := spacing * float64(i)
+
+		book.Bids = append(book.Bids, BookLevel{
+			Price: tick.Bid - offset,
+			Qty:   int64(float64(tick.BidQty) * scale),
+		})
+		book.Asks = append(book.Asks, BookLevel{
+			Price: tick.Ask + offset,
+			Qty:   int64(float64(tick.AskQty) * scale),
+		})
+	}
+
+	return book
+}
+
+// ==================== BOOK WALK ====================
+
+// BookWalk is the result of sweeping the book with an order
+type BookWalk struct {
+	FilledSize   float64
+	AveragePrice float64
+	WorstPrice   float64
+	LevelsUsed   int
+	Exhausted    bool // The order was larger than the whole side
+}
+
+// Walk sweeps the side an order would take (asks for BUY, bids for SELL)
+// and returns the volume-weighted fill
+func (ob *SyntheticOrderBook) Walk(action string, size float64) *BookWalk {
+	levels := ob.Asks
+	if action == types.OrderActionSell {
+		levels = ob.Bids
+	}
+
+	walk := &BookWalk{}
+	remaining := size
+	notional := 0.0
+
+	for _, level := range levels {
+		if remaining <= 0 {
+			break
+		}
+		if level.Qty <= 0 {
+			continue
+		}
+
+		take := math.Min(remaining, float64(level.Qty))
+		notional += take * level.Price
+		walk.FilledSize += take
+		walk.WorstPrice = level.Price
+		walk.LevelsUsed++
+		remaining -= take
+	}
+
+	if walk.FilledSize > 0 {
+		walk.AveragePrice = notional / walk.FilledSize
+	}
+	walk.Exhausted = remaining > 0
+
+	return walk
+}
+
+// GetTotalQty returns the total quantity on one side of the book
+func (ob *SyntheticOrderBook) GetTotalQty(action string) int64 {
+	levels := ob.Asks
+	if action == types.OrderActionSell {
+		levels = ob.Bids
+	}
+
+	total := int64(0)
+	for _, level := range levels {
+		total += level.Qty
+	}
+	return total
+}
+
+// ==================== DEBUG ====================
+
+// String returns a human-readable representation
+func (ob *SyntheticOrderBook) String() string {
+	return fmt.Sprintf(
+		"SyntheticOrderBook[Levels:%d, BidQty:%d, AskQty:%d]",
+		len(ob.Asks),
+		ob.GetTotalQty(types.OrderActionSell),
+		ob.GetTotalQty(types.OrderActionBuy),
+	)
+}
+
+// DebugString returns the full ladder
+func (ob *SyntheticOrderBook) DebugString() string {
+	s := "Synthetic Order Book:\n"
+	for i := len(ob.Asks) - 1; i >= 0; i-- {
+		s += fmt.Sprintf("  ASK %.8f x %d\n", ob.Asks[i].Price, ob.Asks[i].Qty)
+	}
+	for _, level := range ob.Bids {
+		s += fmt.Sprintf("  BID %.8f x %d\n", level.Price, level.Qty)
+	}
+	return s
+}
+
+// String returns a human-readable representation
+func (bw *BookWalk) String() string {
+	return fmt.Sprintf(
+		"BookWalk[Filled:%.2f, Avg:%.8f, Worst:%.8f, Levels:%d, Exhausted:%v]",
+		bw.FilledSize,
+		bw.AveragePrice,
+		bw.WorstPrice,
+		bw.LevelsUsed,
+		bw.Exhausted,
+	)
+}
@@ -125,6 +125,22 @@ func (pfc PartialFillCalculator) CalculateVolumeLimitedFill(
 	return requestedSize * (maxFillPercent / 100)
 }
 
+// ==================== PARTICIPATION FILLS ====================
+
+// CalculateParticipationFill caps a fill at a share of the tick's volume
+// Formula: min(requested_size, volume × participation_rate)
+func (pfc PartialFillCalculator) CalculateParticipationFill(
+	requestedSize float64,
+	volume int64,
+	participationRate float64,
+) float64 {
+
+	if volume <= 0 || participationRate <= 0 {
+		return 0
+	}
+	return math.Min(requestedSize, float64(volume)*participationRate)
+}
+
 // ==================== ICEBERG-STYLE FILLS ====================
 
 // IcebergFillCalculator handles iceberg order fills
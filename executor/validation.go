@@ -126,6 +126,7 @@ func (ov OrderValidator) ValidateOrderSize(
 }
 
 // ValidateLimitPrice validates a limit price
+// currentPrice is the ask for BUY and the bid for SELL (0 = no market context)
 func (ov OrderValidator) ValidateLimitPrice(
 	limitPrice float64,
 	currentPrice float64,
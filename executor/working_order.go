@@ -0,0 +1,455 @@
+package executor
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== WORKING ORDER ====================
+
+// WorkingOrder tracks an order whose unfilled remainder keeps working
+// against depth on subsequent ticks
+type WorkingOrder struct {
+	Order         *types.Order
+	RequestedSize float64
+	FilledSize    float64
+	Notional      float64 // Sum of fill price × fill size
+	Commission    float64
+	SlippageUnits float64
+	Fills         []*types.ExecutionReport
+	TicksWorked   int64
+
+	// reported is how many of Fills the executor already returned (the
+	// first partial fill of an order that started working)
+	reported int
+
+	// Resting limit orders wait in the queue at their price level
+	// QueueAhead is the volume that must trade before this order fills
+	// (negative until the order's price first becomes the touch)
+	Resting    bool
+	QueueAhead float64
+}
+
+// GetRemainingSize returns the size still waiting to be filled
+func (wo *WorkingOrder) GetRemainingSize() float64 {
+	return math.Max(wo.RequestedSize-wo.FilledSize, 0)
+}
+
+// GetAverageFillPrice returns the volume-weighted average fill price
+func (wo *WorkingOrder) GetAverageFillPrice() float64 {
+	if wo.FilledSize == 0 {
+		return 0
+	}
+	return wo.Notional / wo.FilledSize
+}
+
+// IsComplete checks if the order has been filled in full
+func (wo *WorkingOrder) IsComplete() bool {
+	return wo.GetRemainingSize() <= 1e-9
+}
+
+// recordFill adds a fill to the working order
+func (wo *WorkingOrder) recordFill(fill *types.ExecutionReport) {
+	wo.FilledSize += fill.FilledSize
+	wo.Notional += fill.FillPrice * fill.FilledSize
+	wo.Commission += fill.Commission
+	wo.SlippageUnits += fill.SlippageUnits
+	wo.Fills = append(wo.Fills, fill)
+}
+
+// ConsolidatedReport builds the final execution report of the remainder:
+// the fills made while the order was working, not the first partial fill
+// the executor already returned, so fill totals never count a size twice
+// Status is FILLED when complete, PARTIAL when the remainder was cancelled
+// part-filled, CANCELLED when nothing filled while the order was working
+func (wo *WorkingOrder) ConsolidatedReport() *types.ExecutionReport {
+	requested := wo.RequestedSize
+	for _, fill := range wo.Fills[:wo.reported] {
+		requested -= fill.FilledSize
+	}
+
+	fills := wo.Fills[wo.reported:]
+	if len(fills) == 0 {
+		// Cancelled before any more volume reached the order
+		return &types.ExecutionReport{
+			OrderID:       wo.Order.OrderID,
+			Symbol:        wo.Order.Symbol,
+			Timestamp:     wo.Order.Timestamp,
+			Action:        wo.Order.Action,
+			RequestedSize: requested,
+			FillPrice:     wo.Order.LimitPrice,
+			Status:        types.OrderStatusCancelled,
+		}
+	}
+
+	var filled, notional, commission, slippageUnits float64
+	for _, fill := range fills {
+		filled += fill.FilledSize
+		notional += fill.FillPrice * fill.FilledSize
+		commission += fill.Commission
+		slippageUnits += fill.SlippageUnits
+	}
+	last := fills[len(fills)-1]
+	avgPrice := notional / filled
+
+	report := &types.ExecutionReport{
+		OrderID:          wo.Order.OrderID,
+		Symbol:           wo.Order.Symbol,
+		Timestamp:        last.Timestamp,
+		Action:           wo.Order.Action,
+		RequestedSize:    requested,
+		FilledSize:       filled,
+		FillPrice:        avgPrice,
+		SlippageUnits:    slippageUnits,
+		Commission:       commission,
+		Status:           types.OrderStatusFilled,
+		AvailableDepth:   last.AvailableDepth,
+		AverageFillPrice: avgPrice,
+	}
+
+	if !wo.IsComplete() {
+		report.Status = types.OrderStatusPartial
+	}
+
+	return report
+}
+
+// ExpiredReport builds the final report of an order that expired
+// Status is EXPIRED when nothing filled while working, PARTIAL otherwise
+func (wo *WorkingOrder) ExpiredReport(now time.Time) *types.ExecutionReport {
+	report := wo.ConsolidatedReport()
+	if report.Status == types.OrderStatusCancelled {
+		report.Status = types.OrderStatusExpired
+		report.Timestamp = now
+	}
+	return report
+}
+
+// String returns a human-readable representation
+func (wo *WorkingOrder) String() string {
+	return fmt.Sprintf(
+		"WorkingOrder[%s %s %.4f/%.4f @ %.5f, Fills:%d, Ticks:%d]",
+		wo.Order.OrderID,
+		wo.Order.Action,
+		wo.FilledSize,
+		wo.RequestedSize,
+		wo.GetAverageFillPrice(),
+		len(wo.Fills),
+		wo.TicksWorked,
+	)
+}
+
+// ==================== WORKING ORDER BOOK ====================
+
+// WorkingOrderBook holds orders with outstanding remainders
+type WorkingOrderBook struct {
+	orders []*WorkingOrder
+
+	// participationRate caps each remainder fill at a share of the tick's
+	// volume (0 = fills limited by depth only)
+	participationRate float64
+
+	// fillHook adjusts each new fill (e.g. commission) before it is recorded
+	fillHook func(order *types.Order, fill *types.ExecutionReport)
+
+	// Statistics
+	ordersWorked    int64
+	ordersCompleted int64
+	ordersCancelled int64
+	ordersExpired   int64
+}
+
+// NewWorkingOrderBook creates an empty working order book
+func NewWorkingOrderBook() *WorkingOrderBook {
+	return &WorkingOrderBook{
+		orders: make([]*WorkingOrder, 0),
+	}
+}
+
+// SetParticipationRate caps remainder fills at a share of tick volume
+func (wob *WorkingOrderBook) SetParticipationRate(rate float64) {
+	wob.participationRate = rate
+}
+
+// SetFillHook sets a function applied to every fill before it is recorded
+func (wob *WorkingOrderBook) SetFillHook(hook func(order *types.Order, fill *types.ExecutionReport)) {
+	wob.fillHook = hook
+}
+
+// recordFill runs the fill hook and adds the fill to a working order
+func (wob *WorkingOrderBook) recordFill(wo *WorkingOrder, fill *types.ExecutionReport) {
+	if wob.fillHook != nil {
+		wob.fillHook(wo.Order, fill)
+	}
+	wo.recordFill(fill)
+}
+
+// Add starts working the remainder of a partially filled order
+func (wob *WorkingOrderBook) Add(order *types.Order, firstFill *types.ExecutionReport) *WorkingOrder {
+	wo := &WorkingOrder{
+		Order:         order,
+		RequestedSize: order.Size,
+		Fills:         make([]*types.ExecutionReport, 0),
+	}
+	wo.recordFill(firstFill)
+	wo.reported = 1
+
+	wob.orders = append(wob.orders, wo)
+	wob.ordersWorked++
+	return wo
+}
+
+// AddResting queues an unfilled limit order at its price level
+// An order placed at the touch queues behind the displayed quantity
+func (wob *WorkingOrderBook) AddResting(order *types.Order, tick *types.Tick) *WorkingOrder {
+	wo := &WorkingOrder{
+		Order:         order,
+		RequestedSize: order.Size,
+		Fills:         make([]*types.ExecutionReport, 0),
+		Resting:       true,
+		QueueAhead:    -1,
+	}
+	if qty, atTouch := touchQty(order, tick); atTouch {
+		wo.QueueAhead = float64(qty)
+	} else if insideSpread(order, tick) {
+		wo.QueueAhead = 0
+	}
+
+	wob.orders = append(wob.orders, wo)
+	wob.ordersWorked++
+	return wo
+}
+
+// ProcessTick fills the working remainders in a new tick's symbol against
+// its depth (orders with no symbol trade on every tick)
+// Returns the incremental fills and the consolidated reports of orders
+// that completed or expired on this tick
+func (wob *WorkingOrderBook) ProcessTick(tick *types.Tick) ([]*types.ExecutionReport, []*types.ExecutionReport) {
+	fills := make([]*types.ExecutionReport, 0)
+	completed := make([]*types.ExecutionReport, 0)
+
+	if tick == nil || len(wob.orders) == 0 {
+		return fills, completed
+	}
+
+	pfc := NewPartialFillCalculator()
+	remaining := wob.orders[:0]
+
+	for _, wo := range wob.orders {
+		// Expiry uses tick time, so it is reproducible at any speed
+		if wo.Order.IsExpired(tick.Timestamp) {
+			wob.ordersExpired++
+			completed = append(completed, wo.ExpiredReport(tick.Timestamp))
+			continue
+		}
+
+		// Other symbols' orders wait for their own ticks
+		if wo.Order.Symbol != "" && wo.Order.Symbol != tick.Symbol {
+			remaining = append(remaining, wo)
+			continue
+		}
+
+		wo.TicksWorked++
+
+		if wo.Resting {
+			if fill := wob.processQueue(wo, tick); fill != nil {
+				fills = append(fills, fill)
+			}
+			if wo.IsComplete() {
+				wob.ordersCompleted++
+				completed = append(completed, wo.ConsolidatedReport())
+				continue
+			}
+			remaining = append(remaining, wo)
+			continue
+		}
+
+		depth := tick.AskQty
+		fillPrice := tick.GetBuyPrice()
+		if wo.Order.IsSell() {
+			depth = tick.BidQty
+			fillPrice = tick.GetSellPrice()
+		}
+
+		// Limit remainders only trade while the limit is still marketable
+		if wo.Order.IsLimit() &&
+			((wo.Order.IsBuy() && fillPrice > wo.Order.LimitPrice) ||
+				(wo.Order.IsSell() && fillPrice < wo.Order.LimitPrice)) {
+			remaining = append(remaining, wo)
+			continue
+		}
+
+		size := pfc.CalculateFilledSize(wo.GetRemainingSize(), depth, tick.Volume)
+		if wob.participationRate > 0 {
+			size = pfc.CalculateParticipationFill(size, tick.Volume, wob.participationRate)
+		}
+		if size > 0 {
+			fill := &types.ExecutionReport{
+				OrderID:          wo.Order.OrderID,
+				Symbol:           wo.Order.Symbol,
+				Timestamp:        tick.Timestamp,
+				Action:           wo.Order.Action,
+				RequestedSize:    wo.GetRemainingSize(),
+				FilledSize:       size,
+				FillPrice:        fillPrice,
+				Status:           types.OrderStatusPartial,
+				AvailableDepth:   depth,
+				AverageFillPrice: fillPrice,
+			}
+			wob.recordFill(wo, fill)
+
+			if wo.IsComplete() {
+				fill.Status = types.OrderStatusFilled
+			}
+			fills = append(fills, fill)
+		}
+
+		if wo.IsComplete() {
+			wob.ordersCompleted++
+			completed = append(completed, wo.ConsolidatedReport())
+			continue
+		}
+
+		remaining = append(remaining, wo)
+	}
+
+	wob.orders = remaining
+	return fills, completed
+}
+
+// processQueue advances a resting limit order through its queue
+// The order fills in full once price trades through its level; while it
+// sits at the touch, only traded volume beyond the queue ahead fills it
+func (wob *WorkingOrderBook) processQueue(wo *WorkingOrder, tick *types.Tick) *types.ExecutionReport {
+	limit := wo.Order.LimitPrice
+	size := 0.0
+
+	qty, atTouch := touchQty(wo.Order, tick)
+	switch {
+	case tradedThrough(wo.Order, tick):
+		size = wo.GetRemainingSize()
+
+	case atTouch && wo.QueueAhead < 0:
+		// Price just reached our level; join behind the displayed size
+		wo.QueueAhead = float64(qty)
+
+	case wo.QueueAhead < 0 && insideSpread(wo.Order, tick):
+		// Our order improves the touch, so nobody is ahead of it
+		wo.QueueAhead = 0
+
+	case wo.QueueAhead >= 0:
+		// Cancellations ahead of us shrink the queue
+		if atTouch {
+			wo.QueueAhead = math.Min(wo.QueueAhead, float64(qty))
+		}
+
+		traded := 0.0
+		if tick.LastPrice == limit {
+			traded = float64(tick.Volume)
+		}
+		if traded > wo.QueueAhead {
+			size = math.Min(traded-wo.QueueAhead, wo.GetRemainingSize())
+		}
+		wo.QueueAhead = math.Max(wo.QueueAhead-traded, 0)
+	}
+
+	if size <= 0 {
+		return nil
+	}
+
+	fill := &types.ExecutionReport{
+		OrderID:          wo.Order.OrderID,
+		Symbol:           wo.Order.Symbol,
+		Timestamp:        tick.Timestamp,
+		Action:           wo.Order.Action,
+		RequestedSize:    wo.GetRemainingSize(),
+		FilledSize:       size,
+		FillPrice:        limit,
+		Status:           types.OrderStatusPartial,
+		AvailableDepth:   qty,
+		AverageFillPrice: limit,
+	}
+	wob.recordFill(wo, fill)
+
+	if wo.IsComplete() {
+		fill.Status = types.OrderStatusFilled
+	}
+	return fill
+}
+
+// touchQty returns the displayed quantity at a limit order's level and
+// whether the order's price is the current best bid (BUY) or ask (SELL)
+func touchQty(order *types.Order, tick *types.Tick) (int64, bool) {
+	if order.IsBuy() {
+		return tick.BidQty, tick.Bid == order.LimitPrice
+	}
+	return tick.AskQty, tick.Ask == order.LimitPrice
+}
+
+// insideSpread checks if a limit order's price is better than the touch
+func insideSpread(order *types.Order, tick *types.Tick) bool {
+	if order.IsBuy() {
+		return order.LimitPrice > tick.Bid
+	}
+	return order.LimitPrice < tick.Ask
+}
+
+// tradedThrough checks if the market crossed a limit order's level (the
+// opposite side reached it or trades printed beyond it), which means every
+// order resting there has been filled
+func tradedThrough(order *types.Order, tick *types.Tick) bool {
+	if order.IsBuy() {
+		return tick.Ask <= order.LimitPrice ||
+			(tick.LastPrice > 0 && tick.LastPrice < order.LimitPrice)
+	}
+	return tick.Bid >= order.LimitPrice ||
+		(tick.LastPrice > 0 && tick.LastPrice > order.LimitPrice)
+}
+
+// CancelAll stops working every outstanding remainder
+// Returns consolidated reports (status PARTIAL) for the cancelled orders
+// Orders that filled nothing more while working are reported as CANCELLED
+func (wob *WorkingOrderBook) CancelAll() []*types.ExecutionReport {
+	reports := make([]*types.ExecutionReport, 0, len(wob.orders))
+
+	for _, wo := range wob.orders {
+		wob.ordersCancelled++
+		reports = append(reports, wo.ConsolidatedReport())
+	}
+
+	wob.orders = make([]*WorkingOrder, 0)
+	return reports
+}
+
+// GetWorkingOrders returns the orders currently being worked
+func (wob *WorkingOrderBook) GetWorkingOrders() []*WorkingOrder {
+	return wob.orders
+}
+
+// GetWorkingCount returns the number of orders currently being worked
+func (wob *WorkingOrderBook) GetWorkingCount() int {
+	return len(wob.orders)
+}
+
+// GetStatistics returns working order statistics
+func (wob *WorkingOrderBook) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"working_orders":   len(wob.orders),
+		"orders_worked":    wob.ordersWorked,
+		"orders_completed": wob.ordersCompleted,
+		"orders_cancelled": wob.ordersCancelled,
+		"orders_expired":   wob.ordersExpired,
+	}
+}
+
+// Reset clears all working orders and statistics
+func (wob *WorkingOrderBook) Reset() {
+	wob.orders = make([]*WorkingOrder, 0)
+	wob.ordersWorked = 0
+	wob.ordersCompleted = 0
+	wob.ordersCancelled = 0
+	wob.ordersExpired = 0
+}
@@ -0,0 +1,315 @@
+package financing
+
+import (
+	"fmt"
+	"math"
+	"time"
+	_ "time/tzdata" // Rollover time zones must resolve on hosts without tzdata
+
+	"holodeck/types"
+)
+
+// ==================== DEFAULTS ====================
+
+const (
+	// DefaultRolloverTime is the forex market's daily rollover (5pm New York)
+	DefaultRolloverTime     = "17:00"
+	DefaultRolloverTimezone = "America/New_York"
+
+	// DefaultTripleSwapDay charges three nights to cover the weekend
+	// (spot forex settles T+2, so Wednesday's rollover spans Sat/Sun)
+	DefaultTripleSwapDay = "Wednesday"
+)
+
+// ==================== CONFIGURATION ====================
+
+// SwapRate is the overnight financing per lot per night, in account
+// currency; positive rates credit the account, negative rates debit it
+type SwapRate struct {
+	Long  float64 `json:"long"`
+	Short float64 `json:"short"`
+}
+
+// FinancingConfig configures overnight swap charges
+type FinancingConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// RolloverTime is the daily rollover as HH:MM in RolloverTimezone
+	RolloverTime     string `json:"rollover_time"`
+	RolloverTimezone string `json:"rollover_timezone"`
+
+	// TripleSwapDay is the weekday charged three nights ("" = none)
+	TripleSwapDay string `json:"triple_swap_day"`
+
+	// SwapRates are keyed by symbol or instrument type
+	SwapRates map[string]SwapRate `json:"swap_rates"`
+}
+
+// DefaultFinancingConfig returns a disabled config with forex conventions
+func DefaultFinancingConfig() FinancingConfig {
+	return FinancingConfig{
+		RolloverTime:     DefaultRolloverTime,
+		RolloverTimezone: DefaultRolloverTimezone,
+		TripleSwapDay:    DefaultTripleSwapDay,
+		SwapRates:        make(map[string]SwapRate),
+	}
+}
+
+// ==================== SWAP CHARGE ====================
+
+// SwapCharge is the financing applied at one rollover
+type SwapCharge struct {
+	Timestamp    time.Time // Rollover time
+	PositionSize float64   // Signed size held across the rollover
+	Nights       int       // 3 on the triple swap day, else 1
+	Rate         float64   // Per lot per night
+	Amount       float64   // Positive = credit, negative = debit
+}
+
+// String returns a human-readable representation
+func (sc *SwapCharge) String() string {
+	return fmt.Sprintf(
+		"Swap[%s Size:%.4f Nights:%d Amount:%.2f]",
+		sc.Timestamp.Format("2006-01-02T15:04"),
+		sc.PositionSize,
+		sc.Nights,
+		sc.Amount,
+	)
+}
+
+// ==================== FINANCING ENGINE ====================
+
+// FinancingEngine applies swap to positions held across the daily rollover
+// Rollovers are detected from tick timestamps, so results don't depend on
+// the replay speed
+type FinancingEngine struct {
+	config      FinancingConfig
+	location    *time.Location
+	rolloverHr  int
+	rolloverMin int
+	tripleDay   time.Weekday
+	hasTriple   bool
+
+	lastTime time.Time
+
+	// Statistics
+	totalCredited float64
+	totalDebited  float64
+	rollovers     int64
+	charges       int64
+}
+
+// NewFinancingEngine creates a financing engine
+func NewFinancingEngine(config FinancingConfig) (*FinancingEngine, error) {
+	if config.RolloverTime == "" {
+		config.RolloverTime = DefaultRolloverTime
+	}
+	if config.RolloverTimezone == "" {
+		config.RolloverTimezone = DefaultRolloverTimezone
+	}
+
+	rollover, err := time.Parse("15:04", config.RolloverTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rollover_time %q (want HH:MM)", config.RolloverTime)
+	}
+
+	location, err := time.LoadLocation(config.RolloverTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rollover_timezone %q: %w", config.RolloverTimezone, err)
+	}
+
+	fe := &FinancingEngine{
+		config:      config,
+		location:    location,
+		rolloverHr:  rollover.Hour(),
+		rolloverMin: rollover.Minute(),
+	}
+
+	if config.TripleSwapDay != "" {
+		day, ok := parseWeekday(config.TripleSwapDay)
+		if !ok {
+			return nil, fmt.Errorf("invalid triple_swap_day %q", config.TripleSwapDay)
+		}
+		fe.tripleDay = day
+		fe.hasTriple = true
+	}
+
+	return fe, nil
+}
+
+// parseWeekday parses an English weekday name
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if day.String() == name {
+			return day, true
+		}
+	}
+	return time.Sunday, false
+}
+
+// GetSwapRate returns the rate for an instrument, looked up by symbol
+// first and then by instrument type
+func (fe *FinancingEngine) GetSwapRate(instrument types.Instrument) (SwapRate, bool) {
+	if instrument == nil {
+		return SwapRate{}, false
+	}
+	if rate, ok := fe.config.SwapRates[instrument.GetSymbol()]; ok {
+		return rate, true
+	}
+	rate, ok := fe.config.SwapRates[instrument.GetType()]
+	return rate, ok
+}
+
+// ProcessTick returns the swap charges for every rollover crossed since
+// the previous tick, given the position held over that interval
+// The first tick only starts the clock
+func (fe *FinancingEngine) ProcessTick(
+	now time.Time,
+	positionSize float64,
+	instrument types.Instrument,
+) []*SwapCharge {
+	return fe.ProcessTickPositions(now, []float64{positionSize}, instrument)
+}
+
+// ProcessTickPositions is ProcessTick for several positions held at once
+// (hedging-mode tickets), each charged at its own side's rate
+func (fe *FinancingEngine) ProcessTickPositions(
+	now time.Time,
+	positionSizes []float64,
+	instrument types.Instrument,
+) []*SwapCharge {
+	positions := make([]FinancedPosition, 0, len(positionSizes))
+	for _, size := range positionSizes {
+		positions = append(positions, FinancedPosition{Size: size, Instrument: instrument})
+	}
+	return fe.ProcessTickInstruments(now, positions)
+}
+
+// FinancedPosition is a signed position size and the instrument whose
+// swap rate it pays
+type FinancedPosition struct {
+	Size       float64
+	Instrument types.Instrument
+}
+
+// ProcessTickInstruments is ProcessTickPositions for positions in
+// different instruments (multi-symbol sessions), each charged at its own
+// instrument's rate
+// Charges come rollover by rollover, in the order of positions
+func (fe *FinancingEngine) ProcessTickInstruments(now time.Time, positions []FinancedPosition) []*SwapCharge {
+	if fe.lastTime.IsZero() || !now.After(fe.lastTime) {
+		if fe.lastTime.IsZero() {
+			fe.lastTime = now
+		}
+		return nil
+	}
+
+	rollovers := fe.rolloversBetween(fe.lastTime, now)
+	fe.lastTime = now
+	fe.rollovers += int64(len(rollovers))
+
+	if len(rollovers) == 0 {
+		return nil
+	}
+
+	charges := make([]*SwapCharge, 0, len(rollovers))
+	for _, rollover := range rollovers {
+		nights := 1
+		if fe.hasTriple && rollover.Weekday() == fe.tripleDay {
+			nights = 3
+		}
+
+		for _, position := range positions {
+			rate, ok := fe.GetSwapRate(position.Instrument)
+			if !ok {
+				continue
+			}
+			perNight := rate.Long
+			if position.Size < 0 {
+				perNight = rate.Short
+			}
+			if position.Size == 0 || perNight == 0 {
+				continue
+			}
+
+			amount := perNight * math.Abs(position.Size) * float64(nights)
+			charges = append(charges, &SwapCharge{
+				Timestamp:    rollover,
+				PositionSize: position.Size,
+				Nights:       nights,
+				Rate:         perNight,
+				Amount:       amount,
+			})
+			fe.charges++
+			if amount > 0 {
+				fe.totalCredited += amount
+			} else {
+				fe.totalDebited -= amount
+			}
+		}
+	}
+
+	return charges
+}
+
+// rolloversBetween returns the weekday rollovers in (from, to]
+// No rollover is charged on Saturday or Sunday; the triple swap day
+// covers the weekend instead
+func (fe *FinancingEngine) rolloversBetween(from, to time.Time) []time.Time {
+	rollovers := make([]time.Time, 0)
+
+	local := from.In(fe.location)
+	next := time.Date(local.Year(), local.Month(), local.Day(), fe.rolloverHr, fe.rolloverMin, 0, 0, fe.location)
+	if !next.After(from) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	for !next.After(to) {
+		if next.Weekday() != time.Saturday && next.Weekday() != time.Sunday {
+			rollovers = append(rollovers, next)
+		}
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return rollovers
+}
+
+// ==================== STATISTICS ====================
+
+// GetNetFinancing returns credits minus debits
+func (fe *FinancingEngine) GetNetFinancing() float64 {
+	return fe.totalCredited - fe.totalDebited
+}
+
+// GetStatistics returns financing statistics
+func (fe *FinancingEngine) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"rollover_time":  fe.config.RolloverTime,
+		"rollover_zone":  fe.config.RolloverTimezone,
+		"rollovers":      fe.rollovers,
+		"swap_charges":   fe.charges,
+		"total_credited": fe.totalCredited,
+		"total_debited":  fe.totalDebited,
+		"net_financing":  fe.GetNetFinancing(),
+	}
+}
+
+// Reset clears the clock and statistics
+func (fe *FinancingEngine) Reset() {
+	fe.lastTime = time.Time{}
+	fe.totalCredited = 0
+	fe.totalDebited = 0
+	fe.rollovers = 0
+	fe.charges = 0
+}
+
+// String returns a human-readable representation
+func (fe *FinancingEngine) String() string {
+	return fmt.Sprintf(
+		"FinancingEngine[Rollover:%s %s, Charges:%d, Net:%.2f]",
+		fe.config.RolloverTime,
+		fe.config.RolloverTimezone,
+		fe.charges,
+		fe.GetNetFinancing(),
+	)
+}
@@ -0,0 +1,272 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== CONSOLE LOGGER ====================
+
+// ANSI colors by level
+const (
+	colorReset  = "\033[0m"
+	colorGray   = "\033[90m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorRed    = "\033[31m"
+	colorCyan   = "\033[36m"
+)
+
+// ConsoleLogger writes compact one-line entries to a terminal, filtered by
+// level and colored by level when the output is a terminal
+// It implements Logger; Simulator wraps it for Holodeck.WithLogger
+type ConsoleLogger struct {
+	out   io.Writer
+	level LogLevel
+	color bool
+
+	sessionID     string
+	moneyDecimals int
+	clock         types.Clock
+
+	mu            sync.Mutex
+	entriesLogged int64
+}
+
+// NewConsoleLogger creates a console logger at LevelInfo
+// Color is on when out is a terminal and NO_COLOR is not set
+func NewConsoleLogger(out io.Writer) *ConsoleLogger {
+	return &ConsoleLogger{
+		out:           out,
+		level:         LevelInfo,
+		color:         isTerminal(out) && os.Getenv("NO_COLOR") == "",
+		moneyDecimals: types.DefaultMoneyDecimals,
+	}
+}
+
+// isTerminal returns true if w is a character device
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ==================== CONFIGURATION ====================
+
+// SetLevel sets the lowest level written
+func (cl *ConsoleLogger) SetLevel(level LogLevel) {
+	cl.level = level
+}
+
+// GetLevel returns the lowest level written
+func (cl *ConsoleLogger) GetLevel() LogLevel {
+	return cl.level
+}
+
+// SetColor turns color output on or off
+func (cl *ConsoleLogger) SetColor(enabled bool) {
+	cl.color = enabled
+}
+
+// SetMoneyDecimals sets the precision for balances, P&L and costs
+func (cl *ConsoleLogger) SetMoneyDecimals(decimals int) {
+	if decimals >= 0 && decimals <= types.MaxMoneyDecimals {
+		cl.moneyDecimals = decimals
+	}
+}
+
+// SetClock stamps lines from clock instead of the wall clock
+func (cl *ConsoleLogger) SetClock(clock types.Clock) {
+	cl.clock = clock
+}
+
+// SetVerbosity maps a verbosity to a level: QUIET shows errors, MINIMAL
+// warnings, NORMAL and VERBOSE info, DEBUG everything
+func (cl *ConsoleLogger) SetVerbosity(level VerbosityLevel) error {
+	switch {
+	case level <= VerbosityQuiet:
+		cl.level = LevelError
+	case level == VerbosityMinimal:
+		cl.level = LevelWarning
+	case level >= VerbosityDebug:
+		cl.level = LevelDebug
+	default:
+		cl.level = LevelInfo
+	}
+	return nil
+}
+
+// ==================== OUTPUT ====================
+
+// enabled returns true if a level passes the filter
+func (cl *ConsoleLogger) enabled(level LogLevel) bool {
+	return level >= cl.level
+}
+
+// levelColor returns the color of a level
+func levelColor(level LogLevel) string {
+	switch level {
+	case LevelDebug:
+		return colorGray
+	case LevelWarning:
+		return colorYellow
+	case LevelError:
+		return colorRed
+	default:
+		return colorGreen
+	}
+}
+
+// write prints one line: time, level, category and message
+func (cl *ConsoleLogger) write(level LogLevel, timestamp time.Time, category, message string) error {
+	if !cl.enabled(level) {
+		return nil
+	}
+
+	levelText := fmt.Sprintf("%-5s", level.String())
+	if level == LevelWarning {
+		levelText = "WARN "
+	}
+	category = fmt.Sprintf("%-7s", category)
+	if cl.color {
+		levelText = levelColor(level) + levelText + colorReset
+		category = colorCyan + category + colorReset
+	}
+
+	line := fmt.Sprintf("%s %s %s %s\n", timestamp.Format("15:04:05.000"), levelText, category, message)
+
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if _, err := io.WriteString(cl.out, line); err != nil {
+		return err
+	}
+	cl.entriesLogged++
+	return nil
+}
+
+// ==================== LOGGER INTERFACE ====================
+
+// LogTrade logs a trade on one line
+func (cl *ConsoleLogger) LogTrade(trade *TradeLog) error {
+	level := LevelInfo
+	if trade.Status == types.OrderStatusRejected {
+		level = LevelWarning
+	}
+
+	message := fmt.Sprintf("%s %s %.4f @ %.5f pnl=%.*f comm=%.*f [%s]",
+		trade.Instrument, trade.Action, trade.FilledSize, trade.FillPrice,
+		cl.moneyDecimals, trade.RealizedPnL, cl.moneyDecimals, trade.Commission, trade.Status)
+	if trade.ErrorMessage != "" {
+		message += " " + trade.ErrorMessage
+	}
+	return cl.write(level, trade.Timestamp, "TRADE", message)
+}
+
+// LogError logs an error on one line, at a level from its severity
+func (cl *ConsoleLogger) LogError(errLog *ErrorLog) error {
+	level := LevelError
+	switch errLog.Severity {
+	case SeverityInfo:
+		level = LevelInfo
+	case SeverityWarning:
+		level = LevelWarning
+	}
+
+	message := errLog.Message
+	if errLog.ErrorCode != "" {
+		message = fmt.Sprintf("[%s] %s", errLog.ErrorCode, message)
+	}
+	return cl.write(level, errLog.Timestamp, "ERROR", message)
+}
+
+// LogMetrics logs the headline metrics on one line
+func (cl *ConsoleLogger) LogMetrics(metrics *MetricsLog) error {
+	return cl.write(LevelInfo, metrics.Timestamp, "METRICS", fmt.Sprintf(
+		"bal=%.*f pnl=%.*f (%.2f%%) trades=%d win=%.1f%% dd=%.2f%% sharpe=%.2f",
+		cl.moneyDecimals, metrics.CurrentBalance,
+		cl.moneyDecimals, metrics.TotalPnL,
+		metrics.TotalPnLPercent,
+		metrics.TradeCount,
+		metrics.WinRate,
+		metrics.MaxDrawdownPercent,
+		metrics.SharpeRatio,
+	))
+}
+
+// LogInfo logs an informational message
+func (cl *ConsoleLogger) LogInfo(message string) error {
+	return cl.write(LevelInfo, stamp(cl.clock), "INFO", message)
+}
+
+// LogWarning logs a warning message
+func (cl *ConsoleLogger) LogWarning(message string) error {
+	return cl.write(LevelWarning, stamp(cl.clock), "WARN", message)
+}
+
+// LogDebug logs a debug message
+func (cl *ConsoleLogger) LogDebug(message string) error {
+	return cl.write(LevelDebug, stamp(cl.clock), "DEBUG", message)
+}
+
+// StartSession starts a session
+func (cl *ConsoleLogger) StartSession(sessionID string) error {
+	cl.sessionID = sessionID
+	return cl.write(LevelInfo, stamp(cl.clock), "SESSION", "started "+sessionID)
+}
+
+// EndSession ends a session
+func (cl *ConsoleLogger) EndSession(sessionID string) error {
+	return cl.write(LevelInfo, stamp(cl.clock), "SESSION", fmt.Sprintf("ended %s (%d entries)", sessionID, cl.entriesLogged))
+}
+
+// GetSessionID returns the session ID
+func (cl *ConsoleLogger) GetSessionID() string {
+	return cl.sessionID
+}
+
+// Flush flushes the output when it is buffered
+func (cl *ConsoleLogger) Flush() error {
+	if f, ok := cl.out.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close flushes the output; the writer itself is left open
+func (cl *ConsoleLogger) Close() error {
+	return cl.Flush()
+}
+
+// GetStatistics returns logger statistics
+func (cl *ConsoleLogger) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"entries_logged": cl.entriesLogged,
+		"level":          cl.level.String(),
+		"color":          cl.color,
+		"session_id":     cl.sessionID,
+	}
+}
+
+// ==================== SIMULATOR VIEW ====================
+
+// LogTick logs a tick (debug)
+func (cl *ConsoleLogger) LogTick(tick *types.Tick) error {
+	return cl.write(LevelDebug, tick.Timestamp, "TICK",
+		fmt.Sprintf("%s bid=%.5f ask=%.5f spread=%.1f", tick.Symbol, tick.Bid, tick.Ask, tick.SpreadPips))
+}
+
+// Simulator returns the logger wrapped for Holodeck.WithLogger
+func (cl *ConsoleLogger) Simulator() *SimulatorAdapter {
+	return NewSimulatorAdapter(cl)
+}
@@ -0,0 +1,302 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== ERROR FILTER CONFIG ====================
+
+// ErrorFilterConfig bounds error logging so one bad region of data cannot
+// flood the error log with identical entries
+type ErrorFilterConfig struct {
+	// MinSeverity drops errors below it: INFO, WARNING, ERROR or CRITICAL
+	// ("" keeps all)
+	MinSeverity string `json:"min_severity"`
+
+	// MaxPerCode logs at most N errors per error code, then counts the
+	// rest into a summary entry (0 disables)
+	MaxPerCode int `json:"max_per_code"`
+
+	// CodeLimits overrides MaxPerCode for single codes, e.g.
+	// {"CSV_READ_ERROR": 10}
+	CodeLimits map[string]int `json:"code_limits"`
+
+	// WindowSeconds restarts the per-code counts every N seconds, writing
+	// the summary of what was suppressed (0 counts over the session)
+	WindowSeconds float64 `json:"window_seconds"`
+}
+
+// IsEnabled returns true if any filtering is configured
+func (fc ErrorFilterConfig) IsEnabled() bool {
+	return fc.MinSeverity != "" || fc.MaxPerCode > 0 || len(fc.CodeLimits) > 0
+}
+
+// Validate checks the severity and limits
+func (fc ErrorFilterConfig) Validate() error {
+	if _, err := ParseErrorSeverity(fc.MinSeverity); fc.MinSeverity != "" && err != nil {
+		return err
+	}
+	if fc.MaxPerCode < 0 || fc.WindowSeconds < 0 {
+		return fmt.Errorf("error filter limits cannot be negative")
+	}
+	for code, limit := range fc.CodeLimits {
+		if limit < 0 {
+			return fmt.Errorf("error filter limit for %s cannot be negative", code)
+		}
+	}
+	return nil
+}
+
+// ParseErrorSeverity parses a severity name (case-insensitive)
+func ParseErrorSeverity(name string) (ErrorSeverity, error) {
+	for _, severity := range []ErrorSeverity{SeverityInfo, SeverityWarning, SeverityError, SeverityCritical} {
+		if strings.EqualFold(name, severity.String()) {
+			return severity, nil
+		}
+	}
+	return SeverityInfo, fmt.Errorf("unknown error severity: %s", name)
+}
+
+// ==================== FILTERED LOGGER ====================
+
+// FilteredLogger wraps a logger, dropping errors below a severity and
+// rate limiting each error code: past the limit errors are only counted,
+// and one summary per code is logged when the window restarts or the
+// logger is flushed, ended or closed
+type FilteredLogger struct {
+	Logger
+
+	minSeverity ErrorSeverity
+	maxPerCode  int
+	codeLimits  map[string]int
+	window      time.Duration
+
+	codes       map[string]*errorCodeCount
+	windowStart time.Time
+
+	// Totals
+	passed     int64
+	belowLevel int64
+	suppressed int64
+	summaries  int64
+
+	mu sync.Mutex
+}
+
+// errorCodeCount counts one code's errors in the current window
+type errorCodeCount struct {
+	logged     int
+	suppressed int64
+	last       *ErrorLog
+}
+
+// NewFilteredLogger wraps a logger with error filtering
+func NewFilteredLogger(l Logger, config ErrorFilterConfig) (*FilteredLogger, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	fl := &FilteredLogger{
+		Logger:     l,
+		maxPerCode: config.MaxPerCode,
+		codeLimits: config.CodeLimits,
+		window:     time.Duration(config.WindowSeconds * float64(time.Second)),
+		codes:      make(map[string]*errorCodeCount),
+	}
+	if config.MinSeverity != "" {
+		fl.minSeverity, _ = ParseErrorSeverity(config.MinSeverity)
+	}
+	return fl, nil
+}
+
+// GetLogger returns the wrapped logger
+func (fl *FilteredLogger) GetLogger() Logger {
+	return fl.Logger
+}
+
+// limit returns the number of errors logged per window for a code
+// (0 = unlimited)
+func (fl *FilteredLogger) limit(code string) int {
+	if limit, ok := fl.codeLimits[code]; ok {
+		return limit
+	}
+	return fl.maxPerCode
+}
+
+// LogError logs the error unless it is below the severity threshold or
+// its code is over the limit
+func (fl *FilteredLogger) LogError(errLog *ErrorLog) error {
+	fl.mu.Lock()
+	if errLog.Severity < fl.minSeverity {
+		fl.belowLevel++
+		fl.mu.Unlock()
+		return nil
+	}
+
+	var summaries []*ErrorLog
+	if fl.window > 0 {
+		if fl.windowStart.IsZero() {
+			fl.windowStart = errLog.Timestamp
+		} else if errLog.Timestamp.Sub(fl.windowStart) >= fl.window {
+			summaries = fl.drain()
+			fl.windowStart = errLog.Timestamp
+		}
+	}
+
+	count := fl.codes[errLog.ErrorCode]
+	if count == nil {
+		count = &errorCodeCount{}
+		fl.codes[errLog.ErrorCode] = count
+	}
+
+	limit := fl.limit(errLog.ErrorCode)
+	pass := limit == 0 || count.logged < limit
+	if pass {
+		count.logged++
+		fl.passed++
+	} else {
+		count.suppressed++
+		count.last = errLog
+		fl.suppressed++
+	}
+	fl.mu.Unlock()
+
+	err := fl.logAll(summaries)
+	if pass {
+		if logErr := fl.Logger.LogError(errLog); logErr != nil && err == nil {
+			err = logErr
+		}
+	}
+	return err
+}
+
+// drain returns a summary for every code with suppressed errors and
+// restarts the counts
+// Caller must hold the lock
+func (fl *FilteredLogger) drain() []*ErrorLog {
+	codes := make([]string, 0, len(fl.codes))
+	for code, count := range fl.codes {
+		if count.suppressed > 0 {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	summaries := make([]*ErrorLog, 0, len(codes))
+	for _, code := range codes {
+		count := fl.codes[code]
+		summaries = append(summaries, &ErrorLog{
+			Timestamp: count.last.Timestamp,
+			ErrorCode: code,
+			ErrorType: "SUPPRESSED",
+			Message: fmt.Sprintf("%d more %s errors suppressed after the first %d",
+				count.suppressed, codeName(code), count.logged),
+			Details:  "last: " + count.last.Message,
+			Severity: count.last.Severity,
+		})
+	}
+	fl.summaries += int64(len(summaries))
+	fl.codes = make(map[string]*errorCodeCount)
+	return summaries
+}
+
+// codeName names a code in summaries
+func codeName(code string) string {
+	if code == "" {
+		return "uncoded"
+	}
+	return code
+}
+
+// logAll logs summary entries to the wrapped logger
+func (fl *FilteredLogger) logAll(summaries []*ErrorLog) error {
+	var first error
+	for _, summary := range summaries {
+		if err := fl.Logger.LogError(summary); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// flushSummaries logs the summaries of the current window
+func (fl *FilteredLogger) flushSummaries() error {
+	fl.mu.Lock()
+	summaries := fl.drain()
+	fl.windowStart = time.Time{}
+	fl.mu.Unlock()
+	return fl.logAll(summaries)
+}
+
+// Flush logs pending summaries and flushes the wrapped logger
+func (fl *FilteredLogger) Flush() error {
+	if err := fl.flushSummaries(); err != nil {
+		return err
+	}
+	return fl.Logger.Flush()
+}
+
+// EndSession logs pending summaries and ends the session
+func (fl *FilteredLogger) EndSession(sessionID string) error {
+	if err := fl.flushSummaries(); err != nil {
+		return err
+	}
+	return fl.Logger.EndSession(sessionID)
+}
+
+// Close logs pending summaries and closes the wrapped logger
+func (fl *FilteredLogger) Close() error {
+	if err := fl.flushSummaries(); err != nil {
+		return err
+	}
+	return fl.Logger.Close()
+}
+
+// LogTick forwards a tick to loggers that log ticks
+func (fl *FilteredLogger) LogTick(tick *types.Tick) error {
+	if tl, ok := fl.Logger.(interface{ LogTick(*types.Tick) error }); ok {
+		return tl.LogTick(tick)
+	}
+	return nil
+}
+
+// Simulator returns an adapter for Holodeck.WithLogger
+func (fl *FilteredLogger) Simulator() *SimulatorAdapter {
+	return NewSimulatorAdapter(fl)
+}
+
+// ==================== STATISTICS ====================
+
+// GetStatistics returns filter statistics
+func (fl *FilteredLogger) GetStatistics() map[string]interface{} {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	pending := make(map[string]int64)
+	for code, count := range fl.codes {
+		if count.suppressed > 0 {
+			pending[codeName(code)] = count.suppressed
+		}
+	}
+
+	stats := map[string]interface{}{
+		"min_severity":       fl.minSeverity.String(),
+		"max_per_code":       fl.maxPerCode,
+		"window_seconds":     fl.window.Seconds(),
+		"errors_logged":      fl.passed,
+		"errors_below_level": fl.belowLevel,
+		"errors_suppressed":  fl.suppressed,
+		"summaries_logged":   fl.summaries,
+		"pending_suppressed": pending,
+	}
+	if sl, ok := fl.Logger.(interface{ GetStatistics() map[string]interface{} }); ok {
+		stats["logger"] = sl.GetStatistics()
+	}
+	return stats
+}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"holodeck/types"
 )
 
 // ==================== FILE LOGGER ====================
@@ -18,11 +20,25 @@ type FileLogger struct {
 	verbosity  VerbosityLevel
 	bufferSize int
 
+	// moneyDecimals is the precision for balances, P&L and costs
+	moneyDecimals int
+
+	// clock stamps info lines and session headers (wall clock if nil)
+	clock types.Clock
+
+	// rotation bounds each log file by size and age
+	rotation RotationConfig
+
 	// File handles
-	tradeFile   *os.File
-	errorFile   *os.File
-	metricsFile *os.File
-	infoFile    *os.File
+	tradeFile   *rotatingFile
+	errorFile   *rotatingFile
+	metricsFile *rotatingFile
+	infoFile    *rotatingFile
+
+	// tradeCSV mirrors the trade log as CSV when tradeCSVEnabled is set
+	tradeCSV        *TradeCSVWriter
+	tradeCSVEnabled bool
+	tradeCSVColumns []string
 
 	// Buffering
 	buffer      []string
@@ -44,11 +60,12 @@ func NewFileLogger(logDir string) (*FileLogger, error) {
 	}
 
 	return &FileLogger{
-		logDir:      logDir,
-		verbosity:   VerbosityNormal,
-		bufferSize:  100,
-		lastFlush:   time.Now(),
-		createdTime: time.Now(),
+		logDir:        logDir,
+		verbosity:     VerbosityNormal,
+		bufferSize:    100,
+		moneyDecimals: types.DefaultMoneyDecimals,
+		lastFlush:     time.Now(),
+		createdTime:   time.Now(),
 	}, nil
 }
 
@@ -62,52 +79,46 @@ func (fl *FileLogger) StartSession(sessionID string) error {
 	baseName := fmt.Sprintf("%s_%s", sessionID, timestamp)
 
 	// Open trade log
-	tradeFile, err := os.OpenFile(
-		filepath.Join(fl.logDir, baseName+"_trades.log"),
-		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
-		0644,
-	)
+	tradeFile, err := openRotatingFile(filepath.Join(fl.logDir, baseName+"_trades.log"), fl.rotation)
 	if err != nil {
 		return err
 	}
 	fl.tradeFile = tradeFile
 
 	// Open error log
-	errorFile, err := os.OpenFile(
-		filepath.Join(fl.logDir, baseName+"_errors.log"),
-		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
-		0644,
-	)
+	errorFile, err := openRotatingFile(filepath.Join(fl.logDir, baseName+"_errors.log"), fl.rotation)
 	if err != nil {
 		return err
 	}
 	fl.errorFile = errorFile
 
 	// Open metrics log
-	metricsFile, err := os.OpenFile(
-		filepath.Join(fl.logDir, baseName+"_metrics.log"),
-		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
-		0644,
-	)
+	metricsFile, err := openRotatingFile(filepath.Join(fl.logDir, baseName+"_metrics.log"), fl.rotation)
 	if err != nil {
 		return err
 	}
 	fl.metricsFile = metricsFile
 
 	// Open info log
-	infoFile, err := os.OpenFile(
-		filepath.Join(fl.logDir, baseName+"_info.log"),
-		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
-		0644,
-	)
+	infoFile, err := openRotatingFile(filepath.Join(fl.logDir, baseName+"_info.log"), fl.rotation)
 	if err != nil {
 		return err
 	}
 	fl.infoFile = infoFile
 
+	// Open trade CSV
+	if fl.tradeCSVEnabled {
+		tradeCSV, err := NewTradeCSVWriter(filepath.Join(fl.logDir, baseName+"_trades.csv"), fl.tradeCSVColumns)
+		if err != nil {
+			return err
+		}
+		tradeCSV.SetMoneyDecimals(fl.moneyDecimals)
+		fl.tradeCSV = tradeCSV
+	}
+
 	// Write session header
 	header := fmt.Sprintf("=== Holodeck Session %s ===\n", sessionID)
-	header += fmt.Sprintf("Started: %s\n\n", time.Now().Format(time.RFC3339))
+	header += fmt.Sprintf("Started: %s\n\n", stamp(fl.clock).Format(time.RFC3339))
 
 	fl.tradeFile.WriteString(header)
 	fl.errorFile.WriteString(header)
@@ -126,7 +137,7 @@ func (fl *FileLogger) EndSession(sessionID string) error {
 
 	// Write session footer
 	footer := fmt.Sprintf("\n=== Session %s Ended ===\n", sessionID)
-	footer += fmt.Sprintf("Ended: %s\n", time.Now().Format(time.RFC3339))
+	footer += fmt.Sprintf("Ended: %s\n", stamp(fl.clock).Format(time.RFC3339))
 	footer += fmt.Sprintf("Total Entries Logged: %d\n", fl.entriesLogged)
 
 	if fl.tradeFile != nil {
@@ -145,6 +156,10 @@ func (fl *FileLogger) EndSession(sessionID string) error {
 		fl.infoFile.WriteString(footer)
 		fl.infoFile.Close()
 	}
+	if fl.tradeCSV != nil {
+		fl.tradeCSV.Close()
+		fl.tradeCSV = nil
+	}
 
 	return nil
 }
@@ -172,8 +187,8 @@ func (fl *FileLogger) LogTrade(trade *TradeLog) error {
 			"  Instrument: %s\n"+
 			"  Action: %s | Type: %s\n"+
 			"  Requested: %.4f | Filled: %.4f @ %.5f\n"+
-			"  Commission: %.2f | Slippage: %.4f pips\n"+
-			"  P&L: %.2f | Status: %s\n\n",
+			"  Commission: %.*f | Slippage: %.4f pips\n"+
+			"  P&L: %.*f | MFE: %.*f | MAE: %.*f | Status: %s\n\n",
 		trade.Timestamp.Format("2006-01-02 15:04:05.000"),
 		trade.TradeID,
 		trade.OrderID,
@@ -183,9 +198,11 @@ func (fl *FileLogger) LogTrade(trade *TradeLog) error {
 		trade.RequestedSize,
 		trade.FilledSize,
 		trade.FillPrice,
-		trade.Commission,
+		fl.moneyDecimals, trade.Commission,
 		trade.Slippage,
-		trade.RealizedPnL,
+		fl.moneyDecimals, trade.RealizedPnL,
+		fl.moneyDecimals, trade.MFE,
+		fl.moneyDecimals, trade.MAE,
 		trade.Status,
 	)
 
@@ -193,6 +210,12 @@ func (fl *FileLogger) LogTrade(trade *TradeLog) error {
 	fl.buffer = append(fl.buffer, entry)
 	fl.bufferMutex.Unlock()
 
+	if fl.tradeCSV != nil {
+		if err := fl.tradeCSV.Write(trade); err != nil {
+			return err
+		}
+	}
+
 	fl.entriesLogged++
 
 	// Auto-flush if buffer is full
@@ -252,31 +275,48 @@ func (fl *FileLogger) LogMetrics(metrics *MetricsLog) error {
 	entry := fmt.Sprintf(
 		"[%s] METRICS SNAPSHOT\n"+
 			"  Session Duration: %v\n"+
-			"  Initial Balance: $%.2f\n"+
-			"  Current Balance: $%.2f\n"+
-			"  Total P&L: $%.2f (%.2f%%)\n"+
-			"  Trades: %d (Won: %d | Lost: %d | Win Rate: %.1f%%)\n"+
-			"  Largest Win: $%.2f | Largest Loss: $%.2f\n"+
-			"  Commission: $%.2f | Slippage: $%.2f\n"+
+			"  Initial Balance: $%.*f\n"+
+			"  Current Balance: $%.*f\n"+
+			"  Total P&L: $%.*f (%.2f%%)\n"+
+			"  Trades: %d (Won: %d | Lost: %d | Break-Even: %d | Win Rate: %.1f%%)\n"+
+			"  Largest Win: $%.*f | Largest Loss: $%.*f\n"+
+			"  Commission: $%.*f | Slippage: $%.*f\n"+
 			"  Max Drawdown: %.2f%%\n"+
-			"  Sharpe Ratio: %.2f\n"+
+			"  Sharpe Ratio: %.2f | Sortino Ratio: %.2f\n"+
+			"  Expectancy: $%.*f (%.2fR, SE %.*f) | Kelly: %.2f\n"+
+			"  P&L Skew: %.2f | Kurtosis: %.2f | Avg Streak: %.1f won / %.1f lost\n"+
+			"  Daily VaR: %.2f%% (95%%) %.2f%% (99%%) | CVaR: %.2f%% (95%%) %.2f%% (99%%)\n"+
 			"  Ticks Processed: %d | Errors: %d\n\n",
 		metrics.Timestamp.Format("2006-01-02 15:04:05.000"),
 		metrics.SessionDuration,
-		metrics.InitialBalance,
-		metrics.CurrentBalance,
-		metrics.TotalPnL,
+		fl.moneyDecimals, metrics.InitialBalance,
+		fl.moneyDecimals, metrics.CurrentBalance,
+		fl.moneyDecimals, metrics.TotalPnL,
 		metrics.TotalPnLPercent,
 		metrics.TradeCount,
 		metrics.WinningTrades,
 		metrics.LosingTrades,
+		metrics.BreakevenTrades,
 		metrics.WinRate,
-		metrics.LargestWin,
-		metrics.LargestLoss,
-		metrics.CommissionTotal,
-		metrics.SlippageTotal,
+		fl.moneyDecimals, metrics.LargestWin,
+		fl.moneyDecimals, metrics.LargestLoss,
+		fl.moneyDecimals, metrics.CommissionTotal,
+		fl.moneyDecimals, metrics.SlippageTotal,
 		metrics.MaxDrawdownPercent,
 		metrics.SharpeRatio,
+		metrics.SortinoRatio,
+		fl.moneyDecimals, metrics.Expectancy,
+		metrics.ExpectancyR,
+		fl.moneyDecimals, metrics.ExpectancyStdError,
+		metrics.KellyFraction,
+		metrics.PnLSkewness,
+		metrics.PnLKurtosis,
+		metrics.AvgWinStreak,
+		metrics.AvgLossStreak,
+		metrics.VaR95,
+		metrics.VaR99,
+		metrics.CVaR95,
+		metrics.CVaR99,
 		metrics.TicksProcessed,
 		metrics.ErrorCount,
 	)
@@ -300,7 +340,7 @@ func (fl *FileLogger) LogInfo(message string) error {
 		return fmt.Errorf("info log file not initialized")
 	}
 
-	entry := fmt.Sprintf("[%s] INFO: %s\n", time.Now().Format("2006-01-02 15:04:05.000"), message)
+	entry := fmt.Sprintf("[%s] INFO: %s\n", stamp(fl.clock).Format("2006-01-02 15:04:05.000"), message)
 
 	_, err := fl.infoFile.WriteString(entry)
 	if err != nil {
@@ -321,7 +361,7 @@ func (fl *FileLogger) LogWarning(message string) error {
 		return fmt.Errorf("info log file not initialized")
 	}
 
-	entry := fmt.Sprintf("[%s] WARNING: %s\n", time.Now().Format("2006-01-02 15:04:05.000"), message)
+	entry := fmt.Sprintf("[%s] WARNING: %s\n", stamp(fl.clock).Format("2006-01-02 15:04:05.000"), message)
 
 	_, err := fl.infoFile.WriteString(entry)
 	if err != nil {
@@ -342,7 +382,7 @@ func (fl *FileLogger) LogDebug(message string) error {
 		return fmt.Errorf("info log file not initialized")
 	}
 
-	entry := fmt.Sprintf("[%s] DEBUG: %s\n", time.Now().Format("2006-01-02 15:04:05.000"), message)
+	entry := fmt.Sprintf("[%s] DEBUG: %s\n", stamp(fl.clock).Format("2006-01-02 15:04:05.000"), message)
 
 	_, err := fl.infoFile.WriteString(entry)
 	if err != nil {
@@ -355,12 +395,48 @@ func (fl *FileLogger) LogDebug(message string) error {
 
 // ==================== CONTROL METHODS ====================
 
+// SetMoneyDecimals sets the precision for balances, P&L and costs
+// Use types.PrecisionPolicy to pick it from the instrument class
+func (fl *FileLogger) SetMoneyDecimals(decimals int) {
+	if decimals >= 0 && decimals <= types.MaxMoneyDecimals {
+		fl.moneyDecimals = decimals
+	}
+}
+
+// SetRotation bounds the log files by size and age; it applies to the
+// files opened by the next StartSession
+func (fl *FileLogger) SetRotation(config RotationConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	fl.rotation = config
+	return nil
+}
+
+// SetTradeCSV also writes the trades to <session>_trades.csv, one row per
+// trade with the given columns (empty selects every TradeCSVColumns
+// column); it applies to the files opened by the next StartSession
+func (fl *FileLogger) SetTradeCSV(columns []string) error {
+	if err := ValidateTradeCSVColumns(columns); err != nil {
+		return err
+	}
+	fl.tradeCSVEnabled = true
+	fl.tradeCSVColumns = append([]string(nil), columns...)
+	return nil
+}
+
 // SetVerbosity sets the verbosity level
 func (fl *FileLogger) SetVerbosity(level VerbosityLevel) error {
 	fl.verbosity = level
 	return nil
 }
 
+// SetClock stamps info lines and session headers from clock instead of
+// the wall clock
+func (fl *FileLogger) SetClock(clock types.Clock) {
+	fl.clock = clock
+}
+
 // Flush flushes all buffered entries to disk
 func (fl *FileLogger) Flush() error {
 	fl.bufferMutex.Lock()
@@ -388,6 +464,9 @@ func (fl *FileLogger) Flush() error {
 	if fl.infoFile != nil {
 		fl.infoFile.Sync()
 	}
+	if fl.tradeCSV != nil {
+		fl.tradeCSV.Flush()
+	}
 
 	return nil
 }
@@ -421,6 +500,12 @@ func (fl *FileLogger) Close() error {
 			lastErr = err
 		}
 	}
+	if fl.tradeCSV != nil {
+		if err := fl.tradeCSV.Close(); err != nil {
+			lastErr = err
+		}
+		fl.tradeCSV = nil
+	}
 
 	return lastErr
 }
@@ -429,6 +514,13 @@ func (fl *FileLogger) Close() error {
 
 // GetStatistics returns logger statistics
 func (fl *FileLogger) GetStatistics() map[string]interface{} {
+	rotations := int64(0)
+	for _, file := range []*rotatingFile{fl.tradeFile, fl.errorFile, fl.metricsFile, fl.infoFile} {
+		if file != nil {
+			rotations += file.rotations
+		}
+	}
+
 	return map[string]interface{}{
 		"entries_logged": fl.entriesLogged,
 		"last_flush":     fl.lastFlush,
@@ -436,5 +528,6 @@ func (fl *FileLogger) GetStatistics() map[string]interface{} {
 		"verbosity":      fl.verbosity.String(),
 		"session_id":     fl.sessionID,
 		"uptime":         time.Since(fl.createdTime),
+		"rotations":      rotations,
 	}
 }
@@ -0,0 +1,347 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== JSON LOGGER ====================
+
+// JSON event names, the "event" field of every line
+const (
+	JSONEventSessionStart = "session_start"
+	JSONEventSessionEnd   = "session_end"
+	JSONEventTick         = "tick"
+	JSONEventTrade        = "trade"
+	JSONEventError        = "error"
+	JSONEventMetrics      = "metrics"
+	JSONEventInfo         = "info"
+	JSONEventWarning      = "warning"
+	JSONEventDebug        = "debug"
+)
+
+// JSONLogger implements Logger as newline-delimited JSON: one object per
+// event with "ts", "event" and "session_id" followed by the event's
+// fields, so logs can be ingested by jq, ELK or ClickHouse as they are
+type JSONLogger struct {
+	// Configuration
+	sessionID string
+	logDir    string // Empty when writing to a stream
+	verbosity VerbosityLevel
+	clock     types.Clock // Stamps messages and session events
+
+	// Output
+	file   *os.File // Owned file, nil for streams
+	writer *bufio.Writer
+	mu     sync.Mutex
+
+	// autoFlush writes every line out immediately (streaming)
+	autoFlush bool
+
+	// Statistics
+	entriesLogged int64
+	lastFlush     time.Time
+	createdTime   time.Time
+}
+
+// ==================== CREATION ====================
+
+// NewJSONLogger creates a JSON logger writing one <session>_<time>.ndjson
+// file per session into logDir
+func NewJSONLogger(logDir string) (*JSONLogger, error) {
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &JSONLogger{
+		logDir:      logDir,
+		verbosity:   VerbosityNormal,
+		lastFlush:   time.Now(),
+		createdTime: time.Now(),
+	}, nil
+}
+
+// NewJSONStreamLogger creates a JSON logger writing to w (e.g. os.Stdout)
+// Closing the logger flushes but does not close w
+func NewJSONStreamLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{
+		verbosity:   VerbosityNormal,
+		writer:      bufio.NewWriter(w),
+		lastFlush:   time.Now(),
+		createdTime: time.Now(),
+	}
+}
+
+// ==================== SESSION MANAGEMENT ====================
+
+// StartSession opens the session's file (directory mode) and writes a
+// session_start event
+func (jl *JSONLogger) StartSession(sessionID string) error {
+	jl.mu.Lock()
+	jl.sessionID = sessionID
+	if jl.logDir != "" {
+		name := fmt.Sprintf("%s_%s.ndjson", sessionID, time.Now().Format("2006-01-02_15-04-05"))
+		file, err := os.OpenFile(filepath.Join(jl.logDir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			jl.mu.Unlock()
+			return err
+		}
+		jl.file = file
+		jl.writer = bufio.NewWriter(file)
+	}
+	jl.mu.Unlock()
+
+	return jl.write(JSONEventSessionStart, stamp(jl.clock), nil)
+}
+
+// EndSession writes a session_end event and closes the session's file
+func (jl *JSONLogger) EndSession(sessionID string) error {
+	if err := jl.write(JSONEventSessionEnd, stamp(jl.clock), map[string]interface{}{
+		"entries_logged": jl.entriesLogged,
+	}); err != nil {
+		return err
+	}
+	return jl.Close()
+}
+
+// GetSessionID returns current session ID
+func (jl *JSONLogger) GetSessionID() string {
+	return jl.sessionID
+}
+
+// ==================== LOGGING METHODS ====================
+
+// LogTick logs a market tick (verbose only, ticks dominate the volume)
+func (jl *JSONLogger) LogTick(tick *types.Tick) error {
+	if jl.verbosity < VerbosityVerbose {
+		return nil
+	}
+
+	return jl.write(JSONEventTick, tick.Timestamp, map[string]interface{}{
+		"symbol":      tick.Symbol,
+		"sequence":    tick.Sequence,
+		"bid":         tick.Bid,
+		"ask":         tick.Ask,
+		"bid_qty":     tick.BidQty,
+		"ask_qty":     tick.AskQty,
+		"last_price":  tick.LastPrice,
+		"volume":      tick.Volume,
+		"spread_pips": tick.SpreadPips,
+	})
+}
+
+// LogTrade logs a trade entry
+func (jl *JSONLogger) LogTrade(trade *TradeLog) error {
+	if jl.verbosity < VerbosityMinimal {
+		return nil
+	}
+
+	return jl.write(JSONEventTrade, trade.Timestamp, map[string]interface{}{
+		"trade_id":       trade.TradeID,
+		"order_id":       trade.OrderID,
+		"instrument":     trade.Instrument,
+		"action":         trade.Action,
+		"order_type":     trade.OrderType,
+		"requested_size": trade.RequestedSize,
+		"filled_size":    trade.FilledSize,
+		"fill_price":     trade.FillPrice,
+		"commission":     trade.Commission,
+		"slippage":       trade.Slippage,
+		"realized_pnl":   trade.RealizedPnL,
+		"mfe":            trade.MFE,
+		"mae":            trade.MAE,
+		"status":         trade.Status,
+		"error_message":  trade.ErrorMessage,
+		"entry_price":    trade.EntryPrice,
+		"current_price":  trade.CurrentPrice,
+		"position_size":  trade.PositionSize,
+		"position_value": trade.PositionValue,
+		"unrealized_pnl": trade.UnrealizedPnL,
+	})
+}
+
+// LogError logs an error entry
+func (jl *JSONLogger) LogError(errLog *ErrorLog) error {
+	if jl.verbosity < VerbosityMinimal {
+		return nil
+	}
+
+	return jl.write(JSONEventError, errLog.Timestamp, map[string]interface{}{
+		"severity":   errLog.Severity.String(),
+		"error_code": errLog.ErrorCode,
+		"error_type": errLog.ErrorType,
+		"message":    errLog.Message,
+		"details":    errLog.Details,
+		"trade_id":   errLog.TradeID,
+		"order_id":   errLog.OrderID,
+	})
+}
+
+// LogMetrics logs periodic metrics
+func (jl *JSONLogger) LogMetrics(metrics *MetricsLog) error {
+	if jl.verbosity < VerbosityNormal {
+		return nil
+	}
+
+	return jl.write(JSONEventMetrics, metrics.Timestamp, map[string]interface{}{
+		"session_duration_ms":  metrics.SessionDuration.Milliseconds(),
+		"initial_balance":      metrics.InitialBalance,
+		"current_balance":      metrics.CurrentBalance,
+		"total_pnl":            metrics.TotalPnL,
+		"total_pnl_percent":    metrics.TotalPnLPercent,
+		"trade_count":          metrics.TradeCount,
+		"winning_trades":       metrics.WinningTrades,
+		"losing_trades":        metrics.LosingTrades,
+		"breakeven_trades":     metrics.BreakevenTrades,
+		"win_rate":             metrics.WinRate,
+		"max_drawdown":         metrics.MaxDrawdown,
+		"max_drawdown_percent": metrics.MaxDrawdownPercent,
+		"commission_total":     metrics.CommissionTotal,
+		"slippage_total":       metrics.SlippageTotal,
+		"average_trade_pnl":    metrics.AverageTradePnL,
+		"largest_win":          metrics.LargestWin,
+		"largest_loss":         metrics.LargestLoss,
+		"profit_factor":        metrics.ProfitFactor,
+		"sharpe_ratio":         metrics.SharpeRatio,
+		"sortino_ratio":        metrics.SortinoRatio,
+		"expectancy":           metrics.Expectancy,
+		"kelly_fraction":       metrics.KellyFraction,
+		"var_95":               metrics.VaR95,
+		"cvar_95":              metrics.CVaR95,
+		"var_99":               metrics.VaR99,
+		"cvar_99":              metrics.CVaR99,
+		"ticks_processed":      metrics.TicksProcessed,
+		"error_count":          metrics.ErrorCount,
+		"rejected_orders":      metrics.RejectedOrders,
+	})
+}
+
+// LogInfo logs informational message
+func (jl *JSONLogger) LogInfo(message string) error {
+	if jl.verbosity < VerbosityVerbose {
+		return nil
+	}
+	return jl.write(JSONEventInfo, stamp(jl.clock), map[string]interface{}{"message": message})
+}
+
+// LogWarning logs a warning message
+func (jl *JSONLogger) LogWarning(message string) error {
+	if jl.verbosity < VerbosityMinimal {
+		return nil
+	}
+	return jl.write(JSONEventWarning, stamp(jl.clock), map[string]interface{}{"message": message})
+}
+
+// LogDebug logs a debug message
+func (jl *JSONLogger) LogDebug(message string) error {
+	if jl.verbosity < VerbosityDebug {
+		return nil
+	}
+	return jl.write(JSONEventDebug, stamp(jl.clock), map[string]interface{}{"message": message})
+}
+
+// write encodes one event line
+// Fields may not override ts, event or session_id
+func (jl *JSONLogger) write(event string, timestamp time.Time, fields map[string]interface{}) error {
+	jl.mu.Lock()
+	defer jl.mu.Unlock()
+
+	if jl.writer == nil {
+		return fmt.Errorf("json log not initialized")
+	}
+
+	line := make(map[string]interface{}, len(fields)+3)
+	for k, v := range fields {
+		line[k] = v
+	}
+	line["ts"] = timestamp.UTC().Format(time.RFC3339Nano)
+	line["event"] = event
+	line["session_id"] = jl.sessionID
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	if _, err := jl.writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if jl.autoFlush {
+		if err := jl.writer.Flush(); err != nil {
+			return err
+		}
+	}
+
+	jl.entriesLogged++
+	return nil
+}
+
+// ==================== CONTROL METHODS ====================
+
+// SetVerbosity sets the verbosity level
+func (jl *JSONLogger) SetVerbosity(level VerbosityLevel) error {
+	jl.verbosity = level
+	return nil
+}
+
+// SetClock stamps messages and session events from clock instead of the
+// wall clock
+func (jl *JSONLogger) SetClock(clock types.Clock) {
+	jl.clock = clock
+}
+
+// Flush writes buffered lines out
+func (jl *JSONLogger) Flush() error {
+	jl.mu.Lock()
+	defer jl.mu.Unlock()
+
+	jl.lastFlush = time.Now()
+	if jl.writer == nil {
+		return nil
+	}
+	if err := jl.writer.Flush(); err != nil {
+		return err
+	}
+	if jl.file != nil {
+		return jl.file.Sync()
+	}
+	return nil
+}
+
+// Close flushes and closes the session's file (streams are left open)
+func (jl *JSONLogger) Close() error {
+	if err := jl.Flush(); err != nil {
+		return err
+	}
+
+	jl.mu.Lock()
+	defer jl.mu.Unlock()
+
+	if jl.file == nil {
+		return nil
+	}
+	err := jl.file.Close()
+	jl.file = nil
+	jl.writer = nil
+	return err
+}
+
+// ==================== STATISTICS ====================
+
+// GetStatistics returns logger statistics
+func (jl *JSONLogger) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"entries_logged": jl.entriesLogged,
+		"last_flush":     jl.lastFlush,
+		"verbosity":      jl.verbosity.String(),
+		"session_id":     jl.sessionID,
+		"uptime":         time.Since(jl.createdTime),
+	}
+}
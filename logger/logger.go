@@ -29,6 +29,24 @@ type Logger interface {
 	Close() error
 }
 
+// Clocked is implemented by loggers that can stamp log lines and session
+// start/end times from a types.Clock instead of the wall clock (e.g. a
+// types.ManualClock, for logs that compare equal across runs)
+type Clocked interface {
+	SetClock(clock types.Clock)
+}
+
+// stamp returns the clock's time, or the wall clock's if clock is nil or
+// has not started
+func stamp(clock types.Clock) time.Time {
+	if clock != nil {
+		if now := clock.Now(); !now.IsZero() {
+			return now
+		}
+	}
+	return time.Now()
+}
+
 // ==================== VERBOSITY LEVELS ====================
 
 // VerbosityLevel defines logging verbosity
@@ -76,7 +94,9 @@ type TradeLog struct {
 	Commission    float64
 	Slippage      float64
 	RealizedPnL   float64
-	Status        string // FILLED, PARTIAL, REJECTED
+	MFE           float64 // Max favorable excursion of the closed size
+	MAE           float64 // Max adverse excursion of the closed size (<= 0)
+	Status        string  // FILLED, PARTIAL, REJECTED
 	ErrorMessage  string
 	EntryPrice    float64
 	CurrentPrice  float64
@@ -140,6 +160,7 @@ type MetricsLog struct {
 	TradeCount         int64
 	WinningTrades      int64
 	LosingTrades       int64
+	BreakevenTrades    int64 // P&L inside the break-even band
 	WinRate            float64
 	MaxDrawdown        float64
 	MaxDrawdownPercent float64
@@ -152,6 +173,19 @@ type MetricsLog struct {
 	MeanLoss           float64
 	ProfitFactor       float64
 	SharpeRatio        float64
+	SortinoRatio       float64 // Mean return over downside deviation
+	Expectancy         float64 // Expected P&L per decided trade
+	ExpectancyR        float64 // Expectancy in units of the average loss
+	ExpectancyStdError float64 // Standard error of the expectancy
+	KellyFraction      float64 // Kelly fraction of capital to risk per trade
+	PnLSkewness        float64 // Skewness of decided trades' P&L
+	PnLKurtosis        float64 // Excess kurtosis of decided trades' P&L
+	AvgWinStreak       float64 // Mean winning streak length
+	AvgLossStreak      float64 // Mean losing streak length
+	VaR95              float64 // Daily 95% Value-at-Risk, percent of equity
+	CVaR95             float64 // Daily 95% Conditional VaR, percent of equity
+	VaR99              float64 // Daily 99% Value-at-Risk, percent of equity
+	CVaR99             float64 // Daily 99% Conditional VaR, percent of equity
 	MDD                float64 // Maximum Drawdown
 	MWL                int64   // Maximum Winning Streak Length
 	MLS                int64   // Maximum Losing Streak Length
@@ -321,6 +355,8 @@ func NewTradeLog(
 		Commission:    report.Commission,
 		Slippage:      report.SlippageUnits,
 		RealizedPnL:   report.RealizedPnL,
+		MFE:           report.MaxFavorableExcursion,
+		MAE:           report.MaxAdverseExcursion,
 		Status:        report.Status,
 		ErrorMessage:  report.ErrorMessage,
 	}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math"
 	"time"
+
+	"holodeck/types"
 )
 
 // ==================== METRICS CALCULATOR ====================
@@ -13,6 +15,8 @@ type MetricsCalculator struct {
 	initialBalance float64
 	tradeLogger    *TradeLogger
 	startTime      time.Time
+	sharpe         types.SharpeConfig
+	equityCurve    *types.EquityCurve
 }
 
 // ==================== CREATION ====================
@@ -26,6 +30,20 @@ func NewMetricsCalculator(initialBalance float64, tradeLogger *TradeLogger) *Met
 	}
 }
 
+// SetSharpeConfig sets the Sharpe ratio's return period, risk-free rate
+// and annualization
+func (mc *MetricsCalculator) SetSharpeConfig(config types.SharpeConfig) *MetricsCalculator {
+	mc.sharpe = config
+	return mc
+}
+
+// SetEquityCurve sets the sampled equity curve the Sharpe ratio is
+// computed from; without one the curve is rebuilt from the trade log
+func (mc *MetricsCalculator) SetEquityCurve(curve *types.EquityCurve) *MetricsCalculator {
+	mc.equityCurve = curve
+	return mc
+}
+
 // ==================== CALCULATION METHODS ====================
 
 // CalculateMetrics calculates all metrics and returns MetricsLog
@@ -48,6 +66,7 @@ func (mc *MetricsCalculator) CalculateMetrics(
 
 	winningTrades := mc.tradeLogger.GetWinningTrades()
 	losingTrades := mc.tradeLogger.GetLosingTrades()
+	breakevenTrades := mc.tradeLogger.GetBreakEvenTrades()
 
 	winRate := 0.0
 	if totalTrades > 0 {
@@ -63,8 +82,13 @@ func (mc *MetricsCalculator) CalculateMetrics(
 	meanWin := ratios["average_win"]
 	meanLoss := ratios["average_loss"]
 	profitFactor := mc.tradeLogger.GetProfitFactor()
+	distribution := mc.tradeLogger.GetPnLDistribution()
+	streaks := mc.tradeLogger.GetStreakDistribution()
 
 	sharpeRatio := mc.CalculateSharpeRatio()
+	sortinoRatio := mc.CalculateSortinoRatio()
+	var95 := mc.CalculateValueAtRisk(95)
+	var99 := mc.CalculateValueAtRisk(99)
 	avgHoldTime := mc.CalculateAverageHoldTime()
 
 	commissionTotal := mc.CalculateTotalCommission()
@@ -81,6 +105,7 @@ func (mc *MetricsCalculator) CalculateMetrics(
 		TradeCount:         totalTrades,
 		WinningTrades:      winningTrades,
 		LosingTrades:       losingTrades,
+		BreakevenTrades:    breakevenTrades,
 		WinRate:            winRate,
 		MaxDrawdown:        maxDrawdown,
 		MaxDrawdownPercent: maxDrawdownPercent,
@@ -93,6 +118,19 @@ func (mc *MetricsCalculator) CalculateMetrics(
 		MeanLoss:           meanLoss,
 		ProfitFactor:       profitFactor,
 		SharpeRatio:        sharpeRatio,
+		SortinoRatio:       sortinoRatio,
+		Expectancy:         mc.tradeLogger.GetExpectancy(),
+		ExpectancyR:        mc.tradeLogger.GetExpectancyR(),
+		ExpectancyStdError: mc.tradeLogger.GetExpectancyStandardError(),
+		KellyFraction:      mc.tradeLogger.GetKellyFraction(),
+		PnLSkewness:        distribution.Skewness,
+		PnLKurtosis:        distribution.Kurtosis,
+		AvgWinStreak:       streaks.AverageWin,
+		AvgLossStreak:      streaks.AverageLoss,
+		VaR95:              var95.VaR,
+		CVaR95:             var95.CVaR,
+		VaR99:              var99.VaR,
+		CVaR99:             var99.CVaR,
 		MDD:                maxDrawdown,
 		MWL:                mc.tradeLogger.GetMaxWinStreak(),
 		MLS:                mc.tradeLogger.GetMaxLoseStreak(),
@@ -177,42 +215,82 @@ func (mc *MetricsCalculator) CalculateTotalSlippage() float64 {
 	return totalSlippage
 }
 
-// CalculateSharpeRatio calculates Sharpe ratio
+// CalculateSharpeRatio calculates the annualized Sharpe ratio of the
+// equity curve's period returns over the configured risk-free rate
 func (mc *MetricsCalculator) CalculateSharpeRatio() float64 {
+	curve := mc.equityCurve
+	if curve == nil {
+		curve = mc.tradeEquityCurve()
+	}
+	return curve.GetSharpeRatio(mc.sharpe)
+}
+
+// CalculateValueAtRisk estimates the daily VaR and CVaR at a confidence
+// level (percent) from the equity curve's daily returns
+func (mc *MetricsCalculator) CalculateValueAtRisk(confidence float64) types.ValueAtRisk {
+	curve := mc.equityCurve
+	if curve == nil {
+		curve = mc.tradeEquityCurve()
+	}
+	return curve.GetValueAtRisk(confidence)
+}
+
+// tradeEquityCurve rebuilds realized equity from the trade log, sampled
+// at each trade's timestamp
+func (mc *MetricsCalculator) tradeEquityCurve() *types.EquityCurve {
+	curve := types.NewEquityCurve(0)
 	trades := mc.tradeLogger.GetTrades()
-	if len(trades) < 2 {
-		return 0
+	if len(trades) == 0 {
+		return curve
 	}
 
-	// Calculate returns
-	returns := make([]float64, len(trades))
-	for i, trade := range trades {
-		if mc.initialBalance > 0 {
-			returns[i] = trade.RealizedPnL / mc.initialBalance
-		}
+	equity := mc.initialBalance
+	curve.Record(trades[0].Timestamp, equity, 0)
+	for _, trade := range trades {
+		equity += trade.RealizedPnL
+		curve.Record(trade.Timestamp, equity, 0)
+	}
+	return curve
+}
+
+// CalculateSortinoRatio calculates the Sortino ratio: mean return over
+// downside deviation, so only losing returns count as risk
+// Returns 0 when no trade lost (the ratio is unbounded)
+func (mc *MetricsCalculator) CalculateSortinoRatio() float64 {
+	returns := mc.tradeReturns()
+	if len(returns) < 2 {
+		return 0
 	}
 
-	// Calculate mean return
 	meanReturn := 0.0
+	downside := 0.0
 	for _, r := range returns {
 		meanReturn += r
+		if r < 0 {
+			downside += r * r
+		}
 	}
 	meanReturn /= float64(len(returns))
 
-	// Calculate standard deviation
-	variance := 0.0
-	for _, r := range returns {
-		diff := r - meanReturn
-		variance += diff * diff
+	// Downside deviation below a 0% target, over all returns
+	downsideDev := math.Sqrt(downside / float64(len(returns)))
+	if downsideDev > 0 {
+		return meanReturn / downsideDev
 	}
-	variance /= float64(len(returns))
-	stdDev := math.Sqrt(variance)
+	return 0
+}
 
-	// Sharpe ratio = mean return / std dev (assuming risk-free rate = 0)
-	if stdDev > 0 {
-		return meanReturn / stdDev
+// tradeReturns returns each trade's realized P&L as a fraction of the
+// initial balance
+func (mc *MetricsCalculator) tradeReturns() []float64 {
+	trades := mc.tradeLogger.GetTrades()
+	returns := make([]float64, len(trades))
+	for i, trade := range trades {
+		if mc.initialBalance > 0 {
+			returns[i] = trade.RealizedPnL / mc.initialBalance
+		}
 	}
-	return 0
+	return returns
 }
 
 // CalculateAverageHoldTime calculates average holding time per trade
@@ -296,20 +374,45 @@ func (mc *MetricsCalculator) CalculateRecoveryFactor(finalBalance float64) float
 func (mc *MetricsCalculator) GetMetricsString(finalBalance float64) string {
 	maxDrawdown, maxDrawdownPct := mc.CalculateMaxDrawdown()
 	sharpeRatio := mc.CalculateSharpeRatio()
+	sortinoRatio := mc.CalculateSortinoRatio()
 	riskRewardRatio := mc.CalculateRiskRewardRatio()
 	recoveryFactor := mc.CalculateRecoveryFactor(finalBalance)
 	cumulativeReturn := mc.CalculateCumulativeReturn(finalBalance)
+	distribution := mc.tradeLogger.GetPnLDistribution()
+	streaks := mc.tradeLogger.GetStreakDistribution()
+	var95 := mc.CalculateValueAtRisk(95)
+	var99 := mc.CalculateValueAtRisk(99)
 
 	return fmt.Sprintf(
 		"=== PERFORMANCE METRICS ===\n"+
 			"Cumulative Return:      %.2f%%\n"+
 			"Sharpe Ratio:           %.2f\n"+
+			"Sortino Ratio:          %.2f\n"+
+			"Expectancy:             $%.2f (%.2fR, SE %.2f)\n"+
+			"Kelly Fraction:         %.2f\n"+
+			"P&L Skew / Kurtosis:    %.2f / %.2f\n"+
+			"Avg Win / Loss Streak:  %.1f / %.1f\n"+
+			"Daily VaR 95%% / 99%%:    %.2f%% / %.2f%%\n"+
+			"Daily CVaR 95%% / 99%%:   %.2f%% / %.2f%%\n"+
 			"Max Drawdown:           $%.2f (%.2f%%)\n"+
 			"Risk/Reward Ratio:      %.2f\n"+
 			"Recovery Factor:        %.2f\n"+
 			"Session Duration:       %v\n",
 		cumulativeReturn,
 		sharpeRatio,
+		sortinoRatio,
+		mc.tradeLogger.GetExpectancy(),
+		mc.tradeLogger.GetExpectancyR(),
+		mc.tradeLogger.GetExpectancyStandardError(),
+		mc.tradeLogger.GetKellyFraction(),
+		distribution.Skewness,
+		distribution.Kurtosis,
+		streaks.AverageWin,
+		streaks.AverageLoss,
+		var95.VaR,
+		var99.VaR,
+		var95.CVaR,
+		var99.CVaR,
 		maxDrawdown,
 		maxDrawdownPct,
 		riskRewardRatio,
@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"sync"
+
+	"holodeck/types"
+)
+
+// ==================== MULTI LOGGER ====================
+
+// MultiLogger fans every call out to its child loggers, e.g. a FileLogger,
+// a ConsoleLogger and a JSONLogger at once
+// Every child is called even when one fails; the first error is returned
+type MultiLogger struct {
+	loggers []Logger
+	mu      sync.RWMutex
+}
+
+// NewMultiLogger creates a logger writing to every non-nil logger given
+func NewMultiLogger(loggers ...Logger) *MultiLogger {
+	ml := &MultiLogger{}
+	for _, l := range loggers {
+		ml.Add(l)
+	}
+	return ml
+}
+
+// Add adds a child logger (nil is ignored)
+func (ml *MultiLogger) Add(l Logger) {
+	if l == nil {
+		return
+	}
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+	ml.loggers = append(ml.loggers, l)
+}
+
+// GetLoggers returns the child loggers
+func (ml *MultiLogger) GetLoggers() []Logger {
+	ml.mu.RLock()
+	defer ml.mu.RUnlock()
+	return append([]Logger(nil), ml.loggers...)
+}
+
+// each calls fn on every child and returns the first error
+func (ml *MultiLogger) each(fn func(l Logger) error) error {
+	ml.mu.RLock()
+	defer ml.mu.RUnlock()
+
+	var first error
+	for _, l := range ml.loggers {
+		if err := fn(l); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// ==================== LOGGER INTERFACE ====================
+
+// LogTrade logs a trade to every child
+func (ml *MultiLogger) LogTrade(trade *TradeLog) error {
+	return ml.each(func(l Logger) error { return l.LogTrade(trade) })
+}
+
+// LogError logs an error to every child
+func (ml *MultiLogger) LogError(errLog *ErrorLog) error {
+	return ml.each(func(l Logger) error { return l.LogError(errLog) })
+}
+
+// LogMetrics logs metrics to every child
+func (ml *MultiLogger) LogMetrics(metrics *MetricsLog) error {
+	return ml.each(func(l Logger) error { return l.LogMetrics(metrics) })
+}
+
+// LogInfo logs an informational message to every child
+func (ml *MultiLogger) LogInfo(message string) error {
+	return ml.each(func(l Logger) error { return l.LogInfo(message) })
+}
+
+// LogWarning logs a warning to every child
+func (ml *MultiLogger) LogWarning(message string) error {
+	return ml.each(func(l Logger) error { return l.LogWarning(message) })
+}
+
+// LogDebug logs a debug message to every child
+func (ml *MultiLogger) LogDebug(message string) error {
+	return ml.each(func(l Logger) error { return l.LogDebug(message) })
+}
+
+// LogTick logs a tick to the children that log ticks (e.g. JSONLogger)
+func (ml *MultiLogger) LogTick(tick *types.Tick) error {
+	return ml.each(func(l Logger) error {
+		if tl, ok := l.(interface{ LogTick(*types.Tick) error }); ok {
+			return tl.LogTick(tick)
+		}
+		return nil
+	})
+}
+
+// StartSession starts the session on every child
+func (ml *MultiLogger) StartSession(sessionID string) error {
+	return ml.each(func(l Logger) error { return l.StartSession(sessionID) })
+}
+
+// EndSession ends the session on every child
+func (ml *MultiLogger) EndSession(sessionID string) error {
+	return ml.each(func(l Logger) error { return l.EndSession(sessionID) })
+}
+
+// GetSessionID returns the first child's session ID
+func (ml *MultiLogger) GetSessionID() string {
+	ml.mu.RLock()
+	defer ml.mu.RUnlock()
+	if len(ml.loggers) == 0 {
+		return ""
+	}
+	return ml.loggers[0].GetSessionID()
+}
+
+// SetVerbosity sets the verbosity of every child
+func (ml *MultiLogger) SetVerbosity(level VerbosityLevel) error {
+	return ml.each(func(l Logger) error { return l.SetVerbosity(level) })
+}
+
+// SetClock passes the clock to every child that takes one (see Clocked)
+func (ml *MultiLogger) SetClock(clock types.Clock) {
+	ml.each(func(l Logger) error {
+		if clocked, ok := l.(Clocked); ok {
+			clocked.SetClock(clock)
+		}
+		return nil
+	})
+}
+
+// Flush flushes every child
+func (ml *MultiLogger) Flush() error {
+	return ml.each(func(l Logger) error { return l.Flush() })
+}
+
+// Close closes every child
+func (ml *MultiLogger) Close() error {
+	return ml.each(func(l Logger) error { return l.Close() })
+}
+
+// ==================== STATISTICS ====================
+
+// GetStatistics returns the statistics of the children that report them
+func (ml *MultiLogger) GetStatistics() map[string]interface{} {
+	ml.mu.RLock()
+	defer ml.mu.RUnlock()
+
+	children := make([]map[string]interface{}, 0, len(ml.loggers))
+	for _, l := range ml.loggers {
+		if sl, ok := l.(interface{ GetStatistics() map[string]interface{} }); ok {
+			children = append(children, sl.GetStatistics())
+		}
+	}
+	return map[string]interface{}{
+		"loggers":  len(ml.loggers),
+		"children": children,
+	}
+}
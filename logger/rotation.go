@@ -0,0 +1,200 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ==================== ROTATION CONFIG ====================
+
+// RotationConfig bounds log files by size and age
+// A file is rotated when either limit is reached; zero disables a limit
+type RotationConfig struct {
+	MaxSizeMB   float64 `json:"max_size_mb"`
+	MaxAgeHours float64 `json:"max_age_hours"`
+
+	// MaxBackups is the number of rotated files kept per log (0 keeps all)
+	MaxBackups int `json:"max_backups"`
+
+	// Compress gzips rotated files
+	Compress bool `json:"compress"`
+}
+
+// IsEnabled returns true if a size or age limit is set
+func (rc RotationConfig) IsEnabled() bool {
+	return rc.MaxSizeMB > 0 || rc.MaxAgeHours > 0
+}
+
+// Validate checks the limits
+func (rc RotationConfig) Validate() error {
+	if rc.MaxSizeMB < 0 || rc.MaxAgeHours < 0 {
+		return fmt.Errorf("rotation size and age cannot be negative")
+	}
+	if rc.MaxBackups < 0 {
+		return fmt.Errorf("rotation max_backups cannot be negative")
+	}
+	return nil
+}
+
+// ==================== ROTATING FILE ====================
+
+// rotationTimeFormat suffixes rotated files; it sorts chronologically
+const rotationTimeFormat = "20060102-150405.000"
+
+// rotatingFile is an append-only log file that moves itself aside to
+// <name>.<time>[.gz] when it grows past the size or age limit
+type rotatingFile struct {
+	path   string
+	config RotationConfig
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	rotations int64
+}
+
+// openRotatingFile opens (appending to) a log file
+func openRotatingFile(path string, config RotationConfig) (*rotatingFile, error) {
+	rf := &rotatingFile{path: path, config: config}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// open opens the file and picks up its current size
+func (rf *rotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+	rf.openedAt = time.Now()
+	return nil
+}
+
+// WriteString writes s, rotating first when the file is due
+func (rf *rotatingFile) WriteString(s string) (int, error) {
+	if rf.due(int64(len(s))) {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.WriteString(s)
+	rf.size += int64(n)
+	return n, err
+}
+
+// due returns true if writing n more bytes would pass a limit
+// An empty file is never rotated, so a single oversized entry still lands
+func (rf *rotatingFile) due(n int64) bool {
+	if !rf.config.IsEnabled() || rf.size == 0 {
+		return false
+	}
+	if rf.config.MaxSizeMB > 0 && float64(rf.size+n) > rf.config.MaxSizeMB*1024*1024 {
+		return true
+	}
+	if rf.config.MaxAgeHours > 0 && time.Since(rf.openedAt).Hours() >= rf.config.MaxAgeHours {
+		return true
+	}
+	return false
+}
+
+// rotate moves the current file aside, compresses it if configured,
+// prunes old backups and starts a new file
+func (rf *rotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+
+	backup := fmt.Sprintf("%s.%s", rf.path, time.Now().Format(rotationTimeFormat))
+	if err := os.Rename(rf.path, backup); err != nil {
+		return err
+	}
+	if rf.config.Compress {
+		if err := gzipFile(backup); err != nil {
+			return err
+		}
+	}
+	rf.rotations++
+
+	if err := rf.prune(); err != nil {
+		return err
+	}
+	return rf.open()
+}
+
+// prune removes the oldest backups beyond MaxBackups
+func (rf *rotatingFile) prune() error {
+	if rf.config.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := filepath.Glob(rf.path + ".*")
+	if err != nil {
+		return err
+	}
+	sort.Strings(backups)
+	for len(backups) > rf.config.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// Sync commits the file to disk
+func (rf *rotatingFile) Sync() error {
+	return rf.file.Sync()
+}
+
+// Close closes the file
+func (rf *rotatingFile) Close() error {
+	return rf.file.Close()
+}
+
+// gzipFile replaces path with path.gz
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	zw.Name = filepath.Base(path)
+	if _, err := io.Copy(zw, src); err != nil {
+		zw.Close()
+		dst.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,262 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== SIMULATOR ADAPTER ====================
+
+// SimulatorAdapter plugs any Logger (FileLogger, JSONLogger, MultiLogger...)
+// into Holodeck.WithLogger by converting the simulator's ticks, orders,
+// execution reports, errors and metric maps into this package's types
+//
+// The session_start and session_stop events the simulator logs start and
+// end the wrapped logger's session, so files are opened and closed with it
+type SimulatorAdapter struct {
+	logger Logger
+
+	// orderTypes remembers each order's type until its execution
+	orderTypes map[string]string
+	mu         sync.Mutex
+
+	ticksLogged  int64
+	sessionEnded bool
+
+	// clock stamps errors (wall clock if nil)
+	clock types.Clock
+}
+
+// NewSimulatorAdapter wraps a logger for Holodeck.WithLogger
+func NewSimulatorAdapter(logger Logger) *SimulatorAdapter {
+	return &SimulatorAdapter{
+		logger:     logger,
+		orderTypes: make(map[string]string),
+	}
+}
+
+// GetLogger returns the wrapped logger
+func (sa *SimulatorAdapter) GetLogger() Logger {
+	return sa.logger
+}
+
+// SetClock stamps errors from clock and passes it to the wrapped logger
+// if it takes one (see Clocked)
+func (sa *SimulatorAdapter) SetClock(clock types.Clock) {
+	sa.clock = clock
+	if clocked, ok := sa.logger.(Clocked); ok {
+		clocked.SetClock(clock)
+	}
+}
+
+// LogTick forwards a tick to loggers that log ticks (e.g. JSONLogger)
+func (sa *SimulatorAdapter) LogTick(tick *types.Tick) {
+	sa.mu.Lock()
+	sa.ticksLogged++
+	sa.mu.Unlock()
+
+	if tl, ok := sa.logger.(interface{ LogTick(*types.Tick) error }); ok {
+		tl.LogTick(tick)
+	}
+}
+
+// LogOrder logs an order as a debug message
+func (sa *SimulatorAdapter) LogOrder(order *types.Order) {
+	sa.mu.Lock()
+	sa.orderTypes[order.OrderID] = order.OrderType
+	sa.mu.Unlock()
+
+	sa.logger.LogDebug(order.String())
+}
+
+// LogExecution logs an execution report as a trade
+func (sa *SimulatorAdapter) LogExecution(exec *types.ExecutionReport) {
+	sa.mu.Lock()
+	orderType := sa.orderTypes[exec.OrderID]
+	delete(sa.orderTypes, exec.OrderID)
+	sa.mu.Unlock()
+
+	trade := NewTradeLogFromExecution(exec)
+	trade.OrderType = orderType
+	sa.logger.LogTrade(trade)
+}
+
+// LogError logs an error, keeping the code of a HolodeckError
+// Critical errors (account blown) are logged as SeverityCritical
+func (sa *SimulatorAdapter) LogError(err error) {
+	errLog := NewErrorLog(err, SeverityError)
+	errLog.Timestamp = stamp(sa.clock)
+	if herr, ok := err.(*types.HolodeckError); ok {
+		errLog.ErrorCode = herr.Code
+		errLog.Message = herr.Message
+		if herr.IsCritical() {
+			errLog.Severity = SeverityCritical
+		}
+	}
+	sa.logger.LogError(errLog)
+}
+
+// LogMetrics converts a metrics map: session_start and session_stop
+// events start and end the session, maps with account figures (other than
+// progress reports) become a MetricsLog, anything else is logged as an
+// info line
+func (sa *SimulatorAdapter) LogMetrics(metrics map[string]interface{}) {
+	sessionID, _ := metrics["session_id"].(string)
+
+	switch metrics["event"] {
+	case "session_start":
+		if sessionID != "" && sa.logger.GetSessionID() != sessionID {
+			sa.logger.StartSession(sessionID)
+			sa.sessionEnded = false
+		}
+	case "session_stop":
+		sa.logInfo(metrics)
+		if sessionID != "" {
+			sa.logger.EndSession(sessionID)
+			sa.sessionEnded = true
+		}
+		return
+	case "progress":
+		sa.logInfo(metrics)
+		return
+	}
+
+	if metricsLog, ok := NewMetricsLogFromMap(metrics); ok {
+		sa.mu.Lock()
+		metricsLog.TicksProcessed = sa.ticksLogged
+		sa.mu.Unlock()
+		sa.logger.LogMetrics(metricsLog)
+		return
+	}
+	sa.logInfo(metrics)
+}
+
+// logInfo logs a map as sorted key=value pairs
+func (sa *SimulatorAdapter) logInfo(metrics map[string]interface{}) {
+	keys := make([]string, 0, len(metrics))
+	for key := range metrics {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		switch metrics[key].(type) {
+		case map[string]interface{}, []interface{}:
+			// Nested statistics do not fit on one line
+		default:
+			pairs = append(pairs, fmt.Sprintf("%s=%v", key, metrics[key]))
+		}
+	}
+	sa.logger.LogInfo(strings.Join(pairs, " "))
+}
+
+// Close closes the wrapped logger unless session_stop already ended it
+func (sa *SimulatorAdapter) Close() error {
+	if sa.sessionEnded {
+		return nil
+	}
+	return sa.logger.Close()
+}
+
+// ==================== CONVERSIONS ====================
+
+// NewTradeLogFromExecution creates a trade log from an execution report,
+// using the report's own symbol and timestamp
+func NewTradeLogFromExecution(exec *types.ExecutionReport) *TradeLog {
+	tradeID := exec.ExecutionID
+	if tradeID == "" {
+		tradeID = exec.OrderID
+	}
+
+	return &TradeLog{
+		Timestamp:     exec.Timestamp,
+		TradeID:       tradeID,
+		OrderID:       exec.OrderID,
+		Instrument:    exec.Symbol,
+		Action:        exec.Action,
+		RequestedSize: exec.RequestedSize,
+		FilledSize:    exec.FilledSize,
+		FillPrice:     exec.FillPrice,
+		Commission:    exec.Commission,
+		Slippage:      exec.SlippageUnits,
+		RealizedPnL:   exec.RealizedPnL,
+		MFE:           exec.MaxFavorableExcursion,
+		MAE:           exec.MaxAdverseExcursion,
+		Status:        exec.Status,
+		ErrorMessage:  exec.ErrorMessage,
+		EntryPrice:    exec.EntryPrice,
+		CurrentPrice:  exec.FillPrice,
+		PositionSize:  exec.PositionAfter,
+		PositionValue: exec.PositionAfter * exec.FillPrice,
+		UnrealizedPnL: exec.UnrealizedPnL,
+	}
+}
+
+// NewMetricsLogFromMap creates a metrics log from a simulator metrics map
+// (e.g. Holodeck.GetMetrics or a rolling_metrics event)
+// Returns false if the map holds none of the account figures
+func NewMetricsLogFromMap(metrics map[string]interface{}) (*MetricsLog, bool) {
+	sessionID, _ := metrics["session_id"].(string)
+	metricsLog := NewMetricsLog(sessionID)
+	found := false
+
+	setFloat := func(key string, dst *float64) {
+		if v, ok := toFloat(metrics[key]); ok {
+			*dst = v
+			found = true
+		}
+	}
+	setInt := func(key string, dst *int64) {
+		if v, ok := toFloat(metrics[key]); ok {
+			*dst = int64(v)
+			found = true
+		}
+	}
+
+	setFloat("initial_balance", &metricsLog.InitialBalance)
+	setFloat("current_balance", &metricsLog.CurrentBalance)
+	setFloat("equity", &metricsLog.CurrentBalance)
+	setFloat("total_pnl", &metricsLog.TotalPnL)
+	setFloat("return_percent", &metricsLog.TotalPnLPercent)
+	setInt("trade_count", &metricsLog.TradeCount)
+	setInt("trades", &metricsLog.TradeCount)
+	setInt("winning_trades", &metricsLog.WinningTrades)
+	setInt("losing_trades", &metricsLog.LosingTrades)
+	setInt("breakeven_trades", &metricsLog.BreakevenTrades)
+	setFloat("win_rate", &metricsLog.WinRate)
+	setFloat("max_drawdown_percent", &metricsLog.MaxDrawdownPercent)
+	setFloat("commission_paid", &metricsLog.CommissionTotal)
+	setFloat("profit_factor", &metricsLog.ProfitFactor)
+	setFloat("sharpe_ratio", &metricsLog.SharpeRatio)
+
+	if d, ok := metrics["session_duration"].(time.Duration); ok {
+		metricsLog.SessionDuration = d
+	}
+	if ts, ok := metrics["timestamp"].(time.Time); ok {
+		metricsLog.Timestamp = ts
+	}
+	return metricsLog, found
+}
+
+// toFloat converts a numeric map value
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	}
+	return 0, false
+}
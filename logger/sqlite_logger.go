@@ -0,0 +1,415 @@
+package logger
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== SQLITE LOGGER ====================
+
+// sqliteBatchSize is the number of rows written per transaction
+const sqliteBatchSize = 500
+
+// sqliteTimeFormat stores timestamps in UTC in the format SQLite's date
+// functions read, so they sort and compare as text
+const sqliteTimeFormat = "2006-01-02 15:04:05.000000"
+
+// sqliteSchema creates the tables and their session/timestamp indexes
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS sessions (
+		session_id TEXT PRIMARY KEY,
+		started_at TEXT NOT NULL,
+		ended_at   TEXT
+	)`,
+	`CREATE TABLE IF NOT EXISTS trades (
+		id             INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id     TEXT NOT NULL,
+		ts             TEXT NOT NULL,
+		trade_id       TEXT,
+		order_id       TEXT,
+		instrument     TEXT,
+		action         TEXT,
+		order_type     TEXT,
+		requested_size REAL,
+		filled_size    REAL,
+		fill_price     REAL,
+		commission     REAL,
+		slippage       REAL,
+		realized_pnl   REAL,
+		mfe            REAL,
+		mae            REAL,
+		status         TEXT,
+		error_message  TEXT,
+		entry_price    REAL,
+		current_price  REAL,
+		position_size  REAL,
+		position_value REAL,
+		unrealized_pnl REAL,
+		hold_seconds   REAL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_trades_session_ts ON trades (session_id, ts)`,
+	`CREATE INDEX IF NOT EXISTS idx_trades_ts ON trades (ts)`,
+	`CREATE TABLE IF NOT EXISTS errors (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		ts         TEXT NOT NULL,
+		severity   TEXT,
+		error_code TEXT,
+		error_type TEXT,
+		message    TEXT,
+		details    TEXT,
+		trade_id   TEXT,
+		order_id   TEXT
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_errors_session_ts ON errors (session_id, ts)`,
+	`CREATE INDEX IF NOT EXISTS idx_errors_ts ON errors (ts)`,
+	`CREATE TABLE IF NOT EXISTS metrics (
+		id                   INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id           TEXT NOT NULL,
+		ts                   TEXT NOT NULL,
+		initial_balance      REAL,
+		current_balance      REAL,
+		total_pnl            REAL,
+		total_pnl_percent    REAL,
+		trade_count          INTEGER,
+		winning_trades       INTEGER,
+		losing_trades        INTEGER,
+		breakeven_trades     INTEGER,
+		win_rate             REAL,
+		max_drawdown_percent REAL,
+		commission_total     REAL,
+		profit_factor        REAL,
+		sharpe_ratio         REAL,
+		sortino_ratio        REAL,
+		expectancy           REAL,
+		ticks_processed      INTEGER,
+		error_count          INTEGER
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_metrics_session_ts ON metrics (session_id, ts)`,
+	`CREATE TABLE IF NOT EXISTS messages (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		ts         TEXT NOT NULL,
+		level      TEXT,
+		message    TEXT
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_messages_session_ts ON messages (session_id, ts)`,
+}
+
+// SQLiteLogger implements Logger by writing trades, errors, metrics
+// snapshots and messages into SQLite tables keyed by session_id and ts,
+// e.g. losing trades held over an hour:
+//
+//	SELECT * FROM trades WHERE realized_pnl < 0 AND hold_seconds > 3600
+//
+// Holodeck has no driver dependency: import one (e.g. modernc.org/sqlite
+// as "sqlite" or github.com/mattn/go-sqlite3 as "sqlite3") and pass its name
+// Rows are written in transactions of sqliteBatchSize, committed on Flush
+type SQLiteLogger struct {
+	db        *sql.DB
+	ownsDB    bool
+	sessionID string
+	verbosity VerbosityLevel
+	clock     types.Clock // Stamps messages and session rows
+
+	tx      *sql.Tx
+	pending int
+	mu      sync.Mutex
+
+	// positions tracks each instrument's open position for hold_seconds
+	positions map[string]sqlitePosition
+
+	// Statistics
+	entriesLogged int64
+	lastFlush     time.Time
+	createdTime   time.Time
+}
+
+// sqlitePosition is an open position and when it was opened
+type sqlitePosition struct {
+	openedAt time.Time
+	size     float64
+}
+
+// ==================== CREATION ====================
+
+// NewSQLiteLogger opens (creating if needed) a SQLite file with a
+// registered driver and creates the tables
+func NewSQLiteLogger(driverName, path string) (*SQLiteLogger, error) {
+	db, err := sql.Open(driverName, path)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite log %s: %w", path, err)
+	}
+
+	sl, err := NewSQLiteLoggerFromDB(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	sl.ownsDB = true
+	return sl, nil
+}
+
+// NewSQLiteLoggerFromDB creates the tables in an open database
+// Closing the logger does not close db
+func NewSQLiteLoggerFromDB(db *sql.DB) (*SQLiteLogger, error) {
+	for _, statement := range sqliteSchema {
+		if _, err := db.Exec(statement); err != nil {
+			return nil, fmt.Errorf("create sqlite log schema: %w", err)
+		}
+	}
+
+	return &SQLiteLogger{
+		db:          db,
+		verbosity:   VerbosityNormal,
+		positions:   make(map[string]sqlitePosition),
+		lastFlush:   time.Now(),
+		createdTime: time.Now(),
+	}, nil
+}
+
+// GetDB returns the database, e.g. for queries after the run
+func (sl *SQLiteLogger) GetDB() *sql.DB {
+	return sl.db
+}
+
+// ==================== SESSION MANAGEMENT ====================
+
+// StartSession records the session
+func (sl *SQLiteLogger) StartSession(sessionID string) error {
+	sl.mu.Lock()
+	sl.sessionID = sessionID
+	sl.positions = make(map[string]sqlitePosition)
+	sl.mu.Unlock()
+
+	return sl.insert(
+		`INSERT OR REPLACE INTO sessions (session_id, started_at) VALUES (?, ?)`,
+		sessionID, sqliteTime(stamp(sl.clock)),
+	)
+}
+
+// EndSession records the session's end and commits pending rows
+func (sl *SQLiteLogger) EndSession(sessionID string) error {
+	if err := sl.insert(
+		`UPDATE sessions SET ended_at = ? WHERE session_id = ?`,
+		sqliteTime(stamp(sl.clock)), sessionID,
+	); err != nil {
+		return err
+	}
+	return sl.Flush()
+}
+
+// GetSessionID returns current session ID
+func (sl *SQLiteLogger) GetSessionID() string {
+	return sl.sessionID
+}
+
+// ==================== LOGGING METHODS ====================
+
+// LogTrade logs a trade row
+// hold_seconds is set on trades that reduce, close or flip a position,
+// measured from the trade that opened it
+func (sl *SQLiteLogger) LogTrade(trade *TradeLog) error {
+	if sl.verbosity < VerbosityMinimal {
+		return nil
+	}
+
+	var holdSeconds float64
+	if trade.FilledSize > 0 {
+		sl.mu.Lock()
+		prev := sl.positions[trade.Instrument]
+		flipped := prev.size*trade.PositionSize < 0
+		if prev.size != 0 && (flipped || math.Abs(trade.PositionSize) < math.Abs(prev.size)) {
+			holdSeconds = trade.Timestamp.Sub(prev.openedAt).Seconds()
+		}
+		switch {
+		case trade.PositionSize == 0:
+			delete(sl.positions, trade.Instrument)
+		case prev.size == 0 || flipped:
+			sl.positions[trade.Instrument] = sqlitePosition{openedAt: trade.Timestamp, size: trade.PositionSize}
+		default:
+			sl.positions[trade.Instrument] = sqlitePosition{openedAt: prev.openedAt, size: trade.PositionSize}
+		}
+		sl.mu.Unlock()
+	}
+
+	return sl.insert(
+		`INSERT INTO trades (session_id, ts, trade_id, order_id, instrument, action, order_type,
+			requested_size, filled_size, fill_price, commission, slippage, realized_pnl, mfe, mae,
+			status, error_message, entry_price, current_price, position_size, position_value,
+			unrealized_pnl, hold_seconds)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sl.sessionID, sqliteTime(trade.Timestamp), trade.TradeID, trade.OrderID,
+		trade.Instrument, trade.Action, trade.OrderType,
+		trade.RequestedSize, trade.FilledSize, trade.FillPrice, trade.Commission,
+		trade.Slippage, trade.RealizedPnL, trade.MFE, trade.MAE,
+		trade.Status, trade.ErrorMessage, trade.EntryPrice, trade.CurrentPrice,
+		trade.PositionSize, trade.PositionValue, trade.UnrealizedPnL, holdSeconds,
+	)
+}
+
+// LogError logs an error row
+func (sl *SQLiteLogger) LogError(errLog *ErrorLog) error {
+	if sl.verbosity < VerbosityMinimal {
+		return nil
+	}
+
+	return sl.insert(
+		`INSERT INTO errors (session_id, ts, severity, error_code, error_type, message,
+			details, trade_id, order_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sl.sessionID, sqliteTime(errLog.Timestamp), errLog.Severity.String(),
+		errLog.ErrorCode, errLog.ErrorType, errLog.Message, errLog.Details,
+		errLog.TradeID, errLog.OrderID,
+	)
+}
+
+// LogMetrics logs a metrics snapshot row
+func (sl *SQLiteLogger) LogMetrics(metrics *MetricsLog) error {
+	if sl.verbosity < VerbosityNormal {
+		return nil
+	}
+
+	return sl.insert(
+		`INSERT INTO metrics (session_id, ts, initial_balance, current_balance, total_pnl,
+			total_pnl_percent, trade_count, winning_trades, losing_trades, breakeven_trades,
+			win_rate, max_drawdown_percent, commission_total, profit_factor, sharpe_ratio,
+			sortino_ratio, expectancy, ticks_processed, error_count)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sl.sessionID, sqliteTime(metrics.Timestamp), metrics.InitialBalance,
+		metrics.CurrentBalance, metrics.TotalPnL, metrics.TotalPnLPercent,
+		metrics.TradeCount, metrics.WinningTrades, metrics.LosingTrades,
+		metrics.BreakevenTrades, metrics.WinRate, metrics.MaxDrawdownPercent,
+		metrics.CommissionTotal, metrics.ProfitFactor, metrics.SharpeRatio,
+		metrics.SortinoRatio, metrics.Expectancy, metrics.TicksProcessed,
+		metrics.ErrorCount,
+	)
+}
+
+// LogInfo logs informational message
+func (sl *SQLiteLogger) LogInfo(message string) error {
+	if sl.verbosity < VerbosityVerbose {
+		return nil
+	}
+	return sl.logMessage(LevelInfo, message)
+}
+
+// LogWarning logs a warning message
+func (sl *SQLiteLogger) LogWarning(message string) error {
+	if sl.verbosity < VerbosityMinimal {
+		return nil
+	}
+	return sl.logMessage(LevelWarning, message)
+}
+
+// LogDebug logs a debug message
+func (sl *SQLiteLogger) LogDebug(message string) error {
+	if sl.verbosity < VerbosityDebug {
+		return nil
+	}
+	return sl.logMessage(LevelDebug, message)
+}
+
+// logMessage logs a message row
+func (sl *SQLiteLogger) logMessage(level LogLevel, message string) error {
+	return sl.insert(
+		`INSERT INTO messages (session_id, ts, level, message) VALUES (?, ?, ?, ?)`,
+		sl.sessionID, sqliteTime(stamp(sl.clock)), level.String(), message,
+	)
+}
+
+// insert executes a statement in the current batch, committing the batch
+// when it is full
+func (sl *SQLiteLogger) insert(query string, args ...interface{}) error {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	if sl.tx == nil {
+		tx, err := sl.db.Begin()
+		if err != nil {
+			return err
+		}
+		sl.tx = tx
+	}
+
+	if _, err := sl.tx.Exec(query, args...); err != nil {
+		return err
+	}
+	sl.entriesLogged++
+	sl.pending++
+
+	if sl.pending >= sqliteBatchSize {
+		return sl.commit()
+	}
+	return nil
+}
+
+// commit commits the current batch (caller holds mu)
+func (sl *SQLiteLogger) commit() error {
+	if sl.tx == nil {
+		return nil
+	}
+	err := sl.tx.Commit()
+	sl.tx = nil
+	sl.pending = 0
+	return err
+}
+
+// sqliteTime formats a timestamp for storage
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeFormat)
+}
+
+// ==================== CONTROL METHODS ====================
+
+// SetVerbosity sets the verbosity level
+func (sl *SQLiteLogger) SetVerbosity(level VerbosityLevel) error {
+	sl.verbosity = level
+	return nil
+}
+
+// SetClock stamps messages and session rows from clock instead of the
+// wall clock
+func (sl *SQLiteLogger) SetClock(clock types.Clock) {
+	sl.clock = clock
+}
+
+// Flush commits pending rows
+func (sl *SQLiteLogger) Flush() error {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	sl.lastFlush = time.Now()
+	return sl.commit()
+}
+
+// Close commits pending rows and closes the database if the logger
+// opened it
+func (sl *SQLiteLogger) Close() error {
+	if err := sl.Flush(); err != nil {
+		return err
+	}
+	if !sl.ownsDB {
+		return nil
+	}
+	return sl.db.Close()
+}
+
+// ==================== STATISTICS ====================
+
+// GetStatistics returns logger statistics
+func (sl *SQLiteLogger) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"entries_logged": sl.entriesLogged,
+		"pending_rows":   sl.pending,
+		"last_flush":     sl.lastFlush,
+		"verbosity":      sl.verbosity.String(),
+		"session_id":     sl.sessionID,
+		"uptime":         time.Since(sl.createdTime),
+	}
+}
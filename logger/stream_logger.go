@@ -0,0 +1,278 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==================== STREAM LOGGER ====================
+
+// streamClientBuffer is the number of events queued per client before
+// events are dropped for that client
+const streamClientBuffer = 256
+
+// websocketGUID is the RFC 6455 handshake constant
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// StreamLogger streams the JSONLogger's events to connected clients in
+// real time so dashboards can watch a running simulation
+// TCP clients receive NDJSON lines (ListenTCP); WebSocket clients receive
+// one text message per event (mount the logger as an http.Handler)
+// A slow client never blocks the simulation: its events are dropped
+type StreamLogger struct {
+	*JSONLogger
+
+	hub      *streamHub
+	listener net.Listener
+}
+
+// NewStreamLogger creates a stream logger with no clients
+func NewStreamLogger() *StreamLogger {
+	hub := &streamHub{clients: make(map[*streamClient]bool)}
+
+	jl := NewJSONStreamLogger(hub)
+	jl.autoFlush = true
+
+	return &StreamLogger{
+		JSONLogger: jl,
+		hub:        hub,
+	}
+}
+
+// ListenTCP accepts TCP clients on addr (e.g. ":9000") in the background
+func (sl *StreamLogger) ListenTCP(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	sl.listener = listener
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			sl.hub.add(conn, func(w io.Writer, line []byte) error {
+				_, err := w.Write(append(line, '\n'))
+				return err
+			})
+		}
+	}()
+	return nil
+}
+
+// Addr returns the TCP listener's address, nil before ListenTCP
+func (sl *StreamLogger) Addr() net.Addr {
+	if sl.listener == nil {
+		return nil
+	}
+	return sl.listener.Addr()
+}
+
+// ServeHTTP upgrades the request to a WebSocket and streams events to it
+// Messages sent by the client are read and discarded
+func (sl *StreamLogger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+		http.Error(w, "websocket upgrade required", http.StatusBadRequest)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(sum[:]))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return
+	}
+
+	sl.hub.add(conn, writeWebSocketText)
+}
+
+// writeWebSocketText writes line as one unmasked text frame
+func writeWebSocketText(w io.Writer, line []byte) error {
+	var header []byte
+	switch n := len(line); {
+	case n < 126:
+		header = []byte{0x81, byte(n)}
+	case n <= 0xFFFF:
+		header = []byte{0x81, 126, 0, 0}
+		binary.BigEndian.PutUint16(header[2:], uint16(n))
+	default:
+		header = make([]byte, 10)
+		header[0], header[1] = 0x81, 127
+		binary.BigEndian.PutUint64(header[2:], uint64(n))
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(line)
+	return err
+}
+
+// Close stops accepting clients, disconnects them and flushes the logger
+func (sl *StreamLogger) Close() error {
+	if sl.listener != nil {
+		sl.listener.Close()
+	}
+	sl.hub.closeAll()
+	return sl.JSONLogger.Close()
+}
+
+// GetStatistics returns logger and streaming statistics
+func (sl *StreamLogger) GetStatistics() map[string]interface{} {
+	stats := sl.JSONLogger.GetStatistics()
+	for k, v := range sl.hub.getStatistics() {
+		stats[k] = v
+	}
+	return stats
+}
+
+// ==================== HUB ====================
+
+// streamClient is one connection and its queue of event lines
+type streamClient struct {
+	conn  net.Conn
+	queue chan []byte
+	write func(w io.Writer, line []byte) error
+}
+
+// streamHub is the io.Writer behind the JSONLogger; it splits the output
+// into lines and queues each line to every client
+type streamHub struct {
+	clients map[*streamClient]bool
+	pending []byte
+	mu      sync.Mutex
+
+	connected     int64
+	eventsSent    int64
+	eventsDropped int64
+}
+
+// Write broadcasts every complete line in p
+func (hub *streamHub) Write(p []byte) (int, error) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	hub.pending = append(hub.pending, p...)
+	for {
+		i := bytes.IndexByte(hub.pending, '\n')
+		if i < 0 {
+			break
+		}
+		line := append([]byte(nil), hub.pending[:i]...)
+		hub.pending = hub.pending[i+1:]
+
+		for client := range hub.clients {
+			select {
+			case client.queue <- line:
+				hub.eventsSent++
+			default:
+				hub.eventsDropped++
+			}
+		}
+	}
+	return len(p), nil
+}
+
+// add registers a connection and starts its writer and reader
+func (hub *streamHub) add(conn net.Conn, write func(w io.Writer, line []byte) error) {
+	client := &streamClient{
+		conn:  conn,
+		queue: make(chan []byte, streamClientBuffer),
+		write: write,
+	}
+
+	hub.mu.Lock()
+	hub.clients[client] = true
+	hub.connected++
+	hub.mu.Unlock()
+
+	go hub.writeLoop(client)
+	go func() {
+		// A read error means the client went away
+		io.Copy(io.Discard, conn)
+		hub.remove(client)
+	}()
+}
+
+// writeLoop sends queued lines until the queue is closed or a write fails
+func (hub *streamHub) writeLoop(client *streamClient) {
+	w := bufio.NewWriter(client.conn)
+	for line := range client.queue {
+		client.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+		if err := client.write(w, line); err != nil {
+			break
+		}
+		if len(client.queue) == 0 {
+			if err := w.Flush(); err != nil {
+				break
+			}
+		}
+	}
+	hub.remove(client)
+}
+
+// remove closes a client's connection and queue once
+func (hub *streamHub) remove(client *streamClient) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if !hub.clients[client] {
+		return
+	}
+	delete(hub.clients, client)
+	close(client.queue)
+	client.conn.Close()
+}
+
+// closeAll disconnects every client
+func (hub *streamHub) closeAll() {
+	hub.mu.Lock()
+	clients := make([]*streamClient, 0, len(hub.clients))
+	for client := range hub.clients {
+		clients = append(clients, client)
+	}
+	hub.mu.Unlock()
+
+	for _, client := range clients {
+		hub.remove(client)
+	}
+}
+
+// getStatistics returns client and event counts
+func (hub *streamHub) getStatistics() map[string]interface{} {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	return map[string]interface{}{
+		"clients":           len(hub.clients),
+		"clients_connected": hub.connected,
+		"events_sent":       hub.eventsSent,
+		"events_dropped":    hub.eventsDropped,
+	}
+}
@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ==================== TRADE CSV ====================
+
+// TradeCSVColumns are every TradeLog field, in the default column order
+var TradeCSVColumns = []string{
+	"timestamp", "trade_id", "order_id", "instrument", "action", "order_type",
+	"requested_size", "filled_size", "fill_price", "commission", "slippage",
+	"realized_pnl", "mfe", "mae", "status", "error_message", "entry_price",
+	"current_price", "position_size", "position_value", "unrealized_pnl",
+}
+
+// ValidateTradeCSVColumns checks that every column is a TradeLog field
+func ValidateTradeCSVColumns(columns []string) error {
+	known := make(map[string]bool, len(TradeCSVColumns))
+	for _, column := range TradeCSVColumns {
+		known[column] = true
+	}
+	for _, column := range columns {
+		if !known[column] {
+			return fmt.Errorf("unknown trade CSV column %q", column)
+		}
+	}
+	return nil
+}
+
+// tradeCSVField formats one column of a trade, money columns with
+// moneyDecimals (-1 for full precision)
+func tradeCSVField(trade *TradeLog, column string, moneyDecimals int) string {
+	f64 := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	money := func(v float64) string { return strconv.FormatFloat(v, 'f', moneyDecimals, 64) }
+
+	switch column {
+	case "timestamp":
+		return trade.Timestamp.Format(time.RFC3339Nano)
+	case "trade_id":
+		return trade.TradeID
+	case "order_id":
+		return trade.OrderID
+	case "instrument":
+		return trade.Instrument
+	case "action":
+		return trade.Action
+	case "order_type":
+		return trade.OrderType
+	case "requested_size":
+		return f64(trade.RequestedSize)
+	case "filled_size":
+		return f64(trade.FilledSize)
+	case "fill_price":
+		return f64(trade.FillPrice)
+	case "commission":
+		return money(trade.Commission)
+	case "slippage":
+		return f64(trade.Slippage)
+	case "realized_pnl":
+		return money(trade.RealizedPnL)
+	case "mfe":
+		return money(trade.MFE)
+	case "mae":
+		return money(trade.MAE)
+	case "status":
+		return trade.Status
+	case "error_message":
+		return trade.ErrorMessage
+	case "entry_price":
+		return f64(trade.EntryPrice)
+	case "current_price":
+		return f64(trade.CurrentPrice)
+	case "position_size":
+		return f64(trade.PositionSize)
+	case "position_value":
+		return money(trade.PositionValue)
+	case "unrealized_pnl":
+		return money(trade.UnrealizedPnL)
+	}
+	return ""
+}
+
+// TradeCSVWriter writes one CSV row per trade, for Excel or pandas
+type TradeCSVWriter struct {
+	file    *os.File
+	writer  *csv.Writer
+	columns []string
+
+	// moneyDecimals is the precision for P&L and costs (-1 = full)
+	moneyDecimals int
+}
+
+// NewTradeCSVWriter creates the file and writes the header
+// Empty columns select every TradeCSVColumns column
+func NewTradeCSVWriter(path string, columns []string) (*TradeCSVWriter, error) {
+	if len(columns) == 0 {
+		columns = TradeCSVColumns
+	}
+	if err := ValidateTradeCSVColumns(columns); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tw := &TradeCSVWriter{
+		file:          file,
+		writer:        csv.NewWriter(file),
+		columns:       append([]string(nil), columns...),
+		moneyDecimals: -1,
+	}
+	if err := tw.writer.Write(tw.columns); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return tw, nil
+}
+
+// Write writes a trade's row
+func (tw *TradeCSVWriter) Write(trade *TradeLog) error {
+	row := make([]string, len(tw.columns))
+	for i, column := range tw.columns {
+		row[i] = tradeCSVField(trade, column, tw.moneyDecimals)
+	}
+	return tw.writer.Write(row)
+}
+
+// SetMoneyDecimals sets the precision for P&L and cost columns
+func (tw *TradeCSVWriter) SetMoneyDecimals(decimals int) {
+	tw.moneyDecimals = decimals
+}
+
+// Flush writes buffered rows to the file
+func (tw *TradeCSVWriter) Flush() error {
+	tw.writer.Flush()
+	return tw.writer.Error()
+}
+
+// Close flushes and closes the file
+func (tw *TradeCSVWriter) Close() error {
+	if err := tw.Flush(); err != nil {
+		tw.file.Close()
+		return err
+	}
+	return tw.file.Close()
+}
+
+// ==================== TRADE LOGGER EXPORT ====================
+
+// ExportCSV writes the tracked trades to a CSV file
+// Empty columns select every TradeCSVColumns column
+func (tl *TradeLogger) ExportCSV(path string, columns []string) error {
+	tw, err := NewTradeCSVWriter(path, columns)
+	if err != nil {
+		return err
+	}
+	for _, trade := range tl.GetTrades() {
+		if err := tw.Write(trade); err != nil {
+			tw.Close()
+			return err
+		}
+	}
+	return tw.Close()
+}
@@ -2,8 +2,11 @@ package logger
 
 import (
 	"fmt"
+	"math"
 	"sync"
 	"time"
+
+	"holodeck/types"
 )
 
 // ==================== TRADE LOGGER ====================
@@ -28,6 +31,9 @@ type TradeLogger struct {
 	successRate     float64
 	profitFactor    float64
 
+	// P&L band that counts a trade as break-even
+	breakeven types.BreakevenBand
+
 	// Streaks
 	currentWinStreak  int64
 	currentLoseStreak int64
@@ -49,6 +55,23 @@ func NewTradeLogger(baseLogger Logger) *TradeLogger {
 	}
 }
 
+// SetBreakevenBand sets the P&L band within which a trade counts as
+// break-even; set it before logging trades
+func (tl *TradeLogger) SetBreakevenBand(band types.BreakevenBand) *TradeLogger {
+	tl.breakeven = band
+	return tl
+}
+
+// GetBreakevenBand returns the break-even band
+func (tl *TradeLogger) GetBreakevenBand() types.BreakevenBand {
+	return tl.breakeven
+}
+
+// classify returns a trade's outcome under the break-even band
+func (tl *TradeLogger) classify(trade *TradeLog) int {
+	return tl.breakeven.Classify(trade.RealizedPnL, trade.FilledSize)
+}
+
 // ==================== LOGGING ====================
 
 // LogTrade logs a trade and updates statistics
@@ -76,7 +99,8 @@ func (tl *TradeLogger) updateStatistics(trade *TradeLog) {
 	tl.totalTrades++
 
 	// P&L classification
-	if trade.RealizedPnL > 0 {
+	switch tl.classify(trade) {
+	case types.TradeOutcomeWin:
 		tl.winningTrades++
 		tl.totalWinAmount += trade.RealizedPnL
 		if trade.RealizedPnL > tl.largestWin {
@@ -88,7 +112,7 @@ func (tl *TradeLogger) updateStatistics(trade *TradeLog) {
 		if tl.currentWinStreak > tl.maxWinStreak {
 			tl.maxWinStreak = tl.currentWinStreak
 		}
-	} else if trade.RealizedPnL < 0 {
+	case types.TradeOutcomeLoss:
 		tl.losingTrades++
 		tl.totalLossAmount += trade.RealizedPnL
 		if trade.RealizedPnL < tl.largestLoss {
@@ -100,7 +124,7 @@ func (tl *TradeLogger) updateStatistics(trade *TradeLog) {
 		if tl.currentLoseStreak > tl.maxLoseStreak {
 			tl.maxLoseStreak = tl.currentLoseStreak
 		}
-	} else {
+	default:
 		tl.breakEvenTrades++
 	}
 
@@ -216,6 +240,12 @@ func (tl *TradeLogger) GetStatistics() map[string]interface{} {
 		"max_lose_streak":     tl.maxLoseStreak,
 		"current_win_streak":  tl.currentWinStreak,
 		"current_lose_streak": tl.currentLoseStreak,
+		"expectancy":          tl.GetExpectancy(),
+		"expectancy_r":        tl.GetExpectancyR(),
+		"expectancy_std_err":  tl.GetExpectancyStandardError(),
+		"kelly_fraction":      tl.GetKellyFraction(),
+		"pnl_distribution":    tl.GetPnLDistribution().GetStatistics(),
+		"streaks":             tl.GetStreakDistribution().GetStatistics(),
 	}
 }
 
@@ -287,28 +317,28 @@ func (tl *TradeLogger) GetTradesByAction(action string) []*TradeLog {
 	return result
 }
 
-// GetWinningTrade returns trades with positive P&L
+// GetWinningTradeList returns trades with P&L above the break-even band
 func (tl *TradeLogger) GetWinningTradeList() []*TradeLog {
 	tl.tradesMutex.RLock()
 	defer tl.tradesMutex.RUnlock()
 
 	var result []*TradeLog
 	for _, trade := range tl.trades {
-		if trade.RealizedPnL > 0 {
+		if tl.classify(trade) == types.TradeOutcomeWin {
 			result = append(result, trade)
 		}
 	}
 	return result
 }
 
-// GetLosingTradeList returns trades with negative P&L
+// GetLosingTradeList returns trades with P&L below the break-even band
 func (tl *TradeLogger) GetLosingTradeList() []*TradeLog {
 	tl.tradesMutex.RLock()
 	defer tl.tradesMutex.RUnlock()
 
 	var result []*TradeLog
 	for _, trade := range tl.trades {
-		if trade.RealizedPnL < 0 {
+		if tl.classify(trade) == types.TradeOutcomeLoss {
 			result = append(result, trade)
 		}
 	}
@@ -354,6 +384,92 @@ func (tl *TradeLogger) AnalyzeWinLossRatio() map[string]float64 {
 	}
 }
 
+// GetExpectancy returns the expected P&L per decided trade: the win rate
+// times the average win less the loss rate times the average loss
+// Break-even trades (including opening fills) are left out
+func (tl *TradeLogger) GetExpectancy() float64 {
+	decided := tl.winningTrades + tl.losingTrades
+	if decided == 0 {
+		return 0
+	}
+	return (tl.totalWinAmount + tl.totalLossAmount) / float64(decided)
+}
+
+// GetExpectancyR returns the expectancy in R, units of the average loss
+// (0 without losses)
+func (tl *TradeLogger) GetExpectancyR() float64 {
+	avgLoss := tl.AnalyzeWinLossRatio()["average_loss"]
+	if avgLoss == 0 {
+		return 0
+	}
+	return tl.GetExpectancy() / avgLoss
+}
+
+// GetExpectancyStandardError returns the standard error of the expectancy,
+// the sample standard deviation of decided trades' P&L over the square
+// root of their count
+func (tl *TradeLogger) GetExpectancyStandardError() float64 {
+	tl.tradesMutex.RLock()
+	defer tl.tradesMutex.RUnlock()
+
+	expectancy := tl.GetExpectancy()
+	n := 0
+	variance := 0.0
+	for _, trade := range tl.trades {
+		if tl.classify(trade) == types.TradeOutcomeBreakeven {
+			continue
+		}
+		diff := trade.RealizedPnL - expectancy
+		variance += diff * diff
+		n++
+	}
+	if n < 2 {
+		return 0
+	}
+	return math.Sqrt(variance/float64(n-1)) / math.Sqrt(float64(n))
+}
+
+// GetKellyFraction returns the Kelly fraction of capital to risk per
+// trade, W - (1 - W) / R with W the decided win rate and R the average
+// win over the average loss; negative when the edge is negative and 0
+// without both wins and losses
+func (tl *TradeLogger) GetKellyFraction() float64 {
+	ratio := tl.AnalyzeWinLossRatio()["win_loss_ratio"]
+	if ratio == 0 {
+		return 0
+	}
+	winRate := float64(tl.winningTrades) / float64(tl.winningTrades+tl.losingTrades)
+	return winRate - (1-winRate)/ratio
+}
+
+// GetPnLDistribution returns the histogram and moments (skewness,
+// kurtosis) of decided trades' P&L
+func (tl *TradeLogger) GetPnLDistribution() *types.ReturnDistribution {
+	tl.tradesMutex.RLock()
+	defer tl.tradesMutex.RUnlock()
+
+	pnls := make([]float64, 0, len(tl.trades))
+	for _, trade := range tl.trades {
+		if tl.classify(trade) != types.TradeOutcomeBreakeven {
+			pnls = append(pnls, trade.RealizedPnL)
+		}
+	}
+	return types.NewReturnDistribution(pnls, types.DistributionBins)
+}
+
+// GetStreakDistribution returns how many winning and losing streaks of
+// each length occurred
+func (tl *TradeLogger) GetStreakDistribution() *types.StreakDistribution {
+	tl.tradesMutex.RLock()
+	defer tl.tradesMutex.RUnlock()
+
+	outcomes := make([]int, len(tl.trades))
+	for i, trade := range tl.trades {
+		outcomes[i] = tl.classify(trade)
+	}
+	return types.NewStreakDistribution(outcomes)
+}
+
 // GetConsecutiveLosses returns longest consecutive loss sequence
 func (tl *TradeLogger) GetConsecutiveLosses() int64 {
 	tl.tradesMutex.RLock()
@@ -363,7 +479,7 @@ func (tl *TradeLogger) GetConsecutiveLosses() int64 {
 	currentLosses := int64(0)
 
 	for _, trade := range tl.trades {
-		if trade.RealizedPnL < 0 {
+		if tl.classify(trade) == types.TradeOutcomeLoss {
 			currentLosses++
 			if currentLosses > maxLosses {
 				maxLosses = currentLosses
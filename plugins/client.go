@@ -0,0 +1,294 @@
+package plugins
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==================== PLUGIN CLIENT ====================
+
+// ClientConfig configures how a plugin binary is launched
+type ClientConfig struct {
+	// Path is the plugin executable
+	Path string
+
+	// Args are extra command-line arguments for the plugin
+	Args []string
+
+	// StartTimeout bounds how long to wait for the handshake
+	StartTimeout time.Duration
+
+	// Stderr receives the plugin's stderr (defaults to os.Stderr)
+	Stderr io.Writer
+}
+
+// Client manages a running plugin process and its RPC connection
+type Client struct {
+	config ClientConfig
+
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	rpc     *rpc.Client
+	address string
+	kinds   map[string]bool
+
+	mu     sync.Mutex
+	exited bool
+}
+
+// NewClient creates a plugin client; call Start to launch the plugin
+func NewClient(config ClientConfig) *Client {
+	if config.StartTimeout <= 0 {
+		config.StartTimeout = 10 * time.Second
+	}
+	if config.Stderr == nil {
+		config.Stderr = os.Stderr
+	}
+
+	return &Client{
+		config: config,
+		kinds:  make(map[string]bool),
+	}
+}
+
+// Start launches the plugin and completes the handshake
+func (c *Client) Start() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rpc != nil {
+		return nil
+	}
+
+	cmd := exec.Command(c.config.Path, c.config.Args...)
+	cmd.Env = append(os.Environ(), MagicCookieKey+"="+MagicCookieValue)
+	cmd.Stderr = c.config.Stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("plugin %s: %w", c.config.Path, err)
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("plugin %s: %w", c.config.Path, err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("plugin %s: failed to start: %w", c.config.Path, err)
+	}
+	c.cmd = cmd
+	c.stdin = stdin
+
+	// Wait for the handshake line
+	lineCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		line, err := bufio.NewReader(stdout).ReadString('\n')
+		if err != nil {
+			errCh <- err
+			return
+		}
+		lineCh <- strings.TrimSpace(line)
+
+		// Keep draining so plugin writes to stdout never block
+		io.Copy(io.Discard, stdout)
+	}()
+
+	var line string
+	select {
+	case line = <-lineCh:
+	case err := <-errCh:
+		c.killLocked()
+		return fmt.Errorf("plugin %s: handshake failed: %w", c.config.Path, err)
+	case <-time.After(c.config.StartTimeout):
+		c.killLocked()
+		return fmt.Errorf("plugin %s: handshake timed out after %v", c.config.Path, c.config.StartTimeout)
+	}
+
+	if err := c.parseHandshake(line); err != nil {
+		c.killLocked()
+		return fmt.Errorf("plugin %s: %w", c.config.Path, err)
+	}
+
+	client, err := rpc.Dial("tcp", c.address)
+	if err != nil {
+		c.killLocked()
+		return fmt.Errorf("plugin %s: failed to connect: %w", c.config.Path, err)
+	}
+	c.rpc = client
+
+	return nil
+}
+
+// parseHandshake parses "<version>|tcp|<address>|<kinds>"
+func (c *Client) parseHandshake(line string) error {
+	parts := strings.Split(line, "|")
+	if len(parts) != 4 {
+		return fmt.Errorf("invalid handshake: %q", line)
+	}
+
+	version, err := strconv.Atoi(parts[0])
+	if err != nil || version != ProtocolVersion {
+		return fmt.Errorf("incompatible plugin protocol %q (expected %d)", parts[0], ProtocolVersion)
+	}
+
+	if parts[1] != "tcp" {
+		return fmt.Errorf("unsupported plugin transport: %s", parts[1])
+	}
+
+	c.address = parts[2]
+	for _, kind := range strings.Split(parts[3], ",") {
+		if kind != "" {
+			c.kinds[kind] = true
+		}
+	}
+
+	return nil
+}
+
+// Kinds returns the extension kinds the plugin serves
+func (c *Client) Kinds() []string {
+	kinds := make([]string, 0, len(c.kinds))
+	for kind := range c.kinds {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+// Serves checks if the plugin serves a kind
+func (c *Client) Serves(kind string) bool {
+	return c.kinds[kind]
+}
+
+// dispense checks that the plugin is running and serves the kind
+func (c *Client) dispense(kind string) (*rpc.Client, error) {
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+	if !c.kinds[kind] {
+		return nil, fmt.Errorf("plugin %s does not serve %s", c.config.Path, kind)
+	}
+	return c.rpc, nil
+}
+
+// Reader returns the plugin's tick reader
+// Closing the reader also shuts the plugin down
+func (c *Client) Reader() (TickReader, error) {
+	client, err := c.dispense(KindReader)
+	if err != nil {
+		return nil, err
+	}
+	return &ReaderClient{client: client, owner: c}, nil
+}
+
+// Executor returns the plugin's order executor
+func (c *Client) Executor() (OrderExecutor, error) {
+	client, err := c.dispense(KindExecutor)
+	if err != nil {
+		return nil, err
+	}
+	return &ExecutorClient{client: client}, nil
+}
+
+// Slippage returns the plugin's slippage model
+func (c *Client) Slippage() (SlippageModel, error) {
+	client, err := c.dispense(KindSlippage)
+	if err != nil {
+		return nil, err
+	}
+	return &SlippageClient{client: client}, nil
+}
+
+// Agent returns the plugin's agent
+func (c *Client) Agent() (Agent, error) {
+	client, err := c.dispense(KindAgent)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentClient{client: client}, nil
+}
+
+// Kill closes the connection and stops the plugin process
+func (c *Client) Kill() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.killLocked()
+}
+
+// killLocked stops the plugin; caller must hold mu
+func (c *Client) killLocked() {
+	if c.exited {
+		return
+	}
+	c.exited = true
+
+	if c.rpc != nil {
+		c.rpc.Close()
+	}
+
+	// Closing stdin asks the plugin to exit; kill it if it lingers
+	if c.stdin != nil {
+		c.stdin.Close()
+	}
+	if c.cmd != nil && c.cmd.Process != nil {
+		done := make(chan struct{})
+		go func() {
+			c.cmd.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			c.cmd.Process.Kill()
+			<-done
+		}
+	}
+}
+
+// String returns a human-readable representation
+func (c *Client) String() string {
+	return fmt.Sprintf("PluginClient[%s, Addr:%s, Kinds:%s]", c.config.Path, c.address, strings.Join(c.Kinds(), ","))
+}
+
+// ==================== PLUGIN SET ====================
+
+// Set tracks every plugin client launched for a session
+// Plugins are launched once per path even if they serve several kinds
+type Set struct {
+	clients map[string]*Client
+}
+
+// NewSet creates an empty plugin set
+func NewSet() *Set {
+	return &Set{clients: make(map[string]*Client)}
+}
+
+// Get returns the client for a plugin path, starting it if needed
+func (s *Set) Get(path string) (*Client, error) {
+	if client, ok := s.clients[path]; ok {
+		return client, nil
+	}
+
+	client := NewClient(ClientConfig{Path: path})
+	if err := client.Start(); err != nil {
+		return nil, err
+	}
+	s.clients[path] = client
+	return client, nil
+}
+
+// KillAll stops every plugin in the set
+func (s *Set) KillAll() {
+	for path, client := range s.clients {
+		client.Kill()
+		delete(s.clients, path)
+	}
+}
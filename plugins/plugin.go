@@ -0,0 +1,211 @@
+package plugins
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"os"
+	"strings"
+
+	"holodeck/types"
+)
+
+// ==================== PLUGIN PROTOCOL ====================
+//
+// Plugins are separate executables that serve one or more extension kinds
+// over net/rpc. The host launches the binary with a magic cookie in the
+// environment; the plugin listens on a loopback port and announces itself
+// with a single handshake line on stdout:
+//
+//	<protocol-version>|tcp|<address>|<kind>,<kind>,...
+//
+// The plugin exits when its stdin is closed, so it never outlives the host.
+
+const (
+	// ProtocolVersion is bumped on incompatible RPC changes
+	ProtocolVersion = 1
+
+	// MagicCookieKey and MagicCookieValue guard against running a plugin
+	// binary directly; they are not a security feature
+	MagicCookieKey   = "HOLODECK_PLUGIN"
+	MagicCookieValue = "7d3c1f0e-holodeck-plugin"
+)
+
+// Plugin kinds a binary may serve
+const (
+	KindReader   = "reader"
+	KindExecutor = "executor"
+	KindSlippage = "slippage"
+	KindAgent    = "agent"
+)
+
+// ==================== EXTENSION INTERFACES ====================
+// These mirror the simulator interfaces so plugin implementations
+// can be used anywhere the built-in subsystems are accepted
+
+// TickReader is a custom tick data source
+type TickReader interface {
+	HasNext() bool
+	Next() (*types.Tick, error)
+	Close() error
+	GetTickCount() int64
+	Reset() error
+}
+
+// OrderExecutor is a custom order execution engine
+type OrderExecutor interface {
+	Execute(order *types.Order, tick *types.Tick, instrument types.Instrument) (*types.ExecutionReport, error)
+	Validate(order *types.Order, instrument types.Instrument, availableBalance float64) error
+	CalculateCommission(price, size float64, instrument types.Instrument, side string) float64
+	CalculateSlippage(size float64, availableDepth int64, momentum int, instrument types.Instrument) float64
+}
+
+// SlippageModel is a custom slippage model
+// Returns slippage in pips/units, matching slippage.SlippageCalculator
+type SlippageModel interface {
+	CalculateSlippage(orderSize, availableDepth, volatility, momentum float64, tick *types.Tick, instrument types.Instrument) (float64, error)
+}
+
+// Agent is a trading strategy driven by ticks
+type Agent interface {
+	// OnTick returns the orders to submit for a tick (may be empty)
+	OnTick(tick *types.Tick) ([]*types.Order, error)
+
+	// OnExecution is notified of every execution report
+	OnExecution(exec *types.ExecutionReport) error
+}
+
+// ==================== INSTRUMENT SPEC ====================
+
+// InstrumentSpec identifies an instrument across the RPC boundary
+// Instruments are interfaces, so only the data needed to rebuild one is sent:
+// its identity and its parameters (pip value, contract size, lot and tick
+// sizes, commission)
+type InstrumentSpec struct {
+	Type        string
+	Symbol      string
+	Description string
+
+	// Config is the instrument's parameters (nil = the type's defaults)
+	Config *types.InstrumentConfig
+}
+
+// NewInstrumentSpec captures the identity and parameters of an instrument
+func NewInstrumentSpec(instrument types.Instrument) InstrumentSpec {
+	if instrument == nil {
+		return InstrumentSpec{}
+	}
+	spec := InstrumentSpec{
+		Type:        instrument.GetType(),
+		Symbol:      instrument.GetSymbol(),
+		Description: instrument.GetDescription(),
+	}
+	if config := instrument.GetConfig(); config != nil {
+		c := *config
+		spec.Config = &c
+	}
+	return spec
+}
+
+// Instrument rebuilds the instrument on the plugin side
+func (is InstrumentSpec) Instrument() (types.Instrument, error) {
+	if is.Type == "" {
+		return nil, nil
+	}
+	instrument, err := types.NewInstrument(is.Type, is.Symbol, is.Description)
+	if err != nil {
+		return nil, err
+	}
+	if is.Config != nil && instrument.GetConfig() != nil {
+		*instrument.GetConfig() = *is.Config
+	}
+	return instrument, nil
+}
+
+// ==================== SERVING ====================
+
+// ServeConfig lists the implementations a plugin binary provides
+// Leave a field nil if the plugin does not serve that kind
+type ServeConfig struct {
+	Reader   TickReader
+	Executor OrderExecutor
+	Slippage SlippageModel
+	Agent    Agent
+}
+
+// kinds returns the kinds configured for serving
+func (sc *ServeConfig) kinds() []string {
+	kinds := make([]string, 0, 4)
+	if sc.Reader != nil {
+		kinds = append(kinds, KindReader)
+	}
+	if sc.Executor != nil {
+		kinds = append(kinds, KindExecutor)
+	}
+	if sc.Slippage != nil {
+		kinds = append(kinds, KindSlippage)
+	}
+	if sc.Agent != nil {
+		kinds = append(kinds, KindAgent)
+	}
+	return kinds
+}
+
+// Serve runs the plugin server; it is called from a plugin's main()
+// Serve blocks until the host closes the plugin's stdin
+func Serve(config *ServeConfig) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		return fmt.Errorf("this binary is a holodeck plugin and must be launched by holodeck")
+	}
+
+	kinds := config.kinds()
+	if len(kinds) == 0 {
+		return fmt.Errorf("plugin serves no extension kinds")
+	}
+
+	server := rpc.NewServer()
+	if config.Reader != nil {
+		if err := server.RegisterName("Reader", &ReaderServer{impl: config.Reader}); err != nil {
+			return err
+		}
+	}
+	if config.Executor != nil {
+		if err := server.RegisterName("Executor", &ExecutorServer{impl: config.Executor}); err != nil {
+			return err
+		}
+	}
+	if config.Slippage != nil {
+		if err := server.RegisterName("Slippage", &SlippageServer{impl: config.Slippage}); err != nil {
+			return err
+		}
+	}
+	if config.Agent != nil {
+		if err := server.RegisterName("Agent", &AgentServer{impl: config.Agent}); err != nil {
+			return err
+		}
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	defer listener.Close()
+
+	// Handshake line tells the host where to connect
+	fmt.Fprintf(os.Stdout, "%d|tcp|%s|%s\n", ProtocolVersion, listener.Addr().String(), strings.Join(kinds, ","))
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.ServeConn(conn)
+		}
+	}()
+
+	// Exit once the host goes away
+	io.Copy(io.Discard, os.Stdin)
+	return nil
+}
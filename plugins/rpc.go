@@ -0,0 +1,365 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"net/rpc"
+
+	"holodeck/types"
+)
+
+// Empty is the placeholder argument/reply for calls without data
+type Empty struct{}
+
+// ==================== READER RPC ====================
+
+// ReaderServer exposes a TickReader over RPC
+type ReaderServer struct {
+	impl TickReader
+}
+
+// HasNext serves TickReader.HasNext
+func (rs *ReaderServer) HasNext(_ Empty, reply *bool) error {
+	*reply = rs.impl.HasNext()
+	return nil
+}
+
+// Next serves TickReader.Next
+func (rs *ReaderServer) Next(_ Empty, reply *types.Tick) error {
+	tick, err := rs.impl.Next()
+	if err != nil {
+		return err
+	}
+	*reply = *tick
+	return nil
+}
+
+// Close serves TickReader.Close
+func (rs *ReaderServer) Close(_ Empty, _ *Empty) error {
+	return rs.impl.Close()
+}
+
+// GetTickCount serves TickReader.GetTickCount
+func (rs *ReaderServer) GetTickCount(_ Empty, reply *int64) error {
+	*reply = rs.impl.GetTickCount()
+	return nil
+}
+
+// Reset serves TickReader.Reset
+func (rs *ReaderServer) Reset(_ Empty, _ *Empty) error {
+	return rs.impl.Reset()
+}
+
+// ReaderClient implements TickReader by calling a plugin
+type ReaderClient struct {
+	client *rpc.Client
+	owner  *Client
+}
+
+// HasNext checks if the plugin has more ticks
+func (rc *ReaderClient) HasNext() bool {
+	var reply bool
+	if err := rc.client.Call("Reader.HasNext", Empty{}, &reply); err != nil {
+		return false
+	}
+	return reply
+}
+
+// Next returns the next tick from the plugin
+func (rc *ReaderClient) Next() (*types.Tick, error) {
+	tick := &types.Tick{}
+	if err := rc.client.Call("Reader.Next", Empty{}, tick); err != nil {
+		return nil, remoteError(err)
+	}
+	return tick, nil
+}
+
+// NextContext is Next that stops waiting for the plugin when ctx is done
+// The tick the plugin was reading is dropped
+func (rc *ReaderClient) NextContext(ctx context.Context) (*types.Tick, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	tick := &types.Tick{}
+	call := rc.client.Go("Reader.Next", Empty{}, tick, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		if call.Error != nil {
+			return nil, remoteError(call.Error)
+		}
+		return tick, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close closes the remote reader and shuts the plugin down
+func (rc *ReaderClient) Close() error {
+	err := rc.client.Call("Reader.Close", Empty{}, &Empty{})
+	if rc.owner != nil {
+		rc.owner.Kill()
+	}
+	return remoteError(err)
+}
+
+// GetTickCount returns the number of ticks the plugin has read
+func (rc *ReaderClient) GetTickCount() int64 {
+	var reply int64
+	if err := rc.client.Call("Reader.GetTickCount", Empty{}, &reply); err != nil {
+		return 0
+	}
+	return reply
+}
+
+// Reset resets the remote reader
+func (rc *ReaderClient) Reset() error {
+	return remoteError(rc.client.Call("Reader.Reset", Empty{}, &Empty{}))
+}
+
+// ==================== EXECUTOR RPC ====================
+
+// ExecuteArgs carries an Execute call
+type ExecuteArgs struct {
+	Order      *types.Order
+	Tick       *types.Tick
+	Instrument InstrumentSpec
+}
+
+// ExecuteReply carries an Execute result
+type ExecuteReply struct {
+	Report *types.ExecutionReport
+}
+
+// ValidateArgs carries a Validate call
+type ValidateArgs struct {
+	Order            *types.Order
+	Instrument       InstrumentSpec
+	AvailableBalance float64
+}
+
+// CommissionArgs carries a CalculateCommission call
+type CommissionArgs struct {
+	Price      float64
+	Size       float64
+	Instrument InstrumentSpec
+	Side       string
+}
+
+// ExecutorSlippageArgs carries an executor CalculateSlippage call
+type ExecutorSlippageArgs struct {
+	Size           float64
+	AvailableDepth int64
+	Momentum       int
+	Instrument     InstrumentSpec
+}
+
+// ExecutorServer exposes an OrderExecutor over RPC
+type ExecutorServer struct {
+	impl OrderExecutor
+}
+
+// Execute serves OrderExecutor.Execute
+func (es *ExecutorServer) Execute(args ExecuteArgs, reply *ExecuteReply) error {
+	instrument, err := args.Instrument.Instrument()
+	if err != nil {
+		return err
+	}
+	report, err := es.impl.Execute(args.Order, args.Tick, instrument)
+	if err != nil {
+		return err
+	}
+	reply.Report = report
+	return nil
+}
+
+// Validate serves OrderExecutor.Validate
+func (es *ExecutorServer) Validate(args ValidateArgs, _ *Empty) error {
+	instrument, err := args.Instrument.Instrument()
+	if err != nil {
+		return err
+	}
+	return es.impl.Validate(args.Order, instrument, args.AvailableBalance)
+}
+
+// CalculateCommission serves OrderExecutor.CalculateCommission
+func (es *ExecutorServer) CalculateCommission(args CommissionArgs, reply *float64) error {
+	instrument, err := args.Instrument.Instrument()
+	if err != nil {
+		return err
+	}
+	*reply = es.impl.CalculateCommission(args.Price, args.Size, instrument, args.Side)
+	return nil
+}
+
+// CalculateSlippage serves OrderExecutor.CalculateSlippage
+func (es *ExecutorServer) CalculateSlippage(args ExecutorSlippageArgs, reply *float64) error {
+	instrument, err := args.Instrument.Instrument()
+	if err != nil {
+		return err
+	}
+	*reply = es.impl.CalculateSlippage(args.Size, args.AvailableDepth, args.Momentum, instrument)
+	return nil
+}
+
+// ExecutorClient implements OrderExecutor by calling a plugin
+type ExecutorClient struct {
+	client *rpc.Client
+}
+
+// Execute executes an order in the plugin
+func (ec *ExecutorClient) Execute(order *types.Order, tick *types.Tick, instrument types.Instrument) (*types.ExecutionReport, error) {
+	var reply ExecuteReply
+	args := ExecuteArgs{Order: order, Tick: tick, Instrument: NewInstrumentSpec(instrument)}
+	if err := ec.client.Call("Executor.Execute", args, &reply); err != nil {
+		return nil, remoteError(err)
+	}
+	return reply.Report, nil
+}
+
+// Validate validates an order in the plugin
+func (ec *ExecutorClient) Validate(order *types.Order, instrument types.Instrument, availableBalance float64) error {
+	args := ValidateArgs{Order: order, Instrument: NewInstrumentSpec(instrument), AvailableBalance: availableBalance}
+	return remoteError(ec.client.Call("Executor.Validate", args, &Empty{}))
+}
+
+// CalculateCommission calculates commission in the plugin
+// Returns 0 if the call fails
+func (ec *ExecutorClient) CalculateCommission(price, size float64, instrument types.Instrument, side string) float64 {
+	var reply float64
+	args := CommissionArgs{Price: price, Size: size, Instrument: NewInstrumentSpec(instrument), Side: side}
+	if err := ec.client.Call("Executor.CalculateCommission", args, &reply); err != nil {
+		return 0
+	}
+	return reply
+}
+
+// CalculateSlippage calculates slippage in the plugin
+// Returns 0 if the call fails
+func (ec *ExecutorClient) CalculateSlippage(size float64, availableDepth int64, momentum int, instrument types.Instrument) float64 {
+	var reply float64
+	args := ExecutorSlippageArgs{Size: size, AvailableDepth: availableDepth, Momentum: momentum, Instrument: NewInstrumentSpec(instrument)}
+	if err := ec.client.Call("Executor.CalculateSlippage", args, &reply); err != nil {
+		return 0
+	}
+	return reply
+}
+
+// ==================== SLIPPAGE RPC ====================
+
+// SlippageArgs carries a SlippageModel.CalculateSlippage call
+type SlippageArgs struct {
+	OrderSize      float64
+	AvailableDepth float64
+	Volatility     float64
+	Momentum       float64
+	Tick           *types.Tick
+	Instrument     InstrumentSpec
+}
+
+// SlippageServer exposes a SlippageModel over RPC
+type SlippageServer struct {
+	impl SlippageModel
+}
+
+// CalculateSlippage serves SlippageModel.CalculateSlippage
+func (ss *SlippageServer) CalculateSlippage(args SlippageArgs, reply *float64) error {
+	instrument, err := args.Instrument.Instrument()
+	if err != nil {
+		return err
+	}
+	slippage, err := ss.impl.CalculateSlippage(args.OrderSize, args.AvailableDepth, args.Volatility, args.Momentum, args.Tick, instrument)
+	if err != nil {
+		return err
+	}
+	*reply = slippage
+	return nil
+}
+
+// SlippageClient implements SlippageModel by calling a plugin
+type SlippageClient struct {
+	client *rpc.Client
+}
+
+// CalculateSlippage calculates slippage in the plugin
+func (sc *SlippageClient) CalculateSlippage(
+	orderSize, availableDepth, volatility, momentum float64,
+	tick *types.Tick,
+	instrument types.Instrument,
+) (float64, error) {
+
+	var reply float64
+	args := SlippageArgs{
+		OrderSize:      orderSize,
+		AvailableDepth: availableDepth,
+		Volatility:     volatility,
+		Momentum:       momentum,
+		Tick:           tick,
+		Instrument:     NewInstrumentSpec(instrument),
+	}
+	if err := sc.client.Call("Slippage.CalculateSlippage", args, &reply); err != nil {
+		return 0, remoteError(err)
+	}
+	return reply, nil
+}
+
+// ==================== AGENT RPC ====================
+
+// AgentOrders carries the orders returned by Agent.OnTick
+type AgentOrders struct {
+	Orders []*types.Order
+}
+
+// AgentServer exposes an Agent over RPC
+type AgentServer struct {
+	impl Agent
+}
+
+// OnTick serves Agent.OnTick
+func (as *AgentServer) OnTick(tick *types.Tick, reply *AgentOrders) error {
+	orders, err := as.impl.OnTick(tick)
+	if err != nil {
+		return err
+	}
+	reply.Orders = orders
+	return nil
+}
+
+// OnExecution serves Agent.OnExecution
+func (as *AgentServer) OnExecution(exec *types.ExecutionReport, _ *Empty) error {
+	return as.impl.OnExecution(exec)
+}
+
+// AgentClient implements Agent by calling a plugin
+type AgentClient struct {
+	client *rpc.Client
+}
+
+// OnTick passes a tick to the plugin agent and returns its orders
+func (ac *AgentClient) OnTick(tick *types.Tick) ([]*types.Order, error) {
+	var reply AgentOrders
+	if err := ac.client.Call("Agent.OnTick", tick, &reply); err != nil {
+		return nil, remoteError(err)
+	}
+	return reply.Orders, nil
+}
+
+// OnExecution passes an execution report to the plugin agent
+func (ac *AgentClient) OnExecution(exec *types.ExecutionReport) error {
+	return remoteError(ac.client.Call("Agent.OnExecution", exec, &Empty{}))
+}
+
+// ==================== HELPERS ====================
+
+// remoteError converts an RPC error into a plain error
+// net/rpc flattens remote errors to strings; keep the message, drop the wrapper
+func remoteError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var serverErr rpc.ServerError
+	if errors.As(err, &serverErr) {
+		return errors.New(string(serverErr))
+	}
+	return err
+}
@@ -44,6 +44,10 @@ type ParserConfig struct {
 	LastPriceCol int
 	VolumeCol    int
 
+	// Optional symbol column (0 = none); found from a "symbol" header
+	// column when unset
+	SymbolCol int
+
 	// Timestamp format
 	TimestampFormat string
 
@@ -110,11 +114,22 @@ func NewCSVTickReaderWithConfig(filePath string, config *ParserConfig) (*CSVTick
 
 	// Skip header if configured
 	if config.SkipHeader {
-		if _, err := csvReader.Read(); err != nil && err != io.EOF {
+		header, err := csvReader.Read()
+		if err != nil && err != io.EOF {
 			file.Close()
 			return nil, types.NewConfigError("csv", fmt.Sprintf("failed to read header: %v", err))
 		}
 		reader.lineNumber++
+
+		if config.SymbolCol == 0 {
+			for i, col := range header {
+				if i > 0 && col == "symbol" {
+					symbolConfig := *config
+					symbolConfig.SymbolCol = i
+					reader.config = &symbolConfig
+				}
+			}
+		}
 	}
 
 	return reader, nil
@@ -141,7 +156,7 @@ func (ctr *CSVTickReader) Next() (*types.Tick, error) {
 	if err != nil {
 		if err == io.EOF {
 			ctr.hasNext = false
-			return nil, fmt.Errorf("EOF")
+			return nil, io.EOF
 		}
 		ctr.lineNumber++
 		ctr.parseErrors++
@@ -250,6 +265,9 @@ func (ctr *CSVTickReader) parseLine(line []string) (*types.Tick, error) {
 
 	// Create tick
 	tick := types.NewTick(timestamp, bid, ask, lastPrice, bidQty, askQty, volume, ctr.tickCount)
+	if ctr.config.SymbolCol > 0 && ctr.config.SymbolCol < len(line) {
+		tick.Symbol = line[ctr.config.SymbolCol]
+	}
 
 	// Validate if configured
 	if ctr.config.ValidateData {
@@ -491,3 +509,39 @@ func (ctr *CSVTickReader) ReadUntil(maxTime time.Time) ([]*types.Tick, error) {
 
 	return ticks, nil
 }
+
+// CountTicks counts the data rows in the reader's file without parsing them
+func (ctr *CSVTickReader) CountTicks() (int64, error) {
+	return CountTicks(ctr.filePath, ctr.config.SkipHeader)
+}
+
+// CountTicks counts the data rows in a CSV file without parsing them
+// Useful for progress and ETA reporting before a run starts
+func CountTicks(filePath string, skipHeader bool) (int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, types.NewConfigError("filePath", fmt.Sprintf("failed to open CSV file: %v", err))
+	}
+	defer file.Close()
+
+	csvReader := csv.NewReader(file)
+	csvReader.ReuseRecord = true
+
+	var count int64
+	for {
+		_, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, types.NewCSVReadError(filePath, int(count+1), fmt.Sprintf("read error: %v", err))
+		}
+		count++
+	}
+
+	if skipHeader && count > 0 {
+		count--
+	}
+
+	return count, nil
+}
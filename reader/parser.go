@@ -195,6 +195,9 @@ func AutodetectColumns(header []string) (*ParserConfig, error) {
 		"vol":    &config.VolumeCol,
 		"qty":    &config.VolumeCol,
 		"size":   &config.VolumeCol,
+
+		"symbol":     &config.SymbolCol,
+		"instrument": &config.SymbolCol,
 	}
 
 	// Try to find columns
@@ -0,0 +1,344 @@
+package reader
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== REPLAY FORMAT ====================
+
+// Replay files record every tick a session consumed so it can be re-run
+// exactly, whatever fed it (CSV, plugin or live data)
+//
+// A .csv replay is the canonical CSV the CSV reader reads, with a trailing
+// symbol column. Any other extension is the binary format: the header
+// "HDTK" + version byte, then one little-endian record per tick:
+// timestamp (unix ns), sequence, bid, ask, last price, bid qty, ask qty,
+// volume (8 bytes each) and the symbol (1-byte length + bytes)
+const (
+	ReplayMagic   = "HDTK"
+	ReplayVersion = 1
+)
+
+// ReplayCSVHeader is the header of CSV replay files
+var ReplayCSVHeader = []string{"timestamp", "bid", "ask", "bid_qty", "ask_qty", "last_price", "volume", "symbol"}
+
+// IsBinaryReplay returns true if a replay written to path is binary
+func IsBinaryReplay(path string) bool {
+	return !strings.EqualFold(filepath.Ext(path), ".csv")
+}
+
+// IsBinaryReplayFile returns true if the file at path starts with the
+// binary replay header
+func IsBinaryReplayFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	magic := make([]byte, len(ReplayMagic))
+	if _, err := io.ReadFull(file, magic); err != nil {
+		return false
+	}
+	return string(magic) == ReplayMagic
+}
+
+// ==================== REPLAY WRITER ====================
+
+// ReplayWriter records ticks to a replay file
+type ReplayWriter struct {
+	path   string
+	file   *os.File
+	buf    *bufio.Writer
+	csv    *csv.Writer // nil for binary
+	ticks  int64
+	closed bool
+}
+
+// NewReplayWriter creates a replay file, CSV when path ends in .csv and
+// binary otherwise
+func NewReplayWriter(path string) (*ReplayWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rw := &ReplayWriter{path: path, file: file, buf: bufio.NewWriter(file)}
+	if IsBinaryReplay(path) {
+		rw.buf.WriteString(ReplayMagic)
+		err = rw.buf.WriteByte(ReplayVersion)
+	} else {
+		rw.csv = csv.NewWriter(rw.buf)
+		err = rw.csv.Write(ReplayCSVHeader)
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return rw, nil
+}
+
+// WriteTick appends a tick
+func (rw *ReplayWriter) WriteTick(tick *types.Tick) error {
+	if rw.closed {
+		return fmt.Errorf("replay file %s is closed", rw.path)
+	}
+	if len(tick.Symbol) > 255 {
+		return fmt.Errorf("symbol too long for replay: %q", tick.Symbol)
+	}
+
+	var err error
+	if rw.csv != nil {
+		err = rw.csv.Write([]string{
+			tick.Timestamp.Format(time.RFC3339Nano),
+			strconv.FormatFloat(tick.Bid, 'f', -1, 64),
+			strconv.FormatFloat(tick.Ask, 'f', -1, 64),
+			strconv.FormatInt(tick.BidQty, 10),
+			strconv.FormatInt(tick.AskQty, 10),
+			strconv.FormatFloat(tick.LastPrice, 'f', -1, 64),
+			strconv.FormatInt(tick.Volume, 10),
+			tick.Symbol,
+		})
+	} else {
+		record := make([]byte, 64, 65+len(tick.Symbol))
+		binary.LittleEndian.PutUint64(record[0:], uint64(tick.Timestamp.UnixNano()))
+		binary.LittleEndian.PutUint64(record[8:], uint64(tick.Sequence))
+		binary.LittleEndian.PutUint64(record[16:], math.Float64bits(tick.Bid))
+		binary.LittleEndian.PutUint64(record[24:], math.Float64bits(tick.Ask))
+		binary.LittleEndian.PutUint64(record[32:], math.Float64bits(tick.LastPrice))
+		binary.LittleEndian.PutUint64(record[40:], uint64(tick.BidQty))
+		binary.LittleEndian.PutUint64(record[48:], uint64(tick.AskQty))
+		binary.LittleEndian.PutUint64(record[56:], uint64(tick.Volume))
+		record = append(record, byte(len(tick.Symbol)))
+		record = append(record, tick.Symbol...)
+		_, err = rw.buf.Write(record)
+	}
+	if err != nil {
+		return err
+	}
+
+	rw.ticks++
+	return nil
+}
+
+// GetTickCount returns the number of ticks written
+func (rw *ReplayWriter) GetTickCount() int64 {
+	return rw.ticks
+}
+
+// GetPath returns the replay file path
+func (rw *ReplayWriter) GetPath() string {
+	return rw.path
+}
+
+// GetStatistics returns replay statistics
+func (rw *ReplayWriter) GetStatistics() map[string]interface{} {
+	format := "binary"
+	if rw.csv != nil {
+		format = "csv"
+	}
+	return map[string]interface{}{
+		"file_path":     rw.path,
+		"format":        format,
+		"ticks_written": rw.ticks,
+		"is_closed":     rw.closed,
+	}
+}
+
+// Flush writes buffered ticks to the file
+func (rw *ReplayWriter) Flush() error {
+	if rw.csv != nil {
+		rw.csv.Flush()
+		if err := rw.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return rw.buf.Flush()
+}
+
+// Close flushes and closes the file
+func (rw *ReplayWriter) Close() error {
+	if rw.closed {
+		return nil
+	}
+	rw.closed = true
+
+	if err := rw.Flush(); err != nil {
+		rw.file.Close()
+		return err
+	}
+	return rw.file.Close()
+}
+
+// ==================== BINARY REPLAY READER ====================
+
+// BinaryTickReader reads a binary replay file
+type BinaryTickReader struct {
+	filePath  string
+	file      *os.File
+	reader    *bufio.Reader
+	tickCount int64
+	closed    bool
+}
+
+// NewBinaryTickReader opens a binary replay file
+func NewBinaryTickReader(filePath string) (*BinaryTickReader, error) {
+	btr := &BinaryTickReader{filePath: filePath}
+	if err := btr.open(); err != nil {
+		return nil, err
+	}
+	return btr, nil
+}
+
+// open opens the file and checks the header
+func (btr *BinaryTickReader) open() error {
+	file, err := os.Open(btr.filePath)
+	if err != nil {
+		return types.NewConfigError("filePath", fmt.Sprintf("failed to open replay file: %v", err))
+	}
+
+	reader := bufio.NewReader(file)
+	header := make([]byte, len(ReplayMagic)+1)
+	if _, err := io.ReadFull(reader, header); err != nil || string(header[:len(ReplayMagic)]) != ReplayMagic {
+		file.Close()
+		return types.NewConfigError("filePath", fmt.Sprintf("not a replay file: %s", btr.filePath))
+	}
+	if header[len(ReplayMagic)] != ReplayVersion {
+		file.Close()
+		return types.NewConfigError("filePath", fmt.Sprintf("unsupported replay version %d", header[len(ReplayMagic)]))
+	}
+
+	btr.file = file
+	btr.reader = reader
+	btr.tickCount = 0
+	return nil
+}
+
+// HasNext checks if there are more ticks to read
+func (btr *BinaryTickReader) HasNext() bool {
+	if btr.closed {
+		return false
+	}
+	_, err := btr.reader.Peek(1)
+	return err == nil
+}
+
+// Next returns the next tick
+func (btr *BinaryTickReader) Next() (*types.Tick, error) {
+	if btr.closed {
+		return nil, types.NewConfigError("reader", "reader is closed")
+	}
+
+	record := make([]byte, 65)
+	if _, err := io.ReadFull(btr.reader, record); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, types.NewCSVReadError(btr.filePath, int(btr.tickCount+1), fmt.Sprintf("truncated tick: %v", err))
+	}
+	symbol := make([]byte, record[64])
+	if _, err := io.ReadFull(btr.reader, symbol); err != nil {
+		return nil, types.NewCSVReadError(btr.filePath, int(btr.tickCount+1), fmt.Sprintf("truncated symbol: %v", err))
+	}
+
+	u64 := func(offset int) uint64 { return binary.LittleEndian.Uint64(record[offset:]) }
+	tick := types.NewTick(
+		time.Unix(0, int64(u64(0))).UTC(),
+		math.Float64frombits(u64(16)),
+		math.Float64frombits(u64(24)),
+		math.Float64frombits(u64(32)),
+		int64(u64(40)),
+		int64(u64(48)),
+		int64(u64(56)),
+		int64(u64(8)),
+	)
+	tick.Symbol = string(symbol)
+
+	btr.tickCount++
+	return tick, nil
+}
+
+// CountTicks counts the ticks in the file by skipping from record to
+// record
+func (btr *BinaryTickReader) CountTicks() (int64, error) {
+	file, err := os.Open(btr.filePath)
+	if err != nil {
+		return 0, types.NewConfigError("filePath", fmt.Sprintf("failed to open replay file: %v", err))
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	if _, err := reader.Discard(len(ReplayMagic) + 1); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for {
+		if _, err := reader.Discard(64); err != nil {
+			if err == io.EOF {
+				return count, nil
+			}
+			return count, err
+		}
+		length, err := reader.ReadByte()
+		if err != nil {
+			return count, err
+		}
+		if _, err := reader.Discard(int(length)); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+// GetTickCount returns the number of ticks read
+func (btr *BinaryTickReader) GetTickCount() int64 {
+	return btr.tickCount
+}
+
+// Reset resets the reader to the beginning
+func (btr *BinaryTickReader) Reset() error {
+	if btr.closed {
+		return types.NewInvalidOperationError("Reset", "reader is closed")
+	}
+	if err := btr.file.Close(); err != nil {
+		return types.NewConfigError("reader", fmt.Sprintf("failed to close file: %v", err))
+	}
+	return btr.open()
+}
+
+// Close closes the reader
+func (btr *BinaryTickReader) Close() error {
+	if btr.closed {
+		return nil
+	}
+	btr.closed = true
+	return btr.file.Close()
+}
+
+// GetStatistics returns reader statistics
+func (btr *BinaryTickReader) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"file_path":  btr.filePath,
+		"format":     "binary",
+		"ticks_read": btr.tickCount,
+		"is_closed":  btr.closed,
+	}
+}
+
+// String returns a human-readable representation
+func (btr *BinaryTickReader) String() string {
+	return fmt.Sprintf("BinaryTickReader[File:%s, Ticks:%d, Closed:%v]", btr.filePath, btr.tickCount, btr.closed)
+}
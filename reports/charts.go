@@ -0,0 +1,150 @@
+package reports
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ==================== SVG CHARTS ====================
+
+// Chart dimensions in SVG user units
+const (
+	ChartWidth     = 900
+	ChartHeight    = 240
+	ChartPadding   = 40
+	ChartMaxPoints = 1000 // Series are downsampled to this many points
+	HistogramBins  = 20
+)
+
+// LineChart is a series scaled into chart coordinates for a polyline
+type LineChart struct {
+	Points string // "x,y x,y ..." for <polyline points>
+	Area   string // Points closed down to the baseline, for <polygon>
+	Min    float64
+	Max    float64
+	Empty  bool
+}
+
+// Bar is one histogram bar in chart coordinates
+type Bar struct {
+	X, Y, Width, Height float64
+	Label               string // Bin range
+	Count               int
+	Negative            bool
+}
+
+// Histogram is a distribution of values as bars
+type Histogram struct {
+	Bars  []Bar
+	Empty bool
+}
+
+// NewLineChart scales values into a line chart, downsampled to
+// ChartMaxPoints
+func NewLineChart(values []float64) LineChart {
+	if len(values) < 2 {
+		return LineChart{Empty: true}
+	}
+	values = downsample(values, ChartMaxPoints)
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	span := hi - lo
+	if span == 0 {
+		span = 1
+	}
+
+	plotWidth := float64(ChartWidth - 2*ChartPadding)
+	plotHeight := float64(ChartHeight - 2*ChartPadding)
+	step := plotWidth / float64(len(values)-1)
+
+	points := make([]string, len(values))
+	for i, v := range values {
+		x := ChartPadding + float64(i)*step
+		y := ChartPadding + plotHeight - (v-lo)/span*plotHeight
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+
+	baseline := float64(ChartHeight - ChartPadding)
+	area := fmt.Sprintf("%.1f,%.1f %s %.1f,%.1f",
+		float64(ChartPadding), baseline, strings.Join(points, " "), ChartPadding+plotWidth, baseline)
+
+	return LineChart{
+		Points: strings.Join(points, " "),
+		Area:   area,
+		Min:    lo,
+		Max:    hi,
+	}
+}
+
+// NewHistogram bins values into HistogramBins bars
+func NewHistogram(values []float64) Histogram {
+	if len(values) == 0 {
+		return Histogram{Empty: true}
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	width := (hi - lo) / HistogramBins
+	if width == 0 {
+		width = 1
+	}
+
+	counts := make([]int, HistogramBins)
+	for _, v := range values {
+		bin := int((v - lo) / width)
+		if bin >= HistogramBins {
+			bin = HistogramBins - 1
+		}
+		counts[bin]++
+	}
+
+	most := 0
+	for _, c := range counts {
+		if c > most {
+			most = c
+		}
+	}
+
+	plotWidth := float64(ChartWidth - 2*ChartPadding)
+	plotHeight := float64(ChartHeight - 2*ChartPadding)
+	barWidth := plotWidth / HistogramBins
+
+	bars := make([]Bar, HistogramBins)
+	for i, c := range counts {
+		height := float64(c) / float64(most) * plotHeight
+		from := lo + float64(i)*width
+		bars[i] = Bar{
+			X:        ChartPadding + float64(i)*barWidth,
+			Y:        ChartPadding + plotHeight - height,
+			Width:    barWidth - 1,
+			Height:   height,
+			Label:    fmt.Sprintf("%.2f to %.2f", from, from+width),
+			Count:    c,
+			Negative: from+width/2 < 0,
+		}
+	}
+
+	return Histogram{Bars: bars}
+}
+
+// downsample keeps every nth value so at most max remain, always keeping
+// the last
+func downsample(values []float64, max int) []float64 {
+	if len(values) <= max {
+		return values
+	}
+	step := float64(len(values)-1) / float64(max-1)
+	sampled := make([]float64, max)
+	for i := range sampled {
+		sampled[i] = values[int(math.Round(float64(i)*step))]
+	}
+	return sampled
+}
@@ -0,0 +1,226 @@
+package reports
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"holodeck/simulator"
+	"holodeck/types"
+)
+
+// ==================== HTML REPORT ====================
+
+// HTMLReportFile is the report's file name in an output directory
+const HTMLReportFile = "report.html"
+
+//go:embed report.html.tmpl
+var reportTemplateSource string
+
+// reportTemplate renders an HTMLReport as a single self-contained page
+var reportTemplate = template.Must(template.New("report").Parse(reportTemplateSource))
+
+// SummaryRow is one line of the report's summary table
+type SummaryRow struct {
+	Label string
+	Value string
+	Class string // "pos", "neg" or ""
+}
+
+// SessionRow is one trading session's performance
+type SessionRow struct {
+	Label      string
+	Trades     int
+	WinRate    float64
+	AveragePnL string
+	TotalPnL   string
+}
+
+// DayRow is one day of the daily P&L table
+type DayRow struct {
+	Date               string
+	NetPnL             string
+	RealizedPnL        string
+	Commission         string
+	Trades             int
+	MaxDrawdownPercent float64
+	Class              string
+}
+
+// HTMLReport is a finished session rendered for sharing: summary figures,
+// equity and drawdown charts, the closed-trade P&L distribution and the
+// session and daily breakdowns, as one HTML file with inline SVG charts
+type HTMLReport struct {
+	Title          string
+	SessionID      string
+	Symbol         string
+	InstrumentType string
+	Start          string
+	End            string
+	GeneratedAt    string
+
+	Summary      []SummaryRow
+	Equity       LineChart
+	Drawdown     LineChart
+	Distribution Histogram
+	Sessions     []SessionRow
+	Days         []DayRow
+
+	// Chart geometry for the template
+	Width    int
+	Height   int
+	Padding  int
+	Baseline int
+}
+
+// NewHTMLReport builds a report from a finished session
+// moneyDecimals is the number of decimals for monetary values
+func NewHTMLReport(h *simulator.Holodeck, moneyDecimals int) *HTMLReport {
+	status := h.GetStatus()
+	symbol := h.GetSymbolReport()
+	points := h.GetEquityCurve()
+	stats := h.GetEquityStatistics()
+
+	money := func(v float64) string { return fmt.Sprintf("%.*f", moneyDecimals, v) }
+	sign := func(v float64) string {
+		switch {
+		case v > 0:
+			return "pos"
+		case v < 0:
+			return "neg"
+		}
+		return ""
+	}
+
+	report := &HTMLReport{
+		Title:          fmt.Sprintf("Backtest Report: %s", symbol.Symbol),
+		SessionID:      status.SessionID,
+		Symbol:         symbol.Symbol,
+		InstrumentType: symbol.InstrumentType,
+		GeneratedAt:    time.Now().Format("2006-01-02 15:04:05"),
+		Width:          ChartWidth,
+		Height:         ChartHeight,
+		Padding:        ChartPadding,
+		Baseline:       ChartHeight - ChartPadding,
+	}
+	if len(points) > 0 {
+		report.Start = points[0].Timestamp.Format("2006-01-02 15:04")
+		report.End = points[len(points)-1].Timestamp.Format("2006-01-02 15:04")
+	}
+
+	// Summary
+	wins := 0
+	pnls := make([]float64, len(symbol.ClosedTrades))
+	for i, trade := range symbol.ClosedTrades {
+		pnls[i] = trade.RealizedPnL
+		if trade.RealizedPnL > 0 {
+			wins++
+		}
+	}
+	winRate := 0.0
+	if len(pnls) > 0 {
+		winRate = float64(wins) / float64(len(pnls)) * 100
+	}
+
+	report.Summary = []SummaryRow{
+		{Label: "Initial Balance", Value: money(symbol.InitialBalance)},
+		{Label: "Final Balance", Value: money(symbol.FinalBalance)},
+		{Label: "Net P&L", Value: money(symbol.NetPnL), Class: sign(symbol.NetPnL)},
+		{Label: "Return", Value: fmt.Sprintf("%.2f%%", symbol.GetReturnPercent()), Class: sign(symbol.NetPnL)},
+		{Label: "Max Drawdown", Value: fmt.Sprintf("%.2f%%", symbol.MaxDrawdownPercent)},
+		{Label: "Sharpe Ratio", Value: fmt.Sprintf("%.2f", floatStat(stats, "sharpe_ratio"))},
+		{Label: "Calmar Ratio", Value: fmt.Sprintf("%.2f", floatStat(stats, "calmar_ratio"))},
+		{Label: "Executions", Value: fmt.Sprintf("%d", symbol.Trades)},
+		{Label: "Closed Trades", Value: fmt.Sprintf("%d", len(pnls))},
+		{Label: "Win Rate", Value: fmt.Sprintf("%.1f%%", winRate)},
+		{Label: "Commission", Value: money(symbol.Commission)},
+		{Label: "Ticks Processed", Value: fmt.Sprintf("%d", symbol.TicksProcessed)},
+	}
+	if b := symbol.Benchmark; b != nil {
+		report.Summary = append(report.Summary,
+			SummaryRow{Label: "Buy-and-Hold Return", Value: fmt.Sprintf("%.2f%%", b.BenchmarkReturnPercent)},
+			SummaryRow{Label: "Relative Return", Value: fmt.Sprintf("%.2f%%", b.RelativeReturnPercent), Class: sign(b.RelativeReturnPercent)},
+		)
+	}
+
+	// Charts
+	equity := make([]float64, len(points))
+	drawdown := make([]float64, len(points))
+	for i, p := range points {
+		equity[i] = p.Equity
+		drawdown[i] = -p.DrawdownPercent
+	}
+	report.Equity = NewLineChart(equity)
+	report.Drawdown = NewLineChart(drawdown)
+	report.Distribution = NewHistogram(pnls)
+
+	// Breakdowns
+	for _, bucket := range symbol.Sessions {
+		report.Sessions = append(report.Sessions, newSessionRow(bucket, money))
+	}
+	for _, day := range symbol.Days {
+		net := day.GetNetPnL()
+		report.Days = append(report.Days, DayRow{
+			Date:               day.Date,
+			NetPnL:             money(net),
+			RealizedPnL:        money(day.RealizedPnL),
+			Commission:         money(day.Commission),
+			Trades:             day.Trades,
+			MaxDrawdownPercent: day.MaxIntradayDrawdownPercent,
+			Class:              sign(net),
+		})
+	}
+
+	return report
+}
+
+// newSessionRow formats a session bucket
+func newSessionRow(bucket types.PerformanceBucket, money func(float64) string) SessionRow {
+	return SessionRow{
+		Label:      bucket.Label,
+		Trades:     bucket.Trades,
+		WinRate:    bucket.GetWinRate(),
+		AveragePnL: money(bucket.GetAveragePnL()),
+		TotalPnL:   money(bucket.TotalPnL),
+	}
+}
+
+// floatStat returns a numeric statistic (0 when missing)
+func floatStat(stats map[string]interface{}, key string) float64 {
+	if v, ok := stats[key].(float64); ok {
+		return v
+	}
+	return 0
+}
+
+// Render writes the report as HTML
+func (r *HTMLReport) Render(w io.Writer) error {
+	return reportTemplate.Execute(w, r)
+}
+
+// WriteFile renders the report to report.html in dir and returns its path
+func (r *HTMLReport) WriteFile(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	path := filepath.Join(dir, HTMLReportFile)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create report: %w", err)
+	}
+	if err := r.Render(f); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to render report: %w", err)
+	}
+	return path, f.Close()
+}
+
+// String returns a human-readable representation
+func (r *HTMLReport) String() string {
+	return fmt.Sprintf("HTMLReport[%s, Session:%s, Days:%d]", r.Symbol, r.SessionID, len(r.Days))
+}
@@ -0,0 +1,117 @@
+package risk
+
+import (
+	"fmt"
+
+	"holodeck/types"
+)
+
+// ==================== DAILY LOSS LIMIT ====================
+
+// DailyLossLimit is a kill switch that halts trading for the rest of a
+// simulated day once the day's loss (realized + unrealized, from the day's
+// opening equity) reaches a fixed amount or a percent of opening equity
+// Trading resumes with the next day
+type DailyLossLimit struct {
+	// MaxLoss is the loss in account currency that halts trading (0 = off)
+	MaxLoss float64
+
+	// MaxLossPercent is the loss as a percent of the day's opening equity
+	// that halts trading (0 = off)
+	MaxLossPercent float64
+
+	// Flatten closes every position when the limit is hit
+	Flatten bool
+
+	haltedDay string
+	halts     int64
+	days      []string
+}
+
+// NewDailyLossLimit creates a daily loss limit; zero limits disable each
+func NewDailyLossLimit(maxLoss, maxLossPercent float64, flatten bool) *DailyLossLimit {
+	return &DailyLossLimit{
+		MaxLoss:        maxLoss,
+		MaxLossPercent: maxLossPercent,
+		Flatten:        flatten,
+		days:           make([]string, 0),
+	}
+}
+
+// IsEnabled returns true if either limit is set
+func (dl *DailyLossLimit) IsEnabled() bool {
+	return dl.MaxLoss > 0 || dl.MaxLossPercent > 0
+}
+
+// GetLoss returns the day's loss from its opening equity (0 when up)
+func GetLoss(day *types.DailyRecord) float64 {
+	if day == nil || day.CloseEquity >= day.OpenEquity {
+		return 0
+	}
+	return day.OpenEquity - day.CloseEquity
+}
+
+// Check halts trading when the day's loss breaches a limit
+// Returns true only on the check that triggers the halt
+func (dl *DailyLossLimit) Check(day *types.DailyRecord) bool {
+	if !dl.IsEnabled() || day == nil || dl.IsHalted(day.Date) {
+		return false
+	}
+
+	loss := GetLoss(day)
+	if loss <= 0 {
+		return false
+	}
+
+	breached := dl.MaxLoss > 0 && loss >= dl.MaxLoss
+	if dl.MaxLossPercent > 0 && day.OpenEquity > 0 && loss/day.OpenEquity*100 >= dl.MaxLossPercent {
+		breached = true
+	}
+	if !breached {
+		return false
+	}
+
+	dl.haltedDay = day.Date
+	dl.halts++
+	dl.days = append(dl.days, day.Date)
+	return true
+}
+
+// IsHalted returns true if trading is halted on date (YYYY-MM-DD)
+func (dl *DailyLossLimit) IsHalted(date string) bool {
+	return dl.haltedDay != "" && dl.haltedDay == date
+}
+
+// GetHaltedDay returns the date of the latest halt ("" if never halted)
+func (dl *DailyLossLimit) GetHaltedDay() string {
+	return dl.haltedDay
+}
+
+// GetStatistics returns daily loss limit statistics
+func (dl *DailyLossLimit) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"max_loss":         dl.MaxLoss,
+		"max_loss_percent": dl.MaxLossPercent,
+		"flatten":          dl.Flatten,
+		"halts":            dl.halts,
+		"halted_days":      append([]string(nil), dl.days...),
+	}
+}
+
+// Reset clears the halt and statistics
+func (dl *DailyLossLimit) Reset() {
+	dl.haltedDay = ""
+	dl.halts = 0
+	dl.days = make([]string, 0)
+}
+
+// String returns a human-readable representation
+func (dl *DailyLossLimit) String() string {
+	return fmt.Sprintf(
+		"DailyLossLimit[MaxLoss:%.2f, MaxLoss%%:%.2f, Flatten:%v, Halts:%d]",
+		dl.MaxLoss,
+		dl.MaxLossPercent,
+		dl.Flatten,
+		dl.halts,
+	)
+}
@@ -0,0 +1,79 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+
+	"holodeck/types"
+)
+
+// ==================== POSITION SIZING ====================
+
+// lotEpsilon absorbs float error when flooring to the lot step, so 0.3 lots
+// computed as 0.29999999 is not rounded down to 0.2
+const lotEpsilon = 1e-9
+
+// lotScale is the precision lot sizes are rounded to (8 decimals)
+const lotScale = 1e8
+
+// SizeForRisk returns the largest size (in lots) whose loss at the stop
+// stays within riskPercent of balance
+// stopDistance is the entry-to-stop distance in price units (e.g. 0.0020
+// for 20 pips on EURUSD); the loss per lot is the instrument's P&L over
+// that distance, so balance must be in the instrument's P&L currency
+// The size is rounded down to a multiple of the instrument's minimum lot
+// size, which is its lot step; an error is returned when even one minimum
+// lot would risk more than allowed
+func SizeForRisk(balance, riskPercent, stopDistance float64, instrument types.Instrument) (float64, error) {
+	if instrument == nil {
+		return 0, fmt.Errorf("instrument is required")
+	}
+	if balance <= 0 {
+		return 0, fmt.Errorf("balance must be positive, got %.2f", balance)
+	}
+	if riskPercent <= 0 || riskPercent > 100 {
+		return 0, fmt.Errorf("risk percent must be between 0 and 100, got %.4f", riskPercent)
+	}
+	if stopDistance <= 0 {
+		return 0, fmt.Errorf("stop distance must be positive, got %.6f", stopDistance)
+	}
+
+	lossPerLot := GetLossPerLot(stopDistance, instrument)
+	if lossPerLot <= 0 {
+		return 0, fmt.Errorf("%s: stop distance %.6f has no value", instrument.GetSymbol(), stopDistance)
+	}
+
+	riskAmount := balance * riskPercent / 100
+	size := RoundDownToLot(riskAmount/lossPerLot, instrument.GetMinimumLotSize())
+
+	minimum := instrument.GetMinimumLotSize()
+	if size <= 0 || size < minimum {
+		return 0, fmt.Errorf(
+			"%s: risking %.2f with a %.6f stop is below the minimum lot size %.4f (one lot loses %.2f)",
+			instrument.GetSymbol(),
+			riskAmount,
+			stopDistance,
+			minimum,
+			lossPerLot*minimum,
+		)
+	}
+
+	return size, nil
+}
+
+// GetLossPerLot returns the loss of one lot over stopDistance
+func GetLossPerLot(stopDistance float64, instrument types.Instrument) float64 {
+	return math.Abs(instrument.CalculatePnL(0, stopDistance, 1, 1))
+}
+
+// RoundDownToLot floors size to a multiple of lotStep (no rounding when
+// lotStep is 0)
+func RoundDownToLot(size, lotStep float64) float64 {
+	if lotStep <= 0 {
+		return size
+	}
+	steps := math.Floor(size/lotStep + lotEpsilon)
+
+	// Trim the float error of steps*lotStep (3 * 0.1 = 0.30000000000000004)
+	return math.Round(steps*lotStep*lotScale) / lotScale
+}
@@ -0,0 +1,100 @@
+package risk
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ==================== DRAWDOWN THROTTLE ====================
+
+// DrawdownStep scales the maximum order size once drawdown from the
+// equity peak reaches DrawdownPercent
+type DrawdownStep struct {
+	DrawdownPercent float64 `json:"drawdown_percent"`
+	SizeMultiplier  float64 `json:"size_multiplier"` // 0.5 halves the size
+}
+
+// DrawdownThrottle reduces the maximum order size in steps as drawdown
+// grows; the deepest step reached applies, and size recovers as equity
+// climbs back toward its peak
+type DrawdownThrottle struct {
+	steps []DrawdownStep // By drawdown, shallowest first
+
+	throttled int64
+}
+
+// NewDrawdownThrottle validates the steps and creates a throttle
+// Returns nil when no steps are configured
+func NewDrawdownThrottle(steps []DrawdownStep) (*DrawdownThrottle, error) {
+	if len(steps) == 0 {
+		return nil, nil
+	}
+
+	sorted := append([]DrawdownStep(nil), steps...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].DrawdownPercent < sorted[j].DrawdownPercent
+	})
+
+	for i, step := range sorted {
+		if step.DrawdownPercent <= 0 || step.DrawdownPercent > 100 {
+			return nil, fmt.Errorf("drawdown_percent must be between 0 and 100, got %.2f", step.DrawdownPercent)
+		}
+		if step.SizeMultiplier < 0 || step.SizeMultiplier > 1 {
+			return nil, fmt.Errorf("size_multiplier at %.2f%% must be between 0 and 1, got %.4f", step.DrawdownPercent, step.SizeMultiplier)
+		}
+		if i > 0 && step.DrawdownPercent == sorted[i-1].DrawdownPercent {
+			return nil, fmt.Errorf("duplicate step at %.2f%% drawdown", step.DrawdownPercent)
+		}
+		if i > 0 && step.SizeMultiplier > sorted[i-1].SizeMultiplier {
+			return nil, fmt.Errorf("size_multiplier must not grow with drawdown (%.2f%% step)", step.DrawdownPercent)
+		}
+	}
+
+	return &DrawdownThrottle{steps: sorted}, nil
+}
+
+// GetMultiplier returns the size multiplier at a drawdown percent
+// (1 above the first step)
+func (dt *DrawdownThrottle) GetMultiplier(drawdownPercent float64) float64 {
+	multiplier := 1.0
+	for _, step := range dt.steps {
+		if drawdownPercent < step.DrawdownPercent {
+			break
+		}
+		multiplier = step.SizeMultiplier
+	}
+	return multiplier
+}
+
+// GetMaxOrderSize returns the throttled maximum order size
+func (dt *DrawdownThrottle) GetMaxOrderSize(maxOrderSize, drawdownPercent float64) float64 {
+	return maxOrderSize * dt.GetMultiplier(drawdownPercent)
+}
+
+// RecordThrottled counts an order rejected by the throttle
+func (dt *DrawdownThrottle) RecordThrottled() {
+	dt.throttled++
+}
+
+// GetSteps returns a copy of the steps, shallowest first
+func (dt *DrawdownThrottle) GetSteps() []DrawdownStep {
+	return append([]DrawdownStep(nil), dt.steps...)
+}
+
+// GetStatistics returns throttle statistics
+func (dt *DrawdownThrottle) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"steps":     len(dt.steps),
+		"throttled": dt.throttled,
+	}
+}
+
+// Reset clears the statistics
+func (dt *DrawdownThrottle) Reset() {
+	dt.throttled = 0
+}
+
+// String returns a human-readable representation
+func (dt *DrawdownThrottle) String() string {
+	return fmt.Sprintf("DrawdownThrottle[Steps:%d, Throttled:%d]", len(dt.steps), dt.throttled)
+}
@@ -0,0 +1,267 @@
+package simulator
+
+import (
+	"fmt"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== ALERT RULES ====================
+
+// Alert metrics
+const (
+	AlertMetricDrawdown   = "drawdown_percent" // From the equity peak
+	AlertMetricReturn     = "return_percent"
+	AlertMetricLossStreak = "loss_streak" // Consecutive losing trades
+	AlertMetricWinRate    = "win_rate"
+	AlertMetricFillRate   = "fill_rate" // Filled / requested volume, percent
+	AlertMetricMargin     = "margin_level"
+)
+
+// Alert operators
+const (
+	AlertAbove = ">"
+	AlertBelow = "<"
+)
+
+// AlertRule fires when a metric crosses a threshold, e.g.
+// {"metric": "drawdown_percent", "operator": ">", "threshold": 10}
+type AlertRule struct {
+	Metric    string  `json:"metric"`
+	Operator  string  `json:"operator"` // ">" or "<"
+	Threshold float64 `json:"threshold"`
+
+	// MinTrades holds the rule back until this many trades have closed,
+	// so rates are not judged on the first few trades
+	MinTrades int `json:"min_trades"`
+}
+
+// Validate checks the rule's metric and operator
+func (ar AlertRule) Validate() error {
+	switch ar.Metric {
+	case AlertMetricDrawdown, AlertMetricReturn, AlertMetricLossStreak,
+		AlertMetricWinRate, AlertMetricFillRate, AlertMetricMargin:
+	default:
+		return fmt.Errorf("unknown alert metric %q", ar.Metric)
+	}
+	if ar.Operator != AlertAbove && ar.Operator != AlertBelow {
+		return fmt.Errorf("alert operator must be %q or %q, got %q", AlertAbove, AlertBelow, ar.Operator)
+	}
+	if ar.MinTrades < 0 {
+		return fmt.Errorf("alert min_trades cannot be negative")
+	}
+	return nil
+}
+
+// Breached returns true if a value is past the threshold
+func (ar AlertRule) Breached(value float64) bool {
+	if ar.Operator == AlertAbove {
+		return value > ar.Threshold
+	}
+	return value < ar.Threshold
+}
+
+// String returns the rule as "metric > threshold"
+func (ar AlertRule) String() string {
+	return fmt.Sprintf("%s %s %g", ar.Metric, ar.Operator, ar.Threshold)
+}
+
+// ==================== ALERTS ====================
+
+// Alert reports a rule breached during the simulation
+type Alert struct {
+	Rule AlertRule
+
+	// Timestamp is the simulated time of the tick that breached the rule
+	Timestamp time.Time
+
+	// Value is the metric's value when breached
+	Value float64
+}
+
+// String returns a human-readable representation
+func (a *Alert) String() string {
+	return fmt.Sprintf("Alert[%s, Value:%.2f at %s]", a.Rule, a.Value, a.Timestamp.Format(time.RFC3339))
+}
+
+// ==================== ALERT MONITOR ====================
+
+// AlertMonitor evaluates the alert rules
+// A rule fires once when breached and re-arms after the metric recovers
+type AlertMonitor struct {
+	rules    []AlertRule
+	breached []bool
+
+	lossStreak      int
+	closedTrades    int
+	requestedVolume float64
+	filledVolume    float64
+
+	alerts []Alert
+}
+
+// NewAlertMonitor validates the rules and creates a monitor
+// Returns nil when no rules are configured
+func NewAlertMonitor(rules []AlertRule) (*AlertMonitor, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	for i, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+
+	return &AlertMonitor{
+		rules:    append([]AlertRule(nil), rules...),
+		breached: make([]bool, len(rules)),
+		alerts:   make([]Alert, 0),
+	}, nil
+}
+
+// RecordTrade tracks the losing streak from a closing trade's outcome
+// (see BreakevenBand.Classify); breakeven trades leave the streak as is
+func (am *AlertMonitor) RecordTrade(outcome int) {
+	am.closedTrades++
+	switch outcome {
+	case types.TradeOutcomeLoss:
+		am.lossStreak++
+	case types.TradeOutcomeWin:
+		am.lossStreak = 0
+	}
+}
+
+// RecordOrder tracks the fill rate from an accepted order; fills of
+// working remainders on later ticks are recorded with no requested volume
+func (am *AlertMonitor) RecordOrder(requested, filled float64) {
+	am.requestedVolume += requested
+	am.filledVolume += filled
+}
+
+// GetFillRate returns filled / requested volume in percent (100 before
+// any order)
+func (am *AlertMonitor) GetFillRate() float64 {
+	if am.requestedVolume == 0 {
+		return 100
+	}
+	return am.filledVolume / am.requestedVolume * 100
+}
+
+// value returns a metric's current value
+func (am *AlertMonitor) value(metric string, balance *types.Balance) float64 {
+	switch metric {
+	case AlertMetricDrawdown:
+		return balance.GetPeakDrawdownPercent()
+	case AlertMetricReturn:
+		return balance.GetReturnPercent()
+	case AlertMetricLossStreak:
+		return float64(am.lossStreak)
+	case AlertMetricWinRate:
+		return balance.GetWinRate()
+	case AlertMetricFillRate:
+		return am.GetFillRate()
+	case AlertMetricMargin:
+		return balance.GetMarginLevel()
+	}
+	return 0
+}
+
+// Check evaluates every rule and returns the newly breached ones
+func (am *AlertMonitor) Check(timestamp time.Time, balance *types.Balance) []Alert {
+	var fired []Alert
+	for i, rule := range am.rules {
+		if am.closedTrades < rule.MinTrades {
+			continue
+		}
+		if rule.Metric == AlertMetricMargin && balance.UsedMargin <= 0 {
+			am.breached[i] = false
+			continue
+		}
+
+		value := am.value(rule.Metric, balance)
+		if !rule.Breached(value) {
+			am.breached[i] = false
+			continue
+		}
+		if am.breached[i] {
+			continue
+		}
+		am.breached[i] = true
+
+		alert := Alert{Rule: rule, Timestamp: timestamp, Value: value}
+		am.alerts = append(am.alerts, alert)
+		fired = append(fired, alert)
+	}
+	return fired
+}
+
+// GetAlerts returns a copy of the alerts raised, oldest first
+func (am *AlertMonitor) GetAlerts() []Alert {
+	alerts := make([]Alert, len(am.alerts))
+	copy(alerts, am.alerts)
+	return alerts
+}
+
+// GetStatistics returns alert monitor statistics
+func (am *AlertMonitor) GetStatistics() map[string]interface{} {
+	byMetric := make(map[string]int)
+	for _, alert := range am.alerts {
+		byMetric[alert.Rule.Metric]++
+	}
+	return map[string]interface{}{
+		"rules":       len(am.rules),
+		"alerts":      len(am.alerts),
+		"by_metric":   byMetric,
+		"loss_streak": am.lossStreak,
+		"fill_rate":   am.GetFillRate(),
+	}
+}
+
+// Reset clears the alerts and tracked metrics and re-arms every rule
+func (am *AlertMonitor) Reset() {
+	am.breached = make([]bool, len(am.rules))
+	am.lossStreak = 0
+	am.closedTrades = 0
+	am.requestedVolume = 0
+	am.filledVolume = 0
+	am.alerts = make([]Alert, 0)
+}
+
+// String returns a human-readable representation
+func (am *AlertMonitor) String() string {
+	return fmt.Sprintf("AlertMonitor[Rules:%d, Alerts:%d]", len(am.rules), len(am.alerts))
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// processAlerts evaluates the alert rules against the marked-to-market
+// account, logging each newly breached rule and calling OnAlert
+// Caller must hold the write lock
+func (h *Holodeck) processAlerts(tick *types.Tick) {
+	if h.alerts == nil || h.state.Balance == nil {
+		return
+	}
+
+	for _, alert := range h.alerts.Check(tick.Timestamp, h.state.Balance) {
+		alert := alert
+		h.logError(types.NewMetricAlertError(alert.Rule.String(), alert.Value))
+		if h.callbacks.OnAlert != nil {
+			callbackStart := h.timing.start()
+			h.callbacks.OnAlert(&alert)
+			h.timing.addCallback(callbackStart)
+		}
+	}
+}
+
+// GetAlerts returns the alerts raised so far (nil when no rules are
+// configured)
+func (h *Holodeck) GetAlerts() []Alert {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.alerts == nil {
+		return nil
+	}
+	return h.alerts.GetAlerts()
+}
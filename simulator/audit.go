@@ -0,0 +1,258 @@
+package simulator
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== AUDIT ENTRY ====================
+
+// AuditGenesisHash is the previous hash of the first entry in a trail
+var AuditGenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// AuditEntry is one immutable record of the audit trail
+// Hash covers every other field plus PrevHash, so editing, removing or
+// reordering any entry breaks the chain from that point on
+type AuditEntry struct {
+	Sequence      int64     `json:"sequence"`
+	ExecutionID   string    `json:"execution_id"`
+	Timestamp     time.Time `json:"timestamp"`
+	OrderID       string    `json:"order_id"`
+	Action        string    `json:"action"`
+	Status        string    `json:"status"`
+	RequestedSize float64   `json:"requested_size"`
+	FilledSize    float64   `json:"filled_size"`
+	FillPrice     float64   `json:"fill_price"`
+	Commission    float64   `json:"commission"`
+	RealizedPnL   float64   `json:"realized_pnl"`
+	PositionAfter float64   `json:"position_after"`
+	ErrorCode     string    `json:"error_code,omitempty"`
+	PrevHash      string    `json:"prev_hash"`
+	Hash          string    `json:"hash"`
+}
+
+// computeHash returns the SHA-256 of the entry's fields and PrevHash
+func (ae *AuditEntry) computeHash() string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+	payload := strings.Join([]string{
+		strconv.FormatInt(ae.Sequence, 10),
+		ae.ExecutionID,
+		ae.Timestamp.UTC().Format(time.RFC3339Nano),
+		ae.OrderID,
+		ae.Action,
+		ae.Status,
+		f(ae.RequestedSize),
+		f(ae.FilledSize),
+		f(ae.FillPrice),
+		f(ae.Commission),
+		f(ae.RealizedPnL),
+		f(ae.PositionAfter),
+		ae.ErrorCode,
+		ae.PrevHash,
+	}, "|")
+
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
+
+// String returns a human-readable representation
+func (ae *AuditEntry) String() string {
+	return fmt.Sprintf(
+		"Audit[#%d %s %s %s %.4f @ %.5f, Hash:%s]",
+		ae.Sequence,
+		ae.ExecutionID,
+		ae.Action,
+		ae.Status,
+		ae.FilledSize,
+		ae.FillPrice,
+		ae.Hash[:12],
+	)
+}
+
+// ==================== AUDIT TRAIL ====================
+
+// AuditTrail is a tamper-evident, hash-chained log of every execution
+// report the simulator produces (fills, rejections and final reports)
+type AuditTrail struct {
+	entries []AuditEntry
+}
+
+// NewAuditTrail creates an empty audit trail
+func NewAuditTrail() *AuditTrail {
+	return &AuditTrail{
+		entries: make([]AuditEntry, 0),
+	}
+}
+
+// Append assigns the report its sequential execution ID and chains a new
+// entry for it
+func (at *AuditTrail) Append(exec *types.ExecutionReport) AuditEntry {
+	sequence := int64(len(at.entries)) + 1
+	exec.ExecutionID = fmt.Sprintf("EXEC-%08d", sequence)
+
+	entry := AuditEntry{
+		Sequence:      sequence,
+		ExecutionID:   exec.ExecutionID,
+		Timestamp:     exec.Timestamp,
+		OrderID:       exec.OrderID,
+		Action:        exec.Action,
+		Status:        exec.Status,
+		RequestedSize: exec.RequestedSize,
+		FilledSize:    exec.FilledSize,
+		FillPrice:     exec.FillPrice,
+		Commission:    exec.Commission,
+		RealizedPnL:   exec.RealizedPnL,
+		PositionAfter: exec.PositionAfter,
+		ErrorCode:     exec.ErrorCode,
+		PrevHash:      at.GetHeadHash(),
+	}
+	entry.Hash = entry.computeHash()
+
+	at.entries = append(at.entries, entry)
+	return entry
+}
+
+// GetHeadHash returns the hash of the latest entry
+func (at *AuditTrail) GetHeadHash() string {
+	if len(at.entries) == 0 {
+		return AuditGenesisHash
+	}
+	return at.entries[len(at.entries)-1].Hash
+}
+
+// GetEntries returns a copy of the entries
+func (at *AuditTrail) GetEntries() []AuditEntry {
+	entries := make([]AuditEntry, len(at.entries))
+	copy(entries, at.entries)
+	return entries
+}
+
+// Size returns the number of entries
+func (at *AuditTrail) Size() int {
+	return len(at.entries)
+}
+
+// Verify recomputes the chain
+func (at *AuditTrail) Verify() error {
+	return VerifyAuditEntries(at.entries)
+}
+
+// Export writes the trail as JSON lines, one entry per line
+func (at *AuditTrail) Export(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	for i := range at.entries {
+		if err := encoder.Encode(&at.entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportFile writes the trail to a JSON lines file
+func (at *AuditTrail) ExportFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create audit file: %w", err)
+	}
+	if err := at.Export(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// GetStatistics returns audit trail statistics
+func (at *AuditTrail) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"entries":   len(at.entries),
+		"head_hash": at.GetHeadHash(),
+	}
+}
+
+// Reset clears the trail
+func (at *AuditTrail) Reset() {
+	at.entries = make([]AuditEntry, 0)
+}
+
+// String returns a human-readable representation
+func (at *AuditTrail) String() string {
+	return fmt.Sprintf("AuditTrail[Entries:%d, Head:%s]", len(at.entries), at.GetHeadHash()[:12])
+}
+
+// ==================== VERIFICATION ====================
+
+// ReadAuditTrail reads entries exported by AuditTrail.Export
+func ReadAuditTrail(r io.Reader) ([]AuditEntry, error) {
+	entries := make([]AuditEntry, 0)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("audit line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// VerifyAuditEntries checks sequence numbers, hash links and hashes
+// Returns an error naming the first entry that does not verify
+func VerifyAuditEntries(entries []AuditEntry) error {
+	prevHash := AuditGenesisHash
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Sequence != int64(i)+1 {
+			return fmt.Errorf("audit entry %d: sequence %d out of order", i+1, entry.Sequence)
+		}
+		if entry.PrevHash != prevHash {
+			return fmt.Errorf("audit entry %d: chain broken (previous hash mismatch)", entry.Sequence)
+		}
+		if entry.computeHash() != entry.Hash {
+			return fmt.Errorf("audit entry %d: contents do not match hash", entry.Sequence)
+		}
+		prevHash = entry.Hash
+	}
+	return nil
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// GetAuditTrail returns a copy of the audit entries
+func (h *Holodeck) GetAuditTrail() []AuditEntry {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.audit.GetEntries()
+}
+
+// ExportAuditTrail writes the audit trail to a JSON lines file
+func (h *Holodeck) ExportAuditTrail(path string) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.audit.ExportFile(path)
+}
+
+// VerifyAuditTrail checks the audit trail's hash chain
+func (h *Holodeck) VerifyAuditTrail() error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.audit.Verify()
+}
@@ -0,0 +1,201 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+
+	"holodeck/types"
+)
+
+// ==================== BATCH EXECUTION ====================
+
+// ExecuteOrderBatch executes several orders against the same tick
+// The whole batch is validated first (order fields, position limit along
+// the sequence of fills, with margin_check the margin for the peak
+// exposure, then a dry run of every order through the pause, halt and
+// throttle gates and the executor's own checks); if any order fails,
+// nothing is executed and every order is reported rejected with the
+// failing order's error code
+// The dry run checks each order against the positions before the batch,
+// so a later order can still be rejected on exposure or margin built up
+// by the earlier ones; the orders already filled stand, and the result
+// holds every order's report
+// HOLD orders are skipped; the rest execute in batch order
+func (h *Holodeck) ExecuteOrderBatch(batch *types.OrderBatch) (*types.ExecutionBatch, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.timing.enter()
+	defer h.timing.leave()
+
+	if err := h.checkCanExecute(); err != nil {
+		return nil, err
+	}
+
+	if batch == nil {
+		return nil, fmt.Errorf("order batch is nil")
+	}
+
+	tick := h.state.CurrentTick
+	result := types.NewExecutionBatch(tick.Timestamp)
+	orders := batch.GetTradeOrders()
+
+	if err := h.validateBatch(orders, tick); err != nil {
+		for _, order := range orders {
+			exec := types.NewRejectedExecution(
+				order.OrderID,
+				tick.Timestamp,
+				order.Action,
+				order.Size,
+				err.Code,
+				err.Message,
+			)
+			h.rejections[exec.ErrorCode]++
+			h.reportExecution(exec)
+			result.Add(exec)
+		}
+		return result, err
+	}
+
+	for _, order := range orders {
+		exec, err := h.executeOrder(order)
+		if err != nil {
+			return result, err
+		}
+		result.Add(exec)
+	}
+
+	return result, nil
+}
+
+// validateBatch checks every order of a batch before any is executed
+// Caller must hold the write lock
+func (h *Holodeck) validateBatch(orders []*types.Order, tick *types.Tick) *types.HolodeckError {
+	if h.pause.isPaused() {
+		return types.NewSessionPausedError()
+	}
+
+	balance := h.state.Balance
+
+	maxPositionSize := math.Inf(1)
+	if balance != nil && balance.MaxPositionSize > 0 {
+		maxPositionSize = balance.MaxPositionSize
+	}
+
+	// Positions and their peak exposure, per symbol
+	positions := make(map[string]float64)
+	peaks := make(map[string]float64)
+
+	for i, order := range orders {
+		symbol := h.state.symbolKey(order.Symbol)
+		instrument := h.instrumentFor(symbol)
+		if instrument == nil {
+			err := types.NewInstrumentNotFoundError(symbol)
+			err.Details["order_index"] = i
+			err.Details["order_id"] = order.OrderID
+			return err
+		}
+		position, seen := positions[symbol]
+		if !seen {
+			if pos, ok := h.state.Positions[symbol]; ok {
+				position = pos.Size
+			}
+			peaks[symbol] = math.Abs(position)
+		}
+
+		if verr := order.Validate(instrument.GetMinimumLotSize(), maxPositionSize); verr != nil {
+			err := types.NewHolodeckError(verr.Code, verr.Message)
+			err.Details["order_index"] = i
+			err.Details["order_id"] = order.OrderID
+			return err
+		}
+
+		position += float64(order.GetDirection()) * order.Size
+		if math.Abs(position) > maxPositionSize {
+			err := types.NewPositionLimitError(math.Abs(position), maxPositionSize)
+			err.Details["order_index"] = i
+			err.Details["order_id"] = order.OrderID
+			return err
+		}
+		positions[symbol] = position
+		peaks[symbol] = math.Max(peaks[symbol], math.Abs(position))
+	}
+
+	// Margin covers the largest exposure reached while legging in, each
+	// symbol at its own price and instrument; like the executor's check,
+	// only the margin added on top of the open positions is charged, and
+	// against the margin still available
+	if balance != nil && h.isMarginCheckEnabled() {
+		required := 0.0
+		for symbol, peak := range peaks {
+			price := tick.GetMidPrice()
+			if last := h.state.LastTicks[symbol]; last != nil {
+				price = last.GetMidPrice()
+			}
+			current := 0.0
+			if pos, ok := h.state.Positions[symbol]; ok {
+				current = math.Abs(pos.Size)
+			}
+			instrument := h.instrumentFor(symbol)
+			tiers := h.config.Config.GetLeverageTiers(instrument)
+			added := types.CalculateTieredMargin(peak, price, instrument, tiers, balance.Leverage) -
+				types.CalculateTieredMargin(current, price, instrument, tiers, balance.Leverage)
+			if added > 0 {
+				required += balance.ConvertToAccount(symbol, added)
+			}
+		}
+		if required > balance.AvailableMargin {
+			return types.NewInsufficientBalanceError(required, balance.AvailableMargin)
+		}
+	}
+
+	// Dry-run every order through the gates executeOrder applies
+	for i, order := range orders {
+		if exec := h.checkOrder(order); exec != nil {
+			err := types.NewHolodeckError(exec.ErrorCode, exec.ErrorMessage)
+			err.Details["order_index"] = i
+			err.Details["order_id"] = order.OrderID
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkOrder runs an order through the halt and throttle gates and the
+// executor's pre-trade checks without executing it
+// Returns the rejection the order would get, or nil if it passes
+// Caller must hold the write lock
+func (h *Holodeck) checkOrder(order *types.Order) *types.ExecutionReport {
+	symbol := h.state.symbolKey(order.Symbol)
+	tick := h.state.LastTicks[symbol]
+	if tick == nil {
+		tick = h.state.CurrentTick
+	}
+
+	keyed := *order
+	keyed.Symbol = symbol
+	if exec := h.checkHalted(&keyed, tick); exec != nil {
+		return exec
+	}
+	if exec := h.checkThrottle(&keyed, tick); exec != nil {
+		return exec
+	}
+
+	oce, ok := h.executor.(OrderCheckingExecutor)
+	if !ok {
+		return nil
+	}
+	if aae, ok := h.executor.(AccountAwareExecutor); ok {
+		aae.SetAccount(h.state.Balance, h.state.position(symbol))
+	}
+	if pae, ok := h.executor.(PositionsAwareExecutor); ok {
+		pae.SetPositions(h.state.Positions)
+	}
+	return oce.CheckOrder(&keyed, tick, h.instrumentFor(symbol))
+}
+
+// isMarginCheckEnabled checks if the session enforces margin
+func (h *Holodeck) isMarginCheckEnabled() bool {
+	return h.config != nil && h.config.Config != nil && h.config.Config.IsMarginCheckEnabled()
+}
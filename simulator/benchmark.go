@@ -0,0 +1,147 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== BUY-AND-HOLD BENCHMARK ====================
+
+// benchmarkSample is the benchmark's mark price at one equity curve sample
+type benchmarkSample struct {
+	timestamp time.Time
+	price     float64
+}
+
+// Benchmark tracks a buy-and-hold position in the session instrument:
+// bought at the first tick's ask and marked at the bid each time the
+// strategy's equity curve is sampled, so the two curves line up
+// Size is the configured benchmark size; 0 holds the strategy's largest
+// position
+type Benchmark struct {
+	Size float64
+
+	entryPrice   float64
+	lastPrice    float64
+	strategySize float64
+	samples      []benchmarkSample
+}
+
+// NewBenchmark creates a buy-and-hold benchmark
+func NewBenchmark(size float64) *Benchmark {
+	return &Benchmark{
+		Size:    size,
+		samples: make([]benchmarkSample, 0),
+	}
+}
+
+// Observe takes the entry from the first tick and the mark from each tick
+func (b *Benchmark) Observe(tick *types.Tick) {
+	if b.entryPrice == 0 {
+		b.entryPrice = tick.GetBuyPrice()
+	}
+	b.lastPrice = tick.GetSellPrice()
+}
+
+// ObservePosition widens the strategy's largest position
+func (b *Benchmark) ObservePosition(size float64) {
+	b.strategySize = math.Max(b.strategySize, math.Abs(size))
+}
+
+// Sample records the mark price at an equity curve sample; samples before
+// the entry hold flat
+func (b *Benchmark) Sample(timestamp time.Time) {
+	b.samples = append(b.samples, benchmarkSample{timestamp: timestamp, price: b.lastPrice})
+}
+
+// Len returns the number of samples
+func (b *Benchmark) Len() int {
+	return len(b.samples)
+}
+
+// GetSize returns the benchmark position size
+func (b *Benchmark) GetSize() float64 {
+	if b.Size > 0 {
+		return b.Size
+	}
+	return b.strategySize
+}
+
+// GetCurve returns the benchmark equity curve from initialBalance, with
+// P&L at one unit per pip per unit of size converted to the account
+// currency by convert
+func (b *Benchmark) GetCurve(initialBalance, pipValue float64, convert func(float64) float64) *types.EquityCurve {
+	curve := types.NewEquityCurve(0)
+	if pipValue == 0 {
+		return curve
+	}
+
+	size := b.GetSize()
+	for _, sample := range b.samples {
+		pnl := 0.0
+		if sample.price > 0 {
+			pnl = convert((sample.price - b.entryPrice) * size / pipValue)
+		}
+		curve.Record(sample.timestamp, initialBalance+pnl, pnl)
+	}
+	return curve
+}
+
+// Reset clears the entry, the strategy size and the samples
+func (b *Benchmark) Reset() {
+	b.entryPrice = 0
+	b.lastPrice = 0
+	b.strategySize = 0
+	b.samples = make([]benchmarkSample, 0)
+}
+
+// String returns a human-readable representation
+func (b *Benchmark) String() string {
+	return fmt.Sprintf(
+		"Benchmark[Size:%.2f, Entry:%.5f, Samples:%d]",
+		b.GetSize(),
+		b.entryPrice,
+		len(b.samples),
+	)
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// sampleBenchmark samples the benchmark when the equity curve took a
+// sample, keeping the two curves aligned
+// Caller must hold the write lock
+func (h *Holodeck) sampleBenchmark(timestamp time.Time) {
+	if h.state.EquityCurve.Size() > h.benchmark.Len() {
+		h.benchmark.Sample(timestamp)
+	}
+}
+
+// getBenchmarkComparison compares the strategy with buy-and-hold over the
+// Sharpe return periods (nil before the first sample)
+// Caller must hold the lock
+func (h *Holodeck) getBenchmarkComparison() *types.BenchmarkComparison {
+	if h.benchmark.Len() == 0 || h.benchmark.Len() != h.state.EquityCurve.Size() {
+		return nil
+	}
+
+	b := h.state.Balance
+	start := h.state.EquityCurve.GetPoints()[0].Equity
+	convert := func(amount float64) float64 {
+		return b.ConvertToAccount(h.state.PrimarySymbol, amount)
+	}
+	curve := h.benchmark.GetCurve(start, h.config.Instrument.GetPipValue(), convert)
+	comparison := types.CompareEquityCurves(h.state.EquityCurve, curve, b.Sharpe)
+	return &comparison
+}
+
+// GetBenchmarkComparison compares the strategy with holding the session
+// instrument from the first tick to the last
+func (h *Holodeck) GetBenchmarkComparison() *types.BenchmarkComparison {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.getBenchmarkComparison()
+}
@@ -0,0 +1,320 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== CARRYOVER RECORDS ====================
+
+// CarryoverVersion is the carryover file format version
+const CarryoverVersion = 1
+
+// CarryoverState is the account carried from the end of one session into
+// the start of the next, so multi-file or multi-day backtests can be
+// chained without flattening at each boundary: the balance, each open
+// position with its lots, and the orders still waiting to be retried
+// Working order remainders are cancelled at session end and not carried
+type CarryoverState struct {
+	Version   int       `json:"version"`
+	SessionID string    `json:"session_id"`
+	Timestamp time.Time `json:"timestamp"` // Simulated time of the last tick
+	SavedAt   time.Time `json:"saved_at"`
+
+	Balance       CarryoverBalance             `json:"balance"`
+	Positions     map[string]CarryoverPosition `json:"positions"`
+	PendingOrders []CarryoverOrder             `json:"pending_orders"`
+}
+
+// CarryoverBalance is the account part of a carryover; the running totals
+// continue so the chained sessions report as one
+type CarryoverBalance struct {
+	Currency         string  `json:"currency"`
+	InitialBalance   float64 `json:"initial_balance"`
+	CurrentBalance   float64 `json:"current_balance"`
+	RealizedPnL      float64 `json:"realized_pnl"`
+	CommissionPaid   float64 `json:"commission_paid"`
+	FinancingPnL     float64 `json:"financing_pnl"`
+	DividendPnL      float64 `json:"dividend_pnl"`
+	InterestPnL      float64 `json:"interest_pnl"`
+	ProtectionCredit float64 `json:"protection_credit,omitempty"`
+	HighWaterMark    float64 `json:"high_water_mark"`
+	LowWaterMark     float64 `json:"low_water_mark"`
+	MaxDrawdown      float64 `json:"max_drawdown_percent"`
+	TradeCount       int     `json:"trade_count"`
+	WinningTrades    int     `json:"winning_trades"`
+	LosingTrades     int     `json:"losing_trades"`
+	BreakevenTrades  int     `json:"breakeven_trades"`
+}
+
+// CarryoverPosition is one symbol's open position
+type CarryoverPosition struct {
+	Side         string         `json:"side"` // LONG or SHORT
+	Size         float64        `json:"size"`
+	EntryPrice   float64        `json:"entry_price"`
+	EntryTime    time.Time      `json:"entry_time"`
+	CurrentPrice float64        `json:"current_price"`
+	Lots         []CarryoverLot `json:"lots"`
+}
+
+// CarryoverLot is one open lot of a carried position
+type CarryoverLot struct {
+	LotID      string    `json:"lot_id"`
+	Size       float64   `json:"size"`
+	Price      float64   `json:"price"`
+	OpenTime   time.Time `json:"open_time"`
+	Commission float64   `json:"commission"`
+	HighPrice  float64   `json:"high_price"`
+	LowPrice   float64   `json:"low_price"`
+	StopPrice  float64   `json:"stop_price,omitempty"`
+
+	Costs types.LotCosts `json:"costs"`
+}
+
+// CarryoverOrder is an order waiting to be retried; DueInTicks counts
+// from the start of the next session
+type CarryoverOrder struct {
+	Order      *types.Order `json:"order"`
+	Policy     RetryPolicy  `json:"policy"`
+	Attempts   int          `json:"attempts"`
+	DueInTicks int64        `json:"due_in_ticks"`
+}
+
+// ==================== SAVE ====================
+
+// newCarryoverState captures the balance, open positions and pending
+// retries
+// Caller must hold the lock
+func (h *Holodeck) newCarryoverState() *CarryoverState {
+	b := h.state.Balance
+	carry := &CarryoverState{
+		Version:   CarryoverVersion,
+		SessionID: h.config.SessionID,
+		SavedAt:   time.Now(),
+		Balance: CarryoverBalance{
+			Currency:         b.Currency,
+			InitialBalance:   b.InitialBalance,
+			CurrentBalance:   b.CurrentBalance,
+			RealizedPnL:      b.TotalRealizedPnL,
+			CommissionPaid:   b.CommissionPaid,
+			FinancingPnL:     b.FinancingPnL,
+			DividendPnL:      b.DividendPnL,
+			InterestPnL:      b.InterestPnL,
+			ProtectionCredit: b.ProtectionCredit,
+			HighWaterMark:    b.HighWaterMark,
+			LowWaterMark:     b.LowWaterMark,
+			MaxDrawdown:      b.MaxDrawdownExperienced,
+			TradeCount:       b.TradeCount,
+			WinningTrades:    b.WinningTrades,
+			LosingTrades:     b.LosingTrades,
+			BreakevenTrades:  b.BreakevenTrades,
+		},
+		Positions:     make(map[string]CarryoverPosition),
+		PendingOrders: make([]CarryoverOrder, 0),
+	}
+	if h.state.CurrentTick != nil {
+		carry.Timestamp = h.state.CurrentTick.Timestamp
+	}
+
+	for symbol, pos := range h.state.Positions {
+		if pos.IsFlat() {
+			continue
+		}
+		cp := CarryoverPosition{
+			Side:         pos.GetStatus(),
+			Size:         pos.Size,
+			EntryPrice:   pos.EntryPrice,
+			EntryTime:    pos.EntryTime,
+			CurrentPrice: pos.CurrentPrice,
+			Lots:         make([]CarryoverLot, 0, len(pos.Lots)),
+		}
+		for _, lot := range pos.Lots {
+			cp.Lots = append(cp.Lots, CarryoverLot{
+				LotID:      lot.LotID,
+				Size:       lot.Size,
+				Price:      lot.Price,
+				OpenTime:   lot.OpenTime,
+				Commission: lot.Commission,
+				HighPrice:  lot.HighPrice,
+				LowPrice:   lot.LowPrice,
+				StopPrice:  lot.StopPrice,
+				Costs:      lot.Costs,
+			})
+		}
+		carry.Positions[symbol] = cp
+	}
+
+	for _, retry := range h.retries.GetPending() {
+		due := retry.DueTick - h.state.TickCount
+		if due < 1 {
+			due = 1
+		}
+		carry.PendingOrders = append(carry.PendingOrders, CarryoverOrder{
+			Order:      retry.Order,
+			Policy:     retry.Policy,
+			Attempts:   retry.Attempts,
+			DueInTicks: due,
+		})
+	}
+
+	return carry
+}
+
+// SaveState writes the balance, open positions and pending retries to a
+// JSON file for LoadState to pick up in the next session
+// Hedging-mode tickets are not carried over
+func (h *Holodeck) SaveState(path string) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.saveState(path)
+}
+
+// saveState writes the carryover file
+// Caller must hold the lock
+func (h *Holodeck) saveState(path string) error {
+	if h.hedges != nil {
+		return fmt.Errorf("cannot save state: hedging positions are not carried over")
+	}
+
+	data, err := json.MarshalIndent(h.newCarryoverState(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create state directory: %w", err)
+		}
+	}
+
+	// Write then rename so a crash never leaves a truncated state file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// ==================== LOAD ====================
+
+// LoadCarryoverState reads a carryover file written by SaveState
+func LoadCarryoverState(path string) (*CarryoverState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	var carry CarryoverState
+	if err := json.Unmarshal(data, &carry); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+	if carry.Version != CarryoverVersion {
+		return nil, fmt.Errorf("unsupported state version %d", carry.Version)
+	}
+	return &carry, nil
+}
+
+// LoadState makes a saved state the starting state of this session: the
+// balance and its running totals, the open positions and the pending
+// retries; positions are revalued on their first tick
+// It must be called before the session starts
+func (h *Holodeck) LoadState(path string) error {
+	carry, err := LoadCarryoverState(path)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.restoreState(carry)
+}
+
+// restoreState applies a carryover to the fresh session state
+// Caller must hold the write lock
+func (h *Holodeck) restoreState(carry *CarryoverState) error {
+	if h.running || h.state.TickCount > 0 {
+		return fmt.Errorf("cannot load state: session already started")
+	}
+	if h.hedges != nil && len(carry.Positions) > 0 {
+		return fmt.Errorf("cannot load state: positions cannot be restored in hedging mode")
+	}
+
+	b := h.state.Balance
+	if carry.Balance.Currency != "" && carry.Balance.Currency != b.Currency {
+		return fmt.Errorf("cannot load state: account currency %s differs from %s", carry.Balance.Currency, b.Currency)
+	}
+
+	for symbol := range carry.Positions {
+		if h.instrumentFor(symbol) == nil {
+			return fmt.Errorf("cannot load state: no instrument configured for %s", symbol)
+		}
+	}
+
+	unrealized := 0.0
+	for symbol, cp := range carry.Positions {
+		lots := make([]types.Lot, 0, len(cp.Lots))
+		for _, lot := range cp.Lots {
+			lots = append(lots, types.Lot{
+				LotID:      lot.LotID,
+				Size:       lot.Size,
+				Price:      lot.Price,
+				OpenTime:   lot.OpenTime,
+				Commission: lot.Commission,
+				HighPrice:  lot.HighPrice,
+				LowPrice:   lot.LowPrice,
+				StopPrice:  lot.StopPrice,
+				Costs:      lot.Costs,
+			})
+		}
+
+		pos := h.state.position(symbol)
+		pos.RestoreLots(cp.Side, lots, cp.EntryTime)
+		if cp.CurrentPrice > 0 {
+			pos.UpdatePrice(cp.CurrentPrice, h.pipValueFor(symbol))
+		}
+		unrealized += b.ConvertToAccount(symbol, pos.UnrealizedPnL)
+	}
+
+	b.InitialBalance = carry.Balance.InitialBalance
+	b.TotalRealizedPnL = carry.Balance.RealizedPnL
+	b.CommissionPaid = carry.Balance.CommissionPaid
+	b.FinancingPnL = carry.Balance.FinancingPnL
+	b.DividendPnL = carry.Balance.DividendPnL
+	b.InterestPnL = carry.Balance.InterestPnL
+	b.ProtectionCredit = carry.Balance.ProtectionCredit
+	b.HighWaterMark = carry.Balance.HighWaterMark
+	b.LowWaterMark = carry.Balance.LowWaterMark
+	b.MaxDrawdownExperienced = carry.Balance.MaxDrawdown
+	b.TradeCount = carry.Balance.TradeCount
+	b.WinningTrades = carry.Balance.WinningTrades
+	b.LosingTrades = carry.Balance.LosingTrades
+	b.BreakevenTrades = carry.Balance.BreakevenTrades
+	b.MarkToMarket(unrealized)
+	h.updateUsedMargin()
+
+	h.state.StartBalance = b.InitialBalance
+	h.state.CurrentBalance = b.CurrentBalance
+	h.state.PeakBalance = b.HighWaterMark
+	h.state.TroughBalance = b.LowWaterMark
+
+	for _, order := range carry.PendingOrders {
+		if order.Order == nil {
+			continue
+		}
+		h.retries.pending = append(h.retries.pending, &PendingRetry{
+			Order:    order.Order,
+			Policy:   order.Policy,
+			Attempts: order.Attempts,
+			DueTick:  order.DueInTicks,
+		})
+	}
+
+	return nil
+}
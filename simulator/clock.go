@@ -0,0 +1,177 @@
+package simulator
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== SIMULATION CLOCK ====================
+
+// SimClock is the session's simulated time: the timestamp of the latest
+// tick read. It reads without locking, so it is safe to query from any
+// goroutine, including from inside callbacks
+type SimClock struct {
+	nanos atomic.Int64 // 0 before the first tick
+}
+
+// NewSimClock creates a clock that has not started
+func NewSimClock() *SimClock {
+	return &SimClock{}
+}
+
+// Now returns the simulated time (the zero time before the first tick)
+func (sc *SimClock) Now() time.Time {
+	nanos := sc.nanos.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos).UTC()
+}
+
+// Set moves the clock to a tick's timestamp
+func (sc *SimClock) Set(t time.Time) {
+	sc.nanos.Store(t.UnixNano())
+}
+
+// IsStarted returns whether the clock has seen a tick
+func (sc *SimClock) IsStarted() bool {
+	return sc.nanos.Load() != 0
+}
+
+// Reset stops the clock until the next tick
+func (sc *SimClock) Reset() {
+	sc.nanos.Store(0)
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// GetSimTime returns the current simulated time, the timestamp of the
+// latest tick (the zero time before the first tick)
+// Agents and risk modules should use it instead of time.Now()
+func (h *Holodeck) GetSimTime() time.Time {
+	return h.getClock().Now()
+}
+
+// SimSince returns the simulated time elapsed since t
+func (h *Holodeck) SimSince(t time.Time) time.Duration {
+	return h.GetSimTime().Sub(t)
+}
+
+// SimUntil returns the simulated time remaining until t
+func (h *Holodeck) SimUntil(t time.Time) time.Duration {
+	return t.Sub(h.GetSimTime())
+}
+
+// GetClock returns a Clock reading the session's simulated time, for
+// components that take a types.Clock; it stays valid across Reset
+func (h *Holodeck) GetClock() types.Clock {
+	return types.ClockFunc(h.GetSimTime)
+}
+
+// WithWallClock replaces the wall clock the session paces against and
+// stamps log lines with, e.g. a types.ManualClock so runs and logs are
+// fully deterministic: it goes to the speed controller and to a logger
+// that takes a clock (logger.SimulatorAdapter does), now and when either
+// is attached later. Simulated time (GetSimTime) is not affected
+func (h *Holodeck) WithWallClock(clock types.Clock) *Holodeck {
+	h.wallClock = clock
+	h.applyWallClock()
+	return h
+}
+
+// GetWallClock returns the clock set by WithWallClock (nil = wall clock)
+func (h *Holodeck) GetWallClock() types.Clock {
+	return h.wallClock
+}
+
+// applyWallClock passes the clock set by WithWallClock on to the speed
+// controller and logger
+func (h *Holodeck) applyWallClock() {
+	if h.wallClock == nil {
+		return
+	}
+	if h.speed != nil {
+		h.speed.SetClock(h.wallClock)
+	}
+	if clocked, ok := h.logger.(interface{ SetClock(types.Clock) }); ok {
+		clocked.SetClock(h.wallClock)
+	}
+}
+
+// wallNow returns the time on the clock set by WithWallClock
+func (h *Holodeck) wallNow() time.Time {
+	if h.wallClock == nil {
+		return time.Now()
+	}
+	return h.wallClock.Now()
+}
+
+// getClock returns the current state's clock
+func (h *Holodeck) getClock() *SimClock {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.state.Clock
+}
+
+// ==================== END TIME ====================
+
+// ParseSimTime parses a simulated time given as RFC3339 or as a date
+// (YYYY-MM-DD, UTC); with endOfDay a date means the end of that day
+func ParseSimTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (want YYYY-MM-DD or RFC3339)", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// SetEndTime ends the session at the first tick at or after end (the zero
+// time runs to the end of the data)
+// From then on GetNextTick returns an error, as at the end of the data,
+// so the tick loop finishes and Stop can be called as usual
+func (h *Holodeck) SetEndTime(end time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.endTime = end
+}
+
+// GetEndTime returns the simulated end time (zero when unset)
+func (h *Holodeck) GetEndTime() time.Time {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.endTime
+}
+
+// IsEndTimeReached returns whether the session has reached its end time
+func (h *Holodeck) IsEndTimeReached() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.ended
+}
+
+// checkEndTime returns an error for a tick at or after the end time, and
+// for every tick after that
+// Caller must hold the write lock
+func (h *Holodeck) checkEndTime(tick *types.Tick) error {
+	if h.endTime.IsZero() {
+		return nil
+	}
+	if !h.ended && tick != nil && tick.Timestamp.Before(h.endTime) {
+		return nil
+	}
+
+	if !h.ended {
+		h.ended = true
+		h.logSessionEvent("end_time_reached")
+	}
+	return fmt.Errorf("simulated end time %s reached", h.endTime.Format(time.RFC3339))
+}
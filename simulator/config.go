@@ -6,11 +6,18 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"holodeck/commission"
+	"holodeck/corporate"
 	"holodeck/executor"
+	"holodeck/financing"
 	"holodeck/logger"
+	"holodeck/plugins"
 	"holodeck/reader"
+	"holodeck/risk"
+	"holodeck/speed"
 	"holodeck/types"
 )
 
@@ -18,14 +25,27 @@ import (
 
 // Config is the root configuration structure loaded from JSON
 type Config struct {
-	CSV        CSVConfig        `json:"csv"`
-	Instrument InstrumentConfig `json:"instrument"`
-	Account    AccountConfig    `json:"account"`
-	Execution  ExecutionConfig  `json:"execution"`
-	OrderTypes OrderTypesConfig `json:"order_types"`
-	Speed      SpeedConfig      `json:"speed"`
-	Session    SessionConfig    `json:"session"`
-	Logging    LoggingConfig    `json:"logging"`
+	CSV        CSVConfig                 `json:"csv"`
+	Instrument InstrumentConfig          `json:"instrument"`
+	Account    AccountConfig             `json:"account"`
+	Execution  ExecutionConfig           `json:"execution"`
+	OrderTypes OrderTypesConfig          `json:"order_types"`
+	Speed      SpeedConfig               `json:"speed"`
+	Session    SessionConfig             `json:"session"`
+	Logging    LoggingConfig             `json:"logging"`
+	Metrics    MetricsConfig             `json:"metrics"`
+	Plugins    PluginsConfig             `json:"plugins"`
+	Financing  financing.FinancingConfig `json:"financing"`
+
+	// Interest accrues on idle cash once per simulated day
+	Interest financing.InterestConfig `json:"interest"`
+
+	// CorporateActions is the dividend and split calendar (stocks)
+	CorporateActions corporate.CorporateActionsConfig `json:"corporate_actions"`
+
+	// Instruments are the other symbols of a multi-symbol session, each
+	// priced with its own parameters
+	Instruments []InstrumentConfig `json:"instruments"`
 }
 
 // CSVConfig defines the CSV data source
@@ -43,6 +63,10 @@ type InstrumentConfig struct {
 	ContractSize   int64   `json:"contract_size"`
 	MinimumLotSize float64 `json:"minimum_lot_size"`
 	TickSize       float64 `json:"tick_size"`
+
+	// QuoteCurrency is the currency P&L is quoted in (e.g. JPY for
+	// EURJPY); empty means the account currency
+	QuoteCurrency string `json:"quote_currency"`
 }
 
 // AccountConfig defines account parameters
@@ -52,19 +76,130 @@ type AccountConfig struct {
 	Leverage           float64 `json:"leverage"`
 	MaxPositionSize    float64 `json:"max_position_size"`
 	MaxDrawdownPercent float64 `json:"max_drawdown_percent"`
+
+	// PositionMode is "netting" (default, one net position) or "hedging"
+	// (each opening fill is its own ticket, closed by ticket)
+	PositionMode string `json:"position_mode"`
+
+	// LotMatching matches closes to open lots: "average" (default), "fifo"
+	// or "lifo"
+	LotMatching string `json:"lot_matching"`
+
+	// Conversion converts P&L from the instrument's quote currency to the
+	// account currency
+	Conversion ConversionConfig `json:"conversion"`
+
+	// Conversions convert the other quote currencies of a multi-symbol
+	// session, keyed by currency (e.g. "JPY")
+	Conversions map[string]ConversionConfig `json:"conversions"`
+
+	// Margin levels (equity / used margin, in percent): below
+	// margin_call_level a margin call is raised, below stop_out_level
+	// positions are liquidated; 0 disables each check
+	MarginCallLevel float64 `json:"margin_call_level"`
+	StopOutLevel    float64 `json:"stop_out_level"`
+
+	// NegativeBalanceProtection closes everything and clamps the account
+	// at zero when a move would take equity negative (ESMA-style retail)
+	NegativeBalanceProtection bool `json:"negative_balance_protection"`
+
+	// Exposure limits across all open positions, in notional (size x
+	// price x contract size); orders that would exceed one are rejected
+	// with EXPOSURE_LIMIT_EXCEEDED; 0 disables each
+	MaxGrossExposure float64 `json:"max_gross_exposure"`
+	MaxNetExposure   float64 `json:"max_net_exposure"`
+
+	// DailyLossLimit halts trading for the rest of a simulated day once
+	// the day's loss reaches a limit
+	DailyLossLimit DailyLossLimitConfig `json:"daily_loss_limit"`
+
+	// DrawdownThrottle scales max_position_size down as drawdown from the
+	// equity peak grows, e.g. [{"drawdown_percent": 10, "size_multiplier": 0.5}]
+	// halves the largest order allowed beyond 10% drawdown
+	DrawdownThrottle []risk.DrawdownStep `json:"drawdown_throttle"`
+
+	// LeverageTiers replace the flat leverage with notional bands, keyed
+	// by instrument type (or symbol), e.g. {"FOREX": [{"up_to_notional":
+	// 50000, "leverage": 30}, {"leverage": 10}]}
+	LeverageTiers map[string]types.LeverageTiers `json:"leverage_tiers"`
+
+	// Breakeven is the P&L band (amount and/or pips) within which a closed
+	// trade counts as breakeven instead of a win or loss
+	Breakeven types.BreakevenBand `json:"breakeven"`
+}
+
+// DailyLossLimitConfig sets the daily loss kill switch; the loss is
+// realized + unrealized from the day's opening equity, and 0 disables
+// each limit
+type DailyLossLimitConfig struct {
+	MaxLoss        float64 `json:"max_loss"`         // Account currency
+	MaxLossPercent float64 `json:"max_loss_percent"` // Of opening equity
+	Flatten        bool    `json:"flatten"`          // Close all positions when hit
+}
+
+// ConversionConfig sets the quote-to-account currency rate: a fixed rate
+// ("static") or the mid of a companion pair in the tick stream ("tick")
+type ConversionConfig struct {
+	Source string  `json:"source"`
+	Rate   float64 `json:"rate"`   // Static rate, or the rate until the first companion tick
+	Symbol string  `json:"symbol"` // Companion pair, e.g. USDJPY
 }
 
 // ExecutionConfig defines execution parameters
 type ExecutionConfig struct {
-	Slippage           bool    `json:"slippage"`
-	SlippageModel      string  `json:"slippage_model"`
-	Latency            bool    `json:"latency"`
-	LatencyMs          int64   `json:"latency_ms"`
-	Commission         bool    `json:"commission"`
-	CommissionType     string  `json:"commission_type"`
-	CommissionValue    float64 `json:"commission_value"`
-	PartialFills       bool    `json:"partial_fills"`
-	PartialFillBasedOn string  `json:"partial_fill_based_on"`
+	// Executor selects a custom executor from the executor registry
+	// (empty uses the built-in executor)
+	Executor string `json:"executor"`
+
+	Slippage        bool    `json:"slippage"`
+	SlippageModel   string  `json:"slippage_model"`
+	Latency         bool    `json:"latency"`
+	LatencyMs       int64   `json:"latency_ms"`
+	Commission      bool    `json:"commission"`
+	CommissionType  string  `json:"commission_type"`
+	CommissionValue float64 `json:"commission_value"`
+
+	// CommissionSchedules are tiered schedules (volume tiers, maker/taker
+	// rates) keyed by symbol or instrument type
+	CommissionSchedules map[string]commission.ScheduleConfig `json:"commission_schedules"`
+
+	PartialFills       bool   `json:"partial_fills"`
+	PartialFillBasedOn string `json:"partial_fill_based_on"`
+
+	// ParticipationRate is the share of each tick's volume an order may
+	// take when partial_fill_based_on is "participation" (e.g. 0.1 = 10%)
+	ParticipationRate float64 `json:"participation_rate"`
+
+	// ContinuePartialFills keeps unfilled remainders working on later ticks
+	ContinuePartialFills bool `json:"continue_partial_fills"`
+
+	// LimitQueueModeling rests unfilled limit orders until traded volume
+	// works through the queue ahead of them
+	LimitQueueModeling bool `json:"limit_queue_modeling"`
+
+	// SpreadMarkupType widens raw ticks to retail pricing ("pips" or "percent")
+	SpreadMarkupType  string  `json:"spread_markup_type"`
+	SpreadMarkupValue float64 `json:"spread_markup_value"`
+
+	// Execution guard: reject orders when the spread is wider than
+	// max_spread_pips or the tick is older than max_tick_age_seconds
+	MaxSpreadPips     float64 `json:"max_spread_pips"`
+	MaxTickAgeSeconds float64 `json:"max_tick_age_seconds"`
+
+	// MarginCheck rejects orders needing more margin (notional / leverage)
+	// than the account has available; on unless set to false
+	MarginCheck *bool `json:"margin_check"`
+
+	// SyntheticBook expands L1 ticks into depth levels for large orders
+	SyntheticBook SyntheticBookConfig `json:"synthetic_book"`
+}
+
+// SyntheticBookConfig configures synthetic L2 depth generation
+// Levels <= 1 disables the book
+type SyntheticBookConfig struct {
+	Levels           int     `json:"levels"`
+	Decay            float64 `json:"decay"`
+	LevelSpacingPips float64 `json:"level_spacing_pips"`
 }
 
 // OrderTypesConfig defines supported order types
@@ -76,11 +211,160 @@ type OrderTypesConfig struct {
 // SpeedConfig defines simulation speed
 type SpeedConfig struct {
 	Multiplier float64 `json:"multiplier"`
+
+	// Max runs as fast as possible with no pacing or timing bookkeeping
+	// Set by "speed": "max" or "multiplier": "max" as well
+	Max bool `json:"max"`
+
+	// Pacing is "fixed" (one base tick duration per tick, the default),
+	// "timestamps" (the gap between tick timestamps / multiplier) or
+	// "realtime" (ticks emitted on the wall clock at 1.0x, drift-corrected)
+	Pacing string `json:"pacing"`
+
+	// TicksPerSecond throttles to a tick rate instead of the multiplier
+	// (0 uses the multiplier)
+	TicksPerSecond float64 `json:"ticks_per_second"`
+
+	// RealtimeAlign replays realtime pacing at each tick's own time of day
+	// on today's date, as if the data were live
+	RealtimeAlign bool `json:"realtime_align"`
+
+	// SkipIdleMinutes fast-forwards over gaps between ticks longer than
+	// this many minutes (weekends, overnight) in timestamp pacing
+	// (0 disables)
+	SkipIdleMinutes float64 `json:"skip_idle_minutes"`
+
+	// BatchSize sleeps once per batch of this many ticks instead of after
+	// every tick, amortizing timer overhead at very high multipliers
+	// (0 or 1 sleeps every tick); AutoBatch sizes batches so each sleep is
+	// at least a millisecond
+	BatchSize int  `json:"batch_size"`
+	AutoBatch bool `json:"auto_batch"`
+
+	// Schedule changes the multiplier by simulated time, e.g.
+	// [{"until": "2024-03-01", "multiplier": 10000},
+	//  {"until": "2024-03-08", "multiplier": 1}]
+	// Past the last entry the multiplier above applies
+	Schedule []speed.ScheduleEntry `json:"schedule"`
+
+	// Governor lowers the multiplier when ticks can't be processed in time
+	Governor GovernorConfig `json:"governor"`
+}
+
+// UnmarshalJSON accepts "max" for the whole speed section or for the
+// multiplier, besides the usual object form
+func (sc *SpeedConfig) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		multiplier, max, err := speed.ParseSpeed(value)
+		if err != nil {
+			return err
+		}
+		*sc = SpeedConfig{Multiplier: multiplier, Max: max}
+		return nil
+	}
+
+	type plain SpeedConfig
+	aux := struct {
+		*plain
+		Multiplier json.RawMessage `json:"multiplier"`
+	}{plain: (*plain)(sc)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if len(aux.Multiplier) > 0 {
+		if err := json.Unmarshal(aux.Multiplier, &value); err == nil {
+			multiplier, max, err := speed.ParseSpeed(value)
+			if err != nil {
+				return err
+			}
+			sc.Multiplier = multiplier
+			sc.Max = sc.Max || max
+		} else if err := json.Unmarshal(aux.Multiplier, &sc.Multiplier); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GovernorConfig configures the adaptive speed governor
+// Zero values fall back to speed.DefaultGovernorConfig
+type GovernorConfig struct {
+	Enabled        bool    `json:"enabled"`
+	WindowSize     int64   `json:"window_size"`
+	SlowDownRatio  float64 `json:"slow_down_ratio"`
+	SpeedUpRatio   float64 `json:"speed_up_ratio"`
+	DecreaseFactor float64 `json:"decrease_factor"`
+	IncreaseFactor float64 `json:"increase_factor"`
+	MinMultiplier  float64 `json:"min_multiplier"`
 }
 
 // SessionConfig defines session parameters
 type SessionConfig struct {
 	ClosePositionsAtEnd bool `json:"close_positions_at_end"`
+
+	// EquitySampleSeconds is the simulated time between equity curve
+	// samples (0 samples every tick)
+	EquitySampleSeconds float64 `json:"equity_sample_seconds"`
+
+	// Snapshots periodically writes balance and positions to disk
+	Snapshots SnapshotConfig `json:"snapshots"`
+
+	// History caps the in-memory execution, balance and trade histories
+	History HistoryConfig `json:"history"`
+
+	// LoadStateFile starts the session from the balance, positions and
+	// pending orders saved by an earlier session's SaveStateFile, so
+	// backtests over several files can be chained without flattening
+	LoadStateFile string `json:"load_state_file"`
+	SaveStateFile string `json:"save_state_file"`
+
+	// EndTime ends the session at the first tick at or after this
+	// simulated time (RFC3339, or YYYY-MM-DD for the end of that day), so
+	// a backtest can cover part of a data file, e.g. "2024-01-31"
+	EndTime string `json:"end_time"`
+
+	// PauseOrders is what happens to orders submitted while the session
+	// is paused: "reject" (default) or "queue" them until Resume
+	PauseOrders string `json:"pause_orders"`
+}
+
+// HistoryConfig overrides the history caps (0 keeps the default) and
+// names a directory that receives evicted entries as JSON lines
+type HistoryConfig struct {
+	MaxExecutions     int    `json:"max_executions"`
+	MaxBalanceUpdates int    `json:"max_balance_updates"`
+	MaxPositionTrades int    `json:"max_position_trades"`
+	SpillDir          string `json:"spill_dir"`
+}
+
+// apply copies the configured caps and spill directory over the defaults
+func (hc HistoryConfig) apply(sc *StateConfiguration) {
+	if hc.MaxExecutions > 0 {
+		sc.MaxExecutionHistorySize = hc.MaxExecutions
+	}
+	if hc.MaxBalanceUpdates > 0 {
+		sc.MaxBalanceHistorySize = hc.MaxBalanceUpdates
+	}
+	if hc.MaxPositionTrades > 0 {
+		sc.MaxPositionHistorySize = hc.MaxPositionTrades
+	}
+	sc.HistorySpillDir = hc.SpillDir
+}
+
+// SnapshotConfig writes a snapshot every every_ticks ticks and/or every
+// every_minutes of simulated time to dir, as "json" (default) or "csv"
+type SnapshotConfig struct {
+	Dir          string  `json:"dir"`
+	Format       string  `json:"format"`
+	EveryTicks   int64   `json:"every_ticks"`
+	EveryMinutes float64 `json:"every_minutes"`
+}
+
+// IsEnabled returns true if a directory and an interval are set
+func (sc SnapshotConfig) IsEnabled() bool {
+	return sc.Dir != "" && (sc.EveryTicks > 0 || sc.EveryMinutes > 0)
 }
 
 // LoggingConfig defines logging parameters
@@ -90,6 +374,101 @@ type LoggingConfig struct {
 	LogEveryTick  bool   `json:"log_every_tick"`
 	LogEveryTrade bool   `json:"log_every_trade"`
 	LogMetrics    bool   `json:"log_metrics"`
+
+	// MoneyPrecision overrides decimals for monetary values per
+	// instrument class, e.g. {"CRYPTO": 6}
+	MoneyPrecision map[string]int `json:"money_precision"`
+
+	// EquityFile receives the equity curve and drawdown series at session
+	// end: JSON when it ends in .json, CSV otherwise (empty disables)
+	EquityFile string `json:"equity_file"`
+
+	// HeatmapFile receives the weekday x hour trade heatmap at session
+	// end: JSON when it ends in .json, CSV otherwise (empty disables)
+	HeatmapFile string `json:"heatmap_file"`
+
+	// Sampling thins tick logging and aggregates metrics so verbose
+	// logging stays usable at high speed multipliers
+	Sampling LogSamplingConfig `json:"sampling"`
+
+	// ReplayFile records every tick consumed so the session can be re-run
+	// exactly: canonical CSV when it ends in .csv, binary otherwise
+	// (empty disables). Either format is read back via csv.filepath
+	ReplayFile string `json:"replay_file"`
+
+	// ErrorFilter drops errors below a severity and rate limits each
+	// error code, summarising what it suppressed
+	ErrorFilter logger.ErrorFilterConfig `json:"error_filter"`
+
+	// Progress reports percent complete, ticks/sec and ETA to the logger
+	// and the OnProgress callback
+	Progress ProgressConfig `json:"progress"`
+}
+
+// LogSamplingConfig defines log sampling
+type LogSamplingConfig struct {
+	// TickEvery logs every Nth tick (0 or 1 logs every tick)
+	TickEvery int `json:"tick_every"`
+
+	// MetricsEveryMinutes emits aggregated metrics every M simulated
+	// minutes (0 disables)
+	MetricsEveryMinutes float64 `json:"metrics_every_minutes"`
+}
+
+// IsEnabled returns true if ticks are thinned or metrics aggregated
+func (lc LogSamplingConfig) IsEnabled() bool {
+	return lc.TickEvery > 1 || lc.MetricsEveryMinutes > 0
+}
+
+// ProgressConfig defines how often progress is reported
+type ProgressConfig struct {
+	// EveryTicks reports every N ticks (0 disables)
+	EveryTicks int64 `json:"every_ticks"`
+
+	// EverySeconds reports every N seconds of wall-clock time (0 disables)
+	EverySeconds float64 `json:"every_seconds"`
+}
+
+// IsEnabled returns true if either interval is set
+func (pc ProgressConfig) IsEnabled() bool {
+	return pc.EveryTicks > 0 || pc.EverySeconds > 0
+}
+
+// MetricsConfig defines how performance metrics are computed
+type MetricsConfig struct {
+	// Sharpe sets the Sharpe ratio's return period, annual risk-free rate
+	// and annualization factor
+	Sharpe types.SharpeConfig `json:"sharpe"`
+
+	// Rolling emits return, Sharpe and win rate over a trailing window
+	// of simulated time to the metrics log
+	Rolling RollingConfig `json:"rolling"`
+
+	// BenchmarkSize is the buy-and-hold benchmark's position size; 0
+	// holds the strategy's largest position
+	BenchmarkSize float64 `json:"benchmark_size"`
+
+	// Alerts are metric thresholds reported through OnAlert and the
+	// error log when breached
+	Alerts []AlertRule `json:"alerts"`
+}
+
+// RollingConfig defines the rolling metrics window
+type RollingConfig struct {
+	WindowDays float64 `json:"window_days"`
+	EveryHours float64 `json:"every_hours"` // Simulated time between emissions (default 24)
+}
+
+// IsEnabled returns true if a window is set
+func (rc RollingConfig) IsEnabled() bool {
+	return rc.WindowDays > 0
+}
+
+// PluginsConfig names external plugin binaries that replace built-in subsystems
+// A single binary may serve several kinds; it is launched once
+type PluginsConfig struct {
+	Reader   string `json:"reader"`
+	Executor string `json:"executor"`
 }
 
 // ==================== CONFIGURATION LOADER ====================
@@ -172,7 +551,12 @@ func (cl *ConfigLoader) Validate() error {
 	cl.validateExecution()
 	cl.validateOrderTypes()
 	cl.validateSpeed()
+	cl.validateSession()
 	cl.validateLogging()
+	cl.validateMetrics()
+	cl.validateFinancing()
+	cl.validateInterest()
+	cl.validateCorporateActions()
 
 	// Return first error if any
 	if len(cl.Errors) > 0 {
@@ -184,6 +568,11 @@ func (cl *ConfigLoader) Validate() error {
 
 // validateCSV validates CSV configuration
 func (cl *ConfigLoader) validateCSV() {
+	// A reader plugin supplies ticks instead of the CSV file
+	if cl.Config.Plugins.Reader != "" {
+		return
+	}
+
 	if cl.Config.CSV.FilePath == "" {
 		cl.Errors = append(cl.Errors,
 			types.NewConfigError("csv.filepath", "CSV filepath cannot be empty"))
@@ -197,49 +586,68 @@ func (cl *ConfigLoader) validateCSV() {
 	}
 }
 
-// validateInstrument validates instrument configuration
+// validateInstrument validates instrument configuration, and the other
+// symbols' instruments of a multi-symbol session
 func (cl *ConfigLoader) validateInstrument() {
+	cl.validateInstrumentConfig("instrument", cl.Config.Instrument)
+
+	symbols := map[string]bool{cl.Config.Instrument.Symbol: true}
+	for i, ic := range cl.Config.Instruments {
+		field := fmt.Sprintf("instruments[%d]", i)
+		cl.validateInstrumentConfig(field, ic)
+
+		if ic.Symbol != "" && symbols[ic.Symbol] {
+			cl.Errors = append(cl.Errors,
+				types.NewConfigError(field+".symbol", fmt.Sprintf("duplicate instrument symbol: %s", ic.Symbol)))
+		}
+		symbols[ic.Symbol] = true
+	}
+}
+
+// validateInstrumentConfig validates one instrument's parameters, naming
+// its fields under prefix
+func (cl *ConfigLoader) validateInstrumentConfig(prefix string, ic InstrumentConfig) {
 	// Check instrument type
-	if !types.IsValidInstrumentType(cl.Config.Instrument.Type) {
+	if !types.IsValidInstrumentType(ic.Type) {
 		cl.Errors = append(cl.Errors,
-			types.NewConfigError("instrument.type", fmt.Sprintf("invalid instrument type: %s", cl.Config.Instrument.Type)))
+			types.NewConfigError(prefix+".type", fmt.Sprintf("invalid instrument type: %s", ic.Type)))
 		return
 	}
 
 	// Check symbol
-	if cl.Config.Instrument.Symbol == "" {
+	if ic.Symbol == "" {
 		cl.Errors = append(cl.Errors,
-			types.NewConfigError("instrument.symbol", "symbol cannot be empty"))
+			types.NewConfigError(prefix+".symbol", "symbol cannot be empty"))
 	}
 
 	// Check decimal places
-	if cl.Config.Instrument.DecimalPlaces < 0 {
+	if ic.DecimalPlaces < 0 {
 		cl.Errors = append(cl.Errors,
-			types.NewConfigError("instrument.decimal_places", "decimal places cannot be negative"))
+			types.NewConfigError(prefix+".decimal_places", "decimal places cannot be negative"))
 	}
 
 	// Check pip value
-	if cl.Config.Instrument.PipValue <= 0 {
+	if ic.PipValue <= 0 {
 		cl.Errors = append(cl.Errors,
-			types.NewConfigError("instrument.pip_value", "pip value must be positive"))
+			types.NewConfigError(prefix+".pip_value", "pip value must be positive"))
 	}
 
 	// Check contract size
-	if cl.Config.Instrument.ContractSize <= 0 {
+	if ic.ContractSize <= 0 {
 		cl.Errors = append(cl.Errors,
-			types.NewConfigError("instrument.contract_size", "contract size must be positive"))
+			types.NewConfigError(prefix+".contract_size", "contract size must be positive"))
 	}
 
 	// Check minimum lot size
-	if cl.Config.Instrument.MinimumLotSize <= 0 {
+	if ic.MinimumLotSize <= 0 {
 		cl.Errors = append(cl.Errors,
-			types.NewConfigError("instrument.minimum_lot_size", "minimum lot size must be positive"))
+			types.NewConfigError(prefix+".minimum_lot_size", "minimum lot size must be positive"))
 	}
 
 	// Check tick size
-	if cl.Config.Instrument.TickSize <= 0 {
+	if ic.TickSize <= 0 {
 		cl.Errors = append(cl.Errors,
-			types.NewConfigError("instrument.tick_size", "tick size must be positive"))
+			types.NewConfigError(prefix+".tick_size", "tick size must be positive"))
 	}
 }
 
@@ -274,13 +682,109 @@ func (cl *ConfigLoader) validateAccount() {
 		cl.Errors = append(cl.Errors,
 			types.NewConfigError("account.max_drawdown_percent", "max drawdown must be between 0 and 100"))
 	}
+
+	// Check position mode
+	if !types.IsValidPositionMode(cl.Config.Account.PositionMode) {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("account.position_mode",
+				fmt.Sprintf("position mode must be %s or %s", types.PositionModeNetting, types.PositionModeHedging)))
+	}
+
+	// Check lot matching
+	if !types.IsValidLotMatching(cl.Config.Account.LotMatching) {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("account.lot_matching",
+				fmt.Sprintf("lot matching must be %s, %s or %s",
+					types.LotMatchingAverage, types.LotMatchingFIFO, types.LotMatchingLIFO)))
+	}
+
+	// Check margin call and stop-out levels
+	if cl.Config.Account.MarginCallLevel < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("account.margin_call_level", "margin call level cannot be negative"))
+	}
+	if cl.Config.Account.StopOutLevel < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("account.stop_out_level", "stop-out level cannot be negative"))
+	} else if cl.Config.Account.MarginCallLevel > 0 && cl.Config.Account.StopOutLevel >= cl.Config.Account.MarginCallLevel {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("account.stop_out_level", "stop-out level must be below the margin call level"))
+	}
+
+	// Check exposure limits
+	if cl.Config.Account.MaxGrossExposure < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("account.max_gross_exposure", "max gross exposure cannot be negative"))
+	}
+	if cl.Config.Account.MaxNetExposure < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("account.max_net_exposure", "max net exposure cannot be negative"))
+	}
+
+	// Check daily loss limit
+	if cl.Config.Account.DailyLossLimit.MaxLoss < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("account.daily_loss_limit.max_loss", "max loss cannot be negative"))
+	}
+	if percent := cl.Config.Account.DailyLossLimit.MaxLossPercent; percent < 0 || percent > 100 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("account.daily_loss_limit.max_loss_percent", "max loss percent must be between 0 and 100"))
+	}
+
+	// Check leverage tiers
+	for key, tiers := range cl.Config.Account.LeverageTiers {
+		if len(tiers) == 0 {
+			cl.Errors = append(cl.Errors,
+				types.NewConfigError("account.leverage_tiers."+key, "at least one tier is required"))
+		} else if err := tiers.Validate(); err != nil {
+			cl.Errors = append(cl.Errors,
+				types.NewConfigError("account.leverage_tiers."+key, err.Error()))
+		}
+	}
+
+	// Check breakeven band
+	if err := cl.Config.Account.Breakeven.Validate(); err != nil {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("account.breakeven", err.Error()))
+	}
+
+	// Check drawdown throttle steps
+	if _, err := risk.NewDrawdownThrottle(cl.Config.Account.DrawdownThrottle); err != nil {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("account.drawdown_throttle", err.Error()))
+	}
+
+	// Check currency conversion
+	conversions := map[string]ConversionConfig{"account.conversion": cl.Config.Account.Conversion}
+	for currency, conversion := range cl.Config.Account.Conversions {
+		conversions[fmt.Sprintf("account.conversions.%s", currency)] = conversion
+	}
+	valid := true
+	for field, conversion := range conversions {
+		if !types.IsValidConversionSource(conversion.Source) {
+			cl.Errors = append(cl.Errors,
+				types.NewConfigError(field+".source",
+					fmt.Sprintf("conversion source must be %s or %s", types.ConversionSourceStatic, types.ConversionSourceTick)))
+			valid = false
+		} else if conversion.Rate < 0 {
+			cl.Errors = append(cl.Errors,
+				types.NewConfigError(field+".rate", "conversion rate cannot be negative"))
+			valid = false
+		}
+	}
+	if valid {
+		if _, err := cl.Config.NewCurrencyConverters(); err != nil {
+			cl.Errors = append(cl.Errors,
+				types.NewConfigError("account.conversion", err.Error()))
+		}
+	}
 }
 
 // validateExecution validates execution configuration
 func (cl *ConfigLoader) validateExecution() {
 	// Check slippage model if enabled
 	if cl.Config.Execution.Slippage {
-		validModels := []string{types.SlippageModelDepth, types.SlippageModelMomentum, types.SlippageModelFixed, types.SlippageModelNone}
+		validModels := []string{types.SlippageModelDepth, types.SlippageModelMomentum, types.SlippageModelFixed, types.SlippageModelNone, types.SlippageModelImpact}
 		found := false
 		for _, m := range validModels {
 			if cl.Config.Execution.SlippageModel == m {
@@ -308,7 +812,7 @@ func (cl *ConfigLoader) validateExecution() {
 
 	// Check partial fills
 	if cl.Config.Execution.PartialFills {
-		validLogic := []string{types.PartialFillByVolumeMomentum, types.PartialFillByDepth, types.PartialFillNone}
+		validLogic := []string{types.PartialFillByVolumeMomentum, types.PartialFillByDepth, types.PartialFillNone, types.PartialFillByParticipation}
 		found := false
 		for _, l := range validLogic {
 			if cl.Config.Execution.PartialFillBasedOn == l {
@@ -321,6 +825,120 @@ func (cl *ConfigLoader) validateExecution() {
 				types.NewConfigError("execution.partial_fill_based_on", fmt.Sprintf("invalid partial fill logic: %s", cl.Config.Execution.PartialFillBasedOn)))
 		}
 	}
+
+	// Participation needs a rate
+	if cl.Config.Execution.PartialFills && cl.Config.Execution.PartialFillBasedOn == types.PartialFillByParticipation {
+		if rate := cl.Config.Execution.ParticipationRate; rate <= 0 || rate > 1 {
+			cl.Errors = append(cl.Errors,
+				types.NewConfigError("execution.participation_rate", "must be greater than 0 and at most 1"))
+		}
+	}
+
+	// Continuing partial fills requires partial fills
+	if cl.Config.Execution.ContinuePartialFills && !cl.Config.Execution.PartialFills {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("execution.continue_partial_fills", "requires partial_fills to be enabled"))
+	}
+
+	// Check spread markup
+	switch cl.Config.Execution.SpreadMarkupType {
+	case "", types.SpreadMarkupPips, types.SpreadMarkupPercent:
+	default:
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("execution.spread_markup_type", fmt.Sprintf("invalid spread markup type: %s", cl.Config.Execution.SpreadMarkupType)))
+	}
+	if cl.Config.Execution.SpreadMarkupValue < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("execution.spread_markup_value", "spread markup cannot be negative"))
+	}
+
+	// Check execution guard
+	if cl.Config.Execution.MaxSpreadPips < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("execution.max_spread_pips", "max spread cannot be negative"))
+	}
+	if cl.Config.Execution.MaxTickAgeSeconds < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("execution.max_tick_age_seconds", "max tick age cannot be negative"))
+	}
+
+	// Check synthetic book
+	if cl.Config.Execution.SyntheticBook.Levels < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("execution.synthetic_book.levels", "levels cannot be negative"))
+	}
+	if cl.Config.Execution.SyntheticBook.Decay < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("execution.synthetic_book.decay", "decay cannot be negative"))
+	}
+	if cl.Config.Execution.SyntheticBook.LevelSpacingPips < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("execution.synthetic_book.level_spacing_pips", "level spacing cannot be negative"))
+	}
+
+	// Check custom executor
+	if name := cl.Config.Execution.Executor; name != "" {
+		if !Executors.Has(name) {
+			cl.Errors = append(cl.Errors,
+				types.NewConfigError("execution.executor", fmt.Sprintf("unknown executor: %s (registered: %v)", name, Executors.List())))
+		}
+		if cl.Config.Plugins.Executor != "" {
+			cl.Errors = append(cl.Errors,
+				types.NewConfigError("execution.executor", "cannot be combined with plugins.executor"))
+		}
+	}
+
+	// Check commission schedules
+	for key, schedule := range cl.Config.Execution.CommissionSchedules {
+		if err := commission.ValidateScheduleConfig(schedule); err != nil {
+			cl.Errors = append(cl.Errors,
+				types.NewConfigError("execution.commission_schedules."+key, err.Error()))
+		}
+	}
+}
+
+// validateCorporateActions validates the dividend and split calendar
+func (cl *ConfigLoader) validateCorporateActions() {
+	if !cl.Config.CorporateActions.Enabled {
+		return
+	}
+
+	if _, err := corporate.NewCorporateActionsEngine(cl.Config.CorporateActions); err != nil {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("corporate_actions", err.Error()))
+	}
+}
+
+// validateFinancing validates swap configuration
+func (cl *ConfigLoader) validateFinancing() {
+	if !cl.Config.Financing.Enabled {
+		return
+	}
+
+	if _, err := financing.NewFinancingEngine(cl.Config.Financing); err != nil {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("financing", err.Error()))
+	}
+	if len(cl.Config.Financing.SwapRates) == 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("financing.swap_rates", "at least one swap rate is required when financing is enabled"))
+	}
+}
+
+// validateInterest validates idle cash interest configuration
+func (cl *ConfigLoader) validateInterest() {
+	if !cl.Config.Interest.Enabled {
+		return
+	}
+
+	if _, err := financing.NewInterestEngine(cl.Config.Interest); err != nil {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("interest", err.Error()))
+	}
+	if cl.Config.Interest.AnnualRate == 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("interest.annual_rate", "annual_rate is required when interest is enabled"))
+	}
 }
 
 // validateOrderTypes validates order types configuration
@@ -366,11 +984,103 @@ func (cl *ConfigLoader) validateOrderTypes() {
 
 // validateSpeed validates speed configuration
 func (cl *ConfigLoader) validateSpeed() {
-	// Check speed multiplier
-	if cl.Config.Speed.Multiplier < 0.1 || cl.Config.Speed.Multiplier > 10000.0 {
+	// Check speed multiplier (unused at max speed or a tick rate)
+	if !cl.Config.Speed.Max && cl.Config.Speed.TicksPerSecond == 0 &&
+		(cl.Config.Speed.Multiplier < 0.1 || cl.Config.Speed.Multiplier > 10000.0) {
 		cl.Errors = append(cl.Errors,
 			types.NewConfigError("speed.multiplier", "speed multiplier must be between 0.1 and 10000"))
 	}
+
+	// Check pacing mode
+	pacing, err := speed.ParsePacingMode(cl.Config.Speed.Pacing)
+	if err != nil {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("speed.pacing", err.Error()))
+	}
+	if cl.Config.Speed.RealtimeAlign && pacing != speed.PacingRealtime {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("speed.realtime_align", "realtime_align requires realtime pacing"))
+	}
+
+	if cl.Config.Speed.TicksPerSecond < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("speed.ticks_per_second", "ticks per second cannot be negative"))
+	}
+
+	if cl.Config.Speed.SkipIdleMinutes < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("speed.skip_idle_minutes", "idle skip threshold cannot be negative"))
+	}
+
+	if cl.Config.Speed.BatchSize < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("speed.batch_size", "batch size cannot be negative"))
+	}
+
+	// Check schedule
+	if _, err := speed.NewSpeedSchedule(cl.Config.Speed.Schedule); err != nil {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("speed.schedule", err.Error()))
+	}
+	for _, entry := range cl.Config.Speed.Schedule {
+		if entry.Multiplier > 0 && (entry.Multiplier < 0.1 || entry.Multiplier > 10000.0) {
+			cl.Errors = append(cl.Errors,
+				types.NewConfigError("speed.schedule", "scheduled multipliers must be between 0.1 and 10000"))
+			break
+		}
+	}
+
+	// Check governor
+	if cl.Config.Speed.Governor.Enabled {
+		if _, err := cl.Config.NewSpeedGovernor(); err != nil {
+			cl.Errors = append(cl.Errors,
+				types.NewConfigError("speed.governor", err.Error()))
+		}
+	}
+}
+
+// validateSession validates session configuration
+func (cl *ConfigLoader) validateSession() {
+	if cl.Config.Session.EquitySampleSeconds < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("session.equity_sample_seconds", "equity sample interval cannot be negative"))
+	}
+
+	snapshots := cl.Config.Session.Snapshots
+	if snapshots.EveryTicks < 0 || snapshots.EveryMinutes < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("session.snapshots", "snapshot intervals cannot be negative"))
+	}
+	if snapshots.Dir != "" && snapshots.EveryTicks == 0 && snapshots.EveryMinutes == 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("session.snapshots", "every_ticks or every_minutes required with a snapshot dir"))
+	}
+	if snapshots.Format != "" && snapshots.Format != SnapshotFormatJSON && snapshots.Format != SnapshotFormatCSV {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("session.snapshots.format",
+				fmt.Sprintf("snapshot format must be %s or %s", SnapshotFormatJSON, SnapshotFormatCSV)))
+	}
+
+	history := cl.Config.Session.History
+	if history.MaxExecutions < 0 || history.MaxBalanceUpdates < 0 || history.MaxPositionTrades < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("session.history", "history caps cannot be negative"))
+	}
+
+	if end := cl.Config.Session.EndTime; end != "" {
+		if _, err := ParseSimTime(end, true); err != nil {
+			cl.Errors = append(cl.Errors,
+				types.NewConfigError("session.end_time", err.Error()))
+		}
+	}
+
+	switch cl.Config.Session.PauseOrders {
+	case "", PauseOrdersReject, PauseOrdersQueue:
+	default:
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("session.pause_orders",
+				fmt.Sprintf("pause_orders must be %s or %s", PauseOrdersReject, PauseOrdersQueue)))
+	}
 }
 
 // validateLogging validates logging configuration
@@ -380,6 +1090,55 @@ func (cl *ConfigLoader) validateLogging() {
 		cl.Errors = append(cl.Errors,
 			types.NewConfigError("logging.log_file", "log file path required if logging is enabled"))
 	}
+
+	// Check money precision overrides
+	if _, err := types.NewPrecisionPolicy(cl.Config.Logging.MoneyPrecision); err != nil {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("logging.money_precision", err.Error()))
+	}
+
+	if cl.Config.Logging.Sampling.TickEvery < 0 || cl.Config.Logging.Sampling.MetricsEveryMinutes < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("logging.sampling", "sampling intervals cannot be negative"))
+	}
+
+	if cl.Config.Logging.Progress.EveryTicks < 0 || cl.Config.Logging.Progress.EverySeconds < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("logging.progress", "progress intervals cannot be negative"))
+	}
+
+	if err := cl.Config.Logging.ErrorFilter.Validate(); err != nil {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("logging.error_filter", err.Error()))
+	}
+
+	if path := cl.Config.Logging.ReplayFile; path != "" && path == cl.Config.CSV.FilePath {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("logging.replay_file", "replay file cannot be the CSV input file"))
+	}
+}
+
+// validateMetrics validates metrics configuration
+func (cl *ConfigLoader) validateMetrics() {
+	if err := cl.Config.Metrics.Sharpe.Validate(); err != nil {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("metrics.sharpe", err.Error()))
+	}
+
+	if cl.Config.Metrics.Rolling.WindowDays < 0 || cl.Config.Metrics.Rolling.EveryHours < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("metrics.rolling", "rolling window and interval cannot be negative"))
+	}
+
+	if cl.Config.Metrics.BenchmarkSize < 0 {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("metrics.benchmark_size", "benchmark size cannot be negative"))
+	}
+
+	if _, err := NewAlertMonitor(cl.Config.Metrics.Alerts); err != nil {
+		cl.Errors = append(cl.Errors,
+			types.NewConfigError("metrics.alerts", err.Error()))
+	}
 }
 
 // ==================== GETTERS WITH DEFAULTS ====================
@@ -404,6 +1163,71 @@ func (c *Config) GetLeverage() float64 {
 	return c.Account.Leverage
 }
 
+// IsHedging checks if the account tracks positions by ticket
+func (c *Config) IsHedging() bool {
+	return c.Account.PositionMode == types.PositionModeHedging
+}
+
+// GetQuoteCurrencies maps every symbol quoted in a currency other than the
+// account's to that currency, upper-cased ("" stands for the session
+// instrument)
+func (c *Config) GetQuoteCurrencies() map[string]string {
+	quotes := make(map[string]string)
+	add := func(symbol, quote string) {
+		if quote != "" && !strings.EqualFold(quote, c.Account.Currency) {
+			quotes[symbol] = strings.ToUpper(quote)
+		}
+	}
+
+	add("", c.Instrument.QuoteCurrency)
+	add(c.Instrument.Symbol, c.Instrument.QuoteCurrency)
+	for _, ic := range c.Instruments {
+		add(ic.Symbol, ic.QuoteCurrency)
+	}
+	return quotes
+}
+
+// NewCurrencyConverters builds one quote-to-account currency converter per
+// quote currency other than the account's, keyed by that currency
+// The session instrument's currency uses account.conversion unless
+// account.conversions has an entry for it; every other currency needs one
+func (c *Config) NewCurrencyConverters() (map[string]*types.CurrencyConverter, error) {
+	converters := make(map[string]*types.CurrencyConverter)
+	for _, quote := range c.GetQuoteCurrencies() {
+		if _, ok := converters[quote]; ok {
+			continue
+		}
+
+		conversion, ok := c.Account.Conversions[quote]
+		if !ok && strings.EqualFold(quote, c.Instrument.QuoteCurrency) {
+			conversion = c.Account.Conversion
+		}
+
+		converter, err := newCurrencyConverter(quote, c.Account.Currency, conversion)
+		if err != nil {
+			return nil, err
+		}
+		converters[quote] = converter
+	}
+	return converters, nil
+}
+
+// newCurrencyConverter builds a static or tick-driven converter
+func newCurrencyConverter(from, to string, conversion ConversionConfig) (*types.CurrencyConverter, error) {
+	if conversion.Source == types.ConversionSourceTick {
+		if conversion.Symbol == "" {
+			return nil, fmt.Errorf("tick conversion %s->%s requires a companion symbol", from, to)
+		}
+		return types.NewTickConverter(from, to, conversion.Symbol, conversion.Rate)
+	}
+	return types.NewStaticConverter(from, to, conversion.Rate)
+}
+
+// GetEquitySampleInterval returns the simulated time between equity samples
+func (c *Config) GetEquitySampleInterval() time.Duration {
+	return time.Duration(c.Session.EquitySampleSeconds * float64(time.Second))
+}
+
 // GetMaxDrawdownPercent returns the max drawdown limit
 func (c *Config) GetMaxDrawdownPercent() float64 {
 	return c.Account.MaxDrawdownPercent
@@ -414,6 +1238,20 @@ func (c *Config) GetSpeedMultiplier() float64 {
 	return c.Speed.Multiplier
 }
 
+// IsMarginCheckEnabled returns true unless margin_check is set to false
+func (c *Config) IsMarginCheckEnabled() bool {
+	return c.Execution.MarginCheck == nil || *c.Execution.MarginCheck
+}
+
+// IsSpeedControlled returns true when any speed setting (rate, pacing,
+// idle skipping, batching, schedule or governor) needs a speed controller
+func (c *Config) IsSpeedControlled() bool {
+	s := c.Speed
+	return s.Max || s.Multiplier > 0 || s.TicksPerSecond > 0 ||
+		s.Pacing != "" || s.RealtimeAlign || s.SkipIdleMinutes > 0 ||
+		s.BatchSize > 1 || s.AutoBatch || len(s.Schedule) > 0 || s.Governor.Enabled
+}
+
 // GetCommissionValue returns the commission value
 func (c *Config) GetCommissionValue() float64 {
 	return c.Execution.CommissionValue
@@ -677,6 +1515,15 @@ func (c *Config) NewCSVReader() (TickReader, error) {
 		return nil, types.NewConfigError("csv.filepath", fmt.Sprintf("CSV file not found: %s", c.CSV.FilePath))
 	}
 
+	// Binary replay files are recognised by their header
+	if reader.IsBinaryReplayFile(c.CSV.FilePath) {
+		replayReader, err := reader.NewBinaryTickReader(c.CSV.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create replay reader: %w", err)
+		}
+		return replayReader, nil
+	}
+
 	// Create CSV reader with default configuration
 	// The reader will use RFC3339Nano timestamp format and skip the first line (header)
 	csvReader, err := reader.NewCSVTickReader(c.CSV.FilePath)
@@ -687,81 +1534,373 @@ func (c *Config) NewCSVReader() (TickReader, error) {
 	return csvReader, nil
 }
 
-// NewExecutor creates an order executor from config
+// NewTickReader creates the tick reader from config
+// Uses the reader plugin if one is configured, otherwise the CSV reader
+func (c *Config) NewTickReader(pluginSet *plugins.Set) (TickReader, error) {
+	if c.Plugins.Reader == "" {
+		return c.NewCSVReader()
+	}
+
+	client, err := pluginSet.Get(c.Plugins.Reader)
+	if err != nil {
+		return nil, types.NewConfigError("plugins.reader", err.Error())
+	}
+	return client.Reader()
+}
+
+// NewPluginExecutor creates the executor served by the configured plugin
+// Returns nil if no executor plugin is configured
+func (c *Config) NewPluginExecutor(pluginSet *plugins.Set) (OrderExecutor, error) {
+	if c.Plugins.Executor == "" {
+		return nil, nil
+	}
+
+	client, err := pluginSet.Get(c.Plugins.Executor)
+	if err != nil {
+		return nil, types.NewConfigError("plugins.executor", err.Error())
+	}
+	return client.Executor()
+}
+
+// NewRegisteredExecutor creates the executor selected by execution.executor
+// Returns nil if no registered executor is configured
+func (c *Config) NewRegisteredExecutor() (OrderExecutor, error) {
+	if c.Execution.Executor == "" {
+		return nil, nil
+	}
+
+	exec, err := Executors.New(c.Execution.Executor, c)
+	if err != nil {
+		return nil, types.NewConfigError("execution.executor", err.Error())
+	}
+	return exec, nil
+}
+
+// NewCommissionSchedules creates the configured commission schedules
+func (c *Config) NewCommissionSchedules() (map[string]*commission.CommissionSchedule, error) {
+	schedules := make(map[string]*commission.CommissionSchedule, len(c.Execution.CommissionSchedules))
+	for key, scheduleConfig := range c.Execution.CommissionSchedules {
+		schedule, err := commission.NewCommissionSchedule(scheduleConfig)
+		if err != nil {
+			return nil, types.NewConfigError("execution.commission_schedules."+key, err.Error())
+		}
+		schedules[key] = schedule
+	}
+	return schedules, nil
+}
+
+// GetLeverageTiers returns the leverage tiers for an instrument, looked up
+// by symbol first and then by instrument type (nil = flat leverage)
+func (c *Config) GetLeverageTiers(instrument types.Instrument) types.LeverageTiers {
+	return types.GetLeverageTiers(c.Account.LeverageTiers, instrument)
+}
+
 // NewExecutor creates an order executor from config
 func (c *Config) NewExecutor() (*executor.OrderExecutor, error) {
-	return executor.NewOrderExecutor(executor.ExecutorConfig{
-		CommissionEnabled:   c.Execution.Commission,
-		SlippageEnabled:     c.Execution.Slippage,
-		LatencyEnabled:      c.Execution.Latency,
-		PartialFillsEnabled: c.Execution.PartialFills,
-		MaxOrderSize:        c.Account.MaxPositionSize,
-		MaxPositionSize:     c.Account.MaxPositionSize,
-		MinimumOrderSize:    c.Instrument.MinimumLotSize,
-	}), nil
-}
-
-// NewLogger creates a logger from config
+	schedules, err := c.NewCommissionSchedules()
+	if err != nil {
+		return nil, err
+	}
+
+	oe, err := executor.NewOrderExecutor(executor.ExecutorConfig{
+		CommissionEnabled:    c.Execution.Commission,
+		CommissionSchedules:  schedules,
+		SlippageEnabled:      c.Execution.Slippage,
+		LatencyEnabled:       c.Execution.Latency,
+		PartialFillsEnabled:  c.Execution.PartialFills,
+		PartialFillModel:     c.Execution.PartialFillBasedOn,
+		ParticipationRate:    c.Execution.ParticipationRate,
+		ContinuePartialFills: c.Execution.ContinuePartialFills,
+		LimitQueueModeling:   c.Execution.LimitQueueModeling,
+		SlippageModel:        c.Execution.SlippageModel,
+		SpreadMarkupType:     c.Execution.SpreadMarkupType,
+		SpreadMarkupValue:    c.Execution.SpreadMarkupValue,
+		MaxSpreadPips:        c.Execution.MaxSpreadPips,
+		MaxTickAge:           time.Duration(c.Execution.MaxTickAgeSeconds * float64(time.Second)),
+		MarginCheck:          c.IsMarginCheckEnabled(),
+		MaxGrossExposure:     c.Account.MaxGrossExposure,
+		MaxNetExposure:       c.Account.MaxNetExposure,
+		LeverageTiers:        c.Account.LeverageTiers,
+		SyntheticBook: executor.BookConfig{
+			Levels:           c.Execution.SyntheticBook.Levels,
+			Decay:            c.Execution.SyntheticBook.Decay,
+			LevelSpacingPips: c.Execution.SyntheticBook.LevelSpacingPips,
+		},
+		MaxOrderSize:     c.Account.MaxPositionSize,
+		MaxPositionSize:  c.Account.MaxPositionSize,
+		MinimumOrderSize: c.Instrument.MinimumLotSize,
+	})
+	if err != nil {
+		return nil, types.NewConfigError("execution.slippage_model", err.Error())
+	}
+	return oe, nil
+}
+
+// NewSpeedGovernor creates the adaptive speed governor from config
+// Returns nil if the governor is disabled
+func (c *Config) NewSpeedGovernor() (*speed.SpeedGovernor, error) {
+	if !c.Speed.Governor.Enabled {
+		return nil, nil
+	}
+	return speed.NewSpeedGovernor(speed.GovernorConfig{
+		WindowSize:     c.Speed.Governor.WindowSize,
+		SlowDownRatio:  c.Speed.Governor.SlowDownRatio,
+		SpeedUpRatio:   c.Speed.Governor.SpeedUpRatio,
+		DecreaseFactor: c.Speed.Governor.DecreaseFactor,
+		IncreaseFactor: c.Speed.Governor.IncreaseFactor,
+		MinMultiplier:  c.Speed.Governor.MinMultiplier,
+	})
+}
+
+// NewSpeedController creates a speed controller from config
+// Without a multiplier the controller runs at 1.0x
+func (c *Config) NewSpeedController() (*speed.SpeedController, error) {
+	controller := speed.NewSpeedController()
+	if c.Speed.Max {
+		controller.SetMaxSpeed(true)
+	} else if c.Speed.TicksPerSecond > 0 {
+		if err := controller.SetTicksPerSecond(c.Speed.TicksPerSecond); err != nil {
+			return nil, types.NewConfigError("speed.ticks_per_second", err.Error())
+		}
+	} else if c.Speed.Multiplier > 0 {
+		if err := controller.SetSpeed(c.Speed.Multiplier); err != nil {
+			return nil, types.NewConfigError("speed.multiplier", err.Error())
+		}
+	}
+
+	pacing, err := speed.ParsePacingMode(c.Speed.Pacing)
+	if err != nil {
+		return nil, types.NewConfigError("speed.pacing", err.Error())
+	}
+	controller.SetPacing(pacing)
+	controller.SetRealtimeAlign(c.Speed.RealtimeAlign)
+
+	if err := controller.SetIdleSkip(time.Duration(c.Speed.SkipIdleMinutes * float64(time.Minute))); err != nil {
+		return nil, types.NewConfigError("speed.skip_idle_minutes", err.Error())
+	}
+
+	if err := controller.SetBatchSize(c.Speed.BatchSize); err != nil {
+		return nil, types.NewConfigError("speed.batch_size", err.Error())
+	}
+	controller.SetAutoBatch(c.Speed.AutoBatch)
+
+	if len(c.Speed.Schedule) > 0 {
+		schedule, err := speed.NewSpeedSchedule(c.Speed.Schedule)
+		if err != nil {
+			return nil, types.NewConfigError("speed.schedule", err.Error())
+		}
+		controller.SetSchedule(schedule)
+	}
+
+	governor, err := c.NewSpeedGovernor()
+	if err != nil {
+		return nil, types.NewConfigError("speed.governor", err.Error())
+	}
+	if governor != nil {
+		controller.SetGovernor(governor)
+	}
+
+	return controller, nil
+}
+
+// NewPrecisionPolicy creates the money formatting policy from config
+// Invalid overrides fall back to the default policy (validation reports them)
+func (c *Config) NewPrecisionPolicy() *types.PrecisionPolicy {
+	policy, err := types.NewPrecisionPolicy(c.Logging.MoneyPrecision)
+	if err != nil {
+		return types.DefaultPrecisionPolicy()
+	}
+	return policy
+}
+
+// GetMoneyDecimals returns the decimals for monetary values of the
+// configured instrument
+func (c *Config) GetMoneyDecimals() int {
+	return c.NewPrecisionPolicy().GetMoneyDecimals(c.Instrument.Type)
+}
+
+// GetLogDir returns the directory session logs are written to: the
+// directory of logging.log_file, or log_file itself when it names a
+// directory ("" if no log file is configured)
+func (c *Config) GetLogDir() string {
+	logDir := c.Logging.LogFile
+	if filepath.Ext(logDir) != "" {
+		logDir = filepath.Dir(logDir)
+	}
+	return logDir
+}
+
+// NewFileLogger creates the file logger for logging.log_file
+// Session logs are written next to the log file (or into it when it names
+// a directory); returns nil if no log file is configured
+func (c *Config) NewFileLogger() (*logger.FileLogger, error) {
+	if c.Logging.LogFile == "" {
+		return nil, nil
+	}
+
+	fileLogger, err := logger.NewFileLogger(c.GetLogDir())
+	if err != nil {
+		return nil, types.NewConfigError("logging.log_file", err.Error())
+	}
+	fileLogger.SetMoneyDecimals(c.GetMoneyDecimals())
+
+	// Trades and errors are always written; each flag adds more detail
+	verbosity := logger.VerbosityMinimal
+	switch {
+	case c.Logging.LogEveryTick:
+		verbosity = logger.VerbosityDebug
+	case c.Logging.Verbose:
+		verbosity = logger.VerbosityVerbose
+	case c.Logging.LogMetrics:
+		verbosity = logger.VerbosityNormal
+	}
+	fileLogger.SetVerbosity(verbosity)
+
+	return fileLogger, nil
+}
+
+// NewLogger creates a logger from config: the console when verbose and
+// the file logger when a log file is set, both when both are configured
+// Returns nil if neither is configured
 func (c *Config) NewLogger() (logger.Logger, error) {
-	if !c.Logging.Verbose {
+	var loggers []logger.Logger
+
+	if c.Logging.Verbose {
+		console := logger.NewConsoleLogger(os.Stdout)
+		console.SetMoneyDecimals(c.GetMoneyDecimals())
+		if c.Logging.LogEveryTick {
+			console.SetLevel(logger.LevelDebug)
+		}
+		loggers = append(loggers, console)
+	}
+
+	fileLogger, err := c.NewFileLogger()
+	if err != nil {
+		return nil, err
+	}
+	if fileLogger != nil {
+		loggers = append(loggers, fileLogger)
+	}
+
+	var l logger.Logger
+	switch len(loggers) {
+	case 0:
 		return nil, nil
+	case 1:
+		l = loggers[0]
+	default:
+		l = logger.NewMultiLogger(loggers...)
 	}
 
-	// Create console logger (simple stdout logging)
-	return logger.NewConsoleLogger(c.Logging), nil
+	if c.Logging.ErrorFilter.IsEnabled() {
+		filtered, err := logger.NewFilteredLogger(l, c.Logging.ErrorFilter)
+		if err != nil {
+			return nil, types.NewConfigError("logging.error_filter", err.Error())
+		}
+		return filtered, nil
+	}
+	return l, nil
 }
 
 // NewInstrument creates an instrument from config
 func (c *Config) NewInstrument() (types.Instrument, error) {
-	if c.Instrument.Symbol == "" {
-		return nil, fmt.Errorf("instrument symbol not configured")
+	return newInstrument(c.Instrument)
+}
+
+// NewInstruments creates the other symbols' instruments of a multi-symbol
+// session, keyed by symbol
+func (c *Config) NewInstruments() (map[string]types.Instrument, error) {
+	instruments := make(map[string]types.Instrument, len(c.Instruments))
+	for _, ic := range c.Instruments {
+		instrument, err := newInstrument(ic)
+		if err != nil {
+			return nil, err
+		}
+		instruments[ic.Symbol] = instrument
 	}
+	return instruments, nil
+}
 
-	instrumentType := c.Instrument.Type
+// newInstrument creates an instrument of the configured type, with the
+// configured pricing parameters over the type's defaults
+func newInstrument(ic InstrumentConfig) (types.Instrument, error) {
+	if ic.Symbol == "" {
+		return nil, fmt.Errorf("instrument symbol not configured")
+	}
 
-	switch instrumentType {
+	var instrument types.Instrument
+	switch ic.Type {
 	case "FOREX":
-		return types.NewForexInstrument(c.Instrument.Symbol, c.Instrument.Description), nil
+		instrument = types.NewForexInstrument(ic.Symbol, ic.Description)
 
 	case "STOCKS":
-		return types.NewStocksInstrument(c.Instrument.Symbol, c.Instrument.Description), nil
+		instrument = types.NewStocksInstrument(ic.Symbol, ic.Description)
 
 	case "COMMODITIES":
-		return types.NewCommoditiesInstrument(c.Instrument.Symbol, c.Instrument.Description), nil
+		instrument = types.NewCommoditiesInstrument(ic.Symbol, ic.Description)
 
 	case "CRYPTO":
-		return types.NewCryptoInstrument(c.Instrument.Symbol, c.Instrument.Description), nil
+		instrument = types.NewCryptoInstrument(ic.Symbol, ic.Description)
 
 	default:
-		return nil, fmt.Errorf("unknown instrument type: %s", instrumentType)
+		return nil, fmt.Errorf("unknown instrument type: %s", ic.Type)
+	}
+
+	if configurable, ok := instrument.(interface {
+		GetConfig() *types.InstrumentConfig
+	}); ok && configurable.GetConfig() != nil {
+		config := configurable.GetConfig()
+		if ic.DecimalPlaces > 0 {
+			config.DecimalPlaces = ic.DecimalPlaces
+		}
+		if ic.PipValue > 0 {
+			config.PipValue = ic.PipValue
+		}
+		if ic.ContractSize > 0 {
+			config.ContractSize = ic.ContractSize
+		}
+		if ic.MinimumLotSize > 0 {
+			config.MinimumLotSize = ic.MinimumLotSize
+		}
+		if ic.TickSize > 0 {
+			config.TickSize = ic.TickSize
+		}
 	}
+
+	return instrument, nil
 }
 
 // NewHolodeck creates and configures a complete Holodeck simulator from config
 // This is the main factory method that initializes all subsystems
 func (c *Config) NewHolodeck() (*Holodeck, error) {
-	// Step 1: Create CSV reader
-	reader, err := c.NewCSVReader()
+	// Step 1: Create tick reader (CSV or plugin)
+	pluginSet := plugins.NewSet()
+	reader, err := c.NewTickReader(pluginSet)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create CSV reader: %w", err)
+		pluginSet.KillAll()
+		return nil, fmt.Errorf("failed to create tick reader: %w", err)
 	}
 
 	// Step 2: Create instrument
 	instrument, err := c.NewInstrument()
 	if err != nil {
+		pluginSet.KillAll()
+		return nil, fmt.Errorf("failed to create instrument: %w", err)
+	}
+	instruments, err := c.NewInstruments()
+	if err != nil {
+		pluginSet.KillAll()
 		return nil, fmt.Errorf("failed to create instrument: %w", err)
 	}
 
-	// NOTE: Executor and Logger are optional - can be added later
-	// The interfaces in simulator/holodeck.go don't match the built packages yet
-
-	// Step 4: Create HolodeckConfig
+	// Step 3: Create HolodeckConfig
 	hConfig := &HolodeckConfig{
-		Config:     c, // Add the base config reference
-		SessionID:  fmt.Sprintf("session_%d", time.Now().Unix()),
-		StartTime:  time.Now(),
-		IsRunning:  false,
-		Instrument: instrument,
+		Config:      c, // Add the base config reference
+		SessionID:   fmt.Sprintf("session_%d", time.Now().Unix()),
+		StartTime:   time.Now(),
+		IsRunning:   false,
+		Instrument:  instrument,
+		Instruments: instruments,
 		ExecutionConfig: ExecutionParameters{
 			CommissionEnabled:   c.Execution.Commission,
 			CommissionType:      c.Execution.CommissionType,
@@ -785,19 +1924,106 @@ func (c *Config) NewHolodeck() (*Holodeck, error) {
 			MaxExecutionHistorySize: 10000,
 		},
 	}
+	c.Session.History.apply(&hConfig.StateConfig)
 
-	// Step 5: Create Holodeck
+	// Step 4: Create Holodeck
 	holodeck, err := NewHolodeck(hConfig)
 	if err != nil {
+		pluginSet.KillAll()
 		return nil, fmt.Errorf("failed to create Holodeck: %w", err)
 	}
 
-	// Step 6: Wire subsystems (reader is required, executor/logger are optional)
-	holodeck = holodeck.WithReader(reader)
+	// Step 5: Wire subsystems (reader, executor and logger from config)
+	holodeck = holodeck.WithReader(reader).WithPlugins(pluginSet)
+
+	pluginExecutor, err := c.NewPluginExecutor(pluginSet)
+	if err != nil {
+		pluginSet.KillAll()
+		return nil, fmt.Errorf("failed to create executor plugin: %w", err)
+	}
+	if pluginExecutor != nil {
+		holodeck = holodeck.WithExecutor(pluginExecutor)
+	}
+
+	if c.Financing.Enabled {
+		engine, err := financing.NewFinancingEngine(c.Financing)
+		if err != nil {
+			pluginSet.KillAll()
+			return nil, types.NewConfigError("financing", err.Error())
+		}
+		holodeck = holodeck.WithFinancing(engine)
+	}
+
+	if c.Interest.Enabled {
+		engine, err := financing.NewInterestEngine(c.Interest)
+		if err != nil {
+			pluginSet.KillAll()
+			return nil, types.NewConfigError("interest", err.Error())
+		}
+		holodeck = holodeck.WithInterest(engine)
+	}
+
+	if c.CorporateActions.Enabled {
+		engine, err := corporate.NewCorporateActionsEngine(c.CorporateActions)
+		if err != nil {
+			pluginSet.KillAll()
+			return nil, types.NewConfigError("corporate_actions", err.Error())
+		}
+		holodeck = holodeck.WithCorporateActions(engine)
+	}
+
+	registeredExecutor, err := c.NewRegisteredExecutor()
+	if err != nil {
+		pluginSet.KillAll()
+		return nil, fmt.Errorf("failed to create executor: %w", err)
+	}
+	if registeredExecutor != nil {
+		holodeck = holodeck.WithExecutor(registeredExecutor)
+	}
+
+	// Without a plugin or registered executor, fill with the standard
+	// executor configured by execution.*
+	if pluginExecutor == nil && registeredExecutor == nil {
+		standardExecutor, err := c.NewExecutor()
+		if err != nil {
+			pluginSet.KillAll()
+			return nil, fmt.Errorf("failed to create executor: %w", err)
+		}
+		holodeck = holodeck.WithExecutor(standardExecutor)
+	}
+
+	l, err := c.NewLogger()
+	if err != nil {
+		pluginSet.KillAll()
+		return nil, fmt.Errorf("failed to create logger: %w", err)
+	}
+	if l != nil {
+		holodeck = holodeck.WithLogger(logger.NewSimulatorAdapter(l))
+	}
+
+	// Start from the state carried over from an earlier session
+	if c.Session.LoadStateFile != "" {
+		if err := holodeck.LoadState(c.Session.LoadStateFile); err != nil {
+			pluginSet.KillAll()
+			return nil, types.NewConfigError("session.load_state_file", err.Error())
+		}
+	}
+
+	// Step 6: Pace the tick loop and set speed
+	if c.IsSpeedControlled() {
+		controller, err := c.NewSpeedController()
+		if err != nil {
+			pluginSet.KillAll()
+			return nil, err
+		}
+		holodeck = holodeck.WithSpeedController(controller)
+	}
 
-	// Step 7: Set speed
-	if c.Speed.Multiplier > 0 {
+	if c.Speed.Max {
+		holodeck.SetMaxSpeed(true)
+	} else if c.Speed.TicksPerSecond == 0 && c.Speed.Multiplier > 0 {
 		if err := holodeck.SetSpeed(c.Speed.Multiplier); err != nil {
+			pluginSet.KillAll()
 			return nil, fmt.Errorf("failed to set speed: %w", err)
 		}
 	}
@@ -0,0 +1,335 @@
+package simulator
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"holodeck/speed"
+)
+
+// ==================== CONTROL SERVER ====================
+
+// controlHelp lists the control commands
+const controlHelp = "commands: status | metrics | speed <multiplier|max> | tps <rate> | " +
+	"pause | resume | step [on|off] | help"
+
+// ControlStatus is the reply to the status command
+type ControlStatus struct {
+	Session        *SessionStatus `json:"session"`
+	SimTime        time.Time      `json:"sim_time"`
+	Speed          float64        `json:"speed"` // Effective multiplier
+	MaxSpeed       bool           `json:"max_speed"`
+	TicksPerSecond float64        `json:"ticks_per_second,omitempty"`
+	Adaptive       bool           `json:"adaptive"`
+	StepMode       bool           `json:"step_mode"`
+}
+
+// ControlServer accepts commands such as "speed 1000", "pause", "resume"
+// and "status" against a running session, so a long run can be slowed
+// down or frozen for inspection without restarting it
+// Commands arrive one per line over a Unix socket or TCP connection, or as
+// HTTP requests (the server is an http.Handler); each gets one reply line,
+// "OK ...", "ERR ..." or a JSON document
+type ControlServer struct {
+	holodeck *Holodeck
+
+	mu       sync.Mutex
+	listener net.Listener
+	server   *http.Server
+	conns    map[net.Conn]bool
+	commands int64
+}
+
+// NewControlServer creates a control server for a session
+func NewControlServer(h *Holodeck) *ControlServer {
+	return &ControlServer{
+		holodeck: h,
+		conns:    make(map[net.Conn]bool),
+	}
+}
+
+// Listen starts serving in the background on addr:
+// "unix:/path" (or a bare path) for a Unix socket, "tcp:host:port" for
+// line commands over TCP, or "http:host:port" for HTTP
+func (cs *ControlServer) Listen(addr string) error {
+	network, address := "unix", addr
+	if i := strings.Index(addr, ":"); i > 0 {
+		switch addr[:i] {
+		case "unix", "tcp", "http":
+			network, address = addr[:i], addr[i+1:]
+		}
+	}
+	if address == "" {
+		return fmt.Errorf("control address %q has no path or port", addr)
+	}
+
+	listenNetwork := network
+	if network == "http" {
+		listenNetwork = "tcp"
+	}
+	if network == "unix" {
+		// A socket left behind by a crashed run would block the listen
+		if info, err := os.Lstat(address); err == nil && info.Mode()&os.ModeSocket != 0 {
+			os.Remove(address)
+		}
+	}
+
+	listener, err := net.Listen(listenNetwork, address)
+	if err != nil {
+		return fmt.Errorf("control listen on %s: %w", addr, err)
+	}
+
+	cs.mu.Lock()
+	cs.listener = listener
+	if network == "http" {
+		cs.server = &http.Server{Handler: cs}
+	}
+	server := cs.server
+	cs.mu.Unlock()
+
+	if server != nil {
+		go server.Serve(listener)
+		return nil
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go cs.serveConn(conn)
+		}
+	}()
+	return nil
+}
+
+// Addr returns the listener's address, nil before Listen
+func (cs *ControlServer) Addr() net.Addr {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.listener == nil {
+		return nil
+	}
+	return cs.listener.Addr()
+}
+
+// serveConn replies to line commands until the client disconnects
+func (cs *ControlServer) serveConn(conn net.Conn) {
+	cs.mu.Lock()
+	cs.conns[conn] = true
+	cs.mu.Unlock()
+
+	defer func() {
+		cs.mu.Lock()
+		delete(cs.conns, conn)
+		cs.mu.Unlock()
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "quit" || line == "exit" {
+			return
+		}
+		if _, err := io.WriteString(conn, cs.reply(line)+"\n"); err != nil {
+			return
+		}
+	}
+}
+
+// ServeHTTP runs the command in the "cmd" query parameter or the request
+// body; a request with neither returns the status
+func (cs *ControlServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	command := r.URL.Query().Get("cmd")
+	if command == "" && r.Body != nil {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 4096))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		command = strings.TrimSpace(string(body))
+	}
+	if command == "" {
+		command = "status"
+	}
+
+	result, err := cs.Execute(command)
+	if err != nil {
+		http.Error(w, "ERR "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if strings.HasPrefix(result, "{") {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	fmt.Fprintln(w, result)
+}
+
+// reply runs a command and formats the reply line
+func (cs *ControlServer) reply(command string) string {
+	result, err := cs.Execute(command)
+	if err != nil {
+		return "ERR " + err.Error()
+	}
+	return result
+}
+
+// Execute runs one command and returns its reply
+func (cs *ControlServer) Execute(command string) (string, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty command")
+	}
+
+	cs.mu.Lock()
+	cs.commands++
+	cs.mu.Unlock()
+
+	h := cs.holodeck
+	name, args := strings.ToLower(fields[0]), fields[1:]
+	switch name {
+	case "status":
+		return cs.marshal(cs.status())
+
+	case "metrics":
+		return cs.marshal(h.GetPerformanceMetrics())
+
+	case "speed":
+		if len(args) != 1 {
+			return "", fmt.Errorf("usage: speed <multiplier|max>")
+		}
+		multiplier, max, err := speed.ParseSpeed(args[0])
+		if err != nil {
+			return "", err
+		}
+		if max {
+			h.SetMaxSpeed(true)
+			return "OK speed max", nil
+		}
+		if err := h.SetSpeed(multiplier); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("OK speed %gx", multiplier), nil
+
+	case "tps":
+		if len(args) != 1 {
+			return "", fmt.Errorf("usage: tps <rate>")
+		}
+		rate, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid tick rate %q", args[0])
+		}
+		if err := h.SetTicksPerSecond(rate); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("OK tps %g", rate), nil
+
+	case "pause":
+		if err := h.Pause(); err != nil {
+			return "", err
+		}
+		return "OK paused", nil
+
+	case "resume":
+		if err := h.Resume(); err != nil {
+			return "", err
+		}
+		return "OK resumed", nil
+
+	case "step":
+		if len(args) == 1 {
+			switch strings.ToLower(args[0]) {
+			case "on":
+				h.SetStepMode(true)
+				return "OK step mode on", nil
+			case "off":
+				h.SetStepMode(false)
+				return "OK step mode off", nil
+			}
+			return "", fmt.Errorf("usage: step [on|off]")
+		}
+		// Outside step mode Step would take a tick from the running loop
+		if !h.IsStepMode() {
+			return "", fmt.Errorf("step mode off (use \"step on\" first)")
+		}
+		tick, err := h.Step()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("OK step %s", tick.Timestamp.Format(time.RFC3339Nano)), nil
+
+	case "help":
+		return controlHelp, nil
+	}
+
+	return "", fmt.Errorf("unknown command %q (%s)", name, controlHelp)
+}
+
+// status builds the reply to the status command
+func (cs *ControlServer) status() *ControlStatus {
+	h := cs.holodeck
+	return &ControlStatus{
+		Session:        h.GetStatus(),
+		SimTime:        h.GetSimTime(),
+		Speed:          h.GetEffectiveSpeed(),
+		MaxSpeed:       h.IsMaxSpeed(),
+		TicksPerSecond: h.GetTicksPerSecond(),
+		Adaptive:       h.IsAdaptiveSpeed(),
+		StepMode:       h.IsStepMode(),
+	}
+}
+
+// marshal encodes a reply document on one line
+func (cs *ControlServer) marshal(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode reply: %w", err)
+	}
+	return string(data), nil
+}
+
+// GetStatistics returns control server statistics
+func (cs *ControlServer) GetStatistics() map[string]interface{} {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	stats := map[string]interface{}{
+		"commands":    cs.commands,
+		"connections": len(cs.conns),
+	}
+	if cs.listener != nil {
+		stats["address"] = cs.listener.Addr().String()
+	}
+	return stats
+}
+
+// Close stops listening and disconnects clients
+func (cs *ControlServer) Close() error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	for conn := range cs.conns {
+		conn.Close()
+	}
+	if cs.server != nil {
+		return cs.server.Close()
+	}
+	if cs.listener != nil {
+		return cs.listener.Close()
+	}
+	return nil
+}
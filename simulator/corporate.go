@@ -0,0 +1,49 @@
+package simulator
+
+import (
+	"fmt"
+
+	"holodeck/corporate"
+	"holodeck/types"
+)
+
+// ==================== CORPORATE ACTIONS ====================
+
+// WithCorporateActions sets the dividend and split calendar
+func (h *Holodeck) WithCorporateActions(engine *corporate.CorporateActionsEngine) *Holodeck {
+	h.corporate = engine
+	return h
+}
+
+// processCorporateActions applies the dividends and splits that took
+// effect since the tick symbol's previous tick
+// Caller must hold the write lock
+func (h *Holodeck) processCorporateActions(tick *types.Tick) {
+	if h.corporate == nil || h.state.Balance == nil {
+		return
+	}
+
+	symbol := h.state.symbolKey(tick.Symbol)
+	for _, action := range h.corporate.ProcessTick(tick.Timestamp, symbol) {
+		pos, ok := h.state.Positions[symbol]
+		if !ok || pos.IsFlat() {
+			continue
+		}
+
+		if action.IsSplit() {
+			pos.ApplySplit(action.Ratio)
+			if h.hedges != nil && symbol == h.state.PrimarySymbol {
+				h.hedges.ApplySplit(action.Ratio)
+			}
+			h.corporate.RecordSplit()
+			h.updateUsedMargin()
+			continue
+		}
+
+		amount := h.state.Balance.ConvertToAccount(symbol, action.GetCashAmount(pos.Size*h.contractSizeFor(symbol)))
+
+		h.state.Balance.ApplyDividend(amount, fmt.Sprintf("%s %s", types.BalanceReasonDividend, symbol), action.GetEffectiveTime())
+		h.state.Daily.RecordDividend(tick.Timestamp, amount, h.state.Balance.CurrentBalance)
+		h.corporate.RecordDividend(amount)
+	}
+}
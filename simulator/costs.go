@@ -0,0 +1,221 @@
+package simulator
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"holodeck/financing"
+	"holodeck/types"
+)
+
+// ==================== COST ATTRIBUTION ====================
+
+// TradeCost is one closed lot's P&L decomposed into its friction terms
+type TradeCost struct {
+	Symbol    string    `json:"symbol"`
+	LotID     string    `json:"lot_id"`
+	Side      string    `json:"side"`
+	Size      float64   `json:"size"`
+	OpenTime  time.Time `json:"open_time"`
+	CloseTime time.Time `json:"close_time"`
+
+	types.CostBreakdown
+}
+
+// CostAttribution decomposes the session's gross P&L into spread,
+// slippage, commission and swap, per closed trade and in total, so the
+// friction term eating the edge stands out
+// Amounts are in the account currency; the gross P&L is what the trades
+// would have made filling at the mid price with no costs
+// Swap is attributed to lots in netting mode only; hedging-mode swap is
+// left out of the per-trade figures
+type CostAttribution struct {
+	SessionID   string              `json:"session_id"`
+	Currency    string              `json:"currency"`
+	GeneratedAt time.Time           `json:"generated_at"`
+	Trades      []TradeCost         `json:"trades"`
+	Total       types.CostBreakdown `json:"total"`
+
+	// MoneyDecimals is the precision amounts are written with
+	MoneyDecimals int `json:"money_decimals"`
+}
+
+// String returns a human-readable representation
+func (ca *CostAttribution) String() string {
+	return fmt.Sprintf("CostAttribution[Trades:%d %s]", len(ca.Trades), ca.Total.String())
+}
+
+// GetCostAttribution builds the cost attribution from every symbol's
+// closed lots, ordered by close time
+func (h *Holodeck) GetCostAttribution() *CostAttribution {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.getCostAttribution()
+}
+
+// getCostAttribution builds the cost attribution
+// Caller must hold the lock
+func (h *Holodeck) getCostAttribution() *CostAttribution {
+	attribution := &CostAttribution{
+		SessionID:     h.config.SessionID,
+		Currency:      h.config.Config.Account.Currency,
+		GeneratedAt:   time.Now(),
+		Trades:        make([]TradeCost, 0),
+		MoneyDecimals: h.getMoneyDecimals(),
+	}
+	if h.state == nil {
+		return attribution
+	}
+
+	for symbol, pos := range h.state.Positions {
+		var convert func(float64) float64
+		if b := h.state.Balance; b != nil {
+			symbol := symbol
+			convert = func(amount float64) float64 {
+				return b.ConvertToAccount(symbol, amount)
+			}
+		}
+
+		for _, closed := range pos.GetClosedLots() {
+			breakdown := types.NewCostBreakdown(closed, convert)
+			attribution.Trades = append(attribution.Trades, TradeCost{
+				Symbol:        symbol,
+				LotID:         closed.LotID,
+				Side:          closed.Side,
+				Size:          closed.Size,
+				OpenTime:      closed.OpenTime,
+				CloseTime:     closed.CloseTime,
+				CostBreakdown: breakdown,
+			})
+			attribution.Total.Add(breakdown)
+		}
+	}
+
+	sort.SliceStable(attribution.Trades, func(i, j int) bool {
+		a, b := attribution.Trades[i], attribution.Trades[j]
+		if !a.CloseTime.Equal(b.CloseTime) {
+			return a.CloseTime.Before(b.CloseTime)
+		}
+		return a.Symbol < b.Symbol
+	})
+
+	return attribution
+}
+
+// ==================== FILL COSTS ====================
+
+// attributeFillCosts splits a fill's cost against the mid price of its
+// symbol's latest tick into slippage and spread (the rest, including any
+// broker markup); a limit fill better than the mid has a negative spread
+// cost
+// Caller must hold the write lock
+func (h *Holodeck) attributeFillCosts(exec *types.ExecutionReport) {
+	tick := h.state.LastTicks[h.state.symbolKey(exec.Symbol)]
+	if tick == nil {
+		return
+	}
+
+	direction := 1.0
+	if exec.IsSell() {
+		direction = -1.0
+	}
+
+	pipValue := h.pipValueFor(exec.Symbol)
+	total := (exec.FillPrice - tick.GetMidPrice()) * direction * exec.FilledSize
+	slippage := exec.SlippageUnits * pipValue * exec.FilledSize
+	if pipValue > 0 {
+		total /= pipValue
+		slippage /= pipValue
+	}
+
+	exec.SlippageCost = slippage
+	exec.SpreadCost = total - slippage
+}
+
+// chargeSwapToLots books each swap charge to the open lots of the position
+// it was charged on; positions are in the order their sizes were charged,
+// and charges come rollover by rollover in that order, skipping positions
+// that paid nothing
+// Caller must hold the write lock
+func (h *Holodeck) chargeSwapToLots(positions []*types.Position, charges []*financing.SwapCharge) {
+	if len(positions) == 0 {
+		return
+	}
+
+	next := 0
+	for _, charge := range charges {
+		for tried := 0; tried < len(positions); tried++ {
+			pos := positions[next]
+			next = (next + 1) % len(positions)
+			if pos.Size == charge.PositionSize {
+				pos.ChargeSwap(charge.Amount)
+				break
+			}
+		}
+	}
+}
+
+// ==================== EXPORT ====================
+
+// costCSVHeader is the column layout of the cost attribution CSV
+var costCSVHeader = []string{
+	"symbol", "lot_id", "side", "size", "open_time", "close_time",
+	"gross_pnl", "spread_cost", "slippage_cost", "commission", "swap", "net_pnl",
+}
+
+// WriteCSV writes one row per closed trade and a closing TOTAL row
+func (ca *CostAttribution) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(costCSVHeader)
+
+	f64 := func(v float64) string { return strconv.FormatFloat(math.Round(v*1e8)/1e8, 'f', -1, 64) }
+	money := func(v float64) string { return types.FormatMoney(v, ca.MoneyDecimals) }
+	amounts := func(cb types.CostBreakdown) []string {
+		return []string{
+			money(cb.GrossPnL),
+			money(cb.SpreadCost),
+			money(cb.SlippageCost),
+			money(cb.Commission),
+			money(cb.Swap),
+			money(cb.NetPnL),
+		}
+	}
+
+	for _, trade := range ca.Trades {
+		row := []string{
+			trade.Symbol,
+			trade.LotID,
+			trade.Side,
+			f64(trade.Size),
+			trade.OpenTime.Format(time.RFC3339),
+			trade.CloseTime.Format(time.RFC3339),
+		}
+		cw.Write(append(row, amounts(trade.CostBreakdown)...))
+	}
+	cw.Write(append([]string{"TOTAL", "", "", "", "", ""}, amounts(ca.Total)...))
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportCostAttribution writes the cost attribution to a CSV file
+func (h *Holodeck) ExportCostAttribution(path string) error {
+	attribution := h.GetCostAttribution()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create cost attribution: %w", err)
+	}
+	if err := attribution.WriteCSV(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
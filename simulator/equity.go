@@ -0,0 +1,209 @@
+package simulator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== EQUITY TRACKING ====================
+
+// markToMarket revalues the positions in a tick's symbol and refreshes
+// equity from every position's unrealized P&L, each converted from its
+// quote currency
+// Caller must hold the write lock
+func (h *Holodeck) markToMarket(tick *types.Tick) {
+	unrealized := 0.0
+	if h.hedges != nil {
+		if primary := h.state.LastTicks[h.state.PrimarySymbol]; primary != nil {
+			unrealized = h.state.Balance.ConvertToAccount(h.state.PrimarySymbol,
+				h.hedges.GetUnrealizedPnL(primary.GetSellPrice(), primary.GetBuyPrice(), h.config.Instrument.GetPipValue()))
+			h.state.Symbols.MarkToMarket(h.state.PrimarySymbol, unrealized)
+		}
+	} else {
+		symbol := h.state.symbolKey(tick.Symbol)
+		if pos, ok := h.state.Positions[symbol]; ok {
+			if !pos.IsFlat() {
+				pos.UpdatePrice(markPrice(pos, tick), h.pipValueFor(symbol))
+			}
+			h.state.Symbols.MarkToMarket(symbol, h.state.Balance.ConvertToAccount(symbol, pos.UnrealizedPnL))
+		}
+		for symbol, pos := range h.state.Positions {
+			unrealized += h.state.Balance.ConvertToAccount(symbol, pos.UnrealizedPnL)
+		}
+	}
+
+	h.state.Balance.MarkToMarket(unrealized)
+}
+
+// markPrice returns the price a position would close at: the bid for
+// longs and the ask for shorts
+func markPrice(pos *types.Position, tick *types.Tick) float64 {
+	if pos.IsShort() {
+		return tick.GetBuyPrice()
+	}
+	return tick.GetSellPrice()
+}
+
+// GetEquityCurve returns the sampled equity curve, oldest first
+func (h *Holodeck) GetEquityCurve() []types.EquityPoint {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.state.EquityCurve.GetPoints()
+}
+
+// GetSymbolMetrics returns the per-symbol trades, P&L and drawdown
+// contributions, sorted by symbol
+func (h *Holodeck) GetSymbolMetrics() []types.SymbolMetrics {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.state.Symbols.GetAll()
+}
+
+// GetDailyPnL returns the per-day accounting records, oldest first
+func (h *Holodeck) GetDailyPnL() []types.DailyRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.state.Daily.GetDays()
+}
+
+// GetEquityStatistics returns drawdown statistics computed from the
+// equity curve
+func (h *Holodeck) GetEquityStatistics() map[string]interface{} {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.getEquityStatistics()
+}
+
+// getEquityStatistics returns the equity curve statistics with the
+// return/drawdown ratios annualized by the instrument's trading year
+// Caller must hold the lock
+func (h *Holodeck) getEquityStatistics() map[string]interface{} {
+	curve := h.state.EquityCurve
+	tradingDays := h.getTradingDaysPerYear()
+
+	stats := curve.GetStatistics()
+	stats["trading_days"] = curve.GetTradingDays()
+	stats["annualized_return_percent"] = curve.GetAnnualizedReturn(tradingDays)
+	stats["calmar_ratio"] = curve.GetCalmarRatio(tradingDays)
+	stats["mar_ratio"] = curve.GetMARRatio(tradingDays)
+	stats["sharpe_ratio"] = curve.GetSharpeRatio(h.state.Balance.Sharpe)
+	stats["sharpe_period"] = h.state.Balance.Sharpe.GetPeriod()
+	stats["risk_free_rate"] = h.state.Balance.Sharpe.RiskFreeRate
+	for _, confidence := range types.VaRConfidenceLevels {
+		v := curve.GetValueAtRisk(confidence)
+		stats[fmt.Sprintf("var_%.0f", confidence)] = v.VaR
+		stats[fmt.Sprintf("cvar_%.0f", confidence)] = v.CVaR
+	}
+	return stats
+}
+
+// getTradingDaysPerYear returns the instrument's trading days per year
+func (h *Holodeck) getTradingDaysPerYear() int {
+	return instrumentTradingDays(h.config.Instrument)
+}
+
+// instrumentTradingDays returns an instrument's trading days per year,
+// DefaultTradingDaysPerYear when it does not say
+func instrumentTradingDays(instrument types.Instrument) int {
+	if ic, ok := instrument.(interface {
+		GetConfig() *types.InstrumentConfig
+	}); ok && ic.GetConfig() != nil && ic.GetConfig().TradingDaysPerYear > 0 {
+		return ic.GetConfig().TradingDaysPerYear
+	}
+	return types.DefaultTradingDaysPerYear
+}
+
+// instrumentSessions returns an instrument's trading session hours (nil
+// when it defines none)
+func instrumentSessions(instrument types.Instrument) []types.SessionHour {
+	if ic, ok := instrument.(interface {
+		GetConfig() *types.InstrumentConfig
+	}); ok && ic.GetConfig() != nil {
+		return ic.GetConfig().SessionHours
+	}
+	return nil
+}
+
+// ==================== EQUITY EXPORT ====================
+
+// equityCSVHeader is the column layout of the equity curve CSV export
+var equityCSVHeader = []string{
+	"timestamp", "balance", "unrealized_pnl", "equity", "peak", "drawdown", "drawdown_percent",
+}
+
+// ExportEquityCurve writes the equity curve and its drawdown series to
+// path, as a JSON array of samples when the extension is .json and as CSV
+// otherwise
+func (h *Holodeck) ExportEquityCurve(path string) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return exportEquityCurve(path, h.state.EquityCurve.GetPoints(), h.getMoneyDecimals())
+}
+
+// exportEquityCurve writes samples to path in the format its extension
+// names, with money rounded to moneyDecimals
+func exportEquityCurve(path string, points []types.EquityPoint, moneyDecimals int) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create equity export directory: %w", err)
+		}
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		rounded := make([]types.EquityPoint, len(points))
+		for i, p := range points {
+			p.Balance = types.RoundMoney(p.Balance, moneyDecimals)
+			p.UnrealizedPnL = types.RoundMoney(p.UnrealizedPnL, moneyDecimals)
+			p.Equity = types.RoundMoney(p.Equity, moneyDecimals)
+			p.Peak = types.RoundMoney(p.Peak, moneyDecimals)
+			p.Drawdown = types.RoundMoney(p.Drawdown, moneyDecimals)
+			rounded[i] = p
+		}
+		data, err := json.MarshalIndent(rounded, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write equity export: %w", err)
+		}
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create equity export: %w", err)
+	}
+
+	w := csv.NewWriter(f)
+	w.Write(equityCSVHeader)
+
+	f64 := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	money := func(v float64) string { return types.FormatMoney(v, moneyDecimals) }
+	for _, p := range points {
+		w.Write([]string{
+			p.Timestamp.Format(time.RFC3339Nano),
+			money(p.Balance), money(p.UnrealizedPnL), money(p.Equity),
+			money(p.Peak), money(p.Drawdown), f64(p.DrawdownPercent),
+		})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write equity export: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,91 @@
+package simulator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"holodeck/types"
+)
+
+// ==================== HEATMAP EXPORT ====================
+
+// heatmapCSVHeader is the column layout of the heatmap CSV export
+var heatmapCSVHeader = []string{
+	"weekday", "hour", "trades", "win_rate", "average_pnl", "total_pnl",
+}
+
+// GetHeatmap returns closing trades' count, win rate and P&L by UTC
+// weekday and hour of simulated (tick) time, Monday 00:00 first
+func (h *Holodeck) GetHeatmap() []types.HeatmapCell {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.state == nil {
+		return nil
+	}
+	return h.state.Breakdown.GetHeatmap()
+}
+
+// ExportHeatmap writes the weekday x hour heatmap to path, as a JSON array
+// of cells when the extension is .json and as CSV otherwise
+func (h *Holodeck) ExportHeatmap(path string) error {
+	return exportHeatmap(path, h.GetHeatmap(), h.getMoneyDecimals())
+}
+
+// exportHeatmap writes heatmap cells to path in the format its extension
+// names, with P&L rounded to moneyDecimals
+func exportHeatmap(path string, cells []types.HeatmapCell, moneyDecimals int) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create heatmap export directory: %w", err)
+		}
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		rounded := make([]types.HeatmapCell, len(cells))
+		for i, cell := range cells {
+			cell.AveragePnL = types.RoundMoney(cell.AveragePnL, moneyDecimals)
+			cell.TotalPnL = types.RoundMoney(cell.TotalPnL, moneyDecimals)
+			rounded[i] = cell
+		}
+		data, err := json.MarshalIndent(rounded, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write heatmap export: %w", err)
+		}
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create heatmap export: %w", err)
+	}
+
+	w := csv.NewWriter(f)
+	w.Write(heatmapCSVHeader)
+
+	f64 := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	money := func(v float64) string { return types.FormatMoney(v, moneyDecimals) }
+	for _, cell := range cells {
+		w.Write([]string{
+			cell.Weekday,
+			strconv.Itoa(cell.Hour),
+			strconv.Itoa(cell.Trades),
+			f64(cell.WinRate), money(cell.AveragePnL), money(cell.TotalPnL),
+		})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write heatmap export: %w", err)
+	}
+	return f.Close()
+}
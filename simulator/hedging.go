@@ -0,0 +1,155 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+
+	"holodeck/types"
+)
+
+// ==================== HEDGING MODE ====================
+// With account.position_mode "hedging" every opening fill becomes its own
+// ticket, so a long and a short on the same instrument can be open at once
+// (MT4-style). The net of all tickets is still kept in the symbol's position
+// for metrics and the executor's margin check
+// Hedging tracks the session instrument only
+
+// registerTicketClose checks a close-by-ticket order and routes its fills
+// to the ticket
+// Caller must hold the write lock
+func (h *Holodeck) registerTicketClose(order *types.Order) error {
+	hp, ok := h.hedges.Get(order.CloseTicket)
+	if !ok {
+		return fmt.Errorf("no open position with ticket %s", order.CloseTicket)
+	}
+	if hp.IsLong() == order.IsBuy() {
+		return fmt.Errorf("ticket %s is %s; a close must be on the opposite side", hp.Ticket, hp.Action)
+	}
+	if order.Size > hp.Size+1e-9 {
+		return fmt.Errorf("close size %.4f exceeds ticket %s size %.4f", order.Size, hp.Ticket, hp.Size)
+	}
+
+	// Working remainders are matched back to the ticket by order ID
+	if order.OrderID == "" {
+		order.OrderID = fmt.Sprintf("CLOSE-%s-%d", hp.Ticket, h.state.ExecutionCount+1)
+	}
+	h.hedges.RegisterClose(order.OrderID, hp.Ticket)
+	return nil
+}
+
+// applyFillToHedges books a fill to its ticket and refreshes the net position
+// Caller must hold the write lock
+func (h *Holodeck) applyFillToHedges(exec *types.ExecutionReport) {
+	pos := h.state.position(exec.Symbol)
+
+	realized, ticket := h.hedges.ApplyFill(exec, h.config.Instrument.GetPipValue())
+	exec.Ticket = ticket
+	exec.RealizedPnL += realized
+	pos.RealizedPnL += realized
+
+	prev := pos.Size
+	pos.Size = h.hedges.GetNetSize()
+	pos.EntryPrice = h.hedges.GetNetEntryPrice()
+	if math.Abs(pos.Size) < 1e-9 {
+		pos.Size = 0
+		pos.EntryPrice = 0
+		pos.UnrealizedPnL = 0
+	} else if prev == 0 || (prev > 0) != (pos.Size > 0) {
+		pos.EntryTime = exec.Timestamp
+	}
+	pos.TradeCount++
+
+	exec.PositionAfter = pos.Size
+	exec.EntryPrice = pos.EntryPrice
+}
+
+// ClosePositionByTicket closes a hedging-mode ticket with a market order
+// The order goes through the executor like any other; a partial fill leaves
+// the rest of the ticket open
+func (h *Holodeck) ClosePositionByTicket(ticket string) (*types.ExecutionReport, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.timing.enter()
+	defer h.timing.leave()
+
+	if err := h.checkCanExecute(); err != nil {
+		return nil, err
+	}
+
+	if h.hedges == nil {
+		return nil, fmt.Errorf("close by ticket requires hedging mode")
+	}
+
+	order, err := h.hedges.NewCloseOrder(ticket, 0, h.state.CurrentTick.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return h.executeOrder(order)
+}
+
+// CloseAllPositions closes every open hedging-mode ticket, oldest first
+// Returns the execution reports in the order they were executed
+func (h *Holodeck) CloseAllPositions() ([]*types.ExecutionReport, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.timing.enter()
+	defer h.timing.leave()
+
+	if err := h.checkCanExecute(); err != nil {
+		return nil, err
+	}
+
+	if h.hedges == nil {
+		return nil, fmt.Errorf("close all positions requires hedging mode")
+	}
+
+	reports := make([]*types.ExecutionReport, 0)
+	for _, hp := range h.hedges.GetOpenPositions() {
+		order, err := h.hedges.NewCloseOrder(hp.Ticket, 0, h.state.CurrentTick.Timestamp)
+		if err != nil {
+			return reports, err
+		}
+		exec, err := h.executeOrder(order)
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, exec)
+	}
+	return reports, nil
+}
+
+// GetOpenPositions returns the open hedging-mode tickets (nil when netting)
+func (h *Holodeck) GetOpenPositions() []types.HedgePosition {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.hedges == nil {
+		return nil
+	}
+	return h.hedges.GetOpenPositions()
+}
+
+// GetClosedPositions returns the fully closed hedging-mode tickets
+func (h *Holodeck) GetClosedPositions() []types.HedgePosition {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.hedges == nil {
+		return nil
+	}
+	return h.hedges.GetClosedPositions()
+}
+
+// GetHedgingUnrealizedPnL marks the open tickets against the current tick
+func (h *Holodeck) GetHedgingUnrealizedPnL() float64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.hedges == nil || h.state.CurrentTick == nil {
+		return 0
+	}
+	tick := h.state.CurrentTick
+	return h.hedges.GetUnrealizedPnL(tick.GetSellPrice(), tick.GetBuyPrice(), h.config.Instrument.GetPipValue())
+}
@@ -0,0 +1,160 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"holodeck/types"
+)
+
+// ==================== HISTORY BOUNDS ====================
+
+// History spill file names
+const (
+	HistoryExecutionsFile     = "executions.jsonl"
+	HistoryBalanceUpdatesFile = "balance_updates.jsonl"
+	HistoryPositionTradesFile = "position_trades.jsonl"
+)
+
+// HistoryBounds keeps the execution history, the balance update history
+// and each position's trade history within the state configuration's
+// caps, so long runs hold a bounded window of recent entries
+// Evicted entries are appended to JSON lines files in SpillDir when set,
+// and dropped otherwise
+type HistoryBounds struct {
+	MaxExecutions     int
+	MaxBalanceUpdates int
+	MaxPositionTrades int
+	SpillDir          string
+
+	evictedExecutions int64
+	evictedUpdates    int64
+	evictedTrades     int64
+	spillErrors       int64
+}
+
+// spilledTrade is a position trade as written to the spill file
+type spilledTrade struct {
+	Symbol string       `json:"symbol"`
+	Trade  *types.Trade `json:"trade"`
+}
+
+// NewHistoryBounds creates history bounds from the state configuration
+func NewHistoryBounds(config StateConfiguration) (*HistoryBounds, error) {
+	if config.HistorySpillDir != "" {
+		if err := os.MkdirAll(config.HistorySpillDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create history spill directory: %w", err)
+		}
+	}
+
+	return &HistoryBounds{
+		MaxExecutions:     config.MaxExecutionHistorySize,
+		MaxBalanceUpdates: config.MaxBalanceHistorySize,
+		MaxPositionTrades: config.MaxPositionHistorySize,
+		SpillDir:          config.HistorySpillDir,
+	}, nil
+}
+
+// Trim evicts the oldest entries of every history past its cap
+// Returns the first spill error; the entries are evicted regardless
+func (hb *HistoryBounds) Trim(state *HolodeckState) error {
+	var firstErr error
+	keep := func(err error) {
+		if err != nil {
+			hb.spillErrors++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	kept, executions := types.TrimHistory(state.ExecutionHistory, hb.MaxExecutions)
+	state.ExecutionHistory = kept
+	if len(executions) > 0 {
+		hb.evictedExecutions += int64(len(executions))
+		keep(hb.spill(HistoryExecutionsFile, len(executions), func(i int) interface{} { return executions[i] }))
+	}
+
+	if state.Balance != nil {
+		kept, updates := types.TrimHistory(state.Balance.UpdateHistory, hb.MaxBalanceUpdates)
+		state.Balance.UpdateHistory = kept
+		if len(updates) > 0 {
+			hb.evictedUpdates += int64(len(updates))
+			keep(hb.spill(HistoryBalanceUpdatesFile, len(updates), func(i int) interface{} { return updates[i] }))
+		}
+	}
+
+	for symbol, pos := range state.Positions {
+		kept, trades := types.TrimHistory(pos.TradeHistory, hb.MaxPositionTrades)
+		pos.TradeHistory = kept
+		if len(trades) > 0 {
+			hb.evictedTrades += int64(len(trades))
+			keep(hb.spill(HistoryPositionTradesFile, len(trades), func(i int) interface{} {
+				return spilledTrade{Symbol: symbol, Trade: trades[i]}
+			}))
+		}
+	}
+
+	return firstErr
+}
+
+// spill appends n entries to a JSON lines file in SpillDir
+func (hb *HistoryBounds) spill(name string, n int, entry func(i int) interface{}) error {
+	if hb.SpillDir == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(filepath.Join(hb.SpillDir, name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history spill file: %w", err)
+	}
+
+	enc := json.NewEncoder(f)
+	for i := 0; i < n; i++ {
+		if err := enc.Encode(entry(i)); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to spill history: %w", err)
+		}
+	}
+	return f.Close()
+}
+
+// GetEvictedCount returns the number of entries evicted from all histories
+func (hb *HistoryBounds) GetEvictedCount() int64 {
+	return hb.evictedExecutions + hb.evictedUpdates + hb.evictedTrades
+}
+
+// GetStatistics returns history bound statistics
+func (hb *HistoryBounds) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"max_executions":          hb.MaxExecutions,
+		"max_balance_updates":     hb.MaxBalanceUpdates,
+		"max_position_trades":     hb.MaxPositionTrades,
+		"spill_dir":               hb.SpillDir,
+		"evicted_executions":      hb.evictedExecutions,
+		"evicted_balance_updates": hb.evictedUpdates,
+		"evicted_position_trades": hb.evictedTrades,
+		"spill_errors":            hb.spillErrors,
+	}
+}
+
+// Reset clears the eviction counters
+func (hb *HistoryBounds) Reset() {
+	hb.evictedExecutions = 0
+	hb.evictedUpdates = 0
+	hb.evictedTrades = 0
+	hb.spillErrors = 0
+}
+
+// String returns a human-readable representation
+func (hb *HistoryBounds) String() string {
+	return fmt.Sprintf(
+		"HistoryBounds[Executions:%d, BalanceUpdates:%d, PositionTrades:%d, Evicted:%d]",
+		hb.MaxExecutions,
+		hb.MaxBalanceUpdates,
+		hb.MaxPositionTrades,
+		hb.GetEvictedCount(),
+	)
+}
@@ -1,10 +1,21 @@
 package simulator
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"sync"
 	"time"
 
+	"holodeck/agent"
+	"holodeck/corporate"
+	"holodeck/financing"
+	"holodeck/plugins"
+	"holodeck/reader"
+	"holodeck/risk"
+	"holodeck/speed"
 	"holodeck/types"
 )
 
@@ -22,18 +33,123 @@ type Holodeck struct {
 	reader   TickReader
 	logger   Logger
 
+	// Plugin processes serving the reader or executor, killed by Stop
+	// (optional)
+	plugins *plugins.Set
+
 	// Synchronization
 	mu       sync.RWMutex
+	readMu   sync.Mutex // serializes tick reads, which happen outside mu
 	running  bool
 	stopped  bool
 	stopChan chan bool
 
+	// Ticks read so far, as of the last read (the reader itself is only
+	// touched under readMu)
+	readerTicks int64
+
+	// Context given to StartContext; GetNextTick gives up once it is done
+	// (nil = never cancelled)
+	sessionCtx context.Context
+
 	// Callbacks (for integration with agents)
 	callbacks HolodeckCallbacks
 
 	// Performance tracking
 	startTime    time.Time
 	lastTickTime time.Time
+	timing       *TimingStats
+
+	// Rejected orders waiting to be resubmitted
+	retries *RetryQueue
+
+	// Rejection counts by error code
+	rejections map[string]int64
+
+	// Overnight swap on positions held across the rollover (optional)
+	financing *financing.FinancingEngine
+
+	// Interest on idle cash (optional)
+	interest *financing.InterestEngine
+
+	// Dividend and split calendar (optional)
+	corporate *corporate.CorporateActionsEngine
+
+	// Hash-chained record of every reported execution
+	audit *AuditTrail
+
+	// Per-ticket positions in hedging mode (nil when netting)
+	hedges *types.HedgeBook
+
+	// Margin call and stop-out levels
+	margin *MarginMonitor
+
+	// Set once negative balance protection has closed the account
+	protected bool
+
+	// Daily loss kill switch and the day it halted trading ("" = trading)
+	lossLimit *risk.DailyLossLimit
+	haltedDay string
+
+	// Order size throttle by drawdown (nil when not configured)
+	throttle *risk.DrawdownThrottle
+
+	// Periodic balance and position snapshots (nil when not configured)
+	snapshots *Snapshotter
+
+	// Caps on the execution, balance and trade histories
+	history *HistoryBounds
+
+	// Trailing-window performance (nil when not configured)
+	rolling *RollingMetrics
+
+	// Buy-and-hold of the session instrument, for comparison
+	benchmark *Benchmark
+
+	// Metric threshold alerts (nil when no rules are configured)
+	alerts *AlertMonitor
+
+	// Spans around pipeline stages (nil disables tracing)
+	tracer types.Tracer
+
+	// Tick log thinning and metric aggregation (nil when not configured)
+	sampler *LogSampler
+
+	// Every tick consumed, for replaying the session (nil when not configured)
+	replay *reader.ReplayWriter
+
+	// Percent complete, rate and ETA reports (nil when not configured)
+	progress *ProgressTracker
+
+	// Max speed: no timing bookkeeping on the hot path
+	maxSpeed bool
+
+	// Holds the tick loop between Step calls in step mode
+	step *stepGate
+
+	// Holds the tick loop while paused
+	pause *pauseGate
+
+	// Simulated time the session ends at (zero = end of data), and
+	// whether it has been reached
+	endTime time.Time
+	ended   bool
+
+	// Paces GetNextTick (nil runs unpaced); pacedAt is when the previous
+	// paced tick was handed out
+	speed   *speed.SpeedController
+	pacedAt time.Time
+
+	// Times the controller's batches (nil until it batches)
+	batch *batchTracker
+
+	// Agent driven by Run (nil outside Run) and the fills waiting to be
+	// passed to its OnFill once the lock is released
+	agent      agent.Agent
+	agentFills []*types.ExecutionReport
+
+	// Replaces the wall clock for pacing and log stamps (nil = wall clock)
+	wallClock types.Clock
 }
 
 // ==================== SUBSYSTEM INTERFACES ====================
@@ -53,6 +169,50 @@ type OrderExecutor interface {
 	CalculateSlippage(size float64, availableDepth int64, momentum int, instrument types.Instrument) float64
 }
 
+// AccountAwareExecutor is implemented by executors that check orders
+// against the account (e.g. margin); the simulator passes its balance and
+// position before each order
+type AccountAwareExecutor interface {
+	SetAccount(balance *types.Balance, position *types.Position)
+}
+
+// TracedExecutor is implemented by executors that trace their own stages
+// (validation, slippage); the simulator passes the order span's context
+// before each order
+type TracedExecutor interface {
+	SetTrace(ctx context.Context, tracer types.Tracer)
+}
+
+// PositionsAwareExecutor is implemented by executors that check orders
+// against every open position (e.g. exposure limits); the simulator passes
+// its positions by symbol before each order
+type PositionsAwareExecutor interface {
+	SetPositions(positions map[string]*types.Position)
+}
+
+// TickObservingExecutor is implemented by executors whose models track the
+// market between orders; the simulator passes them every tick
+type TickObservingExecutor interface {
+	ObserveTick(tick *types.Tick)
+}
+
+// OrderCheckingExecutor is implemented by executors that can dry-run their
+// pre-trade checks; batches use it to reject every leg before any fills
+type OrderCheckingExecutor interface {
+	CheckOrder(order *types.Order, tick *types.Tick, instrument types.Instrument) *types.ExecutionReport
+}
+
+// WorkingOrderExecutor is implemented by executors that keep the unfilled
+// remainder of partial fills working across subsequent ticks
+type WorkingOrderExecutor interface {
+	// ProcessWorkingOrders fills working remainders against a new tick and
+	// returns the incremental fills and the consolidated completed reports
+	ProcessWorkingOrders(tick *types.Tick, instrument types.Instrument) ([]*types.ExecutionReport, []*types.ExecutionReport)
+
+	// CancelWorkingOrders cancels all remainders and returns their final reports
+	CancelWorkingOrders() []*types.ExecutionReport
+}
+
 // TickReader defines the tick data source interface
 type TickReader interface {
 	// HasNext checks if there are more ticks to read
@@ -71,6 +231,13 @@ type TickReader interface {
 	Reset() error
 }
 
+// ContextTickReader is implemented by readers whose Next can block (e.g.
+// plugins); the simulator reads with NextContext so a cancelled
+// simulation does not wait on the read
+type ContextTickReader interface {
+	NextContext(ctx context.Context) (*types.Tick, error)
+}
+
 // Logger defines the logging interface
 type Logger interface {
 	// LogTick logs a tick
@@ -92,6 +259,15 @@ type Logger interface {
 	Close() error
 }
 
+// FlushingLogger is a Logger that buffers writes; Stop flushes it so the
+// session's log is complete on disk once the session ends
+type FlushingLogger interface {
+	Logger
+
+	// Flush writes out whatever is buffered
+	Flush() error
+}
+
 // HolodeckCallbacks are optional callbacks for integration
 type HolodeckCallbacks struct {
 	// OnTick is called when a new tick is received
@@ -103,9 +279,18 @@ type HolodeckCallbacks struct {
 	// OnError is called when an error occurs
 	OnError func(err error)
 
+	// OnMarginEvent is called on a margin call or stop-out
+	OnMarginEvent func(event *MarginEvent)
+
+	// OnAlert is called when an alert rule is breached
+	OnAlert func(alert *Alert)
+
 	// OnStatusChange is called when account status changes
 	OnStatusChange func(oldStatus, newStatus string)
 
+	// OnProgress is called with each progress report
+	OnProgress func(progress *Progress)
+
 	// OnSessionEnd is called when the session ends
 	OnSessionEnd func(status *SessionStatus)
 }
@@ -127,12 +312,69 @@ func NewHolodeck(config *HolodeckConfig) (*Holodeck, error) {
 
 	// Create Holodeck instance
 	h := &Holodeck{
-		config:    config,
-		state:     state,
-		running:   false,
-		stopped:   false,
-		stopChan:  make(chan bool, 1),
-		startTime: time.Now(),
+		config:     config,
+		state:      state,
+		running:    false,
+		stopped:    false,
+		stopChan:   make(chan bool, 1),
+		startTime:  time.Now(),
+		timing:     NewTimingStats(),
+		step:       newStepGate(),
+		pause:      newPauseGate(),
+		retries:    NewRetryQueue(),
+		rejections: make(map[string]int64),
+		audit:      NewAuditTrail(),
+		margin: NewMarginMonitor(
+			config.Config.Account.MarginCallLevel,
+			config.Config.Account.StopOutLevel,
+		),
+		lossLimit: risk.NewDailyLossLimit(
+			config.Config.Account.DailyLossLimit.MaxLoss,
+			config.Config.Account.DailyLossLimit.MaxLossPercent,
+			config.Config.Account.DailyLossLimit.Flatten,
+		),
+	}
+	if config.Config.IsHedging() {
+		h.hedges = types.NewHedgeBook()
+	}
+
+	h.throttle, err = risk.NewDrawdownThrottle(config.Config.Account.DrawdownThrottle)
+	if err != nil {
+		return nil, types.NewConfigError("account.drawdown_throttle", err.Error())
+	}
+
+	h.alerts, err = NewAlertMonitor(config.Config.Metrics.Alerts)
+	if err != nil {
+		return nil, types.NewConfigError("metrics.alerts", err.Error())
+	}
+
+	h.snapshots, err = NewSnapshotter(config.Config.Session.Snapshots)
+	if err != nil {
+		return nil, err
+	}
+
+	h.history, err = NewHistoryBounds(config.StateConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	h.rolling = NewRollingMetrics(config.Config.Metrics.Rolling)
+	h.sampler = NewLogSampler(config.Config.Logging.Sampling)
+	h.progress = NewProgressTracker(config.Config.Logging.Progress)
+	h.benchmark = NewBenchmark(config.Config.Metrics.BenchmarkSize)
+
+	if end := config.Config.Session.EndTime; end != "" {
+		h.endTime, err = ParseSimTime(end, true)
+		if err != nil {
+			return nil, types.NewConfigError("session.end_time", err.Error())
+		}
+	}
+
+	if path := config.Config.Logging.ReplayFile; path != "" {
+		h.replay, err = reader.NewReplayWriter(path)
+		if err != nil {
+			return nil, types.NewConfigError("logging.replay_file", err.Error())
+		}
 	}
 
 	return h, nil
@@ -144,15 +386,42 @@ func (h *Holodeck) WithExecutor(executor OrderExecutor) *Holodeck {
 	return h
 }
 
+// WithFinancing sets the overnight swap engine
+func (h *Holodeck) WithFinancing(engine *financing.FinancingEngine) *Holodeck {
+	h.financing = engine
+	return h
+}
+
+// WithInterest sets the idle cash interest engine
+func (h *Holodeck) WithInterest(engine *financing.InterestEngine) *Holodeck {
+	h.interest = engine
+	return h
+}
+
 // WithReader sets the tick reader
 func (h *Holodeck) WithReader(reader TickReader) *Holodeck {
 	h.reader = reader
 	return h
 }
 
+// WithPlugins hands the Holodeck the plugin processes its subsystems use,
+// so Stop can kill them
+func (h *Holodeck) WithPlugins(set *plugins.Set) *Holodeck {
+	h.plugins = set
+	return h
+}
+
+// WithTracer sets the tracer that records spans around tick reads, order
+// validation, slippage and state updates
+func (h *Holodeck) WithTracer(tracer types.Tracer) *Holodeck {
+	h.tracer = tracer
+	return h
+}
+
 // WithLogger sets the logger
 func (h *Holodeck) WithLogger(logger Logger) *Holodeck {
 	h.logger = logger
+	h.applyWallClock()
 	return h
 }
 
@@ -167,48 +436,214 @@ func (h *Holodeck) WithCallbacks(callbacks HolodeckCallbacks) *Holodeck {
 
 // GetNextTick returns the next market tick from the data source
 // Returns types.Tick and error if no more ticks or read error
+// In step mode it blocks until Step is called, and while paused until
+// Resume; with a speed controller attached it waits until the tick is due
 func (h *Holodeck) GetNextTick() (*types.Tick, error) {
+	return h.GetNextTickContext(context.Background())
+}
+
+// GetNextTickContext is GetNextTick that gives up when ctx (or the context
+// given to StartContext) is done, returning its error: waits for a step,
+// a resume or the speed controller are cut short, and readers that
+// implement ContextTickReader stop reading. A tick already handed to the
+// speed controller is still returned; the next call returns the error
+func (h *Holodeck) GetNextTickContext(ctx context.Context) (*types.Tick, error) {
+	ctx, cancel := h.tickContext(ctx)
+	defer cancel()
+
+	tick, err := h.getNextTick(ctx)
+	if err != nil && ctx.Err() != nil {
+		return nil, context.Cause(ctx)
+	}
+	return tick, err
+}
+
+// getNextTick waits for the tick loop's gates, then reads and paces the
+// next tick
+func (h *Holodeck) getNextTick(ctx context.Context) (*types.Tick, error) {
+	stepped, err := h.step.wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stepped {
+		tick, err := h.nextTick(ctx)
+		if err == nil {
+			h.step.count()
+		}
+		h.step.results <- stepResult{tick: tick, err: err}
+		return tick, err
+	}
+
+	if err := h.pause.wait(ctx); err != nil {
+		return nil, err
+	}
+	tick, err := h.nextTick(ctx)
+	if err == nil {
+		h.paceTick(ctx, tick)
+	}
+	return tick, err
+}
+
+// tickContext returns ctx, also cancelled (with the same cause) when the
+// session context is done
+func (h *Holodeck) tickContext(ctx context.Context) (context.Context, context.CancelFunc) {
 	h.mu.RLock()
-	defer h.mu.RUnlock()
+	session := h.sessionCtx
+	h.mu.RUnlock()
 
-	if !h.running {
-		return nil, fmt.Errorf("holodeck not running")
+	if session == nil || session.Done() == nil {
+		return ctx, func() {}
+	}
+	if ctx.Done() == nil {
+		return session, func() {}
 	}
 
-	if h.reader == nil {
-		return nil, fmt.Errorf("reader not set")
+	merged, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(session, func() {
+		cancel(context.Cause(session))
+	})
+	return merged, func() {
+		stop()
+		cancel(nil)
 	}
+}
 
-	// Check if there are more ticks
-	if !h.reader.HasNext() {
-		return nil, fmt.Errorf("no more ticks available")
+// nextTick reads and processes the next tick
+// The read itself happens outside the lock, so a slow or cancelled reader
+// does not hold up the getters; readMu keeps reads in order
+func (h *Holodeck) nextTick(ctx context.Context) (*types.Tick, error) {
+	h.readMu.Lock()
+	defer h.readMu.Unlock()
+
+	readStart, err := h.beginRead(ctx)
+	if err != nil {
+		return nil, err
 	}
 
+	ctx, tickSpan := types.StartSpan(ctx, h.tracer, types.SpanTick)
+	defer tickSpan.End()
+
 	// Get next tick
-	tick, err := h.reader.Next()
+	_, readSpan := types.StartSpan(ctx, h.tracer, types.SpanTickRead)
+	tick, err := h.readTick(ctx)
+	readerTicks := h.reader.GetTickCount()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	defer h.timing.leave()
+
+	h.timing.addReader(readStart)
+	h.readerTicks = readerTicks
 	if err != nil {
-		if h.logger != nil {
-			h.logger.LogError(err)
+		readSpan.RecordError(err)
+		readSpan.End()
+		tickSpan.RecordError(err)
+		// Running off the end of the data or a cancelled read is not an
+		// error worth logging
+		if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+			h.logError(err)
 		}
 		return nil, err
 	}
+	readSpan.End()
+
+	// Stopped while the tick was being read
+	if !h.running {
+		return nil, fmt.Errorf("holodeck not running")
+	}
+
+	// The tick is past the end of the session
+	if err := h.checkEndTime(tick); err != nil {
+		return nil, err
+	}
+
+	tickSpan.SetAttribute("symbol", tick.Symbol)
+	tickSpan.SetAttribute("sequence", tick.Sequence)
+
+	// Record the tick for replay before anything acts on it
+	if h.replay != nil {
+		if err := h.replay.WriteTick(tick); err != nil {
+			h.logError(err)
+		}
+	}
 
 	// Update state - use actual field name: CurrentTick
 	h.state.CurrentTick = tick
+	h.state.Clock.Set(tick.Timestamp)
+	h.state.LastTicks[h.state.symbolKey(tick.Symbol)] = tick
+	h.state.Balance.UpdateConverters(tick)
 	h.state.TickCount++
 	h.lastTickTime = time.Now()
 
-	// Log tick if logger available
-	if h.logger != nil {
-		h.logger.LogTick(tick)
+	// Let the executor's slippage models see every tick
+	if toe, ok := h.executor.(TickObservingExecutor); ok {
+		toe.ObserveTick(tick)
+	}
+
+	// Log tick if logger available (every Nth when sampling)
+	h.logTick(tick)
+
+	_, updateSpan := types.StartSpan(ctx, h.tracer, types.SpanTickUpdate)
+
+	// Charge swap for rollovers crossed since the previous tick
+	h.processFinancing(tick)
+
+	// Accrue interest on idle cash for each day crossed
+	h.processInterest(tick)
+
+	// Apply dividends and splits that went ex since the previous tick
+	h.processCorporateActions(tick)
+
+	// Continue filling working order remainders in the tick's symbol
+	h.processWorkingOrders(tick)
+
+	// Revalue open positions and sample the equity curve
+	h.markToMarket(tick)
+	if h.state.symbolKey(tick.Symbol) == h.state.PrimarySymbol {
+		h.benchmark.Observe(tick)
 	}
+	h.state.EquityCurve.Record(tick.Timestamp, h.state.Balance.CurrentBalance, h.state.Balance.TotalUnrealizedPnL)
+	h.sampleBenchmark(tick.Timestamp)
+	h.state.Daily.RecordEquity(tick.Timestamp, h.state.Balance.CurrentBalance)
+
+	// Raise margin calls and stop out against the marked-to-market equity
+	h.processMargin(tick)
+	h.processBalanceProtection(tick)
+
+	// Halt trading for the day once the daily loss limit is hit
+	h.processDailyLossLimit(tick)
+
+	// Raise alerts for the metric thresholds breached
+	h.processAlerts(tick)
+
+	// Persist a balance snapshot when one is due
+	h.processSnapshots(tick)
+
+	// Emit trailing-window metrics when due
+	h.processRollingMetrics(tick)
+
+	// Emit the sampled interval's aggregated metrics when due
+	h.processSampledMetrics(tick)
+
+	// Report percent complete and ETA when due
+	h.processProgress(tick)
+
+	// Resubmit rejected orders whose backoff has elapsed
+	h.processRetries()
+
+	// Evict history past the caps
+	if err := h.history.Trim(h.state); err != nil {
+		h.logError(err)
+	}
+	updateSpan.End()
 
 	// Call callback if set
 	if h.callbacks.OnTick != nil {
-		if err := h.callbacks.OnTick(tick); err != nil {
-			if h.logger != nil {
-				h.logger.LogError(err)
-			}
+		callbackStart := h.timing.start()
+		err := h.callbacks.OnTick(tick)
+		h.timing.addCallback(callbackStart)
+		if err != nil {
+			h.logError(err)
 		}
 	}
 
@@ -221,86 +656,702 @@ func (h *Holodeck) ExecuteOrder(order *types.Order) (*types.ExecutionReport, err
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	h.timing.enter()
+	defer h.timing.leave()
+
+	if err := h.checkCanExecute(); err != nil {
+		return nil, err
+	}
+
+	return h.executeOrder(order)
+}
+
+// checkCanExecute checks that orders can be executed right now
+func (h *Holodeck) checkCanExecute() error {
 	if !h.running {
-		return nil, fmt.Errorf("holodeck not running")
+		return fmt.Errorf("holodeck not running")
 	}
 
 	if h.executor == nil {
-		return nil, fmt.Errorf("executor not set")
+		return fmt.Errorf("executor not set")
 	}
 
 	if h.state.CurrentTick == nil {
-		return nil, fmt.Errorf("no tick data available")
+		return fmt.Errorf("no tick data available")
+	}
+
+	if h.protected {
+		return types.NewBalanceProtectionError(h.state.Balance.ProtectionCredit)
+	}
+
+	return nil
+}
+
+// executeOrder executes an order against the current tick
+// Caller must hold the write lock
+func (h *Holodeck) executeOrder(order *types.Order) (*types.ExecutionReport, error) {
+	ctx, orderSpan := types.StartSpan(context.Background(), h.tracer, types.SpanOrder)
+	defer orderSpan.End()
+	orderSpan.SetAttribute("order_id", order.OrderID)
+	orderSpan.SetAttribute("action", order.Action)
+	orderSpan.SetAttribute("size", order.Size)
+
+	if h.hedges != nil && order.CloseTicket != "" {
+		if err := h.registerTicketClose(order); err != nil {
+			return nil, err
+		}
+	}
+
+	// Orders fill against the latest tick of their own symbol, priced
+	// with that symbol's instrument
+	symbol := h.state.symbolKey(order.Symbol)
+	instrument := h.instrumentFor(symbol)
+	if instrument == nil {
+		return nil, fmt.Errorf("no instrument configured for %s", symbol)
+	}
+	if h.hedges != nil && symbol != h.state.PrimarySymbol {
+		return nil, fmt.Errorf("hedging mode trades %s only", h.state.PrimarySymbol)
+	}
+	tick := h.state.LastTicks[symbol]
+	if tick == nil {
+		return nil, fmt.Errorf("no tick data for %s", symbol)
+	}
+	order.Symbol = symbol
+
+	// While paused orders are rejected or queued for Resume
+	if exec := h.checkPaused(order, tick); exec != nil {
+		return exec, nil
+	}
+
+	// While halted only orders that reduce exposure may trade, and in
+	// drawdown the largest order allowed shrinks
+	exec := h.checkHalted(order, tick)
+	if exec == nil {
+		exec = h.checkThrottle(order, tick)
+	}
+	if exec != nil {
+		exec.Symbol = symbol
+		h.rejections[exec.ErrorCode]++
+		h.reportExecution(exec)
+		return exec, nil
+	}
+
+	if aae, ok := h.executor.(AccountAwareExecutor); ok {
+		aae.SetAccount(h.state.Balance, h.state.position(symbol))
+	}
+	if pae, ok := h.executor.(PositionsAwareExecutor); ok {
+		pae.SetPositions(h.state.Positions)
 	}
 
 	// Execute the order
-	exec, err := h.executor.Execute(order, h.state.CurrentTick, h.config.Instrument)
+	execCtx, execSpan := types.StartSpan(ctx, h.tracer, types.SpanOrderExecute)
+	if te, ok := h.executor.(TracedExecutor); ok {
+		te.SetTrace(execCtx, h.tracer)
+	}
+	execStart := h.timing.start()
+	exec, err := h.executor.Execute(order, tick, instrument)
+	h.timing.addExecutor(execStart)
+	execSpan.End()
 	if err != nil {
+		orderSpan.RecordError(err)
 		// Log error
-		if h.logger != nil {
-			h.logger.LogError(err)
-		}
+		h.logError(err)
 		// Call error callback
 		if h.callbacks.OnError != nil {
+			callbackStart := h.timing.start()
 			h.callbacks.OnError(err)
+			h.timing.addCallback(callbackStart)
 		}
 		return nil, err
 	}
 
+	exec.Symbol = symbol
+	exec.StopLoss = order.StopLoss
+	if exec.IsRejected() {
+		h.rejections[exec.ErrorCode]++
+	}
+
+	orderSpan.SetAttribute("status", exec.Status)
+
 	// Update state if executed (not rejected)
-	if !exec.IsRejected() && exec.FilledSize > 0 {
-		// Use correct field name: Position (it's *types.Position)
-		if h.state.Position != nil {
-			h.state.Position.Size = exec.FilledSize
-			h.state.Position.EntryPrice = exec.FillPrice
+	_, applySpan := types.StartSpan(ctx, h.tracer, types.SpanOrderStateApply)
+	h.applyExecution(exec)
+	applySpan.End()
+	if h.alerts != nil && !exec.IsRejected() {
+		h.alerts.RecordOrder(order.Size, exec.FilledSize)
+	}
+
+	// Log execution and notify
+	h.reportExecution(exec)
+
+	return exec, nil
+}
+
+// applyExecution updates position, history and balance from a fill
+// Caller must hold the write lock
+func (h *Holodeck) applyExecution(exec *types.ExecutionReport) {
+	if exec.IsRejected() || exec.FilledSize <= 0 {
+		return
+	}
+
+	h.attributeFillCosts(exec)
+	if h.hedges != nil {
+		h.applyFillToHedges(exec)
+	} else {
+		h.applyFillToPosition(exec)
+	}
+
+	// Use correct field name: ExecutionHistory
+	h.state.ExecutionHistory = append(h.state.ExecutionHistory, exec)
+	h.state.ExecutionCount++
+	h.state.TotalSlippageUnits += exec.SlippageUnits
+
+	// Update balance - use correct field name: CurrentBalance (not Current)
+	if h.state.Balance != nil {
+		h.state.Balance.UpdateFromExecution(exec)
+		h.updateUsedMargin()
+
+		// Re-mark so equity drops the unrealized P&L the fill realized
+		if tick := h.state.LastTicks[h.state.symbolKey(exec.Symbol)]; tick != nil {
+			h.markToMarket(tick)
+		}
+		outcome := h.state.Balance.Breakeven.Classify(exec.RealizedPnL, exec.FilledSize)
+		realized := h.state.Balance.ConvertToAccount(exec.Symbol, exec.RealizedPnL)
+		commission := h.state.Balance.ConvertToAccount(exec.Symbol, exec.Commission)
+		if exec.RealizedPnL != 0 {
+			h.state.Breakdown.RecordTrade(exec.Timestamp, realized, outcome)
+			if h.rolling != nil {
+				h.rolling.RecordTrade(exec.Timestamp, outcome)
+			}
+			if h.alerts != nil {
+				h.alerts.RecordTrade(outcome)
+			}
+		}
+		if h.sampler != nil {
+			h.sampler.RecordFill(exec.FilledSize, realized, commission)
+		}
+		h.state.Symbols.RecordFill(h.state.symbolKey(exec.Symbol), realized, commission, outcome)
+		h.state.Daily.RecordTrade(exec.Timestamp, realized, commission, h.state.Balance.CurrentBalance)
+	}
+}
+
+// updateUsedMargin posts margin for the open positions at their entry
+// prices, each with its own instrument's tiers and converted from its quote
+// currency (every ticket, gross, in hedging mode)
+// Caller must hold the write lock
+func (h *Holodeck) updateUsedMargin() {
+	used := 0.0
+	if h.hedges != nil {
+		tiers := h.config.Config.GetLeverageTiers(h.config.Instrument)
+		used = h.state.Balance.ConvertToAccount(h.state.PrimarySymbol,
+			h.hedges.CalculateUsedMargin(h.config.Instrument, tiers, h.state.Balance.Leverage))
+	} else {
+		for symbol, pos := range h.state.Positions {
+			instrument := h.instrumentFor(symbol)
+			if instrument == nil {
+				continue
+			}
+			tiers := h.config.Config.GetLeverageTiers(instrument)
+			margin := types.CalculateTieredMargin(pos.Size, pos.EntryPrice, instrument, tiers, h.state.Balance.Leverage)
+			used += h.state.Balance.ConvertToAccount(symbol, margin)
+		}
+	}
+	h.state.Balance.UpdateMargin(used)
+}
+
+// instrumentFor returns the instrument a symbol is priced with ("" is the
+// session instrument), nil when none is configured for it
+func (h *Holodeck) instrumentFor(symbol string) types.Instrument {
+	symbol = h.state.symbolKey(symbol)
+	if symbol == h.state.PrimarySymbol {
+		return h.config.Instrument
+	}
+	return h.config.Instruments[symbol]
+}
+
+// pipValueFor returns the pip value of a symbol's instrument
+func (h *Holodeck) pipValueFor(symbol string) float64 {
+	if instrument := h.instrumentFor(symbol); instrument != nil {
+		return instrument.GetPipValue()
+	}
+	return h.config.Instrument.GetPipValue()
+}
+
+// contractSizeFor returns the units per lot of a symbol's instrument (1
+// when it does not say)
+func (h *Holodeck) contractSizeFor(symbol string) float64 {
+	if instrument := h.instrumentFor(symbol); instrument != nil && instrument.GetContractSize() > 0 {
+		return float64(instrument.GetContractSize())
+	}
+	return 1.0
+}
+
+// applyFillToPosition nets a fill into the signed position
+// Scale-ins average the entry price; reducing fills realize P&L on the
+// closed size; a fill larger than the position flips it
+func (h *Holodeck) applyFillToPosition(exec *types.ExecutionReport) {
+	pos := h.state.position(exec.Symbol)
+	closedBefore := len(pos.ClosedLots)
+
+	exec.RealizedPnL += pos.ApplyFill(exec, h.pipValueFor(exec.Symbol))
+
+	// The fill's excursions are those of the lots it closed
+	for _, closed := range pos.ClosedLots[closedBefore:] {
+		exec.MaxFavorableExcursion += closed.MaxFavorableExcursion
+		exec.MaxAdverseExcursion += closed.MaxAdverseExcursion
+	}
+
+	exec.PositionAfter = pos.Size
+	exec.EntryPrice = pos.EntryPrice
+	if h.state.symbolKey(exec.Symbol) == h.state.PrimarySymbol {
+		h.benchmark.ObservePosition(pos.Size)
+	}
+}
+
+// ClosePosition flattens a symbol's position ("" is the session
+// instrument) with a market order
+// The order goes through the executor, so commission, slippage and
+// partial fills apply as for any other order
+func (h *Holodeck) ClosePosition(symbol string) (*types.ExecutionReport, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.timing.enter()
+	defer h.timing.leave()
+
+	if err := h.checkCanExecute(); err != nil {
+		return nil, err
+	}
+
+	if h.hedges != nil {
+		return nil, fmt.Errorf("hedging mode: use ClosePositionByTicket or CloseAllPositions")
+	}
+
+	pos := h.state.Positions[h.state.symbolKey(symbol)]
+	if pos == nil || pos.IsFlat() {
+		return nil, fmt.Errorf("no open position to close")
+	}
+
+	order := types.NewClosePositionOrder(pos, h.state.CurrentTick.Timestamp)
+	order.Symbol = symbol
+	return h.executeOrder(order)
+}
+
+// ClosePartial closes size of a symbol's position ("" is the session
+// instrument) with a market order
+// P&L is realized on the closed slice only (matched to lots by
+// account.lot_matching); the report's UnrealizedPnL marks what remains
+func (h *Holodeck) ClosePartial(symbol string, size float64) (*types.ExecutionReport, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.timing.enter()
+	defer h.timing.leave()
+
+	if err := h.checkCanExecute(); err != nil {
+		return nil, err
+	}
+
+	if h.hedges != nil {
+		return nil, fmt.Errorf("hedging mode: close part of a ticket with an order carrying CloseTicket")
+	}
+
+	pos := h.state.Positions[h.state.symbolKey(symbol)]
+	if pos == nil || pos.IsFlat() {
+		return nil, fmt.Errorf("no open position to close")
+	}
+
+	order := types.NewPartialCloseOrder(pos, size, h.state.CurrentTick.Timestamp)
+	if order == nil {
+		return nil, fmt.Errorf("close size %.4f must be between 0 and the position size %.4f", size, pos.GetAbsoluteSize())
+	}
+	order.Symbol = symbol
+
+	exec, err := h.executeOrder(order)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mark the remainder at the side it would close on
+	if tick := h.state.LastTicks[h.state.symbolKey(symbol)]; tick != nil && !pos.IsFlat() {
+		mark := tick.GetSellPrice()
+		if pos.IsShort() {
+			mark = tick.GetBuyPrice()
 		}
+		pos.UpdatePrice(mark, h.pipValueFor(symbol))
+		exec.UnrealizedPnL = pos.UnrealizedPnL
+	}
 
-		// Use correct field name: ExecutionHistory
-		h.state.ExecutionHistory = append(h.state.ExecutionHistory, exec)
-		h.state.ExecutionCount++
+	return exec, nil
+}
 
-		// Update balance - use correct field name: CurrentBalance (not Current)
-		if h.state.Balance != nil {
-			h.state.Balance.UpdateFromExecution(exec)
+// ReversePosition flips a symbol's position ("" is the session
+// instrument) in one call
+// The close leg executes first so its realized P&L is booked against the
+// old entry; the open leg (size <= 0 reuses the current size) only runs
+// once the close leg has filled completely
+// Returns the execution reports in the order they were executed
+func (h *Holodeck) ReversePosition(symbol string, size float64) ([]*types.ExecutionReport, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.timing.enter()
+	defer h.timing.leave()
+
+	if err := h.checkCanExecute(); err != nil {
+		return nil, err
+	}
+
+	if h.hedges != nil {
+		return nil, fmt.Errorf("hedging mode: close tickets and open the new side instead")
+	}
+
+	pos := h.state.Positions[h.state.symbolKey(symbol)]
+	if pos == nil {
+		return nil, fmt.Errorf("no open position to reverse")
+	}
+	orders := types.NewReversePositionOrders(pos, size, h.state.CurrentTick.Timestamp)
+	if orders == nil {
+		return nil, fmt.Errorf("no open position to reverse")
+	}
+
+	reports := make([]*types.ExecutionReport, 0, len(orders))
+	for _, order := range orders {
+		order.Symbol = symbol
+		exec, err := h.executeOrder(order)
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, exec)
+
+		if !exec.IsFilled() {
+			// Never open the new side on top of a leftover old position
+			break
 		}
 	}
 
-	// Log execution
+	return reports, nil
+}
+
+// reportExecution audits and logs an execution, fires the execution
+// callback and queues the fill for the agent
+func (h *Holodeck) reportExecution(exec *types.ExecutionReport) {
+	h.publishExecution(exec)
+	h.notifyAgentFill(exec)
+}
+
+// publishExecution audits and logs an execution and fires the execution
+// callback
+func (h *Holodeck) publishExecution(exec *types.ExecutionReport) {
+	h.audit.Append(exec)
+
 	if h.logger != nil {
+		logStart := h.timing.start()
 		h.logger.LogExecution(exec)
+		h.timing.addLogger(logStart)
 	}
 
-	// Call execution callback
 	if h.callbacks.OnExecution != nil {
-		if err := h.callbacks.OnExecution(exec); err != nil {
-			if h.logger != nil {
-				h.logger.LogError(err)
-			}
+		callbackStart := h.timing.start()
+		err := h.callbacks.OnExecution(exec)
+		h.timing.addCallback(callbackStart)
+		if err != nil {
+			h.logError(err)
 		}
 	}
+}
 
-	return exec, nil
+// beginRead checks that a tick can be read and starts timing the read
+// Caller must hold readMu
+func (h *Holodeck) beginRead(ctx context.Context) (time.Time, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.timing.enter()
+	if err := h.checkCanRead(ctx); err != nil {
+		h.timing.leave()
+		return time.Time{}, err
+	}
+	return h.timing.start(), nil
+}
+
+// checkCanRead checks that the session has another tick to read
+// Caller must hold readMu and the write lock
+func (h *Holodeck) checkCanRead(ctx context.Context) error {
+	if !h.running {
+		return fmt.Errorf("holodeck not running")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if h.reader == nil {
+		return fmt.Errorf("reader not set")
+	}
+
+	if h.ended {
+		return h.checkEndTime(nil)
+	}
+
+	// Check if there are more ticks
+	if !h.reader.HasNext() {
+		return fmt.Errorf("no more ticks available")
+	}
+	return nil
 }
 
-// GetPosition returns the current position state
+// readTick reads the next tick, passing ctx to readers that take one
+// Caller must hold readMu
+func (h *Holodeck) readTick(ctx context.Context) (*types.Tick, error) {
+	if reader, ok := h.reader.(ContextTickReader); ok {
+		return reader.NextContext(ctx)
+	}
+	return h.reader.Next()
+}
+
+// logError logs an error if a logger is set
+func (h *Holodeck) logError(err error) {
+	if h.logger == nil {
+		return
+	}
+	logStart := h.timing.start()
+	h.logger.LogError(err)
+	h.timing.addLogger(logStart)
+}
+
+// processWorkingOrders advances the working remainders in a new tick's
+// symbol
+// Incremental fills update state and go to the agent's OnFill; the
+// consolidated report of each completed order's remainder is what gets
+// logged and passed to OnExecution
+// Caller must hold the write lock
+func (h *Holodeck) processWorkingOrders(tick *types.Tick) {
+	woe, ok := h.executor.(WorkingOrderExecutor)
+	if !ok {
+		return
+	}
+
+	symbol := h.state.symbolKey(tick.Symbol)
+	instrument := h.instrumentFor(symbol)
+	if instrument == nil {
+		return
+	}
+
+	// Working orders carry the resolved symbol, so match the tick to it
+	if tick.Symbol != symbol {
+		keyed := *tick
+		keyed.Symbol = symbol
+		tick = &keyed
+	}
+
+	execStart := h.timing.start()
+	fills, completed := woe.ProcessWorkingOrders(tick, instrument)
+	h.timing.addExecutor(execStart)
+	for _, fill := range fills {
+		h.applyExecution(fill)
+		if h.alerts != nil {
+			h.alerts.RecordOrder(0, fill.FilledSize)
+		}
+		h.notifyAgentFill(fill)
+	}
+
+	for _, report := range completed {
+		h.reportWorkingOrder(report)
+	}
+}
+
+// reportWorkingOrder reports the final state of a working order, with its
+// resolved symbol and the position after its last fill
+// The agent already had the fills, so the summary is not sent to OnFill
+// Caller must hold the write lock
+func (h *Holodeck) reportWorkingOrder(report *types.ExecutionReport) {
+	report.Symbol = h.state.symbolKey(report.Symbol)
+	if pos := h.state.Positions[report.Symbol]; pos != nil {
+		report.PositionAfter = pos.Size
+	}
+	h.publishExecution(report)
+}
+
+// processFinancing books swap for the positions held across rollovers,
+// each at its own instrument's rate
+// Caller must hold the write lock
+func (h *Holodeck) processFinancing(tick *types.Tick) {
+	if h.financing == nil || h.state.Balance == nil {
+		return
+	}
+
+	financed := make([]financing.FinancedPosition, 0, 1)
+	positions := make([]*types.Position, 0, 1)
+	if h.hedges != nil {
+		// Each ticket pays or earns swap on its own side
+		for _, hp := range h.hedges.GetOpenPositions() {
+			financed = append(financed, financing.FinancedPosition{Size: hp.GetSignedSize(), Instrument: h.config.Instrument})
+		}
+	} else {
+		for symbol, pos := range h.state.Positions {
+			financed = append(financed, financing.FinancedPosition{Size: pos.Size, Instrument: h.instrumentFor(symbol)})
+			positions = append(positions, pos)
+		}
+	}
+
+	charges := h.financing.ProcessTickInstruments(tick.Timestamp, financed)
+	for _, charge := range charges {
+		h.state.Balance.ApplyFinancing(charge.Amount, types.BalanceReasonSwap, charge.Timestamp)
+		h.state.Daily.RecordFinancing(charge.Timestamp, charge.Amount, h.state.Balance.CurrentBalance)
+	}
+	h.chargeSwapToLots(positions, charges)
+}
+
+// processInterest books interest on the cash not posted as margin
+// Caller must hold the write lock
+func (h *Holodeck) processInterest(tick *types.Tick) {
+	if h.interest == nil || h.state.Balance == nil {
+		return
+	}
+
+	for _, accrual := range h.interest.ProcessTick(tick.Timestamp, h.state.Balance.GetIdleCash()) {
+		h.state.Balance.ApplyInterest(accrual.Amount, accrual.Timestamp)
+		h.state.Daily.RecordInterest(tick.Timestamp, accrual.Amount, h.state.Balance.CurrentBalance)
+	}
+}
+
+// cancelWorkingOrders cancels working remainders and reports their final state
+// Caller must hold the write lock
+func (h *Holodeck) cancelWorkingOrders() {
+	woe, ok := h.executor.(WorkingOrderExecutor)
+	if !ok {
+		return
+	}
+
+	for _, report := range woe.CancelWorkingOrders() {
+		h.reportWorkingOrder(report)
+	}
+}
+
+// GetPosition returns a symbol's position state ("" is the session
+// instrument; flat if the symbol never traded)
 // Returns position size, entry price, unrealized P&L
-func (h *Holodeck) GetPosition() *types.Position {
+func (h *Holodeck) GetPosition(symbol string) *types.Position {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.state == nil {
+		return &types.Position{}
+	}
+
+	return copyPosition(h.state.Positions[h.state.symbolKey(symbol)])
+}
+
+// GetPositions returns every symbol's position, keyed by symbol
+func (h *Holodeck) GetPositions() map[string]*types.Position {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	if h.state == nil || h.state.Position == nil {
+	positions := make(map[string]*types.Position)
+	if h.state == nil {
+		return positions
+	}
+	for symbol, pos := range h.state.Positions {
+		positions[symbol] = copyPosition(pos)
+	}
+	return positions
+}
+
+// copyPosition returns a copy of position state (flat for nil)
+func copyPosition(pos *types.Position) *types.Position {
+	if pos == nil {
 		return &types.Position{}
 	}
 
-	// Return copy of position state
-	position := &types.Position{
-		Size:          h.state.Position.Size,
-		EntryPrice:    h.state.Position.EntryPrice,
-		UnrealizedPnL: h.state.Position.UnrealizedPnL,
-		RealizedPnL:   h.state.Position.RealizedPnL,
+	return &types.Position{
+		Size:          pos.Size,
+		EntryPrice:    pos.EntryPrice,
+		UnrealizedPnL: pos.UnrealizedPnL,
+		RealizedPnL:   pos.RealizedPnL,
 	}
+}
+
+// GetClosedLots returns every symbol's lots closed so far with their
+// realized P&L, matched by account.lot_matching, in close order
+func (h *Holodeck) GetClosedLots() []types.ClosedLot {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
 
-	return position
+	if h.state == nil {
+		return nil
+	}
+	return h.getClosedLots()
+}
+
+// getClosedLots collects the closed lots of every position, in close
+// order (symbols in name order within the same close time)
+// Caller must hold the lock
+func (h *Holodeck) getClosedLots() []types.ClosedLot {
+	symbols := make([]string, 0, len(h.state.Positions))
+	for symbol := range h.state.Positions {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	lots := make([]types.ClosedLot, 0)
+	for _, symbol := range symbols {
+		lots = append(lots, h.state.Positions[symbol].GetClosedLots()...)
+	}
+	sort.SliceStable(lots, func(i, j int) bool {
+		return lots[i].CloseTime.Before(lots[j].CloseTime)
+	})
+	return lots
+}
+
+// GetRMultiples returns the R-multiple figures of the closed trades opened
+// with an initial stop (Order.StopLoss)
+func (h *Holodeck) GetRMultiples() *types.RMultipleStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.state == nil {
+		return types.NewRMultipleStats(nil)
+	}
+	return h.getRMultipleStats()
+}
+
+// getRMultipleStats computes the R figures over every position
+// Caller must hold the lock
+func (h *Holodeck) getRMultipleStats() *types.RMultipleStats {
+	return types.NewRMultipleStats(h.getClosedLots())
+}
+
+// GetTradeDistributions returns the distribution of closed trades'
+// percent returns and of their winning and losing streaks
+func (h *Holodeck) GetTradeDistributions() (*types.ReturnDistribution, *types.StreakDistribution) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.state == nil {
+		return types.NewReturnDistribution(nil, types.DistributionBins), types.NewStreakDistribution(nil)
+	}
+	return h.getTradeDistributions()
+}
+
+// getTradeDistributions computes the closed-trade distributions over every
+// position, in close order
+// Caller must hold the lock
+func (h *Holodeck) getTradeDistributions() (*types.ReturnDistribution, *types.StreakDistribution) {
+	lots := h.getClosedLots()
+
+	var band types.BreakevenBand
+	if h.state.Balance != nil {
+		band = h.state.Balance.Breakeven
+	}
+
+	returns := make([]float64, len(lots))
+	outcomes := make([]int, len(lots))
+	for i, lot := range lots {
+		returns[i] = lot.GetReturnPercent()
+		outcomes[i] = band.Classify(lot.RealizedPnL, lot.Size)
+	}
+	return types.NewReturnDistribution(returns, types.DistributionBins), types.NewStreakDistribution(outcomes)
 }
 
 // GetBalance returns the current account balance state
@@ -329,45 +1380,75 @@ func (h *Holodeck) GetBalance() *types.Balance {
 	}
 }
 
+// GetExecutionHistory returns a copy of the applied executions
+func (h *Holodeck) GetExecutionHistory() []*types.ExecutionReport {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.state == nil {
+		return []*types.ExecutionReport{}
+	}
+
+	history := make([]*types.ExecutionReport, len(h.state.ExecutionHistory))
+	copy(history, h.state.ExecutionHistory)
+	return history
+}
+
 // GetMetrics returns current performance metrics as a map
-// Includes: ticks processed, trades executed, balance, position info
+// Kept for backward compatibility, prefer GetPerformanceMetrics
 func (h *Holodeck) GetMetrics() map[string]interface{} {
+	return h.GetPerformanceMetrics().ToMap()
+}
+
+// GetStatus returns the current session status
+func (h *Holodeck) GetStatus() *SessionStatus {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	metrics := make(map[string]interface{})
-
 	if h.state == nil {
-		return metrics
+		return &SessionStatus{}
 	}
 
-	// Basic metrics
-	metrics["ticks_processed"] = h.state.TickCount
-	metrics["trades_executed"] = h.state.ExecutionCount
-	metrics["session_duration"] = time.Since(h.startTime)
+	status := h.state.GetStatus()
+	status.IsPaused = h.pause.isPaused()
+	status.PausedSeconds = h.pause.pausedTime().Seconds()
+	status.QueuedOrders = len(h.pause.queued)
+	return status
+}
 
-	if h.state.Balance != nil {
-		metrics["current_balance"] = h.state.Balance.CurrentBalance
-		metrics["initial_balance"] = h.state.Balance.InitialBalance
-		metrics["available_margin"] = h.state.Balance.AvailableMargin
-		metrics["buying_power"] = h.state.Balance.BuyingPower
-		metrics["commission_paid"] = h.state.Balance.CommissionPaid
-		metrics["return_percent"] = h.state.Balance.GetReturnPercent()
-		metrics["drawdown_percent"] = h.state.Balance.GetDrawdownPercent()
-		metrics["win_rate"] = h.state.Balance.GetWinRate()
-	}
+// GetSessionID returns the session ID (log files are named after it)
+func (h *Holodeck) GetSessionID() string {
+	return h.config.SessionID
+}
 
-	if h.state.Position != nil {
-		metrics["position_size"] = h.state.Position.Size
-		metrics["entry_price"] = h.state.Position.EntryPrice
-		metrics["unrealized_pnl"] = h.state.Position.UnrealizedPnL
+// getMoneyDecimals returns the precision money is exported with
+func (h *Holodeck) getMoneyDecimals() int {
+	if h.config == nil || h.config.Config == nil {
+		return types.DefaultMoneyDecimals
 	}
+	return h.config.Config.GetMoneyDecimals()
+}
 
-	if h.reader != nil {
-		metrics["total_ticks_available"] = h.reader.GetTickCount()
+// copyRejections returns a copy of the rejection counts
+func (h *Holodeck) copyRejections() map[string]int64 {
+	counts := make(map[string]int64, len(h.rejections))
+	for code, count := range h.rejections {
+		counts[code] = count
 	}
+	return counts
+}
 
-	return metrics
+// getGatedCount returns orders rejected by the spread/staleness guard
+func (h *Holodeck) getGatedCount() int64 {
+	return h.rejections[types.ErrorCodeSpreadTooWide] + h.rejections[types.ErrorCodeStaleTick]
+}
+
+// GetTimingStats returns how wall time was split between agent and engine
+func (h *Holodeck) GetTimingStats() *TimingStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.timing.Snapshot()
 }
 
 // SetSpeed sets the simulation speed multiplier
@@ -380,14 +1461,46 @@ func (h *Holodeck) SetSpeed(multiplier float64) error {
 		return fmt.Errorf("speed multiplier must be positive")
 	}
 
+	if h.speed != nil {
+		if err := h.speed.SetSpeed(multiplier); err != nil {
+			return err
+		}
+		h.speed.SetMaxSpeed(false)
+	}
+
 	// Store in ExecutionConfig
 	h.config.ExecutionConfig.SpeedMultiplier = multiplier
+	h.maxSpeed = false
+	h.timing.setEnabled(true)
 	return nil
 }
 
+// SetMaxSpeed turns max speed mode on or off
+// At max speed the engine does no timing bookkeeping on the hot path
+// (GetTimingStats stays empty), for as-fast-as-possible batch backtests
+func (h *Holodeck) SetMaxSpeed(enabled bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.maxSpeed = enabled
+	h.timing.setEnabled(!enabled)
+	if h.speed != nil {
+		h.speed.SetMaxSpeed(enabled)
+	}
+}
+
+// IsMaxSpeed returns whether max speed mode is on
+func (h *Holodeck) IsMaxSpeed() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.maxSpeed
+}
+
 // Reset resets the Holodeck to initial state
 // Clears trades, resets balance, closes position
 func (h *Holodeck) Reset() error {
+	h.readMu.Lock()
+	defer h.readMu.Unlock()
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -401,12 +1514,74 @@ func (h *Holodeck) Reset() error {
 		return err
 	}
 	h.state = state
+	h.timing.Reset()
+	h.retries.Reset()
+	h.rejections = make(map[string]int64)
+	h.audit.Reset()
+	h.history.Reset()
+	if h.rolling != nil {
+		h.rolling.Reset()
+	}
+	if h.sampler != nil {
+		h.sampler.Reset()
+	}
+	if h.progress != nil {
+		h.progress.Reset()
+	}
+	h.benchmark.Reset()
+	h.margin.Reset()
+	h.pacedAt = time.Time{}
+	h.batch = nil
+	if h.speed != nil {
+		if err := h.speed.Reset(); err != nil {
+			return err
+		}
+		if multiplier := h.config.ExecutionConfig.SpeedMultiplier; multiplier > 0 && h.speed.GetTicksPerSecond() == 0 {
+			if err := h.speed.SetSpeed(multiplier); err != nil {
+				return err
+			}
+		}
+	}
+	h.protected = false
+	h.ended = false
+	h.lossLimit.Reset()
+	h.haltedDay = ""
+	if h.throttle != nil {
+		h.throttle.Reset()
+	}
+	if h.alerts != nil {
+		h.alerts.Reset()
+	}
+	if h.hedges != nil {
+		h.hedges.Reset()
+	}
+	if h.financing != nil {
+		h.financing.Reset()
+	}
+	if h.interest != nil {
+		h.interest.Reset()
+	}
+	if h.corporate != nil {
+		h.corporate.Reset()
+	}
 
 	// Reset reader if possible
 	if h.reader != nil {
 		if err := h.reader.Reset(); err != nil {
 			return err
 		}
+		h.readerTicks = h.reader.GetTickCount()
+	}
+
+	// Stop closed the replay file; the next session records afresh
+	if h.replay != nil {
+		if err := h.replay.Close(); err != nil {
+			return err
+		}
+		h.replay, err = reader.NewReplayWriter(h.config.Config.Logging.ReplayFile)
+		if err != nil {
+			return types.NewConfigError("logging.replay_file", err.Error())
+		}
 	}
 
 	return nil
@@ -415,6 +1590,17 @@ func (h *Holodeck) Reset() error {
 // Start starts the Holodeck session
 // Must be called before GetNextTick or ExecuteOrder
 func (h *Holodeck) Start() error {
+	return h.StartContext(context.Background())
+}
+
+// StartContext starts the session bound to ctx: once ctx is done (e.g. a
+// timeout), GetNextTick and Run return its error instead of another tick.
+// The session is not stopped by the context; call Stop (Run does)
+func (h *Holodeck) StartContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -429,9 +1615,11 @@ func (h *Holodeck) Start() error {
 
 	h.running = true
 	h.stopped = false
+	h.sessionCtx = ctx
 	h.config.IsRunning = true
-	h.startTime = time.Now()
+	h.startTime = h.wallNow()
 	h.state.SessionStart = h.startTime
+	h.startProgress()
 
 	if h.logger != nil {
 		metrics := map[string]interface{}{
@@ -455,11 +1643,66 @@ func (h *Holodeck) Stop() error {
 		return fmt.Errorf("not running")
 	}
 
+	// Carry the account into the next session before pending orders are
+	// cancelled
+	if path := h.config.Config.Session.SaveStateFile; path != "" {
+		if err := h.saveState(path); err != nil {
+			h.logError(err)
+		}
+	}
+
+	// Release a tick loop held in step mode or paused; orders queued
+	// while paused are dropped
+	h.step.setEnabled(false)
+	if h.pause.setPaused(false) && h.speed != nil && h.speed.IsPaused() {
+		if err := h.speed.Resume(); err != nil {
+			h.logError(err)
+		}
+	}
+	h.pause.queued = nil
+
+	// Remainders cannot keep working after the session ends
+	h.cancelWorkingOrders()
+	h.retries.CancelAll()
+	h.timing.pause()
+
+	// End the equity curve and the snapshots on the last tick
+	if h.state.CurrentTick != nil {
+		h.state.EquityCurve.Close(h.state.CurrentTick.Timestamp, h.state.Balance.CurrentBalance, h.state.Balance.TotalUnrealizedPnL)
+		h.sampleBenchmark(h.state.CurrentTick.Timestamp)
+		if path := h.config.Config.Logging.EquityFile; path != "" {
+			if err := exportEquityCurve(path, h.state.EquityCurve.GetPoints(), h.getMoneyDecimals()); err != nil {
+				h.logError(err)
+			}
+		}
+		if path := h.config.Config.Logging.HeatmapFile; path != "" {
+			if err := exportHeatmap(path, h.state.Breakdown.GetHeatmap(), h.getMoneyDecimals()); err != nil {
+				h.logError(err)
+			}
+		}
+		if h.snapshots != nil && h.snapshots.lastTick != h.state.TickCount {
+			if err := h.snapshots.Write(h.newAccountSnapshot(h.state.CurrentTick.Timestamp)); err != nil {
+				h.logError(err)
+			}
+		}
+	}
+
+	if h.replay != nil {
+		if err := h.replay.Close(); err != nil {
+			h.logError(err)
+		}
+	}
+
 	h.running = false
 	h.stopped = true
 	h.config.IsRunning = false
-	h.state.SessionEnd = time.Now()
-	h.stopChan <- true
+	h.state.SessionEnd = h.wallNow()
+	// A stop signal nobody has taken yet still stands; a second Stop
+	// (after a restart) must not block on it
+	select {
+	case h.stopChan <- true:
+	default:
+	}
 
 	if h.logger != nil {
 		metrics := map[string]interface{}{
@@ -476,9 +1719,41 @@ func (h *Holodeck) Stop() error {
 		h.callbacks.OnSessionEnd(status)
 	}
 
+	// Plugins are not needed once the session is over
+	if h.plugins != nil {
+		h.plugins.KillAll()
+	}
+
+	if fl, ok := h.logger.(FlushingLogger); ok {
+		if err := fl.Flush(); err != nil {
+			return fmt.Errorf("failed to flush logger: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// Close stops the session if it is still running and closes the logger;
+// nothing is logged afterwards
+func (h *Holodeck) Close() error {
+	var stopErr error
+	if h.IsRunning() {
+		stopErr = h.Stop()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.logger != nil {
+		err := h.logger.Close()
+		h.logger = nil
+		if err != nil {
+			return fmt.Errorf("failed to close logger: %w", err)
+		}
+	}
+	return stopErr
+}
+
 // IsRunning returns whether the Holodeck session is currently running
 func (h *Holodeck) IsRunning() bool {
 	h.mu.RLock()
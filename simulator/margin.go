@@ -0,0 +1,329 @@
+package simulator
+
+import (
+	"fmt"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== MARGIN EVENTS ====================
+
+// Margin event types
+const (
+	MarginEventCall    = "MARGIN_CALL"
+	MarginEventStopOut = "STOP_OUT"
+)
+
+// MarginEvent reports a margin call or a stop-out
+type MarginEvent struct {
+	// Type is MARGIN_CALL or STOP_OUT
+	Type string
+
+	// Timestamp is the simulated time of the tick that triggered it
+	Timestamp time.Time
+
+	// MarginLevel is equity / used margin (percent) when triggered
+	MarginLevel float64
+
+	// Equity and UsedMargin when triggered
+	Equity     float64
+	UsedMargin float64
+
+	// Liquidations are the stop-out's close executions
+	Liquidations []*types.ExecutionReport
+}
+
+// String returns a human-readable representation
+func (me *MarginEvent) String() string {
+	return fmt.Sprintf(
+		"MarginEvent[%s Level:%.1f%%, Equity:%.2f, Used:%.2f, Liquidations:%d]",
+		me.Type,
+		me.MarginLevel,
+		me.Equity,
+		me.UsedMargin,
+		len(me.Liquidations),
+	)
+}
+
+// ==================== MARGIN MONITOR ====================
+
+// MarginMonitor applies broker-style margin call and stop-out levels
+// A margin call is raised once when the margin level falls below the call
+// level and re-arms after it recovers
+type MarginMonitor struct {
+	CallLevel    float64
+	StopOutLevel float64
+
+	inCall   bool
+	calls    int64
+	stopOuts int64
+	events   []MarginEvent
+}
+
+// NewMarginMonitor creates a margin monitor (a level of 0 disables it)
+func NewMarginMonitor(callLevel, stopOutLevel float64) *MarginMonitor {
+	return &MarginMonitor{
+		CallLevel:    callLevel,
+		StopOutLevel: stopOutLevel,
+		events:       make([]MarginEvent, 0),
+	}
+}
+
+// IsEnabled returns true if either level is set
+func (mm *MarginMonitor) IsEnabled() bool {
+	return mm.CallLevel > 0 || mm.StopOutLevel > 0
+}
+
+// Check classifies a margin level: STOP_OUT, MARGIN_CALL (only on the
+// first breach) or empty
+func (mm *MarginMonitor) Check(level float64, usedMargin float64) string {
+	if usedMargin <= 0 {
+		mm.inCall = false
+		return ""
+	}
+
+	if mm.StopOutLevel > 0 && level < mm.StopOutLevel {
+		mm.inCall = true
+		return MarginEventStopOut
+	}
+
+	if mm.CallLevel > 0 && level < mm.CallLevel {
+		if mm.inCall {
+			return ""
+		}
+		mm.inCall = true
+		return MarginEventCall
+	}
+
+	mm.inCall = false
+	return ""
+}
+
+// record stores an event
+func (mm *MarginMonitor) record(event *MarginEvent) {
+	if event.Type == MarginEventStopOut {
+		mm.stopOuts++
+	} else {
+		mm.calls++
+	}
+	mm.events = append(mm.events, *event)
+}
+
+// GetEvents returns a copy of the events raised
+func (mm *MarginMonitor) GetEvents() []MarginEvent {
+	events := make([]MarginEvent, len(mm.events))
+	copy(events, mm.events)
+	return events
+}
+
+// GetStatistics returns margin monitor statistics
+func (mm *MarginMonitor) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"margin_call_level": mm.CallLevel,
+		"stop_out_level":    mm.StopOutLevel,
+		"margin_calls":      mm.calls,
+		"stop_outs":         mm.stopOuts,
+		"in_margin_call":    mm.inCall,
+	}
+}
+
+// Reset clears the events and the call state
+func (mm *MarginMonitor) Reset() {
+	mm.inCall = false
+	mm.calls = 0
+	mm.stopOuts = 0
+	mm.events = make([]MarginEvent, 0)
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// processMargin applies the margin call and stop-out levels to the
+// marked-to-market equity; a stop-out closes the worst position at market
+// (with the executor's slippage) until the level recovers or the book is flat
+// Caller must hold the write lock
+func (h *Holodeck) processMargin(tick *types.Tick) {
+	if !h.margin.IsEnabled() || h.state.Balance == nil || h.executor == nil {
+		return
+	}
+
+	balance := h.state.Balance
+
+	eventType := h.margin.Check(balance.GetMarginLevel(), balance.UsedMargin)
+	if eventType == "" {
+		return
+	}
+
+	event := &MarginEvent{
+		Type:        eventType,
+		Timestamp:   tick.Timestamp,
+		MarginLevel: balance.GetMarginLevel(),
+		Equity:      balance.CurrentBalance,
+		UsedMargin:  balance.UsedMargin,
+	}
+
+	if eventType == MarginEventStopOut {
+		event.Liquidations = h.liquidate(tick.Timestamp)
+	}
+
+	h.margin.record(event)
+	if h.callbacks.OnMarginEvent != nil {
+		callbackStart := h.timing.start()
+		h.callbacks.OnMarginEvent(event)
+		h.timing.addCallback(callbackStart)
+	}
+}
+
+// liquidate closes positions, worst first, until the margin level is back
+// above the stop-out level
+// Caller must hold the write lock
+func (h *Holodeck) liquidate(timestamp time.Time) []*types.ExecutionReport {
+	return h.closeWorstFirst(timestamp, "stop out", func() bool {
+		balance := h.state.Balance
+		return balance.UsedMargin <= 0 || balance.GetMarginLevel() >= h.margin.StopOutLevel
+	})
+}
+
+// closeWorstFirst closes positions at market with forced orders, worst
+// first, re-marking equity after each close until done returns true or the
+// book is flat; it stops early if a close is rejected
+// Caller must hold the write lock
+func (h *Holodeck) closeWorstFirst(timestamp time.Time, description string, done func() bool) []*types.ExecutionReport {
+	reports := make([]*types.ExecutionReport, 0)
+
+	for {
+		order := h.nextLiquidationOrder(timestamp)
+		if order == nil {
+			break
+		}
+		order.Description = description
+		order.Forced = true
+
+		exec, err := h.executeOrder(order)
+		if err != nil {
+			h.logError(fmt.Errorf("%s failed: %w", description, err))
+			break
+		}
+		reports = append(reports, exec)
+		if exec.IsRejected() {
+			break
+		}
+
+		if tick := h.state.LastTicks[h.state.symbolKey(order.Symbol)]; tick != nil {
+			h.markToMarket(tick)
+		}
+		if done() {
+			break
+		}
+	}
+
+	return reports
+}
+
+// nextLiquidationOrder returns the close order for the position (or
+// ticket) with the largest unrealized loss, or nil when flat
+// Caller must hold the write lock
+func (h *Holodeck) nextLiquidationOrder(timestamp time.Time) *types.Order {
+	if h.hedges != nil {
+		tick := h.state.LastTicks[h.state.PrimarySymbol]
+		if tick == nil {
+			return nil
+		}
+		pipValue := h.config.Instrument.GetPipValue()
+
+		var worst *types.HedgePosition
+		worstPnL := 0.0
+		for _, hp := range h.hedges.GetOpenPositions() {
+			hp := hp
+			price := tick.GetSellPrice()
+			if !hp.IsLong() {
+				price = tick.GetBuyPrice()
+			}
+			pnl := hp.CalculateUnrealizedPnL(price, pipValue)
+			if worst == nil || pnl < worstPnL {
+				worst, worstPnL = &hp, pnl
+			}
+		}
+		if worst == nil {
+			return nil
+		}
+		order, err := h.hedges.NewCloseOrder(worst.Ticket, 0, timestamp)
+		if err != nil {
+			return nil
+		}
+		return order
+	}
+
+	worstSymbol := ""
+	var worst *types.Position
+	for symbol, pos := range h.state.Positions {
+		if pos.IsFlat() || h.state.LastTicks[symbol] == nil {
+			continue
+		}
+		if worst == nil || pos.UnrealizedPnL < worst.UnrealizedPnL ||
+			(pos.UnrealizedPnL == worst.UnrealizedPnL && symbol < worstSymbol) {
+			worstSymbol, worst = symbol, pos
+		}
+	}
+	if worst == nil {
+		return nil
+	}
+
+	order := types.NewClosePositionOrder(worst, timestamp)
+	order.Symbol = worstSymbol
+	return order
+}
+
+// isFlat returns true when no position (or hedging ticket) is open
+// Caller must hold the lock
+func (h *Holodeck) isFlat() bool {
+	if h.hedges != nil {
+		return len(h.hedges.GetOpenPositions()) == 0
+	}
+	for _, pos := range h.state.Positions {
+		if !pos.IsFlat() {
+			return false
+		}
+	}
+	return true
+}
+
+// GetMarginEvents returns the margin calls and stop-outs raised so far
+func (h *Holodeck) GetMarginEvents() []MarginEvent {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.margin.GetEvents()
+}
+
+// ==================== NEGATIVE BALANCE PROTECTION ====================
+
+// processBalanceProtection closes every position when equity reaches zero
+// and, once the book is flat, writes off any remaining deficit, recording a
+// BLOWN execution
+// Closes that do not complete (no liquidity, partial fills) are retried on
+// the following ticks; further orders are refused once the protection has
+// triggered
+// Caller must hold the write lock
+func (h *Holodeck) processBalanceProtection(tick *types.Tick) {
+	if !h.config.Config.Account.NegativeBalanceProtection || h.protected || h.state.Balance == nil || h.executor == nil {
+		return
+	}
+
+	if h.state.Balance.CurrentBalance > 0 {
+		return
+	}
+
+	h.closeWorstFirst(tick.Timestamp, "negative balance protection", func() bool { return false })
+	if !h.isFlat() {
+		return
+	}
+
+	h.protected = true
+	writtenOff := h.state.Balance.ClampAtZero(tick.Timestamp)
+	h.reportExecution(types.NewBlownExecution(
+		fmt.Sprintf("BLOWN-%d", h.state.TickCount),
+		tick.Timestamp,
+		writtenOff,
+	))
+}
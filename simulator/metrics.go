@@ -0,0 +1,279 @@
+package simulator
+
+import (
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== TYPED METRICS ====================
+
+// Metrics is a typed snapshot of the session's performance metrics
+// The optional subsystems report their open-ended statistics and are nil
+// when the subsystem is not configured
+type Metrics struct {
+	TicksProcessed   int64            `json:"ticks_processed"`
+	TradesExecuted   int              `json:"trades_executed"`
+	SessionDuration  time.Duration    `json:"session_duration"`
+	AgentTime        time.Duration    `json:"agent_time"`
+	EngineTime       time.Duration    `json:"engine_time"`
+	AgentTimePercent float64          `json:"agent_time_percent"`
+	RejectionsByCode map[string]int64 `json:"rejections_by_code"`
+	OrdersGated      int64            `json:"orders_gated"`
+
+	// TotalTicksAvailable is the reader's tick count, 0 without a reader
+	TotalTicksAvailable int64 `json:"total_ticks_available,omitempty"`
+
+	*AccountMetrics
+	*PositionMetrics
+
+	// PositionSizeBySymbol and Symbols are only set in multi-symbol sessions
+	PositionSizeBySymbol map[string]float64     `json:"position_size_by_symbol,omitempty"`
+	Symbols              map[string]interface{} `json:"symbols,omitempty"`
+
+	EquityCurve        map[string]interface{} `json:"equity_curve"`
+	Daily              map[string]interface{} `json:"daily"`
+	Breakdown          map[string]interface{} `json:"breakdown"`
+	RMultiples         map[string]interface{} `json:"r_multiples"`
+	ReturnDistribution map[string]interface{} `json:"return_distribution"`
+	Streaks            map[string]interface{} `json:"streaks"`
+	Costs              map[string]interface{} `json:"costs"`
+	History            map[string]interface{} `json:"history"`
+
+	Hedging          map[string]interface{}     `json:"hedging,omitempty"`
+	Margin           map[string]interface{}     `json:"margin,omitempty"`
+	DailyLossLimit   map[string]interface{}     `json:"daily_loss_limit,omitempty"`
+	DrawdownThrottle map[string]interface{}     `json:"drawdown_throttle,omitempty"`
+	Interest         map[string]interface{}     `json:"interest,omitempty"`
+	CorporateActions map[string]interface{}     `json:"corporate_actions,omitempty"`
+	Snapshots        map[string]interface{}     `json:"snapshots,omitempty"`
+	Rolling          map[string]interface{}     `json:"rolling,omitempty"`
+	LogSampling      map[string]interface{}     `json:"log_sampling,omitempty"`
+	Replay           map[string]interface{}     `json:"replay,omitempty"`
+	Progress         map[string]interface{}     `json:"progress,omitempty"`
+	Speed            map[string]interface{}     `json:"speed,omitempty"`
+	Batches          map[string]interface{}     `json:"batches,omitempty"`
+	Alerts           map[string]interface{}     `json:"alerts,omitempty"`
+	Benchmark        *types.BenchmarkComparison `json:"benchmark,omitempty"`
+}
+
+// AccountMetrics is the account part of the metrics, nil without a balance
+type AccountMetrics struct {
+	CurrentBalance  float64 `json:"current_balance"`
+	InitialBalance  float64 `json:"initial_balance"`
+	AvailableMargin float64 `json:"available_margin"`
+	BuyingPower     float64 `json:"buying_power"`
+	CommissionPaid  float64 `json:"commission_paid"`
+	FinancingPnL    float64 `json:"financing_pnl"`
+	ReturnPercent   float64 `json:"return_percent"`
+	DrawdownPercent float64 `json:"drawdown_percent"`
+	WinRate         float64 `json:"win_rate"`
+}
+
+// PositionMetrics is the primary symbol's position, nil when flat
+type PositionMetrics struct {
+	PositionSize  float64 `json:"position_size"`
+	EntryPrice    float64 `json:"entry_price"`
+	UnrealizedPnL float64 `json:"unrealized_pnl"`
+}
+
+// GetPerformanceMetrics returns current performance metrics
+// Includes: ticks processed, trades executed, balance, position info
+func (h *Holodeck) GetPerformanceMetrics() *Metrics {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	metrics := &Metrics{}
+	if h.state == nil {
+		return metrics
+	}
+
+	// Basic metrics
+	metrics.TicksProcessed = h.state.TickCount
+	metrics.TradesExecuted = h.state.ExecutionCount
+	metrics.SessionDuration = types.Since(h.wallClock, h.startTime)
+	metrics.AgentTime = h.timing.GetAgentTime()
+	metrics.EngineTime = h.timing.GetEngineTime()
+	metrics.AgentTimePercent = h.timing.GetAgentPercent()
+	metrics.RejectionsByCode = h.copyRejections()
+	metrics.OrdersGated = h.getGatedCount()
+
+	if b := h.state.Balance; b != nil {
+		metrics.AccountMetrics = &AccountMetrics{
+			CurrentBalance:  b.CurrentBalance,
+			InitialBalance:  b.InitialBalance,
+			AvailableMargin: b.AvailableMargin,
+			BuyingPower:     b.BuyingPower,
+			CommissionPaid:  b.CommissionPaid,
+			FinancingPnL:    b.FinancingPnL,
+			ReturnPercent:   b.GetReturnPercent(),
+			DrawdownPercent: b.GetDrawdownPercent(),
+			WinRate:         b.GetWinRate(),
+		}
+	}
+
+	if pos := h.state.Positions[h.state.PrimarySymbol]; pos != nil {
+		metrics.PositionMetrics = &PositionMetrics{
+			PositionSize:  pos.Size,
+			EntryPrice:    pos.EntryPrice,
+			UnrealizedPnL: pos.UnrealizedPnL,
+		}
+	}
+	if len(h.state.Positions) > 1 {
+		metrics.PositionSizeBySymbol = make(map[string]float64, len(h.state.Positions))
+		for symbol, pos := range h.state.Positions {
+			metrics.PositionSizeBySymbol[symbol] = pos.Size
+		}
+	}
+
+	if h.state.Symbols.Len() > 1 {
+		metrics.Symbols = h.state.Symbols.GetStatistics()
+	}
+
+	if h.hedges != nil {
+		metrics.Hedging = h.hedges.GetStatistics()
+	}
+
+	if h.margin.IsEnabled() {
+		metrics.Margin = h.margin.GetStatistics()
+	}
+
+	if h.lossLimit.IsEnabled() {
+		metrics.DailyLossLimit = h.lossLimit.GetStatistics()
+	}
+
+	if h.throttle != nil {
+		stats := h.throttle.GetStatistics()
+		if h.state.Balance != nil {
+			stats["size_multiplier"] = h.throttle.GetMultiplier(h.state.Balance.GetPeakDrawdownPercent())
+		}
+		metrics.DrawdownThrottle = stats
+	}
+
+	if h.interest != nil {
+		metrics.Interest = h.interest.GetStatistics()
+	}
+
+	if h.corporate != nil {
+		metrics.CorporateActions = h.corporate.GetStatistics()
+	}
+
+	metrics.EquityCurve = h.getEquityStatistics()
+	metrics.Daily = h.state.Daily.GetStatistics()
+	metrics.Breakdown = h.state.Breakdown.GetStatistics()
+	metrics.RMultiples = h.getRMultipleStats().GetStatistics()
+	returns, streaks := h.getTradeDistributions()
+	metrics.ReturnDistribution = returns.GetStatistics()
+	metrics.Streaks = streaks.GetStatistics()
+	metrics.Costs = h.getCostAttribution().Total.GetStatistics()
+
+	if h.snapshots != nil {
+		metrics.Snapshots = h.snapshots.GetStatistics()
+	}
+	metrics.History = h.history.GetStatistics()
+
+	if h.rolling != nil {
+		metrics.Rolling = h.rolling.GetStatistics()
+	}
+	if h.sampler != nil {
+		metrics.LogSampling = h.sampler.GetStatistics()
+	}
+	if h.replay != nil {
+		metrics.Replay = h.replay.GetStatistics()
+	}
+	if h.progress != nil {
+		metrics.Progress = h.progress.GetStatistics()
+	}
+	if h.speed != nil {
+		metrics.Speed = h.speed.GetStatistics()
+	}
+	if h.batch != nil {
+		metrics.Batches = h.batch.GetStatistics()
+	}
+
+	if h.alerts != nil {
+		metrics.Alerts = h.alerts.GetStatistics()
+	}
+
+	metrics.Benchmark = h.getBenchmarkComparison()
+
+	metrics.TotalTicksAvailable = h.readerTicks
+
+	return metrics
+}
+
+// ToMap returns the metrics as a map keyed by their JSON names, leaving
+// out the sections that are not set
+func (m *Metrics) ToMap() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"ticks_processed":     m.TicksProcessed,
+		"trades_executed":     m.TradesExecuted,
+		"session_duration":    m.SessionDuration,
+		"agent_time":          m.AgentTime,
+		"engine_time":         m.EngineTime,
+		"agent_time_percent":  m.AgentTimePercent,
+		"rejections_by_code":  m.RejectionsByCode,
+		"orders_gated":        m.OrdersGated,
+		"equity_curve":        m.EquityCurve,
+		"daily":               m.Daily,
+		"breakdown":           m.Breakdown,
+		"r_multiples":         m.RMultiples,
+		"return_distribution": m.ReturnDistribution,
+		"streaks":             m.Streaks,
+		"costs":               m.Costs,
+		"history":             m.History,
+	}
+
+	if a := m.AccountMetrics; a != nil {
+		metrics["current_balance"] = a.CurrentBalance
+		metrics["initial_balance"] = a.InitialBalance
+		metrics["available_margin"] = a.AvailableMargin
+		metrics["buying_power"] = a.BuyingPower
+		metrics["commission_paid"] = a.CommissionPaid
+		metrics["financing_pnl"] = a.FinancingPnL
+		metrics["return_percent"] = a.ReturnPercent
+		metrics["drawdown_percent"] = a.DrawdownPercent
+		metrics["win_rate"] = a.WinRate
+	}
+
+	if p := m.PositionMetrics; p != nil {
+		metrics["position_size"] = p.PositionSize
+		metrics["entry_price"] = p.EntryPrice
+		metrics["unrealized_pnl"] = p.UnrealizedPnL
+	}
+	if m.PositionSizeBySymbol != nil {
+		metrics["position_size_by_symbol"] = m.PositionSizeBySymbol
+	}
+
+	optional := map[string]map[string]interface{}{
+		"hedging":           m.Hedging,
+		"margin":            m.Margin,
+		"daily_loss_limit":  m.DailyLossLimit,
+		"drawdown_throttle": m.DrawdownThrottle,
+		"interest":          m.Interest,
+		"corporate_actions": m.CorporateActions,
+		"snapshots":         m.Snapshots,
+		"rolling":           m.Rolling,
+		"log_sampling":      m.LogSampling,
+		"replay":            m.Replay,
+		"progress":          m.Progress,
+		"speed":             m.Speed,
+		"batches":           m.Batches,
+		"symbols":           m.Symbols,
+		"alerts":            m.Alerts,
+	}
+	for name, stats := range optional {
+		if stats != nil {
+			metrics[name] = stats
+		}
+	}
+
+	if m.Benchmark != nil {
+		metrics["benchmark"] = m.Benchmark
+	}
+	if m.TotalTicksAvailable > 0 {
+		metrics["total_ticks_available"] = m.TotalTicksAvailable
+	}
+
+	return metrics
+}
@@ -0,0 +1,322 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"holodeck/speed"
+	"holodeck/types"
+)
+
+// ==================== PAUSE / RESUME ====================
+
+// Order handling while the session is paused (session.pause_orders)
+const (
+	PauseOrdersReject = "reject" // Orders are rejected with SESSION_PAUSED
+	PauseOrdersQueue  = "queue"  // Orders are held and executed on Resume
+)
+
+// pauseGate freezes the tick loop while the session is paused: GetNextTick
+// waits until Resume (or Stop) closes the resume channel
+type pauseGate struct {
+	mu     sync.Mutex
+	paused bool
+	resume chan struct{} // closed when the session resumes
+
+	since time.Time
+	total time.Duration
+
+	// Orders held until Resume in queue mode (guarded by Holodeck.mu)
+	queued []*types.Order
+}
+
+// newPauseGate creates a gate for a running session
+func newPauseGate() *pauseGate {
+	return &pauseGate{}
+}
+
+// isPaused returns whether the session is paused
+func (pg *pauseGate) isPaused() bool {
+	pg.mu.Lock()
+	defer pg.mu.Unlock()
+	return pg.paused
+}
+
+// setPaused pauses or resumes, returning false if already in that state;
+// resuming releases a loop waiting in GetNextTick
+func (pg *pauseGate) setPaused(paused bool) bool {
+	pg.mu.Lock()
+	defer pg.mu.Unlock()
+
+	if paused == pg.paused {
+		return false
+	}
+	pg.paused = paused
+	if paused {
+		pg.resume = make(chan struct{})
+		pg.since = time.Now()
+	} else {
+		close(pg.resume)
+		pg.total += time.Since(pg.since)
+	}
+	return true
+}
+
+// wait blocks while the session is paused, or until ctx is done
+func (pg *pauseGate) wait(ctx context.Context) error {
+	pg.mu.Lock()
+	if !pg.paused {
+		pg.mu.Unlock()
+		return nil
+	}
+	resume := pg.resume
+	pg.mu.Unlock()
+
+	select {
+	case <-resume:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pausedTime returns the total time spent paused, including the current
+// pause
+func (pg *pauseGate) pausedTime() time.Duration {
+	pg.mu.Lock()
+	defer pg.mu.Unlock()
+
+	total := pg.total
+	if pg.paused {
+		total += time.Since(pg.since)
+	}
+	return total
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// Pause freezes the session: GetNextTick blocks until Resume, and orders
+// are rejected with SESSION_PAUSED or queued for Resume as configured by
+// session.pause_orders. An attached speed controller is paused too, so
+// the pause does not count against its pacing
+func (h *Holodeck) Pause() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.running {
+		return fmt.Errorf("holodeck not running")
+	}
+	if !h.pause.setPaused(true) {
+		return fmt.Errorf("simulation already paused")
+	}
+	if h.speed != nil && !h.speed.IsPaused() {
+		if err := h.speed.Pause(); err != nil {
+			h.logError(err)
+		}
+	}
+
+	h.logSessionEvent("session_pause")
+	return nil
+}
+
+// Resume unfreezes the session, executing orders queued while paused (in
+// submission order, against the current tick) before the tick loop
+// continues; their reports go to OnExecution
+func (h *Holodeck) Resume() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.pause.setPaused(false) {
+		return fmt.Errorf("simulation not paused")
+	}
+	if h.speed != nil && h.speed.IsPaused() {
+		if err := h.speed.Resume(); err != nil {
+			h.logError(err)
+		}
+	}
+
+	h.logSessionEvent("session_resume")
+
+	queued := h.pause.queued
+	h.pause.queued = nil
+	for _, order := range queued {
+		if err := h.checkCanExecute(); err != nil {
+			h.logError(err)
+			break
+		}
+		if _, err := h.executeOrder(order); err != nil {
+			h.logError(err)
+		}
+	}
+	return nil
+}
+
+// IsPaused returns whether the session is paused
+func (h *Holodeck) IsPaused() bool {
+	return h.pause.isPaused()
+}
+
+// GetQueuedOrders returns the orders held for Resume
+func (h *Holodeck) GetQueuedOrders() []*types.Order {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]*types.Order(nil), h.pause.queued...)
+}
+
+// checkPaused returns a SESSION_PAUSED rejection for an order submitted
+// while paused in reject mode, or a PENDING report after queueing it in
+// queue mode
+// Caller must hold the write lock
+func (h *Holodeck) checkPaused(order *types.Order, tick *types.Tick) *types.ExecutionReport {
+	if !h.pause.isPaused() {
+		return nil
+	}
+
+	timestamp := tick.Timestamp
+	if h.config.Config.Session.PauseOrders == PauseOrdersQueue {
+		h.pause.queued = append(h.pause.queued, order)
+		return &types.ExecutionReport{
+			OrderID:       order.OrderID,
+			Timestamp:     timestamp,
+			Action:        order.Action,
+			Symbol:        h.state.symbolKey(order.Symbol),
+			RequestedSize: order.Size,
+			Status:        types.OrderStatusPending,
+		}
+	}
+
+	herr := types.NewSessionPausedError()
+	exec := types.NewRejectedExecution(
+		order.OrderID,
+		timestamp,
+		order.Action,
+		order.Size,
+		herr.Code,
+		herr.Message,
+	)
+	exec.Symbol = h.state.symbolKey(order.Symbol)
+	h.rejections[exec.ErrorCode]++
+	h.reportExecution(exec)
+	return exec
+}
+
+// logSessionEvent logs a session lifecycle event
+// Caller must hold the write lock
+func (h *Holodeck) logSessionEvent(event string) {
+	if h.logger == nil {
+		return
+	}
+	h.logger.LogMetrics(map[string]interface{}{
+		"event":      event,
+		"session_id": h.config.SessionID,
+		"timestamp":  h.wallNow(),
+	})
+}
+
+// ==================== SPEED CONTROL ====================
+
+// WithSpeedController sets the speed controller that paces GetNextTick
+// (nil runs unpaced); SetSpeed and SetMaxSpeed are passed on to it
+func (h *Holodeck) WithSpeedController(controller *speed.SpeedController) *Holodeck {
+	h.speed = controller
+	h.applyWallClock()
+	return h
+}
+
+// GetSpeedController returns the attached speed controller, if any
+func (h *Holodeck) GetSpeedController() *speed.SpeedController {
+	return h.speed
+}
+
+// SetAdaptiveSpeed turns the adaptive speed governor on (with default
+// settings unless one is configured) or off
+// The governor lowers the effective multiplier while ticks keep overrunning
+// their time budget (slow agent callbacks) and raises it back toward the
+// set speed when headroom returns; GetEffectiveSpeed reports where it is
+// Requires a speed controller
+func (h *Holodeck) SetAdaptiveSpeed(enabled bool) error {
+	if h.speed == nil {
+		return fmt.Errorf("speed controller not set")
+	}
+
+	if !enabled {
+		h.speed.SetGovernor(nil)
+		return nil
+	}
+	if h.speed.GetGovernor() != nil {
+		return nil
+	}
+	governor, err := speed.NewSpeedGovernor(speed.DefaultGovernorConfig())
+	if err != nil {
+		return err
+	}
+	h.speed.SetGovernor(governor)
+	return nil
+}
+
+// IsAdaptiveSpeed returns whether the adaptive speed governor is on
+func (h *Holodeck) IsAdaptiveSpeed() bool {
+	return h.speed != nil && h.speed.GetGovernor() != nil
+}
+
+// GetEffectiveSpeed returns the multiplier in force, which the governor or
+// a speed schedule may have moved away from the set speed
+func (h *Holodeck) GetEffectiveSpeed() float64 {
+	if h.speed != nil {
+		return h.speed.GetSpeed()
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.config.ExecutionConfig.SpeedMultiplier
+}
+
+// SetTicksPerSecond paces GetNextTick at a target tick rate instead of
+// the speed multiplier (0 returns to the multiplier, as SetSpeed does)
+// Requires a speed controller
+func (h *Holodeck) SetTicksPerSecond(rate float64) error {
+	if h.speed == nil {
+		return fmt.Errorf("speed controller not set")
+	}
+	return h.speed.SetTicksPerSecond(rate)
+}
+
+// GetTicksPerSecond returns the target tick rate (0 = multiplier mode or
+// no speed controller)
+func (h *Holodeck) GetTicksPerSecond() float64 {
+	if h.speed == nil {
+		return 0
+	}
+	return h.speed.GetTicksPerSecond()
+}
+
+// paceTick waits until the tick is due at the controller's speed
+// Processing time is the wall time since the previous tick was handed
+// out, so agent time counts against the wait; the wait itself is not
+// counted as agent time
+func (h *Holodeck) paceTick(ctx context.Context, tick *types.Tick) {
+	if h.speed == nil {
+		return
+	}
+
+	h.mu.Lock()
+	var processing time.Duration
+	if !h.pacedAt.IsZero() {
+		processing = types.Since(h.wallClock, h.pacedAt)
+	}
+	h.mu.Unlock()
+
+	if err := h.speed.WaitForTickContext(ctx, tick.Timestamp, processing); err != nil && ctx.Err() == nil {
+		h.mu.Lock()
+		h.logError(err)
+		h.mu.Unlock()
+	}
+
+	h.mu.Lock()
+	h.pacedAt = h.wallNow()
+	h.trackBatch(processing)
+	h.timing.leave()
+	h.mu.Unlock()
+}
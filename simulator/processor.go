@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"holodeck/speed"
 )
 
 // ==================== SIMULATOR PROCESSOR ====================
@@ -20,6 +22,9 @@ type Processor struct {
 	config     *Config
 	startTime  time.Time
 	results    *SimulationResults
+
+	// timer times the tick loop in batches for progress stats and ETA
+	timer *speed.SessionTimer
 }
 
 // ==================== CREATION ====================
@@ -166,27 +171,43 @@ func (p *Processor) executeSimulation() error {
 	// TODO: This is where the real Holodeck API will be called
 	// For now, simulate with placeholder
 
-	ticksToProcess := 50000
-	progress := 0.0
-
-	for i := 0; i < ticksToProcess; i++ {
-		progress = float64(i) / float64(ticksToProcess) * 100
-
-		// Calculate sleep time based on speed multiplier
-		// At 1000x speed: process 1000 ticks per second
-		// At 100x speed: process 100 ticks per second
-		// At 1x speed: process 1 tick per second
-		sleepDuration := time.Duration(1000000/p.speed) * time.Microsecond
+	ticksToProcess := int64(50000)
+	batchSize := ticksToProcess / 10
 
-		time.Sleep(sleepDuration)
-
-		// Print progress every 10%
-		if i%5000 == 0 && i > 0 {
-			fmt.Printf("[PROGRESS] %.1f%% complete (%d / %d ticks)\n", progress, i, ticksToProcess)
+	// Pace by the speed multiplier
+	// At 1000x speed: process 1000 ticks per second
+	// At 100x speed: process 100 ticks per second
+	// At 1x speed: process 1 tick per second
+	controller := speed.NewSpeedController()
+	if err := controller.SetSpeed(p.speed); err != nil {
+		return err
+	}
+	controller.SetAutoBatch(true)
+
+	p.timer = speed.NewSessionTimer(controller, p.config.Instrument.Symbol)
+	p.timer.StartBatch(batchSize)
+
+	for i := int64(1); i <= ticksToProcess; i++ {
+		tickStart := time.Now()
+		// Placeholder: the tick is processed here
+		processingTime := time.Since(tickStart)
+
+		p.timer.RecordTick(processingTime)
+		controller.WaitTick(processingTime)
+
+		// Print progress with the batch's stats every 10%
+		if i%batchSize == 0 {
+			stats := p.timer.EndBatch()
+			line := fmt.Sprintf("[PROGRESS] %.1f%% complete (%d / %d ticks) | %.0f ticks/s",
+				float64(i)/float64(ticksToProcess)*100, i, ticksToProcess, stats.TicksPerSecond)
+			if i < ticksToProcess {
+				line += " | ETA " + speed.FormatDuration(p.timer.GetEstimatedTimeRemaining(ticksToProcess))
+				p.timer.StartBatch(batchSize)
+			}
+			fmt.Println(line)
 		}
 	}
 
-	fmt.Printf("[PROGRESS] 100%% complete (%d / %d ticks)\n", ticksToProcess, ticksToProcess)
 	return nil
 }
 
@@ -236,14 +257,47 @@ func (p *Processor) printResults() {
 	fmt.Printf("  Winning Trades:    %d\n", p.results.WinCount)
 	fmt.Printf("  Losing Trades:     %d\n\n", p.results.LossCount)
 
+	if p.timer != nil {
+		fmt.Println(p.timer.EndSession().String())
+	}
+
 	fmt.Println(strings.Repeat("=", 70))
 }
 
-// saveResults saves results to file
+// saveResults saves the text results and the SimulationResult JSON
 func (p *Processor) saveResults() error {
 	logPath := filepath.Join(p.outputDir, fmt.Sprintf("simulation_%d.txt", p.startTime.Unix()))
 	content := p.formatResultsForFile()
-	return os.WriteFile(logPath, []byte(content), 0644)
+	if err := os.WriteFile(logPath, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	return p.newSimulationResult().WriteFile(filepath.Join(p.outputDir, SimulationResultFile))
+}
+
+// newSimulationResult converts the results to the SimulationResult schema
+func (p *Processor) newSimulationResult() *SimulationResult {
+	result := NewSimulationResult(fmt.Sprintf("processor_%d", p.startTime.Unix()), p.config)
+	result.Summary = ResultSummary{
+		Symbol:         p.results.Instrument,
+		InstrumentType: p.results.InstrumentType,
+		Currency:       p.config.Account.Currency,
+		TicksProcessed: p.results.TicksProcessed,
+		Executions:     p.results.TradeCount,
+		WinningTrades:  int(p.results.WinCount),
+		LosingTrades:   int(p.results.LossCount),
+		InitialBalance: p.results.InitialBalance,
+		FinalBalance:   p.results.FinalBalance,
+		NetPnL:         p.results.NetProfit,
+		Commission:     p.results.Commission,
+		AccountStatus:  p.results.AccountStatus,
+	}
+	if p.results.InitialBalance > 0 {
+		result.Summary.ReturnPercent = p.results.NetProfit / p.results.InitialBalance * 100
+	}
+	result.Metrics["speed"] = p.results.Speed
+	result.Metrics["elapsed_time"] = p.results.ElapsedTime.String()
+	return result
 }
 
 // formatResultsForFile formats results for file output
@@ -0,0 +1,283 @@
+package simulator
+
+import (
+	"fmt"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== PROGRESS ====================
+
+// TickCounter is implemented by readers that can count the ticks in their
+// data set up front (the CSV and binary replay readers)
+type TickCounter interface {
+	CountTicks() (int64, error)
+}
+
+// Progress is one progress report
+type Progress struct {
+	TicksProcessed int64
+	TotalTicks     int64 // 0 when the size of the data set is unknown
+	Percent        float64
+
+	// TicksPerSecond is the wall-clock rate since the previous report,
+	// AvgTicksPerSecond the rate since the session started
+	TicksPerSecond    float64
+	AvgTicksPerSecond float64
+
+	Elapsed time.Duration
+	ETA     time.Duration // -1 when unknown
+
+	SimulatedTime time.Time
+	Balance       float64
+	Equity        float64
+}
+
+// String returns a human-readable representation
+func (p *Progress) String() string {
+	eta := "unknown"
+	if p.ETA >= 0 {
+		eta = p.ETA.Round(time.Second).String()
+	}
+
+	done := fmt.Sprintf("%d ticks", p.TicksProcessed)
+	if p.TotalTicks > 0 {
+		done = fmt.Sprintf("%d/%d ticks (%.1f%%)", p.TicksProcessed, p.TotalTicks, p.Percent)
+	}
+
+	return fmt.Sprintf("%s | %.0f ticks/s | Elapsed: %s | ETA: %s",
+		done, p.TicksPerSecond, p.Elapsed.Round(time.Second), eta)
+}
+
+// ToMap converts the report to a "progress" metrics event
+func (p *Progress) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"event":                "progress",
+		"timestamp":            p.SimulatedTime,
+		"ticks_processed":      p.TicksProcessed,
+		"total_ticks":          p.TotalTicks,
+		"progress_percent":     p.Percent,
+		"ticks_per_second":     p.TicksPerSecond,
+		"avg_ticks_per_second": p.AvgTicksPerSecond,
+		"elapsed_seconds":      p.Elapsed.Seconds(),
+		"eta_seconds":          p.ETA.Seconds(),
+		"current_balance":      p.Balance,
+		"equity":               p.Equity,
+	}
+}
+
+// ProgressTracker reports progress every N ticks and/or every N seconds of
+// wall-clock time
+type ProgressTracker struct {
+	EveryTicks int64
+	Every      time.Duration
+
+	totalTicks int64
+	ticks      int64
+	startTime  time.Time
+
+	lastTime  time.Time
+	lastTicks int64
+	last      *Progress
+	reports   int64
+}
+
+// NewProgressTracker creates a progress tracker from the configuration
+// Returns nil when progress reporting is not configured
+func NewProgressTracker(config ProgressConfig) *ProgressTracker {
+	if !config.IsEnabled() {
+		return nil
+	}
+
+	return &ProgressTracker{
+		EveryTicks: config.EveryTicks,
+		Every:      time.Duration(config.EverySeconds * float64(time.Second)),
+	}
+}
+
+// SetTotalTicks sets the size of the data set (0 = unknown)
+func (pt *ProgressTracker) SetTotalTicks(total int64) {
+	pt.totalTicks = total
+}
+
+// GetTotalTicks returns the size of the data set (0 = unknown)
+func (pt *ProgressTracker) GetTotalTicks() int64 {
+	return pt.totalTicks
+}
+
+// Start starts timing the run
+func (pt *ProgressTracker) Start(now time.Time) {
+	pt.startTime = now
+	pt.lastTime = now
+}
+
+// Observe counts a tick and returns true when a report is due
+func (pt *ProgressTracker) Observe(now time.Time) bool {
+	pt.ticks++
+	if pt.startTime.IsZero() {
+		pt.Start(now)
+	}
+
+	if pt.EveryTicks > 0 && pt.ticks-pt.lastTicks >= pt.EveryTicks {
+		return true
+	}
+	return pt.Every > 0 && now.Sub(pt.lastTime) >= pt.Every
+}
+
+// Report builds a progress report and starts the next interval
+func (pt *ProgressTracker) Report(now time.Time, tick *types.Tick, balance *types.Balance) *Progress {
+	progress := pt.build(now, tick, balance)
+	pt.last = progress
+	pt.lastTime = now
+	pt.lastTicks = pt.ticks
+	pt.reports++
+	return progress
+}
+
+// Current returns a progress report without starting a new interval
+func (pt *ProgressTracker) Current(now time.Time, tick *types.Tick, balance *types.Balance) *Progress {
+	return pt.build(now, tick, balance)
+}
+
+// build computes the rates, percent complete and ETA
+func (pt *ProgressTracker) build(now time.Time, tick *types.Tick, balance *types.Balance) *Progress {
+	progress := &Progress{
+		TicksProcessed: pt.ticks,
+		TotalTicks:     pt.totalTicks,
+		Elapsed:        now.Sub(pt.startTime),
+		ETA:            -1,
+	}
+	if pt.startTime.IsZero() {
+		progress.Elapsed = 0
+	}
+
+	if interval := now.Sub(pt.lastTime).Seconds(); interval > 0 {
+		progress.TicksPerSecond = float64(pt.ticks-pt.lastTicks) / interval
+	}
+	if elapsed := progress.Elapsed.Seconds(); elapsed > 0 {
+		progress.AvgTicksPerSecond = float64(pt.ticks) / elapsed
+	}
+
+	if pt.totalTicks > 0 {
+		progress.Percent = float64(pt.ticks) / float64(pt.totalTicks) * 100
+		if progress.Percent > 100 {
+			progress.Percent = 100
+		}
+		if progress.AvgTicksPerSecond > 0 {
+			remaining := pt.totalTicks - pt.ticks
+			if remaining < 0 {
+				remaining = 0
+			}
+			progress.ETA = time.Duration(float64(remaining) / progress.AvgTicksPerSecond * float64(time.Second))
+		}
+	}
+
+	if tick != nil {
+		progress.SimulatedTime = tick.Timestamp
+	}
+	if balance != nil {
+		progress.Balance = balance.CurrentBalance
+		progress.Equity = balance.CurrentBalance + balance.TotalUnrealizedPnL
+	}
+	return progress
+}
+
+// GetStatistics returns progress statistics
+func (pt *ProgressTracker) GetStatistics() map[string]interface{} {
+	stats := map[string]interface{}{
+		"every_ticks":     pt.EveryTicks,
+		"every_seconds":   pt.Every.Seconds(),
+		"ticks_processed": pt.ticks,
+		"total_ticks":     pt.totalTicks,
+		"reports":         pt.reports,
+	}
+	if pt.last != nil {
+		stats["last_percent"] = pt.last.Percent
+		stats["last_ticks_per_second"] = pt.last.TicksPerSecond
+	}
+	return stats
+}
+
+// Reset clears the counts, keeping the intervals and total
+func (pt *ProgressTracker) Reset() {
+	*pt = ProgressTracker{EveryTicks: pt.EveryTicks, Every: pt.Every, totalTicks: pt.totalTicks}
+}
+
+// String returns a human-readable representation
+func (pt *ProgressTracker) String() string {
+	return fmt.Sprintf(
+		"ProgressTracker[EveryTicks:%d, Every:%s, Ticks:%d/%d, Reports:%d]",
+		pt.EveryTicks,
+		pt.Every,
+		pt.ticks,
+		pt.totalTicks,
+		pt.reports,
+	)
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// WithProgress sets the progress tracker, replacing the configured one
+func (h *Holodeck) WithProgress(tracker *ProgressTracker) *Holodeck {
+	h.progress = tracker
+	return h
+}
+
+// GetProgress returns the current progress
+// Returns nil when progress reporting is not configured
+func (h *Holodeck) GetProgress() *Progress {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.progress == nil {
+		return nil
+	}
+	return h.progress.Current(time.Now(), h.state.CurrentTick, h.state.Balance)
+}
+
+// startProgress starts timing and, when the total is unknown, counts the
+// reader's data set
+// Caller must hold the write lock
+func (h *Holodeck) startProgress() {
+	if h.progress == nil {
+		return
+	}
+
+	if h.progress.GetTotalTicks() == 0 {
+		if counter, ok := h.reader.(TickCounter); ok {
+			total, err := counter.CountTicks()
+			if err != nil {
+				h.logError(err)
+			}
+			h.progress.SetTotalTicks(total)
+		}
+	}
+	h.progress.Start(time.Now())
+}
+
+// processProgress reports progress to the logger and the OnProgress
+// callback when a report is due
+// Caller must hold the write lock
+func (h *Holodeck) processProgress(tick *types.Tick) {
+	if h.progress == nil {
+		return
+	}
+
+	now := time.Now()
+	if !h.progress.Observe(now) {
+		return
+	}
+
+	progress := h.progress.Report(now, tick, h.state.Balance)
+	if h.logger != nil {
+		event := progress.ToMap()
+		event["session_id"] = h.config.SessionID
+		h.logger.LogMetrics(event)
+	}
+	if h.callbacks.OnProgress != nil {
+		callbackStart := h.timing.start()
+		h.callbacks.OnProgress(progress)
+		h.timing.addCallback(callbackStart)
+	}
+}
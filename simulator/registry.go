@@ -0,0 +1,96 @@
+package simulator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ==================== EXECUTOR REGISTRY ====================
+
+// ExecutorFactory builds an executor from the session configuration
+type ExecutorFactory func(config *Config) (OrderExecutor, error)
+
+// ExecutorRegistry maps executor names to factories
+// Custom fill logic registers itself (typically from an init function) and
+// is selected with execution.executor in the config
+type ExecutorRegistry struct {
+	mu        sync.RWMutex
+	factories map[string]ExecutorFactory
+}
+
+// NewExecutorRegistry creates an empty registry
+func NewExecutorRegistry() *ExecutorRegistry {
+	return &ExecutorRegistry{
+		factories: make(map[string]ExecutorFactory),
+	}
+}
+
+// Register adds a factory under a name
+func (er *ExecutorRegistry) Register(name string, factory ExecutorFactory) error {
+	if name == "" {
+		return fmt.Errorf("executor name cannot be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("executor factory for %s cannot be nil", name)
+	}
+
+	er.mu.Lock()
+	defer er.mu.Unlock()
+
+	if _, exists := er.factories[name]; exists {
+		return fmt.Errorf("executor %s is already registered", name)
+	}
+	er.factories[name] = factory
+	return nil
+}
+
+// Get retrieves a factory
+func (er *ExecutorRegistry) Get(name string) (ExecutorFactory, bool) {
+	er.mu.RLock()
+	defer er.mu.RUnlock()
+
+	factory, ok := er.factories[name]
+	return factory, ok
+}
+
+// Has checks if a name is registered
+func (er *ExecutorRegistry) Has(name string) bool {
+	_, ok := er.Get(name)
+	return ok
+}
+
+// List returns the registered names, sorted
+func (er *ExecutorRegistry) List() []string {
+	er.mu.RLock()
+	defer er.mu.RUnlock()
+
+	names := make([]string, 0, len(er.factories))
+	for name := range er.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New builds the executor registered under name
+func (er *ExecutorRegistry) New(name string, config *Config) (OrderExecutor, error) {
+	factory, ok := er.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown executor: %s (registered: %v)", name, er.List())
+	}
+	return factory(config)
+}
+
+// ==================== DEFAULT REGISTRY ====================
+
+// Executors is the process-wide executor registry consulted by config
+var Executors = NewExecutorRegistry()
+
+// RegisterExecutor registers a factory in the default registry
+// Panics on an empty or duplicate name, like database/sql drivers
+func RegisterExecutor(name string, factory ExecutorFactory) {
+	if err := Executors.Register(name, factory); err != nil {
+		panic(err)
+	}
+}
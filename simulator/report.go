@@ -0,0 +1,299 @@
+package simulator
+
+import (
+	"fmt"
+	"sort"
+
+	"holodeck/types"
+)
+
+// ==================== SYMBOL REPORT ====================
+
+// SymbolReport summarizes one instrument's session
+type SymbolReport struct {
+	Symbol         string
+	InstrumentType string
+	Currency       string
+
+	TicksProcessed int64
+	Trades         int64
+	GatedOrders    int64 // Rejected for wide spread or stale tick
+
+	InitialBalance float64
+	FinalBalance   float64
+	NetPnL         float64
+	RealizedPnL    float64
+	UnrealizedPnL  float64
+
+	// Costs
+	Commission    float64
+	SlippageUnits float64
+
+	// MaxDrawdown is the largest drawdown in account currency
+	MaxDrawdown        float64
+	MaxDrawdownPercent float64
+
+	// DrawdownContribution is this symbol's share of the portfolio's summed
+	// drawdown, in percent (set by NewFinalReport)
+	DrawdownContribution float64
+
+	// Days is the session's daily P&L rollup
+	Days []types.DailyRecord
+
+	// ClosedTrades are the closed lots in close order, each with its
+	// max favorable and adverse excursion, for stop/target analysis
+	ClosedTrades []types.ClosedLot
+
+	// AverageMFE and AverageMAE average the closed trades' excursions
+	AverageMFE float64
+	AverageMAE float64
+
+	// RMultiples summarizes the closed trades opened with an initial stop
+	// in units of their initial risk
+	RMultiples *types.RMultipleStats
+
+	// Sessions and Hours break closing trades down by trading session and
+	// by UTC hour of the closing fill
+	Sessions []types.PerformanceBucket
+	Hours    []types.PerformanceBucket
+
+	// Benchmark compares the session with buying and holding the
+	// instrument (nil for the portfolio)
+	Benchmark *types.BenchmarkComparison
+}
+
+// GetSymbolReport builds the final report for this session's instrument
+func (h *Holodeck) GetSymbolReport() *SymbolReport {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.getSymbolReport()
+}
+
+// getSymbolReport builds the account-level report
+// Caller must hold the lock
+func (h *Holodeck) getSymbolReport() *SymbolReport {
+	report := &SymbolReport{}
+	if h.config != nil && h.config.Instrument != nil {
+		report.Symbol = h.config.Instrument.GetSymbol()
+		report.InstrumentType = h.config.Instrument.GetType()
+	}
+
+	if h.state == nil {
+		return report
+	}
+
+	report.TicksProcessed = h.state.TickCount
+	report.Trades = int64(h.state.ExecutionCount)
+	report.GatedOrders = h.getGatedCount()
+	report.Days = h.state.Daily.GetDays()
+	report.Sessions = h.state.Breakdown.GetSessions()
+	report.Hours = h.state.Breakdown.GetHours()
+	report.SlippageUnits = h.state.TotalSlippageUnits
+	for _, pos := range h.state.Positions {
+		report.ClosedTrades = append(report.ClosedTrades, pos.GetClosedLots()...)
+	}
+	sort.SliceStable(report.ClosedTrades, func(i, j int) bool {
+		return report.ClosedTrades[i].CloseTime.Before(report.ClosedTrades[j].CloseTime)
+	})
+	report.setAverageExcursions()
+	report.RMultiples = types.NewRMultipleStats(report.ClosedTrades)
+	report.Benchmark = h.getBenchmarkComparison()
+
+	if b := h.state.Balance; b != nil {
+		report.Currency = b.Currency
+		report.InitialBalance = b.InitialBalance
+		report.FinalBalance = b.CurrentBalance
+		report.NetPnL = b.CurrentBalance - b.InitialBalance
+		report.RealizedPnL = b.TotalRealizedPnL
+		report.UnrealizedPnL = b.TotalUnrealizedPnL
+		report.Commission = b.CommissionPaid
+		report.MaxDrawdownPercent = b.MaxDrawdownExperienced
+		report.MaxDrawdown = b.InitialBalance * b.MaxDrawdownExperienced / 100
+	}
+
+	return report
+}
+
+// GetFinalReport builds the session's final report with one SymbolReport
+// per traded symbol, from the per-symbol metrics, and the account as the
+// portfolio
+// Per-symbol reports carry no balances, daily or time breakdowns, which
+// only exist for the account; a single-symbol session reports the account
+// as its only symbol
+func (h *Holodeck) GetFinalReport() *FinalReport {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	account := h.getSymbolReport()
+	if h.state == nil || len(h.state.Positions) <= 1 {
+		return NewFinalReport(account)
+	}
+
+	metrics := make(map[string]types.SymbolMetrics)
+	for _, m := range h.state.Symbols.GetAll() {
+		metrics[m.Symbol] = m
+	}
+
+	symbols := make([]*SymbolReport, 0, len(h.state.Positions))
+	for symbol, pos := range h.state.Positions {
+		symbols = append(symbols, h.newPositionReport(symbol, pos, metrics[symbol]))
+	}
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return symbols[i].Symbol < symbols[j].Symbol
+	})
+
+	account.Symbol = "PORTFOLIO"
+	account.Benchmark = nil
+	if account.MaxDrawdown > 0 {
+		account.DrawdownContribution = 100
+	}
+
+	return &FinalReport{
+		Symbols:   symbols,
+		Portfolio: account,
+	}
+}
+
+// newPositionReport builds one symbol's report from its metrics and
+// closed lots
+// Caller must hold the lock
+func (h *Holodeck) newPositionReport(symbol string, pos *types.Position, metrics types.SymbolMetrics) *SymbolReport {
+	report := &SymbolReport{Symbol: symbol}
+	if instrument := h.instrumentFor(symbol); instrument != nil {
+		report.InstrumentType = instrument.GetType()
+	}
+	if b := h.state.Balance; b != nil {
+		report.Currency = b.Currency
+	}
+
+	report.Trades = int64(metrics.Executions)
+	report.RealizedPnL = metrics.RealizedPnL
+	report.UnrealizedPnL = metrics.UnrealizedPnL
+	report.Commission = metrics.Commission
+	report.NetPnL = metrics.GetNetPnL()
+	report.MaxDrawdown = metrics.MaxDrawdown
+	report.DrawdownContribution = metrics.DrawdownContribution
+	if b := h.state.Balance; b != nil && b.InitialBalance > 0 {
+		report.MaxDrawdownPercent = metrics.MaxDrawdown / b.InitialBalance * 100
+	}
+
+	report.ClosedTrades = pos.GetClosedLots()
+	report.setAverageExcursions()
+	report.RMultiples = types.NewRMultipleStats(report.ClosedTrades)
+
+	return report
+}
+
+// setAverageExcursions averages the closed trades' MFE and MAE
+func (sr *SymbolReport) setAverageExcursions() {
+	sr.AverageMFE, sr.AverageMAE = 0, 0
+	if len(sr.ClosedTrades) == 0 {
+		return
+	}
+	for _, trade := range sr.ClosedTrades {
+		sr.AverageMFE += trade.MaxFavorableExcursion
+		sr.AverageMAE += trade.MaxAdverseExcursion
+	}
+	sr.AverageMFE /= float64(len(sr.ClosedTrades))
+	sr.AverageMAE /= float64(len(sr.ClosedTrades))
+}
+
+// GetReturnPercent returns net P&L as a percent of the initial balance
+func (sr *SymbolReport) GetReturnPercent() float64 {
+	if sr.InitialBalance == 0 {
+		return 0
+	}
+	return sr.NetPnL / sr.InitialBalance * 100
+}
+
+// String returns a human-readable representation
+func (sr *SymbolReport) String() string {
+	return fmt.Sprintf(
+		"SymbolReport[%s Trades:%d, P&L:%.2f, Commission:%.2f, MaxDD:%.2f%%]",
+		sr.Symbol,
+		sr.Trades,
+		sr.NetPnL,
+		sr.Commission,
+		sr.MaxDrawdownPercent,
+	)
+}
+
+// ==================== FINAL REPORT ====================
+
+// FinalReport consolidates per-symbol reports into a portfolio view
+type FinalReport struct {
+	Symbols   []*SymbolReport
+	Portfolio *SymbolReport
+}
+
+// NewFinalReport consolidates symbol reports, sorted by symbol
+// Portfolio drawdown is the summed per-symbol drawdown, a conservative
+// bound since the sessions' equity curves are not aligned in time
+func NewFinalReport(reports ...*SymbolReport) *FinalReport {
+	symbols := make([]*SymbolReport, 0, len(reports))
+	for _, r := range reports {
+		if r != nil {
+			symbols = append(symbols, r)
+		}
+	}
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return symbols[i].Symbol < symbols[j].Symbol
+	})
+
+	portfolio := &SymbolReport{Symbol: "PORTFOLIO"}
+	for _, r := range symbols {
+		portfolio.TicksProcessed += r.TicksProcessed
+		portfolio.Trades += r.Trades
+		portfolio.GatedOrders += r.GatedOrders
+		portfolio.InitialBalance += r.InitialBalance
+		portfolio.FinalBalance += r.FinalBalance
+		portfolio.NetPnL += r.NetPnL
+		portfolio.RealizedPnL += r.RealizedPnL
+		portfolio.UnrealizedPnL += r.UnrealizedPnL
+		portfolio.Commission += r.Commission
+		portfolio.SlippageUnits += r.SlippageUnits
+		portfolio.MaxDrawdown += r.MaxDrawdown
+		portfolio.ClosedTrades = append(portfolio.ClosedTrades, r.ClosedTrades...)
+		if portfolio.Currency == "" {
+			portfolio.Currency = r.Currency
+		} else if portfolio.Currency != r.Currency {
+			portfolio.Currency = "MIXED"
+		}
+	}
+
+	portfolio.setAverageExcursions()
+	portfolio.RMultiples = types.NewRMultipleStats(portfolio.ClosedTrades)
+
+	if portfolio.InitialBalance > 0 {
+		portfolio.MaxDrawdownPercent = portfolio.MaxDrawdown / portfolio.InitialBalance * 100
+	}
+	for _, r := range symbols {
+		if portfolio.MaxDrawdown > 0 {
+			r.DrawdownContribution = r.MaxDrawdown / portfolio.MaxDrawdown * 100
+		}
+	}
+	if portfolio.MaxDrawdown > 0 {
+		portfolio.DrawdownContribution = 100
+	}
+
+	return &FinalReport{
+		Symbols:   symbols,
+		Portfolio: portfolio,
+	}
+}
+
+// IsMultiSymbol checks if the report covers more than one instrument
+func (fr *FinalReport) IsMultiSymbol() bool {
+	return len(fr.Symbols) > 1
+}
+
+// String returns a human-readable representation
+func (fr *FinalReport) String() string {
+	return fmt.Sprintf(
+		"FinalReport[Symbols:%d, P&L:%.2f, Trades:%d]",
+		len(fr.Symbols),
+		fr.Portfolio.NetPnL,
+		fr.Portfolio.Trades,
+	)
+}
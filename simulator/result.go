@@ -0,0 +1,234 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== SIMULATION RESULT SCHEMA ====================
+
+// SimulationResultVersion is the result schema version; fields are only
+// added within a version, never renamed or removed
+const SimulationResultVersion = 1
+
+// SimulationResultFile is the result's file name in an output directory
+const SimulationResultFile = "result.json"
+
+// SimulationResult is the machine-readable outcome of a run, so
+// downstream tools can compare runs programmatically: the configuration,
+// the headline figures, the open-ended metrics, every closed trade and
+// the errors raised
+type SimulationResult struct {
+	SchemaVersion int       `json:"schema_version"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	SessionID     string    `json:"session_id"`
+
+	Config  *Config                `json:"config"`
+	Summary ResultSummary          `json:"summary"`
+	Metrics map[string]interface{} `json:"metrics"`
+	Trades  []ResultTrade          `json:"trades"`
+	Errors  []ResultError          `json:"errors"`
+
+	// Rejections counts rejected orders by error code
+	Rejections map[string]int64 `json:"rejections"`
+}
+
+// ResultSummary is the run's headline figures, in the account currency
+type ResultSummary struct {
+	Symbol         string    `json:"symbol"`
+	InstrumentType string    `json:"instrument_type"`
+	Currency       string    `json:"currency"`
+	Start          time.Time `json:"start"` // Simulated time of the first tick
+	End            time.Time `json:"end"`   // Simulated time of the last tick
+
+	TicksProcessed int64 `json:"ticks_processed"`
+	Executions     int64 `json:"executions"`
+	ClosedTrades   int   `json:"closed_trades"`
+	WinningTrades  int   `json:"winning_trades"`
+	LosingTrades   int   `json:"losing_trades"`
+
+	InitialBalance     float64 `json:"initial_balance"`
+	FinalBalance       float64 `json:"final_balance"`
+	NetPnL             float64 `json:"net_pnl"`
+	ReturnPercent      float64 `json:"return_percent"`
+	RealizedPnL        float64 `json:"realized_pnl"`
+	UnrealizedPnL      float64 `json:"unrealized_pnl"`
+	Commission         float64 `json:"commission"`
+	MaxDrawdownPercent float64 `json:"max_drawdown_percent"`
+	SharpeRatio        float64 `json:"sharpe_ratio"`
+	AccountStatus      string  `json:"account_status"`
+}
+
+// ResultTrade is one closed lot
+type ResultTrade struct {
+	LotID                 string    `json:"lot_id"`
+	Side                  string    `json:"side"`
+	Size                  float64   `json:"size"`
+	EntryPrice            float64   `json:"entry_price"`
+	ExitPrice             float64   `json:"exit_price"`
+	OpenTime              time.Time `json:"open_time"`
+	CloseTime             time.Time `json:"close_time"`
+	Commission            float64   `json:"commission"`
+	RealizedPnL           float64   `json:"realized_pnl"`
+	MaxFavorableExcursion float64   `json:"max_favorable_excursion"`
+	MaxAdverseExcursion   float64   `json:"max_adverse_excursion"`
+	InitialRisk           float64   `json:"initial_risk"`
+	RMultiple             float64   `json:"r_multiple"`
+}
+
+// ResultError is one error raised during the run
+type ResultError struct {
+	Code      string    `json:"code"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewResultTrade converts a closed lot
+func NewResultTrade(lot types.ClosedLot) ResultTrade {
+	return ResultTrade{
+		LotID:                 lot.LotID,
+		Side:                  lot.Side,
+		Size:                  lot.Size,
+		EntryPrice:            lot.EntryPrice,
+		ExitPrice:             lot.ExitPrice,
+		OpenTime:              lot.OpenTime,
+		CloseTime:             lot.CloseTime,
+		Commission:            lot.Commission,
+		RealizedPnL:           lot.RealizedPnL,
+		MaxFavorableExcursion: lot.MaxFavorableExcursion,
+		MaxAdverseExcursion:   lot.MaxAdverseExcursion,
+		InitialRisk:           lot.InitialRisk,
+		RMultiple:             lot.GetRMultiple(),
+	}
+}
+
+// NewSimulationResult creates an empty result of the current schema
+func NewSimulationResult(sessionID string, config *Config) *SimulationResult {
+	return &SimulationResult{
+		SchemaVersion: SimulationResultVersion,
+		GeneratedAt:   time.Now(),
+		SessionID:     sessionID,
+		Config:        config,
+		Metrics:       make(map[string]interface{}),
+		Trades:        make([]ResultTrade, 0),
+		Errors:        make([]ResultError, 0),
+		Rejections:    make(map[string]int64),
+	}
+}
+
+// WriteFile writes the result as indented JSON
+func (sr *SimulationResult) WriteFile(path string) error {
+	data, err := json.MarshalIndent(sr, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create result directory: %w", err)
+		}
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadSimulationResult reads a result written by WriteFile
+func LoadSimulationResult(path string) (*SimulationResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read result: %w", err)
+	}
+
+	var result SimulationResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse result: %w", err)
+	}
+	if result.SchemaVersion != SimulationResultVersion {
+		return nil, fmt.Errorf("unsupported result schema version %d", result.SchemaVersion)
+	}
+	return &result, nil
+}
+
+// String returns a human-readable representation
+func (sr *SimulationResult) String() string {
+	return fmt.Sprintf(
+		"SimulationResult[v%d %s, P&L:%.2f, Trades:%d, Errors:%d]",
+		sr.SchemaVersion,
+		sr.Summary.Symbol,
+		sr.Summary.NetPnL,
+		len(sr.Trades),
+		len(sr.Errors),
+	)
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// GetSimulationResult captures the session as a SimulationResult
+func (h *Holodeck) GetSimulationResult() *SimulationResult {
+	report := h.GetSymbolReport()
+	metrics := h.GetMetrics()
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	result := NewSimulationResult(h.config.SessionID, h.config.Config)
+	result.Metrics = metrics
+	result.Rejections = h.copyRejections()
+
+	summary := ResultSummary{
+		Symbol:             report.Symbol,
+		InstrumentType:     report.InstrumentType,
+		Currency:           report.Currency,
+		TicksProcessed:     report.TicksProcessed,
+		Executions:         report.Trades,
+		ClosedTrades:       len(report.ClosedTrades),
+		InitialBalance:     report.InitialBalance,
+		FinalBalance:       report.FinalBalance,
+		NetPnL:             report.NetPnL,
+		ReturnPercent:      report.GetReturnPercent(),
+		RealizedPnL:        report.RealizedPnL,
+		UnrealizedPnL:      report.UnrealizedPnL,
+		Commission:         report.Commission,
+		MaxDrawdownPercent: report.MaxDrawdownPercent,
+		SharpeRatio:        h.state.Balance.GetSharpeRatio(),
+		AccountStatus:      h.state.Balance.AccountStatus,
+	}
+	if points := h.state.EquityCurve.GetPoints(); len(points) > 0 {
+		summary.Start = points[0].Timestamp
+		summary.End = points[len(points)-1].Timestamp
+	}
+
+	for _, lot := range report.ClosedTrades {
+		result.Trades = append(result.Trades, NewResultTrade(lot))
+		switch h.state.Balance.Breakeven.Classify(lot.RealizedPnL, lot.Size) {
+		case types.TradeOutcomeWin:
+			summary.WinningTrades++
+		case types.TradeOutcomeLoss:
+			summary.LosingTrades++
+		}
+	}
+	result.Summary = summary
+
+	for _, err := range h.state.ErrorLog.Errors {
+		result.Errors = append(result.Errors, ResultError{
+			Code:      err.Code,
+			Message:   err.Message,
+			Timestamp: err.Timestamp,
+		})
+	}
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Timestamp.Before(result.Errors[j].Timestamp)
+	})
+
+	return result
+}
+
+// WriteSimulationResult writes the session's SimulationResult to path
+func (h *Holodeck) WriteSimulationResult(path string) error {
+	return h.GetSimulationResult().WriteFile(path)
+}
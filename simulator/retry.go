@@ -0,0 +1,268 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+
+	"holodeck/types"
+)
+
+// ==================== RETRY POLICY ====================
+
+// DefaultRetryableCodes are rejections that may clear up on a later tick
+var DefaultRetryableCodes = []string{
+	types.ErrorCodeNoLiquidity,
+}
+
+// RetryPolicy controls how a rejected order is resubmitted on later ticks
+type RetryPolicy struct {
+	// MaxRetries is the number of resubmissions after the first attempt
+	MaxRetries int
+
+	// BackoffTicks is the number of ticks to wait before the first retry
+	BackoffTicks int64
+
+	// BackoffMultiplier grows the wait after each failed retry (1 = constant)
+	BackoffMultiplier float64
+
+	// MaxBackoffTicks caps the wait between retries (0 = no cap)
+	MaxBackoffTicks int64
+
+	// RetryableCodes are the rejection codes worth retrying
+	// Empty uses DefaultRetryableCodes
+	RetryableCodes []string
+}
+
+// DefaultRetryPolicy retries transient rejections three times with
+// exponential backoff starting at one tick
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:        3,
+		BackoffTicks:      1,
+		BackoffMultiplier: 2.0,
+		MaxBackoffTicks:   16,
+	}
+}
+
+// IsRetryable checks if an execution report is a retryable rejection
+func (rp RetryPolicy) IsRetryable(exec *types.ExecutionReport) bool {
+	if exec == nil || !exec.IsRejected() {
+		return false
+	}
+
+	codes := rp.RetryableCodes
+	if len(codes) == 0 {
+		codes = DefaultRetryableCodes
+	}
+	for _, code := range codes {
+		if exec.ErrorCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// GetBackoff returns the ticks to wait before retry number attempt (1-based)
+func (rp RetryPolicy) GetBackoff(attempt int) int64 {
+	base := rp.BackoffTicks
+	if base <= 0 {
+		base = 1
+	}
+
+	multiplier := rp.BackoffMultiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := int64(float64(base) * math.Pow(multiplier, float64(attempt-1)))
+	if rp.MaxBackoffTicks > 0 && backoff > rp.MaxBackoffTicks {
+		backoff = rp.MaxBackoffTicks
+	}
+	return backoff
+}
+
+// ==================== RETRY QUEUE ====================
+
+// PendingRetry is an order waiting to be resubmitted
+type PendingRetry struct {
+	Order      *types.Order
+	Policy     RetryPolicy
+	Attempts   int   // Retries made so far
+	DueTick    int64 // Tick count at which the next retry runs
+	LastReport *types.ExecutionReport
+}
+
+// RetryQueue holds rejected orders waiting for their next attempt
+type RetryQueue struct {
+	pending []*PendingRetry
+
+	// Statistics
+	ordersQueued    int64
+	retriesMade     int64
+	retriesFilled   int64
+	ordersExhausted int64
+	ordersCancelled int64
+}
+
+// NewRetryQueue creates an empty retry queue
+func NewRetryQueue() *RetryQueue {
+	return &RetryQueue{
+		pending: make([]*PendingRetry, 0),
+	}
+}
+
+// Schedule queues a rejected order if its policy allows another attempt
+// Returns true if the order was queued
+func (rq *RetryQueue) Schedule(retry *PendingRetry, currentTick int64) bool {
+	if retry.Attempts >= retry.Policy.MaxRetries || !retry.Policy.IsRetryable(retry.LastReport) {
+		if retry.Attempts > 0 {
+			rq.ordersExhausted++
+		}
+		return false
+	}
+
+	retry.DueTick = currentTick + retry.Policy.GetBackoff(retry.Attempts+1)
+	rq.pending = append(rq.pending, retry)
+	if retry.Attempts == 0 {
+		rq.ordersQueued++
+	}
+	return true
+}
+
+// TakeDue removes and returns the retries due at the given tick count
+func (rq *RetryQueue) TakeDue(currentTick int64) []*PendingRetry {
+	due := make([]*PendingRetry, 0)
+	remaining := rq.pending[:0]
+
+	for _, retry := range rq.pending {
+		if retry.DueTick <= currentTick {
+			due = append(due, retry)
+			continue
+		}
+		remaining = append(remaining, retry)
+	}
+
+	rq.pending = remaining
+	return due
+}
+
+// recordAttempt counts a retry and whether it filled
+func (rq *RetryQueue) recordAttempt(exec *types.ExecutionReport) {
+	rq.retriesMade++
+	if exec != nil && !exec.IsRejected() && exec.FilledSize > 0 {
+		rq.retriesFilled++
+	}
+}
+
+// CancelAll drops every pending retry
+// Returns the orders that were waiting
+func (rq *RetryQueue) CancelAll() []*PendingRetry {
+	cancelled := rq.pending
+	rq.ordersCancelled += int64(len(cancelled))
+	rq.pending = make([]*PendingRetry, 0)
+	return cancelled
+}
+
+// GetPending returns the orders waiting to be retried
+func (rq *RetryQueue) GetPending() []*PendingRetry {
+	return rq.pending
+}
+
+// GetStatistics returns retry statistics
+func (rq *RetryQueue) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"pending_retries":  len(rq.pending),
+		"orders_queued":    rq.ordersQueued,
+		"retries_made":     rq.retriesMade,
+		"retries_filled":   rq.retriesFilled,
+		"orders_exhausted": rq.ordersExhausted,
+		"orders_cancelled": rq.ordersCancelled,
+	}
+}
+
+// Reset clears pending retries and statistics
+func (rq *RetryQueue) Reset() {
+	rq.pending = make([]*PendingRetry, 0)
+	rq.ordersQueued = 0
+	rq.retriesMade = 0
+	rq.retriesFilled = 0
+	rq.ordersExhausted = 0
+	rq.ordersCancelled = 0
+}
+
+// String returns a human-readable representation
+func (rq *RetryQueue) String() string {
+	return fmt.Sprintf(
+		"RetryQueue[Pending:%d, Retries:%d, Filled:%d, Exhausted:%d]",
+		len(rq.pending),
+		rq.retriesMade,
+		rq.retriesFilled,
+		rq.ordersExhausted,
+	)
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// ExecuteOrderWithRetry executes an order and, on a retryable rejection,
+// resubmits it on later ticks according to the policy
+// Every attempt is reported through the logger and OnExecution callback;
+// the returned report is the first attempt's
+func (h *Holodeck) ExecuteOrderWithRetry(order *types.Order, policy RetryPolicy) (*types.ExecutionReport, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.timing.enter()
+	defer h.timing.leave()
+
+	if err := h.checkCanExecute(); err != nil {
+		return nil, err
+	}
+
+	exec, err := h.executeOrder(order)
+	if err != nil {
+		return nil, err
+	}
+
+	h.retries.Schedule(&PendingRetry{
+		Order:      order,
+		Policy:     policy,
+		LastReport: exec,
+	}, h.state.TickCount)
+
+	return exec, nil
+}
+
+// processRetries resubmits orders whose backoff has elapsed
+// Retries pass the same gate as new orders; once it closes, pending
+// retries are cancelled
+// Caller must hold the write lock
+func (h *Holodeck) processRetries() {
+	if len(h.retries.GetPending()) == 0 {
+		return
+	}
+	if err := h.checkCanExecute(); err != nil {
+		h.retries.CancelAll()
+		return
+	}
+
+	for _, retry := range h.retries.TakeDue(h.state.TickCount) {
+		retry.Attempts++
+
+		exec, err := h.executeOrder(retry.Order)
+		h.retries.recordAttempt(exec)
+		if err != nil {
+			continue
+		}
+
+		retry.LastReport = exec
+		h.retries.Schedule(retry, h.state.TickCount)
+	}
+}
+
+// GetRetryStatistics returns statistics of the retry queue
+func (h *Holodeck) GetRetryStatistics() map[string]interface{} {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.retries.GetStatistics()
+}
@@ -0,0 +1,132 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+
+	"holodeck/types"
+)
+
+// ==================== DAILY LOSS LIMIT ====================
+
+// processDailyLossLimit halts trading when the day's loss reaches the
+// configured limit (closing every position if set to flatten, retrying on
+// later ticks until the book is flat), and resumes it on the first tick of
+// the next day
+// Both transitions are reported through OnStatusChange
+// Caller must hold the write lock
+func (h *Holodeck) processDailyLossLimit(tick *types.Tick) {
+	if !h.lossLimit.IsEnabled() || h.state.Balance == nil {
+		return
+	}
+
+	day := h.state.Daily.GetCurrent()
+	if day == nil {
+		return
+	}
+
+	if h.haltedDay != "" && h.haltedDay != day.Date {
+		h.haltedDay = ""
+		h.notifyStatusChange(types.AccountStatusHalted, types.AccountStatusActive)
+	}
+
+	halting := h.lossLimit.Check(day)
+	if halting {
+		h.haltedDay = day.Date
+	}
+
+	if h.haltedDay != "" && h.lossLimit.Flatten && h.executor != nil && !h.isFlat() {
+		h.closeWorstFirst(tick.Timestamp, "daily loss limit", func() bool { return false })
+	}
+
+	if halting {
+		h.notifyStatusChange(types.AccountStatusActive, types.AccountStatusHalted)
+	}
+}
+
+// checkHalted returns a TRADING_HALTED rejection for an order that would
+// add exposure while the daily loss limit has trading halted
+// Orders that only reduce a position (or close a ticket) are let through
+// Caller must hold the write lock
+func (h *Holodeck) checkHalted(order *types.Order, tick *types.Tick) *types.ExecutionReport {
+	if h.haltedDay == "" || order.CloseTicket != "" {
+		return nil
+	}
+
+	if h.reducesPosition(order) {
+		return nil
+	}
+
+	herr := types.NewTradingHaltedError(fmt.Sprintf("daily loss limit hit on %s", h.haltedDay))
+	return types.NewRejectedExecution(
+		order.OrderID,
+		tick.Timestamp,
+		order.Action,
+		order.Size,
+		herr.Code,
+		herr.Message,
+	)
+}
+
+// ==================== DRAWDOWN THROTTLE ====================
+
+// checkThrottle returns a POSITION_LIMIT_EXCEEDED rejection for an order
+// that adds exposure and is larger than the drawdown-throttled maximum
+// order size
+// Caller must hold the write lock
+func (h *Holodeck) checkThrottle(order *types.Order, tick *types.Tick) *types.ExecutionReport {
+	if h.throttle == nil || h.state.Balance == nil || order.CloseTicket != "" {
+		return nil
+	}
+
+	if h.reducesPosition(order) {
+		return nil
+	}
+
+	drawdown := h.state.Balance.GetPeakDrawdownPercent()
+	maxSize := h.throttle.GetMaxOrderSize(h.config.Config.Account.MaxPositionSize, drawdown)
+	if order.Size <= maxSize {
+		return nil
+	}
+
+	h.throttle.RecordThrottled()
+	herr := types.NewPositionLimitError(order.Size, maxSize)
+	return types.NewRejectedExecution(
+		order.OrderID,
+		tick.Timestamp,
+		order.Action,
+		order.Size,
+		herr.Code,
+		fmt.Sprintf("%s (throttled at %.2f%% drawdown)", herr.Message, drawdown),
+	)
+}
+
+// reducesPosition returns true if an order only reduces (or closes) its
+// symbol's position without reversing it
+// Caller must hold the write lock
+func (h *Holodeck) reducesPosition(order *types.Order) bool {
+	current := h.state.position(order.Symbol).Size
+	after := current + float64(order.GetDirection())*order.Size
+	return math.Abs(after) <= math.Abs(current) && after*current >= 0
+}
+
+// ==================== STATUS CALLBACK ====================
+
+// notifyStatusChange calls the OnStatusChange callback
+// Caller must hold the write lock
+func (h *Holodeck) notifyStatusChange(oldStatus, newStatus string) {
+	if h.callbacks.OnStatusChange == nil {
+		return
+	}
+	callbackStart := h.timing.start()
+	h.callbacks.OnStatusChange(oldStatus, newStatus)
+	h.timing.addCallback(callbackStart)
+}
+
+// IsTradingHalted returns true while a risk control has trading halted
+func (h *Holodeck) IsTradingHalted() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.haltedDay != ""
+}
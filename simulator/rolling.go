@@ -0,0 +1,201 @@
+package simulator
+
+import (
+	"fmt"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== ROLLING METRICS ====================
+
+// RollingSnapshot is the performance over the trailing window at one point
+// of the simulated timeline
+type RollingSnapshot struct {
+	Timestamp          time.Time `json:"timestamp"` // Simulated time
+	WindowDays         float64   `json:"window_days"`
+	Equity             float64   `json:"equity"`
+	ReturnPercent      float64   `json:"return_percent"`
+	SharpeRatio        float64   `json:"sharpe_ratio"`
+	MaxDrawdownPercent float64   `json:"max_drawdown_percent"`
+	Trades             int       `json:"trades"` // Closing trades in the window
+	WinningTrades      int       `json:"winning_trades"`
+	LosingTrades       int       `json:"losing_trades"`
+	WinRate            float64   `json:"win_rate"`
+}
+
+// rollingTrade is a closing trade's outcome
+type rollingTrade struct {
+	timestamp time.Time
+	outcome   int
+}
+
+// RollingMetrics computes return, Sharpe ratio, drawdown and win rate over
+// a trailing window of simulated time at a fixed interval, so strategy
+// degradation shows over the course of a backtest
+type RollingMetrics struct {
+	Window time.Duration
+	Every  time.Duration
+
+	trades    []rollingTrade
+	snapshots []RollingSnapshot
+	lastTime  time.Time
+}
+
+// NewRollingMetrics creates rolling metrics from the configuration
+// Returns nil when rolling metrics are not configured
+func NewRollingMetrics(config RollingConfig) *RollingMetrics {
+	if !config.IsEnabled() {
+		return nil
+	}
+
+	every := config.EveryHours
+	if every == 0 {
+		every = 24
+	}
+
+	return &RollingMetrics{
+		Window:    time.Duration(config.WindowDays * float64(24*time.Hour)),
+		Every:     time.Duration(every * float64(time.Hour)),
+		trades:    make([]rollingTrade, 0),
+		snapshots: make([]RollingSnapshot, 0),
+	}
+}
+
+// RecordTrade records a closing trade's outcome (see BreakevenBand.Classify)
+func (rm *RollingMetrics) RecordTrade(timestamp time.Time, outcome int) {
+	rm.trades = append(rm.trades, rollingTrade{timestamp: timestamp, outcome: outcome})
+}
+
+// Due returns true when the interval has elapsed since the last snapshot
+func (rm *RollingMetrics) Due(timestamp time.Time) bool {
+	if rm.lastTime.IsZero() {
+		rm.lastTime = timestamp
+		return false
+	}
+	return timestamp.Sub(rm.lastTime) >= rm.Every
+}
+
+// Compute takes a snapshot of the window ending at timestamp and restarts
+// the interval
+func (rm *RollingMetrics) Compute(timestamp time.Time, curve *types.EquityCurve, sharpe types.SharpeConfig) RollingSnapshot {
+	rm.lastTime = timestamp
+	from := timestamp.Add(-rm.Window)
+
+	snapshot := RollingSnapshot{
+		Timestamp:  timestamp,
+		WindowDays: rm.Window.Hours() / 24,
+	}
+
+	window := curve.Since(from)
+	if points := window.GetPoints(); len(points) > 0 {
+		first, last := points[0].Equity, points[len(points)-1].Equity
+		snapshot.Equity = last
+		if first > 0 {
+			snapshot.ReturnPercent = (last/first - 1) * 100
+		}
+	}
+	snapshot.SharpeRatio = window.GetSharpeRatio(sharpe)
+	snapshot.MaxDrawdownPercent = window.GetMaxDrawdownPercent()
+
+	// Drop trades that left the window
+	kept := rm.trades[:0]
+	for _, trade := range rm.trades {
+		if trade.timestamp.After(from) {
+			kept = append(kept, trade)
+		}
+	}
+	rm.trades = kept
+
+	for _, trade := range rm.trades {
+		snapshot.Trades++
+		switch trade.outcome {
+		case types.TradeOutcomeWin:
+			snapshot.WinningTrades++
+		case types.TradeOutcomeLoss:
+			snapshot.LosingTrades++
+		}
+	}
+	if snapshot.Trades > 0 {
+		snapshot.WinRate = float64(snapshot.WinningTrades) / float64(snapshot.Trades) * 100
+	}
+
+	rm.snapshots = append(rm.snapshots, snapshot)
+	return snapshot
+}
+
+// GetSnapshots returns a copy of the snapshots taken, oldest first
+func (rm *RollingMetrics) GetSnapshots() []RollingSnapshot {
+	snapshots := make([]RollingSnapshot, len(rm.snapshots))
+	copy(snapshots, rm.snapshots)
+	return snapshots
+}
+
+// GetStatistics returns the window and the latest snapshot
+func (rm *RollingMetrics) GetStatistics() map[string]interface{} {
+	stats := map[string]interface{}{
+		"window_days": rm.Window.Hours() / 24,
+		"every_hours": rm.Every.Hours(),
+		"snapshots":   len(rm.snapshots),
+	}
+	if n := len(rm.snapshots); n > 0 {
+		stats["latest"] = rm.snapshots[n-1]
+	}
+	return stats
+}
+
+// Reset clears the trades and snapshots
+func (rm *RollingMetrics) Reset() {
+	rm.trades = make([]rollingTrade, 0)
+	rm.snapshots = make([]RollingSnapshot, 0)
+	rm.lastTime = time.Time{}
+}
+
+// String returns a human-readable representation
+func (rm *RollingMetrics) String() string {
+	return fmt.Sprintf(
+		"RollingMetrics[Window:%.1fd, Every:%.1fh, Snapshots:%d]",
+		rm.Window.Hours()/24,
+		rm.Every.Hours(),
+		len(rm.snapshots),
+	)
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// processRollingMetrics takes a rolling snapshot when one is due and emits
+// it to the metrics log
+// Caller must hold the write lock
+func (h *Holodeck) processRollingMetrics(tick *types.Tick) {
+	if h.rolling == nil || !h.rolling.Due(tick.Timestamp) {
+		return
+	}
+
+	snapshot := h.rolling.Compute(tick.Timestamp, h.state.EquityCurve, h.state.Balance.Sharpe)
+	if h.logger != nil {
+		h.logger.LogMetrics(map[string]interface{}{
+			"event":                "rolling_metrics",
+			"session_id":           h.config.SessionID,
+			"timestamp":            snapshot.Timestamp,
+			"window_days":          snapshot.WindowDays,
+			"equity":               snapshot.Equity,
+			"return_percent":       snapshot.ReturnPercent,
+			"sharpe_ratio":         snapshot.SharpeRatio,
+			"max_drawdown_percent": snapshot.MaxDrawdownPercent,
+			"trades":               snapshot.Trades,
+			"win_rate":             snapshot.WinRate,
+		})
+	}
+}
+
+// GetRollingMetrics returns the rolling snapshots taken so far (nil when
+// rolling metrics are not configured)
+func (h *Holodeck) GetRollingMetrics() []RollingSnapshot {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.rolling == nil {
+		return nil
+	}
+	return h.rolling.GetSnapshots()
+}
@@ -0,0 +1,156 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+
+	"holodeck/agent"
+	"holodeck/types"
+)
+
+// ==================== AGENT RUN LOOP ====================
+
+// Run drives the session with an agent until the data runs out, the end
+// time is reached, the account is blown or Stop is called: each tick goes
+// to the agent's OnTick with the account state, the orders it returns are
+// executed, fills (working orders included) go to OnFill, and OnSessionEnd
+// gets the final state
+// Run starts the session if it is not running and stops it at the end.
+// Orders that fail are skipped; executor errors still reach the logger and
+// OnError, and rejections go to the agent's OnReject when it implements
+// agent.Rejecter. A tick read error ends the run and is returned
+func (h *Holodeck) Run(a agent.Agent) error {
+	return h.RunContext(context.Background(), a)
+}
+
+// RunContext is Run bound to ctx: when ctx is done (e.g. a timeout) the
+// run stops the session cleanly, calls OnSessionEnd and returns ctx's
+// error. A session it starts is started with StartContext(ctx)
+func (h *Holodeck) RunContext(ctx context.Context, a agent.Agent) error {
+	if a == nil {
+		return fmt.Errorf("agent not set")
+	}
+
+	h.mu.Lock()
+	if h.executor == nil {
+		h.mu.Unlock()
+		return fmt.Errorf("executor not set")
+	}
+	if h.agent != nil {
+		h.mu.Unlock()
+		return fmt.Errorf("agent already running")
+	}
+	h.agent = a
+	running := h.running
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		h.agent = nil
+		h.agentFills = nil
+		h.mu.Unlock()
+	}()
+
+	if !running {
+		if err := h.StartContext(ctx); err != nil {
+			return err
+		}
+	}
+
+	var runErr error
+	for h.IsRunning() && !h.IsAccountBlown() {
+		tick, err := h.GetNextTickContext(ctx)
+		if err != nil {
+			if ctx.Err() != nil || !h.isRunOver() {
+				runErr = err
+			}
+			break
+		}
+		h.deliverAgentFills(a)
+
+		for _, order := range a.OnTick(tick, h.GetAgentState()) {
+			if order != nil {
+				exec, err := h.ExecuteOrder(order)
+				h.deliverAgentFills(a)
+				notifyAgentReject(a, order, exec, err)
+			}
+		}
+	}
+	h.deliverAgentFills(a)
+
+	state := h.GetAgentState()
+	if h.IsRunning() {
+		if err := h.Stop(); err != nil && runErr == nil {
+			runErr = err
+		}
+	}
+	h.deliverAgentFills(a)
+	a.OnSessionEnd(state)
+
+	return runErr
+}
+
+// GetAgentState returns the account state as passed to Agent.OnTick
+func (h *Holodeck) GetAgentState() *agent.State {
+	h.mu.RLock()
+	tickCount := h.state.TickCount
+	h.mu.RUnlock()
+
+	return &agent.State{
+		SimTime:      h.GetSimTime(),
+		TickCount:    tickCount,
+		Balance:      h.GetBalance(),
+		Position:     h.GetPosition(""),
+		Positions:    h.GetPositions(),
+		AccountBlown: h.IsAccountBlown(),
+	}
+}
+
+// isRunOver returns whether GetNextTick failed because the session is
+// over (data exhausted, end time reached or stopped) rather than on a
+// read error
+func (h *Holodeck) isRunOver() bool {
+	h.readMu.Lock()
+	defer h.readMu.Unlock()
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return !h.running || h.ended || (h.reader != nil && !h.reader.HasNext())
+}
+
+// notifyAgentFill queues a fill for the agent driven by Run, if any
+// Caller must hold the write lock
+func (h *Holodeck) notifyAgentFill(exec *types.ExecutionReport) {
+	if h.agent == nil || exec.IsRejected() || exec.FilledSize <= 0 {
+		return
+	}
+	h.agentFills = append(h.agentFills, exec)
+}
+
+// notifyAgentReject passes an order's rejection or failure to the agent,
+// if it implements agent.Rejecter
+func notifyAgentReject(a agent.Agent, order *types.Order, exec *types.ExecutionReport, err error) {
+	rejecter, ok := a.(agent.Rejecter)
+	if !ok {
+		return
+	}
+	if err == nil && exec != nil && exec.IsRejected() {
+		err = types.NewHolodeckError(exec.ErrorCode, exec.ErrorMessage)
+	}
+	if err != nil {
+		rejecter.OnReject(order, err)
+	}
+}
+
+// deliverAgentFills passes the queued fills to the agent's OnFill, in the
+// order they happened, without holding the lock so OnFill may call back
+// into the Holodeck
+func (h *Holodeck) deliverAgentFills(a agent.Agent) {
+	h.mu.Lock()
+	fills := h.agentFills
+	h.agentFills = nil
+	h.mu.Unlock()
+
+	for _, exec := range fills {
+		a.OnFill(exec)
+	}
+}
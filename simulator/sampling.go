@@ -0,0 +1,186 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== LOG SAMPLING ====================
+
+// LogSampler thins logging at high speed multipliers: only every Nth tick
+// is passed to the logger, and ticks and fills are aggregated into one
+// sampled_metrics event per interval of simulated time
+type LogSampler struct {
+	TickEvery    int64
+	MetricsEvery time.Duration
+
+	// Totals
+	ticksSeen   int64
+	ticksLogged int64
+	emitted     int64
+
+	// Current interval
+	lastTime   time.Time
+	ticks      int64
+	open       float64
+	high       float64
+	low        float64
+	close      float64
+	fills      int64
+	volume     float64
+	realized   float64
+	commission float64
+}
+
+// NewLogSampler creates a log sampler from the configuration
+// Returns nil when sampling is not configured
+func NewLogSampler(config LogSamplingConfig) *LogSampler {
+	if !config.IsEnabled() {
+		return nil
+	}
+
+	every := int64(config.TickEvery)
+	if every < 1 {
+		every = 1
+	}
+
+	return &LogSampler{
+		TickEvery:    every,
+		MetricsEvery: time.Duration(config.MetricsEveryMinutes * float64(time.Minute)),
+	}
+}
+
+// SampleTick records a tick in the interval and returns true if it is
+// one to log
+func (ls *LogSampler) SampleTick(tick *types.Tick) bool {
+	mid := tick.GetMidPrice()
+	if ls.ticks == 0 {
+		ls.open, ls.high, ls.low = mid, mid, mid
+	}
+	ls.high = math.Max(ls.high, mid)
+	ls.low = math.Min(ls.low, mid)
+	ls.close = mid
+	ls.ticks++
+
+	ls.ticksSeen++
+	if (ls.ticksSeen-1)%ls.TickEvery != 0 {
+		return false
+	}
+	ls.ticksLogged++
+	return true
+}
+
+// RecordFill records a fill in the interval
+func (ls *LogSampler) RecordFill(size, realizedPnL, commission float64) {
+	ls.fills++
+	ls.volume += size
+	ls.realized += realizedPnL
+	ls.commission += commission
+}
+
+// Due returns true when an aggregation interval has elapsed
+func (ls *LogSampler) Due(timestamp time.Time) bool {
+	if ls.MetricsEvery <= 0 {
+		return false
+	}
+	if ls.lastTime.IsZero() {
+		ls.lastTime = timestamp
+		return false
+	}
+	return timestamp.Sub(ls.lastTime) >= ls.MetricsEvery
+}
+
+// Aggregate returns the interval's sampled_metrics event and starts the
+// next interval
+func (ls *LogSampler) Aggregate(timestamp time.Time, balance *types.Balance) map[string]interface{} {
+	event := map[string]interface{}{
+		"event":            "sampled_metrics",
+		"timestamp":        timestamp,
+		"interval_minutes": timestamp.Sub(ls.lastTime).Minutes(),
+		"ticks":            ls.ticks,
+		"open":             ls.open,
+		"high":             ls.high,
+		"low":              ls.low,
+		"close":            ls.close,
+		"fills":            ls.fills,
+		"volume":           ls.volume,
+		"interval_pnl":     ls.realized,
+		"commission":       ls.commission,
+	}
+	if balance != nil {
+		event["current_balance"] = balance.CurrentBalance
+		event["unrealized_pnl"] = balance.TotalUnrealizedPnL
+		event["return_percent"] = balance.GetReturnPercent()
+		event["drawdown_percent"] = balance.GetDrawdownPercent()
+		event["trade_count"] = balance.TradeCount
+	}
+
+	ls.emitted++
+	ls.lastTime = timestamp
+	ls.ticks, ls.fills = 0, 0
+	ls.volume, ls.realized, ls.commission = 0, 0, 0
+	return event
+}
+
+// GetStatistics returns sampling statistics
+func (ls *LogSampler) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"tick_every":            ls.TickEvery,
+		"metrics_every_minutes": ls.MetricsEvery.Minutes(),
+		"ticks_seen":            ls.ticksSeen,
+		"ticks_logged":          ls.ticksLogged,
+		"ticks_skipped":         ls.ticksSeen - ls.ticksLogged,
+		"metrics_emitted":       ls.emitted,
+	}
+}
+
+// Reset clears the totals and the current interval
+func (ls *LogSampler) Reset() {
+	*ls = LogSampler{TickEvery: ls.TickEvery, MetricsEvery: ls.MetricsEvery}
+}
+
+// String returns a human-readable representation
+func (ls *LogSampler) String() string {
+	return fmt.Sprintf(
+		"LogSampler[TickEvery:%d, MetricsEvery:%.1fm, Logged:%d/%d]",
+		ls.TickEvery,
+		ls.MetricsEvery.Minutes(),
+		ls.ticksLogged,
+		ls.ticksSeen,
+	)
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// logTick passes the tick to the logger unless sampling skips it
+// Caller must hold the write lock
+func (h *Holodeck) logTick(tick *types.Tick) {
+	if h.sampler != nil && !h.sampler.SampleTick(tick) {
+		return
+	}
+	if h.logger == nil {
+		return
+	}
+
+	logStart := h.timing.start()
+	h.logger.LogTick(tick)
+	h.timing.addLogger(logStart)
+}
+
+// processSampledMetrics emits the aggregated interval to the metrics log
+// when one is due
+// Caller must hold the write lock
+func (h *Holodeck) processSampledMetrics(tick *types.Tick) {
+	if h.sampler == nil || !h.sampler.Due(tick.Timestamp) {
+		return
+	}
+
+	event := h.sampler.Aggregate(tick.Timestamp, h.state.Balance)
+	if h.logger != nil {
+		event["session_id"] = h.config.SessionID
+		h.logger.LogMetrics(event)
+	}
+}
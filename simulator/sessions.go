@@ -2,6 +2,7 @@ package simulator
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,6 +19,10 @@ type HolodeckConfig struct {
 	// Instrument is the active instrument
 	Instrument types.Instrument
 
+	// Instruments are the other symbols' instruments in a multi-symbol
+	// session, keyed by symbol
+	Instruments map[string]types.Instrument
+
 	// Session information
 	SessionID string
 	StartTime time.Time
@@ -73,6 +78,10 @@ type StateConfiguration struct {
 	MaxPositionHistorySize  int
 	MaxBalanceHistorySize   int
 	MaxExecutionHistorySize int
+
+	// HistorySpillDir receives history entries evicted past the caps as
+	// JSON lines ("" = evicted entries are dropped)
+	HistorySpillDir string
 }
 
 // ==================== HOLODECK STATE ====================
@@ -89,16 +98,39 @@ type HolodeckState struct {
 	CurrentTick *types.Tick
 	TickCount   int64
 
-	// Position tracking
-	Position *types.Position
+	// Position tracking, keyed by symbol
+	Positions map[string]*types.Position
+
+	// Latest tick of each symbol, for pricing orders and positions
+	LastTicks map[string]*types.Tick
+
+	// PrimarySymbol is the session instrument's symbol; ticks and orders
+	// without a symbol belong to it
+	PrimarySymbol string
 
 	// Account tracking
 	Balance *types.Balance
 
-	// Execution history
+	// EquityCurve samples balance + unrealized P&L over simulated time
+	EquityCurve *types.EquityCurve
+
+	// Daily rolls P&L, costs and trades up by tick date
+	Daily *types.DailyLedger
+
+	// Breakdown buckets closing trades by trading session and UTC hour
+	Breakdown *types.TimeBreakdown
+
+	// Symbols tracks trades, P&L and drawdown per symbol
+	Symbols *types.SymbolMetricsBook
+
+	// Execution history (bounded by StateConfig.MaxExecutionHistorySize)
 	ExecutionHistory []*types.ExecutionReport
 	ExecutionCount   int
 
+	// TotalSlippageUnits sums the slippage of every fill, including fills
+	// evicted from the execution history
+	TotalSlippageUnits float64
+
 	// Error tracking
 	ErrorLog *types.ErrorLog
 
@@ -113,6 +145,9 @@ type HolodeckState struct {
 	LastUpdateTime time.Time
 	SessionStart   time.Time
 	SessionEnd     time.Time
+
+	// Simulated time, from the latest tick
+	Clock *SimClock
 }
 
 // ==================== HOLODECK INITIALIZATION ====================
@@ -163,6 +198,7 @@ func NewHolodeckConfig(config *Config) (*HolodeckConfig, error) {
 		MaxBalanceHistorySize:   1000,
 		MaxExecutionHistorySize: 10000,
 	}
+	config.Session.History.apply(&stateConfig)
 
 	// Create Holodeck config
 	hConfig := &HolodeckConfig{
@@ -185,16 +221,10 @@ func NewHolodeckState(hConfig *HolodeckConfig) (*HolodeckState, error) {
 	}
 
 	// Create balance
-	balance := types.NewBalance(
-		hConfig.Config.Account.InitialBalance,
-		hConfig.Config.Account.Currency,
-		hConfig.Config.Account.Leverage,
-		hConfig.Config.Account.MaxDrawdownPercent,
-		hConfig.Config.Account.MaxPositionSize,
-	)
-
-	// Create position
-	position := types.NewPosition()
+	balance, err := newAccountBalance(hConfig.Config)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create error log
 	errorLog := types.NewErrorLog()
@@ -205,8 +235,14 @@ func NewHolodeckState(hConfig *HolodeckConfig) (*HolodeckState, error) {
 		Config:           hConfig,
 		CurrentTick:      nil,
 		TickCount:        0,
-		Position:         position,
+		Positions:        make(map[string]*types.Position),
+		LastTicks:        make(map[string]*types.Tick),
+		PrimarySymbol:    hConfig.Config.Instrument.Symbol,
 		Balance:          balance,
+		EquityCurve:      types.NewEquityCurve(hConfig.Config.GetEquitySampleInterval()),
+		Daily:            types.NewDailyLedger(),
+		Breakdown:        types.NewTimeBreakdown(instrumentSessions(hConfig.Instrument)),
+		Symbols:          types.NewSymbolMetricsBook(),
 		ExecutionHistory: make([]*types.ExecutionReport, 0, hConfig.StateConfig.MaxExecutionHistorySize),
 		ExecutionCount:   0,
 		ErrorLog:         errorLog,
@@ -217,13 +253,50 @@ func NewHolodeckState(hConfig *HolodeckConfig) (*HolodeckState, error) {
 		TotalPnL:         0,
 		LastUpdateTime:   now,
 		SessionStart:     now,
+		Clock:            NewSimClock(),
 	}
+	state.position(state.PrimarySymbol)
+	state.attachEquityCurve()
+	balance.Clock = state.Clock
 
 	return state, nil
 }
 
 // ==================== STATE QUERIES ====================
 
+// newAccountBalance creates the account balance, with a currency converter
+// per quote currency other than the account's
+func newAccountBalance(config *Config) (*types.Balance, error) {
+	balance := types.NewBalance(
+		config.Account.InitialBalance,
+		config.Account.Currency,
+		config.Account.Leverage,
+		config.Account.MaxDrawdownPercent,
+		config.Account.MaxPositionSize,
+	)
+
+	converters, err := config.NewCurrencyConverters()
+	if err != nil {
+		return nil, types.NewConfigError("account.conversion", err.Error())
+	}
+	balance.Converters = converters
+	balance.QuoteCurrencies = config.GetQuoteCurrencies()
+	balance.Breakeven = config.Account.Breakeven
+
+	return balance, nil
+}
+
+// attachEquityCurve gives the balance the equity curve its Sharpe ratio
+// is computed from, over the instrument's trading year unless configured
+// Caller must hold the write lock
+func (hs *HolodeckState) attachEquityCurve() {
+	hs.Balance.EquityCurve = hs.EquityCurve
+	hs.Balance.Sharpe = hs.Config.Config.Metrics.Sharpe
+	if hs.Balance.Sharpe.TradingDaysPerYear == 0 {
+		hs.Balance.Sharpe.TradingDaysPerYear = instrumentTradingDays(hs.Config.Instrument)
+	}
+}
+
 // GetCurrentTick returns the current tick (thread-safe)
 func (hs *HolodeckState) GetCurrentTick() *types.Tick {
 	hs.mu.RLock()
@@ -231,11 +304,46 @@ func (hs *HolodeckState) GetCurrentTick() *types.Tick {
 	return hs.CurrentTick
 }
 
-// GetPosition returns the current position (thread-safe)
-func (hs *HolodeckState) GetPosition() *types.Position {
+// GetPosition returns a symbol's position, or nil if it never traded
+// (thread-safe); an empty symbol means the session instrument
+func (hs *HolodeckState) GetPosition(symbol string) *types.Position {
 	hs.mu.RLock()
 	defer hs.mu.RUnlock()
-	return hs.Position
+	return hs.Positions[hs.symbolKey(symbol)]
+}
+
+// GetPositionSymbols returns the symbols with a position, sorted (thread-safe)
+func (hs *HolodeckState) GetPositionSymbols() []string {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	symbols := make([]string, 0, len(hs.Positions))
+	for symbol := range hs.Positions {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// symbolKey maps an empty symbol to the session instrument
+func (hs *HolodeckState) symbolKey(symbol string) string {
+	if symbol == "" {
+		return hs.PrimarySymbol
+	}
+	return symbol
+}
+
+// position returns a symbol's position, creating a flat one on first use
+// Not locked: the Holodeck calls it under its own write lock
+func (hs *HolodeckState) position(symbol string) *types.Position {
+	symbol = hs.symbolKey(symbol)
+	pos, ok := hs.Positions[symbol]
+	if !ok {
+		pos = types.NewPosition()
+		pos.LotMatching = hs.Config.Config.Account.LotMatching
+		hs.Positions[symbol] = pos
+	}
+	return pos
 }
 
 // GetBalance returns the current balance (thread-safe)
@@ -285,14 +393,15 @@ func (hs *HolodeckState) UpdateTick(tick *types.Tick) error {
 	defer hs.mu.Unlock()
 
 	hs.CurrentTick = tick
+	hs.Clock.Set(tick.Timestamp)
 	hs.TickCount++
 	hs.LastUpdateTime = time.Now()
 
 	return nil
 }
 
-// UpdatePosition updates the position (thread-safe)
-func (hs *HolodeckState) UpdatePosition(position *types.Position) error {
+// UpdatePosition replaces a symbol's position (thread-safe)
+func (hs *HolodeckState) UpdatePosition(symbol string, position *types.Position) error {
 	if position == nil {
 		return types.NewInvalidOperationError("UpdatePosition", "position cannot be nil")
 	}
@@ -300,7 +409,7 @@ func (hs *HolodeckState) UpdatePosition(position *types.Position) error {
 	hs.mu.Lock()
 	defer hs.mu.Unlock()
 
-	hs.Position = position
+	hs.Positions[hs.symbolKey(symbol)] = position
 	hs.LastUpdateTime = time.Now()
 
 	return nil
@@ -316,6 +425,14 @@ func (hs *HolodeckState) UpdateBalance(balance *types.Balance) error {
 	defer hs.mu.Unlock()
 
 	hs.Balance = balance
+	if balance.Clock == nil {
+		balance.Clock = hs.Clock
+	}
+	hs.EquityCurve.Reset()
+	hs.attachEquityCurve()
+	hs.Daily.Reset()
+	hs.Breakdown.Reset()
+	hs.Symbols.Reset()
 	hs.CurrentBalance = balance.CurrentBalance
 
 	// Update peak and trough
@@ -341,7 +458,9 @@ func (hs *HolodeckState) AddExecution(execution *types.ExecutionReport) error {
 	defer hs.mu.Unlock()
 
 	hs.ExecutionHistory = append(hs.ExecutionHistory, execution)
+	hs.ExecutionHistory, _ = types.TrimHistory(hs.ExecutionHistory, hs.Config.StateConfig.MaxExecutionHistorySize)
 	hs.ExecutionCount++
+	hs.TotalSlippageUnits += execution.SlippageUnits
 
 	// Update total P&L
 	hs.TotalPnL = execution.TotalPnL
@@ -410,55 +529,103 @@ func (hs *HolodeckState) GetMaxDrawdown() float64 {
 	return ((hs.PeakBalance - hs.TroughBalance) / hs.PeakBalance) * 100
 }
 
-// GetMetrics returns a comprehensive metrics map
-func (hs *HolodeckState) GetMetrics() map[string]interface{} {
+// SessionMetrics is a typed snapshot of a session's metrics
+type SessionMetrics struct {
+	SessionID          string        `json:"session_id"`
+	Instrument         string        `json:"instrument"`
+	TickCount          int64         `json:"tick_count"`
+	ExecutionCount     int           `json:"execution_count"`
+	ErrorCount         int           `json:"error_count"`
+	SessionDuration    time.Duration `json:"session_duration"`
+	StartBalance       float64       `json:"start_balance"`
+	CurrentBalance     float64       `json:"current_balance"`
+	PeakBalance        float64       `json:"peak_balance"`
+	TroughBalance      float64       `json:"trough_balance"`
+	TotalPnL           float64       `json:"total_pnl"`
+	ReturnPercent      float64       `json:"return_percent"`
+	DrawdownPercent    float64       `json:"drawdown_percent"`
+	MaxDrawdownPercent float64       `json:"max_drawdown_percent"`
+	LastUpdateTime     time.Time     `json:"last_update_time"`
+	IsRunning          bool          `json:"is_running"`
+}
+
+// GetSessionMetrics returns comprehensive session metrics
+func (hs *HolodeckState) GetSessionMetrics() *SessionMetrics {
 	hs.mu.RLock()
 	defer hs.mu.RUnlock()
 
-	sessionDuration := hs.GetSessionDuration()
-	drawdown := hs.GetDrawdownPercent()
-	returnPct := hs.GetReturnPercent()
-	maxDD := hs.GetMaxDrawdown()
+	return &SessionMetrics{
+		SessionID:          hs.Config.SessionID,
+		Instrument:         hs.Config.Instrument.GetSymbol(),
+		TickCount:          hs.TickCount,
+		ExecutionCount:     hs.ExecutionCount,
+		ErrorCount:         hs.ErrorLog.Size(),
+		SessionDuration:    hs.GetSessionDuration(),
+		StartBalance:       hs.StartBalance,
+		CurrentBalance:     hs.CurrentBalance,
+		PeakBalance:        hs.PeakBalance,
+		TroughBalance:      hs.TroughBalance,
+		TotalPnL:           hs.TotalPnL,
+		ReturnPercent:      hs.GetReturnPercent(),
+		DrawdownPercent:    hs.GetDrawdownPercent(),
+		MaxDrawdownPercent: hs.GetMaxDrawdown(),
+		LastUpdateTime:     hs.LastUpdateTime,
+		IsRunning:          hs.Config.IsRunning,
+	}
+}
 
+// ToMap returns the metrics as a map keyed by their JSON names
+func (sm *SessionMetrics) ToMap() map[string]interface{} {
 	return map[string]interface{}{
-		"session_id":           hs.Config.SessionID,
-		"instrument":           hs.Config.Instrument.GetSymbol(),
-		"tick_count":           hs.TickCount,
-		"execution_count":      hs.ExecutionCount,
-		"error_count":          hs.ErrorLog.Size(),
-		"session_duration":     sessionDuration,
-		"start_balance":        hs.StartBalance,
-		"current_balance":      hs.CurrentBalance,
-		"peak_balance":         hs.PeakBalance,
-		"trough_balance":       hs.TroughBalance,
-		"total_pnl":            hs.TotalPnL,
-		"return_percent":       returnPct,
-		"drawdown_percent":     drawdown,
-		"max_drawdown_percent": maxDD,
-		"last_update_time":     hs.LastUpdateTime,
-		"is_running":           hs.Config.IsRunning,
+		"session_id":           sm.SessionID,
+		"instrument":           sm.Instrument,
+		"tick_count":           sm.TickCount,
+		"execution_count":      sm.ExecutionCount,
+		"error_count":          sm.ErrorCount,
+		"session_duration":     sm.SessionDuration,
+		"start_balance":        sm.StartBalance,
+		"current_balance":      sm.CurrentBalance,
+		"peak_balance":         sm.PeakBalance,
+		"trough_balance":       sm.TroughBalance,
+		"total_pnl":            sm.TotalPnL,
+		"return_percent":       sm.ReturnPercent,
+		"drawdown_percent":     sm.DrawdownPercent,
+		"max_drawdown_percent": sm.MaxDrawdownPercent,
+		"last_update_time":     sm.LastUpdateTime,
+		"is_running":           sm.IsRunning,
 	}
 }
 
+// GetMetrics returns a comprehensive metrics map
+// Kept for backward compatibility, prefer GetSessionMetrics
+func (hs *HolodeckState) GetMetrics() map[string]interface{} {
+	return hs.GetSessionMetrics().ToMap()
+}
+
 // ==================== HOLODECK STATUS ====================
 
 // SessionStatus represents the current status of a Holodeck session
 type SessionStatus struct {
-	SessionID        string
-	InstrumentType   string
-	InstrumentSymbol string
-	StartTime        time.Time
-	CurrentTime      time.Time
-	IsRunning        bool
-	TicksProcessed   int64
-	ExecutionsCount  int
-	ErrorsCount      int
-	CurrentBalance   float64
-	StartBalance     float64
-	TotalPnL         float64
-	DrawdownPercent  float64
-	ReturnPercent    float64
-	AccountStatus    string
+	SessionID        string    `json:"session_id"`
+	InstrumentType   string    `json:"instrument_type"`
+	InstrumentSymbol string    `json:"instrument_symbol"`
+	StartTime        time.Time `json:"start_time"`
+	CurrentTime      time.Time `json:"current_time"`
+	IsRunning        bool      `json:"is_running"`
+	TicksProcessed   int64     `json:"ticks_processed"`
+	ExecutionsCount  int       `json:"executions_count"`
+	ErrorsCount      int       `json:"errors_count"`
+	CurrentBalance   float64   `json:"current_balance"`
+	StartBalance     float64   `json:"start_balance"`
+	TotalPnL         float64   `json:"total_pnl"`
+	DrawdownPercent  float64   `json:"drawdown_percent"`
+	ReturnPercent    float64   `json:"return_percent"`
+	AccountStatus    string    `json:"account_status"`
+
+	// Pause state (filled in by Holodeck.GetStatus)
+	IsPaused      bool    `json:"is_paused"`
+	PausedSeconds float64 `json:"paused_seconds,omitempty"`
+	QueuedOrders  int     `json:"queued_orders,omitempty"`
 }
 
 // GetStatus returns the current session status
@@ -510,6 +677,7 @@ func (ss *SessionStatus) DebugString() string {
 			"  Start Time:        %s\n"+
 			"  Current Time:      %s\n"+
 			"  Is Running:        %v\n"+
+			"  Is Paused:         %v (%d queued orders)\n"+
 			"\n"+
 			"  Processing:\n"+
 			"    Ticks:           %d\n"+
@@ -531,6 +699,7 @@ func (ss *SessionStatus) DebugString() string {
 		ss.StartTime.Format("2006-01-02T15:04:05.000"),
 		ss.CurrentTime.Format("2006-01-02T15:04:05.000"),
 		ss.IsRunning,
+		ss.IsPaused, ss.QueuedOrders,
 		ss.TicksProcessed,
 		ss.ExecutionsCount,
 		ss.ErrorsCount,
@@ -583,8 +752,8 @@ func ValidateHolodeckState(state *HolodeckState) error {
 		return types.NewConfigError("state.config", "configuration cannot be nil")
 	}
 
-	if state.Position == nil {
-		return types.NewConfigError("state.position", "position cannot be nil")
+	if state.Positions == nil {
+		return types.NewConfigError("state.positions", "positions cannot be nil")
 	}
 
 	if state.Balance == nil {
@@ -605,24 +774,29 @@ func (hs *HolodeckState) Reset() error {
 	hs.mu.Lock()
 	defer hs.mu.Unlock()
 
-	// Reset position
-	hs.Position = types.NewPosition()
+	// Reset positions
+	hs.Positions = make(map[string]*types.Position)
+	hs.LastTicks = make(map[string]*types.Tick)
+	hs.position(hs.PrimarySymbol)
 
 	// Reset balance
-	hs.Balance = types.NewBalance(
-		hs.Config.Config.Account.InitialBalance,
-		hs.Config.Config.Account.Currency,
-		hs.Config.Config.Account.Leverage,
-		hs.Config.Config.Account.MaxDrawdownPercent,
-		hs.Config.Config.Account.MaxPositionSize,
-	)
+	balance, err := newAccountBalance(hs.Config.Config)
+	if err != nil {
+		return err
+	}
+	hs.Balance = balance
+	hs.attachEquityCurve()
+	hs.Clock.Reset()
+	balance.Clock = hs.Clock
 
 	// Reset tracking
 	hs.CurrentTick = nil
 	hs.TickCount = 0
 	hs.ExecutionCount = 0
+	hs.TotalSlippageUnits = 0
 	hs.ExecutionHistory = make([]*types.ExecutionReport, 0, hs.Config.StateConfig.MaxExecutionHistorySize)
 	hs.ErrorLog = types.NewErrorLog()
+	hs.Symbols.Reset()
 
 	// Reset metrics
 	hs.StartBalance = hs.Config.Config.Account.InitialBalance
@@ -650,7 +824,7 @@ func (hs *HolodeckState) Snapshot() map[string]interface{} {
 		"timestamp":       hs.LastUpdateTime,
 		"tick_count":      hs.TickCount,
 		"execution_count": hs.ExecutionCount,
-		"position":        hs.Position,
+		"positions":       hs.Positions,
 		"balance":         hs.Balance,
 		"total_pnl":       hs.TotalPnL,
 		"current_balance": hs.CurrentBalance,
@@ -0,0 +1,334 @@
+package simulator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== SNAPSHOT RECORDS ====================
+
+// Snapshot formats
+const (
+	SnapshotFormatJSON = "json"
+	SnapshotFormatCSV  = "csv"
+)
+
+// Snapshot file names
+const (
+	SnapshotLatestFile = "latest.json"
+	SnapshotCSVFile    = "snapshots.csv"
+)
+
+// AccountSnapshot is the balance and positions at one point of a session,
+// written periodically so long runs can be inspected mid-run and recovered
+// after a crash
+type AccountSnapshot struct {
+	Sequence       int64     `json:"sequence"`
+	SessionID      string    `json:"session_id"`
+	Timestamp      time.Time `json:"timestamp"` // Simulated time
+	WrittenAt      time.Time `json:"written_at"`
+	TickCount      int64     `json:"tick_count"`
+	ExecutionCount int       `json:"execution_count"`
+
+	Balance   SnapshotBalance             `json:"balance"`
+	Positions map[string]SnapshotPosition `json:"positions"`
+}
+
+// SnapshotBalance is the account part of a snapshot
+type SnapshotBalance struct {
+	Currency         string  `json:"currency"`
+	InitialBalance   float64 `json:"initial_balance"`
+	CurrentBalance   float64 `json:"current_balance"`
+	RealizedPnL      float64 `json:"realized_pnl"`
+	UnrealizedPnL    float64 `json:"unrealized_pnl"`
+	CommissionPaid   float64 `json:"commission_paid"`
+	FinancingPnL     float64 `json:"financing_pnl"`
+	DividendPnL      float64 `json:"dividend_pnl"`
+	InterestPnL      float64 `json:"interest_pnl"`
+	UsedMargin       float64 `json:"used_margin"`
+	AvailableMargin  float64 `json:"available_margin"`
+	HighWaterMark    float64 `json:"high_water_mark"`
+	TradeCount       int     `json:"trade_count"`
+	AccountStatus    string  `json:"account_status"`
+	ProtectionCredit float64 `json:"protection_credit,omitempty"`
+}
+
+// SnapshotPosition is one symbol's position in a snapshot
+type SnapshotPosition struct {
+	Size          float64   `json:"size"`
+	EntryPrice    float64   `json:"entry_price"`
+	CurrentPrice  float64   `json:"current_price"`
+	UnrealizedPnL float64   `json:"unrealized_pnl"`
+	RealizedPnL   float64   `json:"realized_pnl"`
+	EntryTime     time.Time `json:"entry_time"`
+}
+
+// ==================== SNAPSHOTTER ====================
+
+// Snapshotter decides when snapshots are due and writes them to a directory
+// JSON snapshots are written one file per snapshot plus latest.json
+// (replaced atomically); CSV appends one row per symbol to snapshots.csv
+type Snapshotter struct {
+	Dir        string
+	Format     string
+	EveryTicks int64
+	Every      time.Duration // Simulated time between snapshots
+
+	sequence    int64
+	lastTick    int64
+	lastTime    time.Time
+	wroteHeader bool
+}
+
+// NewSnapshotter creates a snapshotter writing to config.Dir
+// Returns nil when snapshots are not configured
+func NewSnapshotter(config SnapshotConfig) (*Snapshotter, error) {
+	if !config.IsEnabled() {
+		return nil, nil
+	}
+
+	format := config.Format
+	if format == "" {
+		format = SnapshotFormatJSON
+	}
+
+	if err := os.MkdirAll(config.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	return &Snapshotter{
+		Dir:        config.Dir,
+		Format:     format,
+		EveryTicks: config.EveryTicks,
+		Every:      time.Duration(config.EveryMinutes * float64(time.Minute)),
+	}, nil
+}
+
+// Due returns true when either the tick or the simulated time interval has
+// elapsed since the last snapshot
+func (s *Snapshotter) Due(tickCount int64, timestamp time.Time) bool {
+	if s.EveryTicks > 0 && tickCount-s.lastTick >= s.EveryTicks {
+		return true
+	}
+	if s.Every > 0 {
+		if s.lastTime.IsZero() {
+			s.lastTime = timestamp
+			return false
+		}
+		return timestamp.Sub(s.lastTime) >= s.Every
+	}
+	return false
+}
+
+// Write stores a snapshot and restarts the intervals
+func (s *Snapshotter) Write(snapshot *AccountSnapshot) error {
+	s.sequence++
+	snapshot.Sequence = s.sequence
+	snapshot.WrittenAt = time.Now()
+	s.lastTick = snapshot.TickCount
+	s.lastTime = snapshot.Timestamp
+
+	if s.Format == SnapshotFormatCSV {
+		return s.appendCSV(snapshot)
+	}
+	return s.writeJSON(snapshot)
+}
+
+// writeJSON writes the numbered snapshot file and replaces latest.json
+func (s *Snapshotter) writeJSON(snapshot *AccountSnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("snapshot-%06d.json", snapshot.Sequence)
+	if err := os.WriteFile(filepath.Join(s.Dir, name), data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	// Write then rename so a crash never leaves a truncated latest.json
+	tmp := filepath.Join(s.Dir, SnapshotLatestFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(s.Dir, SnapshotLatestFile))
+}
+
+// snapshotCSVHeader is the column layout of snapshots.csv
+var snapshotCSVHeader = []string{
+	"sequence", "timestamp", "tick_count", "execution_count",
+	"current_balance", "realized_pnl", "unrealized_pnl", "commission_paid",
+	"used_margin", "account_status",
+	"symbol", "position_size", "entry_price", "current_price", "position_unrealized_pnl",
+}
+
+// appendCSV appends one row per symbol to snapshots.csv
+func (s *Snapshotter) appendCSV(snapshot *AccountSnapshot) error {
+	path := filepath.Join(s.Dir, SnapshotCSVFile)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot file: %w", err)
+	}
+
+	w := csv.NewWriter(f)
+	if !s.wroteHeader {
+		if info, statErr := f.Stat(); statErr == nil && info.Size() == 0 {
+			w.Write(snapshotCSVHeader)
+		}
+		s.wroteHeader = true
+	}
+
+	f64 := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	b := snapshot.Balance
+
+	symbols := make([]string, 0, len(snapshot.Positions))
+	for symbol := range snapshot.Positions {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		p := snapshot.Positions[symbol]
+		w.Write([]string{
+			strconv.FormatInt(snapshot.Sequence, 10),
+			snapshot.Timestamp.Format(time.RFC3339Nano),
+			strconv.FormatInt(snapshot.TickCount, 10),
+			strconv.Itoa(snapshot.ExecutionCount),
+			f64(b.CurrentBalance), f64(b.RealizedPnL), f64(b.UnrealizedPnL), f64(b.CommissionPaid),
+			f64(b.UsedMargin), b.AccountStatus,
+			symbol, f64(p.Size), f64(p.EntryPrice), f64(p.CurrentPrice), f64(p.UnrealizedPnL),
+		})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// GetStatistics returns snapshotter statistics
+func (s *Snapshotter) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"dir":         s.Dir,
+		"format":      s.Format,
+		"every_ticks": s.EveryTicks,
+		"every":       s.Every.String(),
+		"written":     s.sequence,
+	}
+}
+
+// LoadLatestSnapshot reads latest.json from a snapshot directory, e.g. to
+// see where a crashed session got to
+func LoadLatestSnapshot(dir string) (*AccountSnapshot, error) {
+	data, err := os.ReadFile(filepath.Join(dir, SnapshotLatestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot AccountSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// newAccountSnapshot captures the current balance and positions
+// Caller must hold the lock
+func (h *Holodeck) newAccountSnapshot(timestamp time.Time) *AccountSnapshot {
+	b := h.state.Balance
+	snapshot := &AccountSnapshot{
+		SessionID:      h.config.SessionID,
+		Timestamp:      timestamp,
+		TickCount:      h.state.TickCount,
+		ExecutionCount: h.state.ExecutionCount,
+		Balance: SnapshotBalance{
+			Currency:         b.Currency,
+			InitialBalance:   b.InitialBalance,
+			CurrentBalance:   b.CurrentBalance,
+			RealizedPnL:      b.TotalRealizedPnL,
+			UnrealizedPnL:    b.TotalUnrealizedPnL,
+			CommissionPaid:   b.CommissionPaid,
+			FinancingPnL:     b.FinancingPnL,
+			DividendPnL:      b.DividendPnL,
+			InterestPnL:      b.InterestPnL,
+			UsedMargin:       b.UsedMargin,
+			AvailableMargin:  b.AvailableMargin,
+			HighWaterMark:    b.HighWaterMark,
+			TradeCount:       b.TradeCount,
+			AccountStatus:    b.AccountStatus,
+			ProtectionCredit: b.ProtectionCredit,
+		},
+		Positions: make(map[string]SnapshotPosition, len(h.state.Positions)),
+	}
+
+	for symbol, pos := range h.state.Positions {
+		snapshot.Positions[symbol] = SnapshotPosition{
+			Size:          pos.Size,
+			EntryPrice:    pos.EntryPrice,
+			CurrentPrice:  pos.CurrentPrice,
+			UnrealizedPnL: pos.UnrealizedPnL,
+			RealizedPnL:   pos.RealizedPnL,
+			EntryTime:     pos.EntryTime,
+		}
+	}
+
+	// Symbols not traded yet are flat; they still get a (zero-size) entry
+	// so the CSV time series has a row per symbol at every snapshot
+	for symbol := range h.config.Instruments {
+		if _, ok := snapshot.Positions[symbol]; !ok {
+			snapshot.Positions[symbol] = SnapshotPosition{}
+		}
+	}
+
+	// A flat position is not marked to market; price it at its last tick
+	for symbol, p := range snapshot.Positions {
+		if p.CurrentPrice != 0 {
+			continue
+		}
+		if tick := h.state.LastTicks[symbol]; tick != nil {
+			p.CurrentPrice = tick.GetMidPrice()
+			snapshot.Positions[symbol] = p
+		}
+	}
+
+	return snapshot
+}
+
+// processSnapshots writes a snapshot when one is due
+// Caller must hold the write lock
+func (h *Holodeck) processSnapshots(tick *types.Tick) {
+	if h.snapshots == nil || !h.snapshots.Due(h.state.TickCount, tick.Timestamp) {
+		return
+	}
+	if err := h.snapshots.Write(h.newAccountSnapshot(tick.Timestamp)); err != nil {
+		h.logError(err)
+	}
+}
+
+// WriteSnapshot writes a snapshot immediately
+func (h *Holodeck) WriteSnapshot() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.snapshots == nil {
+		return fmt.Errorf("snapshots not configured")
+	}
+
+	timestamp := time.Now()
+	if h.state.CurrentTick != nil {
+		timestamp = h.state.CurrentTick.Timestamp
+	}
+	return h.snapshots.Write(h.newAccountSnapshot(timestamp))
+}
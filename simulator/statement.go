@@ -0,0 +1,295 @@
+package simulator
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== STATEMENT ENTRIES ====================
+
+// Statement entry types
+const (
+	StatementDeposit    = "DEPOSIT"
+	StatementTrade      = "TRADE"
+	StatementCommission = "COMMISSION"
+	StatementSwap       = "SWAP"
+	StatementDividend   = "DIVIDEND"
+	StatementInterest   = "INTEREST"
+	StatementAdjustment = "ADJUSTMENT"
+	StatementCarried    = "CARRIED_FORWARD" // Balance before the retained history
+)
+
+// StatementEntry is one line of an account statement
+// Balance is the cash balance after the entry (unrealized P&L excluded)
+type StatementEntry struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Type        string    `json:"type"`
+	Reference   string    `json:"reference,omitempty"` // Order ID for trades
+	Description string    `json:"description"`
+	Amount      float64   `json:"amount"`
+	Balance     float64   `json:"balance"`
+}
+
+// Statement is a broker-style account statement: every cash movement in
+// order, with the running balance after each
+type Statement struct {
+	SessionID      string           `json:"session_id"`
+	Currency       string           `json:"currency"`
+	GeneratedAt    time.Time        `json:"generated_at"`
+	OpeningBalance float64          `json:"opening_balance"`
+	ClosingBalance float64          `json:"closing_balance"`
+	UnrealizedPnL  float64          `json:"unrealized_pnl"` // Open positions at close
+	Entries        []StatementEntry `json:"entries"`
+
+	// MoneyDecimals is the precision amounts are written with
+	MoneyDecimals int `json:"money_decimals"`
+}
+
+// GetEquity returns the closing balance plus open positions' P&L
+func (s *Statement) GetEquity() float64 {
+	return s.ClosingBalance + s.UnrealizedPnL
+}
+
+// GetTotals returns the summed amount of each entry type
+func (s *Statement) GetTotals() map[string]float64 {
+	totals := make(map[string]float64)
+	for _, entry := range s.Entries {
+		totals[entry.Type] += entry.Amount
+	}
+	return totals
+}
+
+// ==================== STATEMENT BUILDING ====================
+
+// GetStatement builds the account statement from the balance updates, with
+// trade details taken from the execution history
+func (h *Holodeck) GetStatement() *Statement {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	statement := &Statement{
+		SessionID:     h.config.SessionID,
+		GeneratedAt:   time.Now(),
+		Entries:       make([]StatementEntry, 0),
+		MoneyDecimals: h.getMoneyDecimals(),
+	}
+
+	balance := h.state.Balance
+	if balance == nil {
+		return statement
+	}
+
+	statement.Currency = balance.Currency
+	statement.OpeningBalance = balance.InitialBalance
+	statement.UnrealizedPnL = balance.TotalUnrealizedPnL
+
+	// Each applied fill recorded one execution update, in the same order
+	// as the execution history; either history may have been trimmed, so
+	// they are aligned at the tail
+	fills := h.state.ExecutionHistory
+	tradeUpdates := 0
+	for _, update := range balance.UpdateHistory {
+		if isExecutionUpdate(update) {
+			tradeUpdates++
+		}
+	}
+	undescribed := 0
+	if len(fills) > tradeUpdates {
+		fills = fills[len(fills)-tradeUpdates:]
+	} else {
+		undescribed = tradeUpdates - len(fills)
+	}
+
+	opened := balance.StartTime
+	if len(h.state.ExecutionHistory) > 0 {
+		opened = h.state.ExecutionHistory[0].Timestamp
+	}
+	if len(balance.UpdateHistory) > 0 && balance.UpdateHistory[0].Timestamp.Before(opened) {
+		opened = balance.UpdateHistory[0].Timestamp
+	}
+
+	running := 0.0
+	add := func(entry StatementEntry) {
+		running += entry.Amount
+		entry.Balance = running
+		statement.Entries = append(statement.Entries, entry)
+	}
+
+	add(StatementEntry{
+		Timestamp:   opened,
+		Type:        StatementDeposit,
+		Description: "Initial deposit",
+		Amount:      balance.InitialBalance,
+	})
+
+	// Updates evicted from a bounded history are summarised as one entry
+	// bringing the balance up to the oldest retained update
+	if len(balance.UpdateHistory) > 0 {
+		if carried := balance.UpdateHistory[0].BalanceBefore - running; math.Abs(carried) > 1e-9 {
+			add(StatementEntry{
+				Timestamp:   balance.UpdateHistory[0].Timestamp,
+				Type:        StatementCarried,
+				Description: "Balance carried forward",
+				Amount:      carried,
+			})
+		}
+	}
+
+	for _, update := range balance.UpdateHistory {
+		if !isExecutionUpdate(update) {
+			add(StatementEntry{
+				Timestamp:   update.Timestamp,
+				Type:        statementType(update.Reason),
+				Description: update.Reason,
+				Amount:      update.Change,
+			})
+			continue
+		}
+
+		description := update.Reason
+		if undescribed > 0 {
+			undescribed--
+		} else if len(fills) > 0 {
+			exec := fills[0]
+			fills = fills[1:]
+			description = fmt.Sprintf("%s %s %.4f @ %.5f", exec.Action, exec.Symbol, exec.FilledSize, exec.FillPrice)
+		}
+
+		add(StatementEntry{
+			Timestamp:   update.Timestamp,
+			Type:        StatementTrade,
+			Reference:   update.OrderID,
+			Description: description,
+			Amount:      update.Change,
+		})
+		if update.Commission != 0 {
+			add(StatementEntry{
+				Timestamp:   update.Timestamp,
+				Type:        StatementCommission,
+				Reference:   update.OrderID,
+				Description: "Commission",
+				Amount:      -update.Commission,
+			})
+		}
+	}
+
+	statement.ClosingBalance = running
+	return statement
+}
+
+// isExecutionUpdate returns true for the balance update of a fill
+func isExecutionUpdate(update *types.BalanceUpdate) bool {
+	return strings.HasPrefix(update.Reason, types.BalanceReasonExecution)
+}
+
+// statementType classifies a non-trade balance update by its reason
+func statementType(reason string) string {
+	switch {
+	case strings.HasPrefix(reason, types.BalanceReasonSwap):
+		return StatementSwap
+	case strings.HasPrefix(reason, types.BalanceReasonDividend):
+		return StatementDividend
+	case strings.HasPrefix(reason, types.BalanceReasonInterest):
+		return StatementInterest
+	default:
+		return StatementAdjustment
+	}
+}
+
+// ==================== STATEMENT EXPORT ====================
+
+// statementCSVHeader is the column layout of the CSV statement
+var statementCSVHeader = []string{"timestamp", "type", "reference", "description", "amount", "balance"}
+
+// WriteCSV writes the statement entries as CSV
+func (s *Statement) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(statementCSVHeader)
+
+	f64 := func(v float64) string { return types.FormatMoney(v, s.MoneyDecimals) }
+	for _, entry := range s.Entries {
+		cw.Write([]string{
+			entry.Timestamp.Format(time.RFC3339Nano),
+			entry.Type,
+			entry.Reference,
+			entry.Description,
+			f64(entry.Amount),
+			f64(entry.Balance),
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteText writes the statement as an aligned plain-text report
+func (s *Statement) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "ACCOUNT STATEMENT\n")
+	if s.SessionID != "" {
+		fmt.Fprintf(w, "Session:  %s\n", s.SessionID)
+	}
+	fmt.Fprintf(w, "Currency: %s\n", s.Currency)
+	money := s.MoneyDecimals
+	fmt.Fprintf(w, "Opening balance: %.*f\n\n", money, s.OpeningBalance)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Time\tType\tReference\tDescription\tAmount\tBalance\t")
+	for _, entry := range s.Entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.*f\t%.*f\t\n",
+			entry.Timestamp.Format("2006-01-02 15:04:05"),
+			entry.Type,
+			entry.Reference,
+			entry.Description,
+			money, entry.Amount,
+			money, entry.Balance,
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	totals := s.GetTotals()
+	fmt.Fprintf(w, "\nTrading P&L:     %.*f\n", money, totals[StatementTrade])
+	fmt.Fprintf(w, "Commissions:     %.*f\n", money, totals[StatementCommission])
+	fmt.Fprintf(w, "Swaps:           %.*f\n", money, totals[StatementSwap])
+	fmt.Fprintf(w, "Dividends:       %.*f\n", money, totals[StatementDividend])
+	fmt.Fprintf(w, "Interest:        %.*f\n", money, totals[StatementInterest])
+	fmt.Fprintf(w, "Adjustments:     %.*f\n", money, totals[StatementAdjustment])
+	if carried, ok := totals[StatementCarried]; ok {
+		fmt.Fprintf(w, "Carried forward: %.*f\n", money, carried)
+	}
+	fmt.Fprintf(w, "Closing balance: %.*f\n", money, s.ClosingBalance)
+	fmt.Fprintf(w, "Unrealized P&L:  %.*f\n", money, s.UnrealizedPnL)
+	_, err := fmt.Fprintf(w, "Equity:          %.*f\n", money, s.GetEquity())
+	return err
+}
+
+// ExportStatement writes the account statement to path, as CSV when the
+// path ends in .csv and as text otherwise
+func (h *Holodeck) ExportStatement(path string) error {
+	statement := h.GetStatement()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create statement: %w", err)
+	}
+
+	if strings.HasSuffix(strings.ToLower(path), ".csv") {
+		err = statement.WriteCSV(f)
+	} else {
+		err = statement.WriteText(f)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,162 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"holodeck/types"
+)
+
+// ==================== SINGLE-STEP MODE ====================
+
+// stepGate holds the tick loop in step mode: GetNextTick waits for a
+// permit from Step, advances one tick and hands the result back
+type stepGate struct {
+	mu      sync.Mutex
+	enabled bool
+	release chan struct{} // closed when step mode is turned off
+
+	permits chan struct{}
+	results chan stepResult
+	waiting int
+	steps   int64
+}
+
+// stepResult is the outcome of one stepped tick
+type stepResult struct {
+	tick *types.Tick
+	err  error
+}
+
+// newStepGate creates a gate with step mode off
+func newStepGate() *stepGate {
+	return &stepGate{
+		permits: make(chan struct{}),
+		results: make(chan stepResult),
+	}
+}
+
+// isEnabled returns whether step mode is on
+func (sg *stepGate) isEnabled() bool {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	return sg.enabled
+}
+
+// setEnabled turns step mode on or off; turning it off releases a loop
+// waiting for a step
+func (sg *stepGate) setEnabled(enabled bool) {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	if enabled == sg.enabled {
+		return
+	}
+	sg.enabled = enabled
+	if enabled {
+		sg.release = make(chan struct{})
+	} else {
+		close(sg.release)
+	}
+}
+
+// wait blocks until Step grants a permit (true), step mode is turned off
+// (false) or ctx is done (its error)
+func (sg *stepGate) wait(ctx context.Context) (bool, error) {
+	sg.mu.Lock()
+	if !sg.enabled {
+		sg.mu.Unlock()
+		return false, nil
+	}
+	release := sg.release
+	sg.waiting++
+	sg.mu.Unlock()
+
+	defer func() {
+		sg.mu.Lock()
+		sg.waiting--
+		sg.mu.Unlock()
+	}()
+
+	select {
+	case <-sg.permits:
+		return true, nil
+	case <-release:
+		return false, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// advance releases a loop waiting for a step and returns the stepped
+// tick's result, or false if no loop is waiting
+func (sg *stepGate) advance() (stepResult, bool) {
+	sg.mu.Lock()
+	if !sg.enabled || sg.waiting == 0 {
+		sg.mu.Unlock()
+		return stepResult{}, false
+	}
+	release := sg.release
+	sg.mu.Unlock()
+
+	select {
+	case sg.permits <- struct{}{}:
+		return <-sg.results, true
+	case <-release:
+		return stepResult{}, false
+	}
+}
+
+// count counts a stepped tick
+func (sg *stepGate) count() {
+	sg.mu.Lock()
+	sg.steps++
+	sg.mu.Unlock()
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// SetStepMode turns single-step mode on or off
+// In step mode GetNextTick blocks until Step is called, so a debugger or
+// notebook can inspect state between ticks while the tick loop runs in
+// another goroutine. Turning step mode off (Stop does) lets the loop
+// continue freely
+func (h *Holodeck) SetStepMode(enabled bool) {
+	h.step.setEnabled(enabled)
+}
+
+// IsStepMode returns whether single-step mode is on
+func (h *Holodeck) IsStepMode() bool {
+	return h.step.isEnabled()
+}
+
+// Step advances exactly one tick and returns it
+// When a tick loop is blocked in GetNextTick in step mode, Step releases
+// it for one tick and waits for that tick to be processed (OnTick
+// included); otherwise Step reads the tick itself. Either way nothing
+// further happens until the next call
+func (h *Holodeck) Step() (*types.Tick, error) {
+	if !h.IsRunning() {
+		return nil, fmt.Errorf("holodeck not running")
+	}
+
+	if result, ok := h.step.advance(); ok {
+		return result.tick, result.err
+	}
+
+	ctx, cancel := h.tickContext(context.Background())
+	defer cancel()
+	tick, err := h.nextTick(ctx)
+	if err == nil {
+		h.step.count()
+	}
+	return tick, err
+}
+
+// GetStepCount returns the number of ticks advanced by Step
+func (h *Holodeck) GetStepCount() int64 {
+	h.step.mu.Lock()
+	defer h.step.mu.Unlock()
+	return h.step.steps
+}
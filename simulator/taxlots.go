@@ -0,0 +1,186 @@
+package simulator
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"holodeck/types"
+)
+
+// ==================== TAX LOT RECORDS ====================
+
+// Holding period classifications
+const (
+	HoldingShortTerm = "SHORT_TERM"
+	HoldingLongTerm  = "LONG_TERM"
+)
+
+// LongTermHoldingPeriod is the holding period beyond which a lot is long
+// term (more than one year)
+const LongTermHoldingPeriod = 365 * 24 * time.Hour
+
+// TaxLot is one closed lot in the layout tax software imports (Form
+// 8949 style): what was sold, when it was acquired and disposed of, the
+// proceeds and the cost basis
+// For a short lot the sale comes first, so the proceeds are the opening
+// price and the cost basis the closing price
+// Amounts are in the instrument's quote currency; the opening commission
+// is added to the cost basis
+type TaxLot struct {
+	Symbol      string    `json:"symbol"`
+	LotID       string    `json:"lot_id"`
+	Side        string    `json:"side"` // LONG or SHORT
+	Quantity    float64   `json:"quantity"`
+	Acquired    time.Time `json:"acquired"`
+	Disposed    time.Time `json:"disposed"`
+	Proceeds    float64   `json:"proceeds"`
+	CostBasis   float64   `json:"cost_basis"`
+	GainLoss    float64   `json:"gain_loss"`
+	HoldingDays int       `json:"holding_days"`
+	Term        string    `json:"term"` // SHORT_TERM or LONG_TERM
+}
+
+// TaxLotReport lists every closed lot of a session
+type TaxLotReport struct {
+	SessionID   string    `json:"session_id"`
+	Currency    string    `json:"currency"`
+	LotMatching string    `json:"lot_matching"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Lots        []TaxLot  `json:"lots"`
+
+	// MoneyDecimals is the precision amounts are written with
+	MoneyDecimals int `json:"money_decimals"`
+}
+
+// GetTotals returns the short and long term gain/loss
+func (tr *TaxLotReport) GetTotals() (shortTerm, longTerm float64) {
+	for _, lot := range tr.Lots {
+		if lot.Term == HoldingLongTerm {
+			longTerm += lot.GainLoss
+		} else {
+			shortTerm += lot.GainLoss
+		}
+	}
+	return shortTerm, longTerm
+}
+
+// ==================== REPORT BUILDING ====================
+
+// GetTaxLotReport builds the tax lot report from every symbol's closed
+// lots, ordered by disposal time
+func (h *Holodeck) GetTaxLotReport() *TaxLotReport {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	report := &TaxLotReport{
+		SessionID:     h.config.SessionID,
+		Currency:      h.config.Config.Instrument.QuoteCurrency,
+		LotMatching:   h.config.Config.Account.LotMatching,
+		GeneratedAt:   time.Now(),
+		Lots:          make([]TaxLot, 0),
+		MoneyDecimals: h.getMoneyDecimals(),
+	}
+	if report.Currency == "" {
+		report.Currency = h.config.Config.Account.Currency
+	}
+
+	for symbol, pos := range h.state.Positions {
+		contractSize := h.contractSizeFor(symbol)
+		for _, closed := range pos.GetClosedLots() {
+			quantity := closed.Size * contractSize
+			proceeds := closed.ExitPrice * quantity
+			cost := closed.EntryPrice * quantity
+			if closed.Side == types.PositionStatusShort {
+				proceeds, cost = cost, proceeds
+			}
+			cost += closed.Commission
+
+			holding := closed.GetHoldingPeriod()
+			term := HoldingShortTerm
+			if holding > LongTermHoldingPeriod {
+				term = HoldingLongTerm
+			}
+
+			report.Lots = append(report.Lots, TaxLot{
+				Symbol:      symbol,
+				LotID:       closed.LotID,
+				Side:        closed.Side,
+				Quantity:    quantity,
+				Acquired:    closed.OpenTime,
+				Disposed:    closed.CloseTime,
+				Proceeds:    proceeds,
+				CostBasis:   cost,
+				GainLoss:    proceeds - cost,
+				HoldingDays: int(holding / (24 * time.Hour)),
+				Term:        term,
+			})
+		}
+	}
+
+	sort.SliceStable(report.Lots, func(i, j int) bool {
+		a, b := report.Lots[i], report.Lots[j]
+		if !a.Disposed.Equal(b.Disposed) {
+			return a.Disposed.Before(b.Disposed)
+		}
+		return a.Symbol < b.Symbol
+	})
+
+	return report
+}
+
+// ==================== REPORT EXPORT ====================
+
+// taxLotCSVHeader is the column layout of the tax lot CSV
+var taxLotCSVHeader = []string{
+	"symbol", "lot_id", "side", "quantity", "date_acquired", "date_disposed",
+	"proceeds", "cost_basis", "gain_loss", "holding_days", "term",
+}
+
+// WriteCSV writes the lots as CSV, one row per closed lot
+func (tr *TaxLotReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(taxLotCSVHeader)
+
+	// Quantities are trimmed to 8 decimals to drop float noise from lot splits
+	f64 := func(v float64) string { return strconv.FormatFloat(math.Round(v*1e8)/1e8, 'f', -1, 64) }
+	money := func(v float64) string { return types.FormatMoney(v, tr.MoneyDecimals) }
+	for _, lot := range tr.Lots {
+		cw.Write([]string{
+			lot.Symbol,
+			lot.LotID,
+			lot.Side,
+			f64(lot.Quantity),
+			lot.Acquired.Format(time.RFC3339),
+			lot.Disposed.Format(time.RFC3339),
+			money(lot.Proceeds),
+			money(lot.CostBasis),
+			money(lot.GainLoss),
+			strconv.Itoa(lot.HoldingDays),
+			lot.Term,
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportTaxLots writes the tax lot report to a CSV file
+func (h *Holodeck) ExportTaxLots(path string) error {
+	report := h.GetTaxLotReport()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create tax lot report: %w", err)
+	}
+	if err := report.WriteCSV(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,115 @@
+package simulator
+
+import (
+	"fmt"
+	"time"
+
+	"holodeck/speed"
+)
+
+// ==================== TICK BATCHING ====================
+
+// batchTracker times the batches a batching speed controller sleeps
+// between with a speed.BatchTimer, so batched runs report tick times per
+// batch without the agent loop doing any timing
+type batchTracker struct {
+	timer *speed.BatchTimer
+	seen  int64 // Controller batches seen
+
+	batches   int64
+	ticks     int64
+	wallClock time.Duration
+	last      speed.BatchStatistics
+}
+
+// newBatchTracker starts timing a batch
+func newBatchTracker(controller *speed.SpeedController, seen int64) *batchTracker {
+	timer := speed.NewBatchTimer(controller, int64(controller.GetBatchSize()))
+	timer.StartBatch()
+	return &batchTracker{timer: timer, seen: seen}
+}
+
+// record records a tick's processing time, closing the batch when the
+// controller has slept since the previous tick
+func (bt *batchTracker) record(processing time.Duration, controllerBatches int64) {
+	bt.timer.RecordTick(processing)
+	if controllerBatches <= bt.seen {
+		return
+	}
+
+	bt.seen = controllerBatches
+	bt.last = bt.timer.EndBatch()
+	bt.batches++
+	bt.ticks += bt.last.TicksProcessed
+	bt.wallClock += bt.last.WallClockTime
+	bt.timer.StartBatch()
+}
+
+// GetStatistics returns batch timing statistics
+func (bt *batchTracker) GetStatistics() map[string]interface{} {
+	stats := map[string]interface{}{
+		"batches_timed": bt.batches,
+		"ticks_batched": bt.ticks,
+	}
+	if bt.batches > 0 {
+		stats["average_ticks_per_batch"] = float64(bt.ticks) / float64(bt.batches)
+		stats["average_batch_time"] = (bt.wallClock / time.Duration(bt.batches)).String()
+		stats["last_batch_ticks"] = bt.last.TicksProcessed
+		stats["last_batch_time"] = bt.last.WallClockTime.String()
+		stats["last_batch_avg_tick_time"] = bt.last.AverageTickTime.String()
+		stats["last_batch_max_tick_time"] = bt.last.MaxTickTime.String()
+		stats["last_batch_ticks_per_second"] = bt.last.TicksPerSecond
+	}
+	return stats
+}
+
+// ==================== HOLODECK INTEGRATION ====================
+
+// SetBatchSize makes GetNextTick sleep once per batch of n ticks instead
+// of after every tick (n <= 1 turns batching off), amortizing timer
+// overhead at very high multipliers; batches are timed automatically and
+// reported by GetBatchStatistics and the metrics
+// Requires a speed controller
+func (h *Holodeck) SetBatchSize(n int) error {
+	if h.speed == nil {
+		return fmt.Errorf("speed controller not set")
+	}
+	return h.speed.SetBatchSize(n)
+}
+
+// SetAutoBatch sizes batches automatically, sleeping only once the waits
+// carried forward reach speed.DefaultBatchSleep
+// Requires a speed controller
+func (h *Holodeck) SetAutoBatch(enabled bool) error {
+	if h.speed == nil {
+		return fmt.Errorf("speed controller not set")
+	}
+	h.speed.SetAutoBatch(enabled)
+	return nil
+}
+
+// GetBatchStatistics returns the timing of the latest completed batch, or
+// nil before one has completed
+func (h *Holodeck) GetBatchStatistics() *speed.BatchStatistics {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.batch == nil || h.batch.batches == 0 {
+		return nil
+	}
+	last := h.batch.last
+	return &last
+}
+
+// trackBatch times a paced tick in its batch once the controller batches
+// Caller must hold the write lock
+func (h *Holodeck) trackBatch(processing time.Duration) {
+	batches := h.speed.GetBatchCount()
+	if h.batch == nil {
+		if !h.speed.IsBatching() {
+			return
+		}
+		h.batch = newBatchTracker(h.speed, batches)
+	}
+	h.batch.record(processing, batches)
+}
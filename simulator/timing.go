@@ -0,0 +1,210 @@
+package simulator
+
+import (
+	"fmt"
+	"time"
+)
+
+// ==================== TIMING STATS ====================
+
+// TimingStats splits wall time between agent code and the engine
+// Agent time is spent in callbacks plus the time between returning control
+// to the caller and the caller's next call into Holodeck; engine time is
+// spent in the reader, executor and logger
+type TimingStats struct {
+	ReaderTime   time.Duration
+	ExecutorTime time.Duration
+	LoggerTime   time.Duration
+	CallbackTime time.Duration // Time inside OnTick/OnExecution/... callbacks
+	ExternalTime time.Duration // Time in caller code between Holodeck calls
+
+	ReaderCalls   int64
+	ExecutorCalls int64
+	LoggerCalls   int64
+	CallbackCalls int64
+
+	// lastReturn is when control was last handed back to the caller
+	lastReturn time.Time
+
+	// disabled turns recording off (max speed mode)
+	disabled bool
+}
+
+// NewTimingStats creates empty timing statistics
+func NewTimingStats() *TimingStats {
+	return &TimingStats{}
+}
+
+// ==================== RECORDING ====================
+
+// setEnabled turns recording on or off
+func (ts *TimingStats) setEnabled(enabled bool) {
+	ts.disabled = !enabled
+	ts.lastReturn = time.Time{}
+}
+
+// start returns the start time of a timed call, or the zero time when
+// recording is off so the hot path makes no clock reads
+func (ts *TimingStats) start() time.Time {
+	if ts.disabled {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// enter records caller time since the last return from Holodeck
+func (ts *TimingStats) enter() {
+	if !ts.disabled && !ts.lastReturn.IsZero() {
+		ts.ExternalTime += time.Since(ts.lastReturn)
+	}
+}
+
+// leave marks control returning to the caller
+func (ts *TimingStats) leave() {
+	if ts.disabled {
+		return
+	}
+	ts.lastReturn = time.Now()
+}
+
+// pause stops attributing time to the caller (e.g. when the session stops)
+func (ts *TimingStats) pause() {
+	ts.lastReturn = time.Time{}
+}
+
+// addReader records time spent in the tick reader
+func (ts *TimingStats) addReader(start time.Time) {
+	if ts.disabled {
+		return
+	}
+	ts.ReaderTime += time.Since(start)
+	ts.ReaderCalls++
+}
+
+// addExecutor records time spent in the order executor
+func (ts *TimingStats) addExecutor(start time.Time) {
+	if ts.disabled {
+		return
+	}
+	ts.ExecutorTime += time.Since(start)
+	ts.ExecutorCalls++
+}
+
+// addLogger records time spent in the logger
+func (ts *TimingStats) addLogger(start time.Time) {
+	if ts.disabled {
+		return
+	}
+	ts.LoggerTime += time.Since(start)
+	ts.LoggerCalls++
+}
+
+// addCallback records time spent in agent callbacks
+func (ts *TimingStats) addCallback(start time.Time) {
+	if ts.disabled {
+		return
+	}
+	ts.CallbackTime += time.Since(start)
+	ts.CallbackCalls++
+}
+
+// ==================== QUERIES ====================
+
+// GetEngineTime returns total time spent in the engine
+func (ts *TimingStats) GetEngineTime() time.Duration {
+	return ts.ReaderTime + ts.ExecutorTime + ts.LoggerTime
+}
+
+// GetAgentTime returns total time spent in agent code
+func (ts *TimingStats) GetAgentTime() time.Duration {
+	return ts.CallbackTime + ts.ExternalTime
+}
+
+// GetAgentPercent returns agent time as a percentage of agent + engine time
+func (ts *TimingStats) GetAgentPercent() float64 {
+	total := ts.GetAgentTime() + ts.GetEngineTime()
+	if total == 0 {
+		return 0
+	}
+	return float64(ts.GetAgentTime()) / float64(total) * 100
+}
+
+// GetBottleneck names the side that dominates wall time
+func (ts *TimingStats) GetBottleneck() string {
+	agent := ts.GetAgentTime()
+	engine := ts.GetEngineTime()
+	switch {
+	case agent == 0 && engine == 0:
+		return "NONE"
+	case agent > engine:
+		return "AGENT"
+	default:
+		return "ENGINE"
+	}
+}
+
+// Snapshot returns a copy of the current statistics
+func (ts *TimingStats) Snapshot() *TimingStats {
+	snapshot := *ts
+	return &snapshot
+}
+
+// GetStatistics returns timing statistics
+func (ts *TimingStats) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"reader_time":    ts.ReaderTime,
+		"executor_time":  ts.ExecutorTime,
+		"logger_time":    ts.LoggerTime,
+		"callback_time":  ts.CallbackTime,
+		"external_time":  ts.ExternalTime,
+		"engine_time":    ts.GetEngineTime(),
+		"agent_time":     ts.GetAgentTime(),
+		"agent_percent":  ts.GetAgentPercent(),
+		"bottleneck":     ts.GetBottleneck(),
+		"reader_calls":   ts.ReaderCalls,
+		"executor_calls": ts.ExecutorCalls,
+		"logger_calls":   ts.LoggerCalls,
+		"callback_calls": ts.CallbackCalls,
+	}
+}
+
+// Reset clears all timing statistics
+func (ts *TimingStats) Reset() {
+	*ts = TimingStats{disabled: ts.disabled}
+}
+
+// ==================== DEBUG ====================
+
+// String returns a human-readable representation
+func (ts *TimingStats) String() string {
+	return fmt.Sprintf(
+		"TimingStats[Agent:%v (%.1f%%), Engine:%v, Bottleneck:%s]",
+		ts.GetAgentTime(),
+		ts.GetAgentPercent(),
+		ts.GetEngineTime(),
+		ts.GetBottleneck(),
+	)
+}
+
+// DebugString returns detailed timing information
+func (ts *TimingStats) DebugString() string {
+	return fmt.Sprintf(
+		"Timing Stats:\n"+
+			"  Agent:                %v (%.1f%%)\n"+
+			"    Callbacks:          %v (%d calls)\n"+
+			"    Between Calls:      %v\n"+
+			"  Engine:               %v\n"+
+			"    Reader:             %v (%d calls)\n"+
+			"    Executor:           %v (%d calls)\n"+
+			"    Logger:             %v (%d calls)\n"+
+			"  Bottleneck:           %s",
+		ts.GetAgentTime(), ts.GetAgentPercent(),
+		ts.CallbackTime, ts.CallbackCalls,
+		ts.ExternalTime,
+		ts.GetEngineTime(),
+		ts.ReaderTime, ts.ReaderCalls,
+		ts.ExecutorTime, ts.ExecutorCalls,
+		ts.LoggerTime, ts.LoggerCalls,
+		ts.GetBottleneck(),
+	)
+}
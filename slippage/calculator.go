@@ -10,8 +10,10 @@ import (
 
 // SlippageCalculator orchestrates slippage calculation using depth and momentum models
 type SlippageCalculator struct {
+	model         string // types.SlippageModelDepth, Impact, None, ...
 	depthModel    *DepthModel
 	momentumModel *MomentumModel
+	impactModel   *ImpactModel
 
 	// Statistics
 	totalSlippage      float64
@@ -25,13 +27,47 @@ type SlippageCalculator struct {
 
 // NewSlippageCalculator creates a new slippage calculator
 func NewSlippageCalculator() *SlippageCalculator {
+	return newSlippageCalculator(types.SlippageModelDepth)
+}
+
+// NewSlippageCalculatorForModel creates a calculator using the named base
+// model: "depth" (the default when empty), "impact" for the square-root
+// impact model or "none" for no slippage; "fixed" and "momentum" keep
+// their original depth-plus-momentum behaviour
+// Returns an error for any other model
+func NewSlippageCalculatorForModel(model string) (*SlippageCalculator, error) {
+	switch model {
+	case "":
+		model = types.SlippageModelDepth
+	case types.SlippageModelDepth, types.SlippageModelImpact, types.SlippageModelNone,
+		types.SlippageModelFixed, types.SlippageModelMomentum:
+	default:
+		return nil, fmt.Errorf("unsupported slippage model: %s", model)
+	}
+	return newSlippageCalculator(model), nil
+}
+
+// newSlippageCalculator creates a calculator for a supported model
+func newSlippageCalculator(model string) *SlippageCalculator {
 	return &SlippageCalculator{
+		model:         model,
 		depthModel:    NewDepthModel(),
 		momentumModel: NewMomentumModel(),
+		impactModel:   NewImpactModel(),
 		minSlippage:   1e9, // Initialize to large value
 	}
 }
 
+// GetModel returns the base slippage model name
+func (sc *SlippageCalculator) GetModel() string {
+	return sc.model
+}
+
+// GetImpactModel returns the impact model (for tuning the coefficient)
+func (sc *SlippageCalculator) GetImpactModel() *ImpactModel {
+	return sc.impactModel
+}
+
 // ==================== CORE CALCULATION ====================
 
 // CalculateSlippage calculates slippage based on order size and available depth
@@ -61,14 +97,27 @@ func (sc *SlippageCalculator) CalculateSlippage(
 		return 0, types.NewOrderRejectedError("instrument cannot be nil")
 	}
 
-	// Calculate depth-based slippage
-	depthSlippage, err := sc.depthModel.CalculateSlippage(orderSize, availableDepth, volatility)
+	// Calculate base slippage from the selected model
+	var baseSlippage float64
+	var err error
+	switch sc.model {
+	case types.SlippageModelImpact:
+		averageVolume := 0.0
+		if cfg := instrument.GetConfig(); cfg != nil {
+			averageVolume = float64(cfg.AverageVolume)
+		}
+		baseSlippage, err = sc.impactModel.CalculateSlippage(orderSize, averageVolume, volatility)
+	case types.SlippageModelNone:
+		// Fills at the quote; momentum has nothing to scale
+	default:
+		baseSlippage, err = sc.depthModel.CalculateSlippage(orderSize, availableDepth, volatility)
+	}
 	if err != nil {
 		return 0, err
 	}
 
 	// Apply momentum adjustment
-	adjustedSlippage, err := sc.momentumModel.AdjustSlippage(depthSlippage, momentum, tick)
+	adjustedSlippage, err := sc.momentumModel.AdjustSlippage(baseSlippage, momentum, tick)
 	if err != nil {
 		return 0, err
 	}
@@ -87,6 +136,15 @@ func (sc *SlippageCalculator) CalculateSlippage(
 	return adjustedSlippage, nil
 }
 
+// ObserveTick feeds a market tick to the models that track the market
+// between orders (the impact model's running average volume)
+func (sc *SlippageCalculator) ObserveTick(tick *types.Tick) {
+	if tick == nil {
+		return
+	}
+	sc.impactModel.ObserveVolume(tick.Volume)
+}
+
 // CalculateFillPrice calculates the fill price accounting for slippage
 // Parameters:
 //   - midPrice: Mid-market price (bid + ask) / 2
@@ -198,6 +256,8 @@ func (sc *SlippageCalculator) GetStatistics() map[string]interface{} {
 		"min_slippage":         sc.GetMinSlippage(),
 		"depth_model_stats":    sc.depthModel.GetStatistics(),
 		"momentum_model_stats": sc.momentumModel.GetStatistics(),
+		"impact_model_stats":   sc.impactModel.GetStatistics(),
+		"model":                sc.model,
 	}
 }
 
@@ -226,7 +286,8 @@ func (sc *SlippageCalculator) DebugString() string {
 			"\n"+
 			"  Sub-models:\n"+
 			"    Depth Model:         %s\n"+
-			"    Momentum Model:      %s",
+			"    Momentum Model:      %s\n"+
+			"    Impact Model:        %s",
 		sc.totalSlippage,
 		sc.slippageCount,
 		sc.GetAverageSlippage(),
@@ -234,6 +295,7 @@ func (sc *SlippageCalculator) DebugString() string {
 		sc.GetMinSlippage(),
 		sc.depthModel.String(),
 		sc.momentumModel.String(),
+		sc.impactModel.String(),
 	)
 }
 
@@ -246,6 +308,7 @@ func (sc *SlippageCalculator) Reset() {
 	sc.minSlippage = 1e9
 	sc.depthModel.Reset()
 	sc.momentumModel.Reset()
+	sc.impactModel.Reset()
 }
 
 // ==================== SLIPPAGE INPUT ====================
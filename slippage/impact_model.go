@@ -0,0 +1,185 @@
+package slippage
+
+import (
+	"fmt"
+	"math"
+)
+
+// ==================== IMPACT MODEL ====================
+
+// ImpactModel calculates slippage using the square-root market impact law
+// Formula: slippage = coefficient × volatility × sqrt(order_size / average_volume)
+// Impact grows sub-linearly, so large orders are penalized less harshly than
+// the linear depth model but never become free
+type ImpactModel struct {
+	// Constants
+	Coefficient float64 // Impact scaling factor (default 1.0)
+
+	// Observed market volume (used when no average volume is configured)
+	volumeSum   float64
+	volumeCount int64
+
+	// Statistics
+	totalSlippage     float64
+	slippageCount     int64
+	avgParticipation  float64
+	maxParticipation  float64
+	lastAverageVolume float64
+}
+
+// ==================== MODEL CREATION ====================
+
+// NewImpactModel creates a new impact model
+func NewImpactModel() *ImpactModel {
+	return &ImpactModel{
+		Coefficient: 1.0,
+	}
+}
+
+// ==================== VOLUME OBSERVATION ====================
+
+// ObserveVolume records a tick volume for the running average
+func (im *ImpactModel) ObserveVolume(volume int64) {
+	if volume <= 0 {
+		return
+	}
+	im.volumeSum += float64(volume)
+	im.volumeCount++
+}
+
+// GetObservedAverageVolume returns the running average of observed volume
+func (im *ImpactModel) GetObservedAverageVolume() float64 {
+	if im.volumeCount == 0 {
+		return 0
+	}
+	return im.volumeSum / float64(im.volumeCount)
+}
+
+// ==================== CORE CALCULATION ====================
+
+// CalculateSlippage calculates slippage from square-root market impact
+// Formula: slippage = coefficient × volatility × sqrt(order_size / average_volume)
+// Parameters:
+//   - orderSize: Size of the order
+//   - averageVolume: Typical traded volume; 0 uses the observed running average
+//   - volatility: Market volatility (0.0 to 1.0+)
+//
+// Returns: Slippage in pips/units
+func (im *ImpactModel) CalculateSlippage(
+	orderSize float64,
+	averageVolume float64,
+	volatility float64,
+) (float64, error) {
+
+	if orderSize < 0 {
+		orderSize = -orderSize
+	}
+
+	if averageVolume <= 0 {
+		averageVolume = im.GetObservedAverageVolume()
+	}
+
+	// Prevent division by zero
+	if averageVolume <= 0 {
+		averageVolume = 0.001 // Minimum volume
+	}
+
+	participation := orderSize / averageVolume
+	slippage := im.Coefficient * volatility * math.Sqrt(participation)
+
+	// Track statistics
+	im.totalSlippage += slippage
+	im.slippageCount++
+	im.avgParticipation = (im.avgParticipation*float64(im.slippageCount-1) + participation) / float64(im.slippageCount)
+	if participation > im.maxParticipation {
+		im.maxParticipation = participation
+	}
+	im.lastAverageVolume = averageVolume
+
+	return slippage, nil
+}
+
+// SetCoefficient sets the impact scaling factor
+func (im *ImpactModel) SetCoefficient(coefficient float64) {
+	if coefficient > 0 {
+		im.Coefficient = coefficient
+	}
+}
+
+// ==================== STATISTICS ====================
+
+// GetTotalSlippage returns total slippage from impact model
+func (im *ImpactModel) GetTotalSlippage() float64 {
+	return im.totalSlippage
+}
+
+// GetSlippageCount returns number of slippage calculations
+func (im *ImpactModel) GetSlippageCount() int64 {
+	return im.slippageCount
+}
+
+// GetAverageSlippage returns average slippage
+func (im *ImpactModel) GetAverageSlippage() float64 {
+	if im.slippageCount == 0 {
+		return 0
+	}
+	return im.totalSlippage / float64(im.slippageCount)
+}
+
+// GetStatistics returns comprehensive impact model statistics
+func (im *ImpactModel) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"coefficient":         im.Coefficient,
+		"total_slippage":      im.totalSlippage,
+		"slippage_count":      im.slippageCount,
+		"average_slippage":    im.GetAverageSlippage(),
+		"avg_participation":   im.avgParticipation,
+		"max_participation":   im.maxParticipation,
+		"observed_avg_volume": im.GetObservedAverageVolume(),
+		"last_average_volume": im.lastAverageVolume,
+	}
+}
+
+// ==================== DEBUG ====================
+
+// String returns a human-readable representation
+func (im *ImpactModel) String() string {
+	return fmt.Sprintf(
+		"ImpactModel[Total:%.4f, Avg:%.4f, Participation:%.4f]",
+		im.totalSlippage,
+		im.GetAverageSlippage(),
+		im.avgParticipation,
+	)
+}
+
+// DebugString returns detailed debug information
+func (im *ImpactModel) DebugString() string {
+	return fmt.Sprintf(
+		"Impact Model:\n"+
+			"  Coefficient:           %.4f\n"+
+			"  Total Slippage:        %.4f pips\n"+
+			"  Slippage Count:        %d\n"+
+			"  Average Slippage:      %.4f pips\n"+
+			"  Avg Participation:     %.4f\n"+
+			"  Max Participation:     %.4f\n"+
+			"  Observed Avg Volume:   %.2f",
+		im.Coefficient,
+		im.totalSlippage,
+		im.slippageCount,
+		im.GetAverageSlippage(),
+		im.avgParticipation,
+		im.maxParticipation,
+		im.GetObservedAverageVolume(),
+	)
+}
+
+// Reset resets model statistics and observed volume
+func (im *ImpactModel) Reset() {
+	im.volumeSum = 0
+	im.volumeCount = 0
+	im.totalSlippage = 0
+	im.slippageCount = 0
+	im.avgParticipation = 0
+	im.maxParticipation = 0
+	im.lastAverageVolume = 0
+}
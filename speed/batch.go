@@ -0,0 +1,112 @@
+package speed
+
+import (
+	"fmt"
+	"time"
+)
+
+// ==================== TICK BATCHING ====================
+
+// DefaultBatchSleep is the shortest wait auto batching sleeps for: below
+// about a millisecond timer overhead and granularity dominate, so the
+// actual speed falls short of high multipliers
+const DefaultBatchSleep = time.Millisecond
+
+// SetBatchSize makes the controller sleep once per batch of n ticks
+// instead of after every tick (n <= 1 sleeps every tick)
+// The waits of the ticks in between are carried into the batch's one
+// sleep, so the speed is unchanged on average while the timer overhead is
+// paid once per batch; ticks within a batch are emitted back to back
+func (sc *SpeedController) SetBatchSize(n int) error {
+	if n < 0 {
+		return fmt.Errorf("batch size cannot be negative")
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.batchSize = n
+	sc.batchTicks = 0
+	return nil
+}
+
+// GetBatchSize returns the batch size set by SetBatchSize
+func (sc *SpeedController) GetBatchSize() int {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.batchSize
+}
+
+// SetAutoBatch sizes batches automatically: waits are carried forward
+// until they add up to DefaultBatchSleep, which at high multipliers
+// batches as many ticks as it takes. A fixed batch size takes precedence
+func (sc *SpeedController) SetAutoBatch(enabled bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.autoBatch = enabled
+	sc.batchTicks = 0
+}
+
+// IsAutoBatch returns whether batches are sized automatically
+func (sc *SpeedController) IsAutoBatch() bool {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.autoBatch
+}
+
+// IsBatching returns whether sleeps are batched, fixed or automatic
+func (sc *SpeedController) IsBatching() bool {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.batchSize > 1 || sc.autoBatch
+}
+
+// deferSleep decides whether a tick's wait of owed is deferred to the end
+// of its batch (true) or slept now, closing the batch
+// Caller must hold mu
+func (sc *SpeedController) deferSleep(owed time.Duration) bool {
+	switch {
+	case sc.batchSize > 1:
+		if sc.batchTicks+1 < sc.batchSize {
+			sc.batchTicks++
+			sc.deferredSleeps++
+			return true
+		}
+	case sc.autoBatch:
+		if owed < DefaultBatchSleep {
+			sc.batchTicks++
+			sc.deferredSleeps++
+			return true
+		}
+	default:
+		return false
+	}
+
+	sc.batchTicks = 0
+	sc.batches++
+	return false
+}
+
+// getBatchStatistics returns batching statistics
+// Caller must hold mu
+func (sc *SpeedController) getBatchStatistics() map[string]interface{} {
+	var average float64
+	if sc.batches > 0 {
+		average = float64(sc.deferredSleeps+sc.batches) / float64(sc.batches)
+	}
+	return map[string]interface{}{
+		"batch_size":         sc.batchSize,
+		"auto_batch":         sc.autoBatch,
+		"batches":            sc.batches,
+		"deferred_sleeps":    sc.deferredSleeps,
+		"average_batch_size": average,
+	}
+}
+
+// GetBatchCount returns the number of batches closed by a sleep
+func (sc *SpeedController) GetBatchCount() int64 {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.batches
+}
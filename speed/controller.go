@@ -1,9 +1,15 @@
 package speed
 
 import (
+	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"holodeck/types"
 )
 
 // ==================== SPEED CONTROLLER ====================
@@ -15,12 +21,46 @@ type SpeedController struct {
 	minMultiplier float64
 	maxMultiplier float64
 
-	// Timing
+	// Timing, on the wall clock unless SetClock gives another
+	clock             types.Clock
 	startTime         time.Time
 	baseTickDuration  time.Duration
 	targetTimePerTick time.Duration
 	lastTickTime      time.Time
 
+	// Timestamp pacing: the wall-clock time the anchor tick was due and
+	// the span of tick timestamps seen
+	pacing            PacingMode
+	anchorWall        time.Time
+	anchorTick        time.Time
+	firstTickTime     time.Time
+	lastTickTimestamp time.Time
+
+	// Ticks-per-second throttle (0 = multiplier mode) and the ticks
+	// emitted since its anchor
+	ticksPerSecond float64
+	rateTicks      int64
+
+	// Realtime pacing: align to the time of day, and how late the latest
+	// tick was emitted
+	realtimeAlign bool
+	realtimeLag   time.Duration
+
+	// Batching: sleep once per batchSize ticks, or once the carried wait
+	// reaches DefaultBatchSleep (autoBatch); batchOwed is the wait carried
+	// forward in fixed pacing
+	batchSize      int
+	autoBatch      bool
+	batchTicks     int
+	batchOwed      time.Duration
+	batches        int64
+	deferredSleeps int64
+
+	// Idle gaps fast-forwarded over in timestamp pacing
+	idleThreshold time.Duration
+	idleSkips     int64
+	idleSkipped   time.Duration
+
 	// Statistics
 	ticksProcessed   int64
 	totalProcessTime time.Duration
@@ -28,10 +68,23 @@ type SpeedController struct {
 	skippedSleeps    int64
 	actualMultiplier float64
 
+	// Optional adaptive governor
+	governor *SpeedGovernor
+
+	// Optional schedule by simulated time; baseMultiplier is the speed
+	// set by SetSpeed, in force outside the schedule
+	schedule        *SpeedSchedule
+	baseMultiplier  float64
+	scheduled       bool
+	scheduleChanges int64
+
 	// State
 	mu         sync.RWMutex
 	paused     bool
 	pausedTime time.Time
+
+	// Max speed bypasses waiting and bookkeeping; read without mu
+	maxSpeed atomic.Bool
 }
 
 // ==================== CREATION ====================
@@ -40,6 +93,7 @@ type SpeedController struct {
 func NewSpeedController() *SpeedController {
 	return &SpeedController{
 		multiplier:       1.0,
+		baseMultiplier:   1.0,
 		minMultiplier:    0.1,
 		maxMultiplier:    10000.0,
 		baseTickDuration: time.Second,
@@ -48,6 +102,45 @@ func NewSpeedController() *SpeedController {
 	}
 }
 
+// SetClock replaces the wall clock for pacing and statistics (nil restores
+// it); with a types.ManualClock waits advance the clock instead of
+// sleeping, so runs and statistics are deterministic
+// Call before the run starts
+func (sc *SpeedController) SetClock(clock types.Clock) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.clock = clock
+	sc.startTime = sc.now()
+	sc.lastTickTime = sc.startTime
+	sc.anchorWall = time.Time{}
+}
+
+// GetClock returns the clock set by SetClock (nil = wall clock)
+func (sc *SpeedController) GetClock() types.Clock {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.clock
+}
+
+// now returns the controller clock's time
+func (sc *SpeedController) now() time.Time {
+	if sc.clock == nil {
+		return time.Now()
+	}
+	return sc.clock.Now()
+}
+
+// since returns the time elapsed on the controller clock since t
+func (sc *SpeedController) since(t time.Time) time.Duration {
+	return types.Since(sc.clock, t)
+}
+
+// sleep waits on the controller clock, giving up when ctx is done
+func (sc *SpeedController) sleep(ctx context.Context, d time.Duration) error {
+	return types.SleepContext(ctx, sc.clock, d)
+}
+
 // ==================== SPEED CONTROL ====================
 
 // SetSpeed sets the simulation speed multiplier
@@ -65,12 +158,76 @@ func (sc *SpeedController) SetSpeed(multiplier float64) error {
 	sc.mu.Lock()
 	defer sc.mu.Unlock()
 
-	sc.multiplier = multiplier
-	sc.calculateTargetTime()
-
+	sc.baseMultiplier = multiplier
+	sc.ticksPerSecond = 0
+	sc.setMultiplier(multiplier)
 	return nil
 }
 
+// SetMaxSpeed turns max speed mode on or off
+// At max speed WaitTick and WaitForTick return at once without taking the
+// lock or recording statistics, for as-fast-as-possible batch backtests
+func (sc *SpeedController) SetMaxSpeed(enabled bool) {
+	sc.maxSpeed.Store(enabled)
+}
+
+// IsMaxSpeed returns whether max speed mode is on
+func (sc *SpeedController) IsMaxSpeed() bool {
+	return sc.maxSpeed.Load()
+}
+
+// ParseSpeed parses a speed setting: "max" or a multiplier
+func ParseSpeed(value string) (multiplier float64, max bool, err error) {
+	if strings.EqualFold(strings.TrimSpace(value), "max") {
+		return 0, true, nil
+	}
+	multiplier, err = strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid speed %q: expected a multiplier or \"max\"", value)
+	}
+	return multiplier, false, nil
+}
+
+// SetGovernor attaches an adaptive speed governor (nil disables it)
+// The current multiplier becomes the governor's target speed; removing
+// the governor restores the speed it was recovering toward
+func (sc *SpeedController) SetGovernor(governor *SpeedGovernor) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	previous := sc.governor
+	sc.governor = governor
+	if governor != nil {
+		governor.SetTargetMultiplier(sc.multiplier)
+	} else if previous != nil && previous.targetMultiplier > 0 && previous.targetMultiplier != sc.multiplier {
+		sc.setMultiplier(previous.targetMultiplier)
+	}
+}
+
+// GetGovernor returns the attached speed governor, if any
+func (sc *SpeedController) GetGovernor() *SpeedGovernor {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.governor
+}
+
+// govern lets the governor adjust the multiplier; caller must hold mu
+func (sc *SpeedController) govern(skipped bool) {
+	if sc.governor == nil || sc.ticksPerSecond > 0 {
+		return
+	}
+
+	next := sc.governor.Observe(skipped, sc.multiplier)
+	if next < sc.minMultiplier {
+		next = sc.minMultiplier
+	}
+	if next != sc.multiplier {
+		sc.multiplier = next
+		sc.calculateTargetTime()
+		sc.reanchor()
+	}
+}
+
 // GetSpeed returns the current speed multiplier
 func (sc *SpeedController) GetSpeed() float64 {
 	sc.mu.RLock()
@@ -78,8 +235,14 @@ func (sc *SpeedController) GetSpeed() float64 {
 	return sc.multiplier
 }
 
-// calculateTargetTime calculates target time per tick based on multiplier
+// calculateTargetTime calculates target time per tick based on multiplier,
+// or on the tick rate when one is set
 func (sc *SpeedController) calculateTargetTime() {
+	if sc.ticksPerSecond > 0 {
+		sc.targetTimePerTick = time.Duration(float64(time.Second) / sc.ticksPerSecond)
+		return
+	}
+
 	// targetTime = baseTime / multiplier
 	// If multiplier = 100, target = 1s / 100 = 10ms
 	sc.targetTimePerTick = time.Duration(float64(sc.baseTickDuration) / sc.multiplier)
@@ -89,8 +252,23 @@ func (sc *SpeedController) calculateTargetTime() {
 
 // WaitTick waits the appropriate amount of time before the next tick
 // Pass the actual processing time for this tick for accurate timing
+// Ticks are assumed baseTickDuration apart; see WaitForTick for pacing by
+// tick timestamps. With a tick rate set it is waitRate
 func (sc *SpeedController) WaitTick(processingTime time.Duration) error {
+	return sc.WaitTickContext(context.Background(), processingTime)
+}
+
+// WaitTickContext is WaitTick that stops waiting when ctx is done,
+// returning ctx.Err()
+func (sc *SpeedController) WaitTickContext(ctx context.Context, processingTime time.Duration) error {
+	if sc.maxSpeed.Load() {
+		return nil
+	}
+
 	sc.mu.Lock()
+	if sc.ticksPerSecond > 0 {
+		return sc.waitRate(ctx, processingTime)
+	}
 	defer sc.mu.Unlock()
 
 	// Check if paused
@@ -108,14 +286,24 @@ func (sc *SpeedController) WaitTick(processingTime time.Duration) error {
 	// If processing took longer than target, no sleep needed
 	if requiredSleep <= 0 {
 		sc.skippedSleeps++
+		sc.govern(true)
 		return nil
 	}
+	sc.govern(false)
+
+	// Carry the wait to the end of the batch, if batching
+	owed := sc.batchOwed + requiredSleep
+	if sc.deferSleep(owed) {
+		sc.batchOwed = owed
+		return nil
+	}
+	sc.batchOwed = 0
 
 	// Sleep for the required time
-	time.Sleep(requiredSleep)
-	sc.totalWaitTime += requiredSleep
+	err := sc.sleep(ctx, owed)
+	sc.totalWaitTime += owed
 
-	return nil
+	return err
 }
 
 // ==================== PAUSE/RESUME ====================
@@ -130,7 +318,7 @@ func (sc *SpeedController) Pause() error {
 	}
 
 	sc.paused = true
-	sc.pausedTime = time.Now()
+	sc.pausedTime = sc.now()
 	return nil
 }
 
@@ -143,9 +331,14 @@ func (sc *SpeedController) Resume() error {
 		return fmt.Errorf("simulation not paused")
 	}
 
-	// Adjust start time for pause duration
-	pauseDuration := time.Since(sc.pausedTime)
+	// Adjust start time for pause duration; realtime pacing carries on
+	// from where it paused, shifted by the pause
+	pauseDuration := sc.since(sc.pausedTime)
 	sc.startTime = sc.startTime.Add(pauseDuration)
+	if sc.pacing == PacingRealtime && !sc.anchorWall.IsZero() {
+		sc.anchorWall = sc.anchorWall.Add(pauseDuration)
+	}
+	sc.reanchor()
 
 	sc.paused = false
 	return nil
@@ -165,17 +358,21 @@ func (sc *SpeedController) GetStatistics() map[string]interface{} {
 	sc.mu.RLock()
 	defer sc.mu.RUnlock()
 
-	elapsed := time.Since(sc.startTime)
+	elapsed := sc.since(sc.startTime)
 
 	// Calculate actual multiplier (simulated time / real time)
 	var actualMultiplier float64
 	if elapsed > 0 {
-		simulatedTime := time.Duration(float64(sc.ticksProcessed) * float64(sc.baseTickDuration))
-		actualMultiplier = float64(simulatedTime) / float64(elapsed)
+		actualMultiplier = float64(sc.simulatedElapsed()) / float64(elapsed)
 	}
 
-	return map[string]interface{}{
+	stats := map[string]interface{}{
 		"configured_speed":     sc.multiplier,
+		"max_speed":            sc.maxSpeed.Load(),
+		"pacing":               sc.pacing.String(),
+		"idle_skip_threshold":  sc.idleThreshold.String(),
+		"idle_gaps_skipped":    sc.idleSkips,
+		"idle_time_skipped":    sc.idleSkipped.String(),
 		"actual_speed":         actualMultiplier,
 		"target_time_per_tick": sc.targetTimePerTick.String(),
 		"ticks_processed":      sc.ticksProcessed,
@@ -185,6 +382,28 @@ func (sc *SpeedController) GetStatistics() map[string]interface{} {
 		"elapsed_time":         elapsed.String(),
 		"is_paused":            sc.paused,
 	}
+	if sc.ticksPerSecond > 0 {
+		stats["ticks_per_second"] = sc.ticksPerSecond
+		stats["actual_ticks_per_second"] = sc.getActualTicksPerSecond()
+	}
+	if sc.pacing == PacingRealtime {
+		stats["realtime_align"] = sc.realtimeAlign
+		stats["realtime_lag"] = sc.realtimeLag.String()
+	}
+	if sc.batchSize > 1 || sc.autoBatch {
+		stats["batching"] = sc.getBatchStatistics()
+	}
+	if sc.governor != nil {
+		stats["governor"] = sc.governor.GetStatistics()
+	}
+	if sc.schedule != nil {
+		stats["base_speed"] = sc.baseMultiplier
+		stats["schedule"] = sc.schedule.String()
+		stats["schedule_active"] = sc.scheduled
+		stats["schedule_changes"] = sc.scheduleChanges
+	}
+
+	return stats
 }
 
 // PrintStatistics returns formatted statistics string
@@ -225,7 +444,7 @@ func (sc *SpeedController) GetTicksProcessed() int64 {
 func (sc *SpeedController) GetElapsedTime() time.Duration {
 	sc.mu.RLock()
 	defer sc.mu.RUnlock()
-	return time.Since(sc.startTime)
+	return sc.since(sc.startTime)
 }
 
 // GetActualMultiplier returns the actual achieved multiplier
@@ -233,13 +452,12 @@ func (sc *SpeedController) GetActualMultiplier() float64 {
 	sc.mu.RLock()
 	defer sc.mu.RUnlock()
 
-	elapsed := time.Since(sc.startTime)
+	elapsed := sc.since(sc.startTime)
 	if elapsed <= 0 {
 		return 0
 	}
 
-	simulatedTime := time.Duration(float64(sc.ticksProcessed) * float64(sc.baseTickDuration))
-	return float64(simulatedTime) / float64(elapsed)
+	return float64(sc.simulatedElapsed()) / float64(elapsed)
 }
 
 // ==================== RESET ====================
@@ -250,13 +468,31 @@ func (sc *SpeedController) Reset() error {
 	defer sc.mu.Unlock()
 
 	sc.multiplier = 1.0
-	sc.startTime = time.Now()
-	sc.lastTickTime = time.Now()
+	sc.baseMultiplier = 1.0
+	sc.scheduled = false
+	sc.scheduleChanges = 0
+	sc.startTime = sc.now()
+	sc.lastTickTime = sc.now()
 	sc.ticksProcessed = 0
 	sc.totalProcessTime = 0
 	sc.totalWaitTime = 0
 	sc.skippedSleeps = 0
 	sc.paused = false
+	sc.anchorWall = time.Time{}
+	sc.firstTickTime = time.Time{}
+	sc.lastTickTimestamp = time.Time{}
+	sc.idleSkips = 0
+	sc.idleSkipped = 0
+	sc.realtimeLag = 0
+	sc.rateTicks = 0
+	sc.batchTicks = 0
+	sc.batchOwed = 0
+	sc.batches = 0
+	sc.deferredSleeps = 0
+	if sc.governor != nil {
+		sc.governor.Reset()
+		sc.governor.SetTargetMultiplier(sc.multiplier)
+	}
 
 	sc.calculateTargetTime()
 	return nil
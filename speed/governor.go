@@ -0,0 +1,188 @@
+package speed
+
+import (
+	"fmt"
+)
+
+// ==================== SPEED GOVERNOR ====================
+
+// GovernorConfig configures the adaptive speed governor
+type GovernorConfig struct {
+	// WindowSize is the number of ticks evaluated per adjustment
+	WindowSize int64
+
+	// SlowDownRatio is the skipped-sleep ratio at which speed is reduced
+	// (e.g. 0.5 = half the ticks in the window overran their time budget)
+	SlowDownRatio float64
+
+	// SpeedUpRatio is the skipped-sleep ratio at or below which speed is raised
+	SpeedUpRatio float64
+
+	// DecreaseFactor multiplies the speed when slowing down (0 < f < 1)
+	DecreaseFactor float64
+
+	// IncreaseFactor multiplies the speed when recovering (f > 1)
+	IncreaseFactor float64
+
+	// MinMultiplier is the lowest speed the governor will select
+	MinMultiplier float64
+}
+
+// DefaultGovernorConfig returns sensible governor defaults
+func DefaultGovernorConfig() GovernorConfig {
+	return GovernorConfig{
+		WindowSize:     100,
+		SlowDownRatio:  0.5,
+		SpeedUpRatio:   0.05,
+		DecreaseFactor: 0.5,
+		IncreaseFactor: 1.25,
+		MinMultiplier:  0.1,
+	}
+}
+
+// SpeedGovernor lowers the speed multiplier when the consumer can't keep up
+// (sustained skipped sleeps) and raises it back toward the target speed when
+// headroom returns
+type SpeedGovernor struct {
+	config GovernorConfig
+
+	// targetMultiplier is the speed requested by the user; never exceeded
+	targetMultiplier float64
+
+	// Current window
+	windowTicks   int64
+	windowSkipped int64
+
+	// Statistics
+	slowdowns        int64
+	speedups         int64
+	lowestMultiplier float64
+	lastRatio        float64
+}
+
+// ==================== CREATION ====================
+
+// NewSpeedGovernor creates a governor; zero config fields use defaults
+func NewSpeedGovernor(config GovernorConfig) (*SpeedGovernor, error) {
+	defaults := DefaultGovernorConfig()
+	if config.WindowSize <= 0 {
+		config.WindowSize = defaults.WindowSize
+	}
+	if config.SlowDownRatio <= 0 {
+		config.SlowDownRatio = defaults.SlowDownRatio
+	}
+	if config.SpeedUpRatio <= 0 {
+		config.SpeedUpRatio = defaults.SpeedUpRatio
+	}
+	if config.DecreaseFactor <= 0 {
+		config.DecreaseFactor = defaults.DecreaseFactor
+	}
+	if config.IncreaseFactor <= 0 {
+		config.IncreaseFactor = defaults.IncreaseFactor
+	}
+	if config.MinMultiplier <= 0 {
+		config.MinMultiplier = defaults.MinMultiplier
+	}
+
+	if config.SlowDownRatio > 1 {
+		return nil, fmt.Errorf("slow down ratio must be between 0 and 1")
+	}
+	if config.SpeedUpRatio >= config.SlowDownRatio {
+		return nil, fmt.Errorf("speed up ratio must be below slow down ratio")
+	}
+	if config.DecreaseFactor >= 1 {
+		return nil, fmt.Errorf("decrease factor must be below 1")
+	}
+	if config.IncreaseFactor <= 1 {
+		return nil, fmt.Errorf("increase factor must be above 1")
+	}
+
+	return &SpeedGovernor{config: config}, nil
+}
+
+// ==================== GOVERNING ====================
+
+// SetTargetMultiplier sets the speed the governor recovers toward
+func (sg *SpeedGovernor) SetTargetMultiplier(multiplier float64) {
+	sg.targetMultiplier = multiplier
+	sg.windowTicks = 0
+	sg.windowSkipped = 0
+}
+
+// Observe records whether a tick overran its time budget
+// Returns the multiplier to use from now on
+func (sg *SpeedGovernor) Observe(skipped bool, current float64) float64 {
+	if sg.targetMultiplier <= 0 {
+		sg.targetMultiplier = current
+	}
+
+	sg.windowTicks++
+	if skipped {
+		sg.windowSkipped++
+	}
+	if sg.windowTicks < sg.config.WindowSize {
+		return current
+	}
+
+	ratio := float64(sg.windowSkipped) / float64(sg.windowTicks)
+	sg.lastRatio = ratio
+	sg.windowTicks = 0
+	sg.windowSkipped = 0
+
+	next := current
+	switch {
+	case ratio >= sg.config.SlowDownRatio && current > sg.config.MinMultiplier:
+		next = current * sg.config.DecreaseFactor
+		if next < sg.config.MinMultiplier {
+			next = sg.config.MinMultiplier
+		}
+		sg.slowdowns++
+	case ratio <= sg.config.SpeedUpRatio && current < sg.targetMultiplier:
+		next = current * sg.config.IncreaseFactor
+		if next > sg.targetMultiplier {
+			next = sg.targetMultiplier
+		}
+		sg.speedups++
+	}
+
+	if sg.lowestMultiplier == 0 || next < sg.lowestMultiplier {
+		sg.lowestMultiplier = next
+	}
+
+	return next
+}
+
+// ==================== STATISTICS ====================
+
+// GetStatistics returns governor statistics
+func (sg *SpeedGovernor) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"target_multiplier": sg.targetMultiplier,
+		"slowdowns":         sg.slowdowns,
+		"speedups":          sg.speedups,
+		"lowest_multiplier": sg.lowestMultiplier,
+		"last_skip_ratio":   sg.lastRatio,
+		"window_size":       sg.config.WindowSize,
+	}
+}
+
+// Reset clears the governor window and statistics
+func (sg *SpeedGovernor) Reset() {
+	sg.windowTicks = 0
+	sg.windowSkipped = 0
+	sg.slowdowns = 0
+	sg.speedups = 0
+	sg.lowestMultiplier = 0
+	sg.lastRatio = 0
+}
+
+// String returns a human-readable representation
+func (sg *SpeedGovernor) String() string {
+	return fmt.Sprintf(
+		"SpeedGovernor[Target:%.1fx, Slowdowns:%d, Speedups:%d, LastSkipRatio:%.2f]",
+		sg.targetMultiplier,
+		sg.slowdowns,
+		sg.speedups,
+		sg.lastRatio,
+	)
+}
@@ -0,0 +1,288 @@
+package speed
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ==================== PACING MODES ====================
+
+// PacingMode decides how long the controller waits between ticks
+type PacingMode int
+
+const (
+	// PacingFixed spaces ticks baseTickDuration/multiplier apart
+	PacingFixed PacingMode = iota
+
+	// PacingTimestamps spaces ticks by the gap between their timestamps
+	// divided by the multiplier, reproducing bursts and quiet periods
+	PacingTimestamps
+
+	// PacingRealtime emits each tick when the wall clock reaches its
+	// timestamp (shifted to the session start, or to the same time of day
+	// with SetRealtimeAlign) at 1.0x whatever the multiplier; the anchor
+	// never moves, so a late tick is caught up on rather than delaying
+	// the rest
+	PacingRealtime
+)
+
+// String returns string representation
+func (pm PacingMode) String() string {
+	switch pm {
+	case PacingFixed:
+		return "fixed"
+	case PacingTimestamps:
+		return "timestamps"
+	case PacingRealtime:
+		return "realtime"
+	default:
+		return "unknown"
+	}
+}
+
+// ParsePacingMode parses a pacing mode name ("" = fixed)
+func ParsePacingMode(name string) (PacingMode, error) {
+	switch strings.ToLower(name) {
+	case "", "fixed":
+		return PacingFixed, nil
+	case "timestamps", "timestamp":
+		return PacingTimestamps, nil
+	case "realtime", "live":
+		return PacingRealtime, nil
+	default:
+		return PacingFixed, fmt.Errorf("unknown pacing mode: %s (expected fixed, timestamps or realtime)", name)
+	}
+}
+
+// SetPacing sets the pacing mode
+func (sc *SpeedController) SetPacing(mode PacingMode) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.pacing = mode
+	sc.anchorWall = time.Time{}
+}
+
+// GetPacing returns the pacing mode
+func (sc *SpeedController) GetPacing() PacingMode {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.pacing
+}
+
+// SetRealtimeAlign makes realtime pacing emit each tick at its own time of
+// day (on today's date) instead of starting the replay at once: ticks
+// before the current time of day are caught up on immediately and later
+// ones wait, as if the data were happening live
+func (sc *SpeedController) SetRealtimeAlign(enabled bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.realtimeAlign = enabled
+	sc.anchorWall = time.Time{}
+}
+
+// GetRealtimeAlign returns whether realtime pacing aligns to the time of day
+func (sc *SpeedController) GetRealtimeAlign() bool {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.realtimeAlign
+}
+
+// ==================== IDLE SKIPPING ====================
+
+// SetIdleSkip fast-forwards over gaps between tick timestamps longer than
+// threshold (e.g. weekends and overnight) instead of waiting them out in
+// timestamp pacing (0 disables)
+func (sc *SpeedController) SetIdleSkip(threshold time.Duration) error {
+	if threshold < 0 {
+		return fmt.Errorf("idle skip threshold cannot be negative")
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.idleThreshold = threshold
+	return nil
+}
+
+// GetIdleSkip returns the idle skip threshold (0 = disabled)
+func (sc *SpeedController) GetIdleSkip() time.Duration {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.idleThreshold
+}
+
+// skipIdle returns true if the gap since the latest tick seen is long
+// enough to fast-forward over, counting it
+// Caller must hold mu, before lastTickTimestamp moves to tickTime
+func (sc *SpeedController) skipIdle(tickTime time.Time) bool {
+	if sc.idleThreshold <= 0 || sc.lastTickTimestamp.IsZero() {
+		return false
+	}
+
+	gap := tickTime.Sub(sc.lastTickTimestamp)
+	if gap <= sc.idleThreshold {
+		return false
+	}
+	sc.idleSkips++
+	sc.idleSkipped += gap
+	return true
+}
+
+// ==================== TIMESTAMP PACING ====================
+
+// WaitForTick waits until the tick is due
+// In timestamp mode the tick is due (tickTime - anchor)/multiplier after
+// the anchor tick, so sleep error and processing time do not accumulate;
+// the anchor moves to the current tick whenever the controller falls
+// behind, skips an idle gap, the speed changes or it resumes. In fixed
+// mode it is WaitTick, in realtime mode waitRealtime, and with a tick rate
+// set waitRate. In multiplier modes the speed schedule, if any, is applied
+// first
+func (sc *SpeedController) WaitForTick(tickTime time.Time, processingTime time.Duration) error {
+	return sc.WaitForTickContext(context.Background(), tickTime, processingTime)
+}
+
+// WaitForTickContext is WaitForTick that stops waiting when ctx is done,
+// returning ctx.Err()
+func (sc *SpeedController) WaitForTickContext(ctx context.Context, tickTime time.Time, processingTime time.Duration) error {
+	if sc.maxSpeed.Load() {
+		return nil
+	}
+	sc.mu.Lock()
+	if sc.ticksPerSecond > 0 {
+		return sc.waitRate(ctx, processingTime)
+	}
+	if sc.pacing == PacingRealtime {
+		return sc.waitRealtime(ctx, tickTime, processingTime)
+	}
+	sc.applySchedule(tickTime)
+	if sc.pacing != PacingTimestamps {
+		sc.mu.Unlock()
+		return sc.WaitTickContext(ctx, processingTime)
+	}
+
+	if sc.paused {
+		sc.mu.Unlock()
+		return nil
+	}
+
+	now := sc.now()
+	sc.ticksProcessed++
+	sc.totalProcessTime += processingTime
+
+	if sc.firstTickTime.IsZero() {
+		sc.firstTickTime = tickTime
+	}
+	idle := sc.skipIdle(tickTime)
+	if tickTime.After(sc.lastTickTimestamp) {
+		sc.lastTickTimestamp = tickTime
+	}
+
+	if idle || sc.anchorWall.IsZero() || tickTime.Before(sc.anchorTick) {
+		sc.anchorWall, sc.anchorTick = now, tickTime
+		sc.mu.Unlock()
+		return nil
+	}
+
+	due := sc.anchorWall.Add(time.Duration(float64(tickTime.Sub(sc.anchorTick)) / sc.multiplier))
+	requiredSleep := due.Sub(now)
+	if requiredSleep <= 0 {
+		sc.skippedSleeps++
+		sc.anchorWall, sc.anchorTick = now, tickTime
+		sc.govern(true)
+		sc.mu.Unlock()
+		return nil
+	}
+	sc.govern(false)
+	if sc.deferSleep(requiredSleep) {
+		sc.mu.Unlock()
+		return nil
+	}
+	sc.totalWaitTime += requiredSleep
+	sc.mu.Unlock()
+
+	return sc.sleep(ctx, requiredSleep)
+}
+
+// waitRealtime waits until the wall clock reaches the tick's timestamp
+// shifted by the fixed anchor offset
+// Caller must hold mu; it is released before sleeping
+func (sc *SpeedController) waitRealtime(ctx context.Context, tickTime time.Time, processingTime time.Duration) error {
+	if sc.paused {
+		sc.mu.Unlock()
+		return nil
+	}
+
+	now := sc.now()
+	sc.ticksProcessed++
+	sc.totalProcessTime += processingTime
+
+	if sc.firstTickTime.IsZero() {
+		sc.firstTickTime = tickTime
+	}
+	if tickTime.After(sc.lastTickTimestamp) {
+		sc.lastTickTimestamp = tickTime
+	}
+
+	if sc.anchorWall.IsZero() {
+		sc.anchorWall, sc.anchorTick = now, tickTime
+		if sc.realtimeAlign {
+			sc.anchorWall = alignTimeOfDay(tickTime, now)
+		}
+	}
+
+	due := sc.anchorWall.Add(tickTime.Sub(sc.anchorTick))
+	requiredSleep := due.Sub(now)
+	if requiredSleep <= 0 {
+		sc.skippedSleeps++
+		sc.realtimeLag = -requiredSleep
+		sc.mu.Unlock()
+		return nil
+	}
+	sc.realtimeLag = 0
+	if sc.deferSleep(requiredSleep) {
+		sc.mu.Unlock()
+		return nil
+	}
+	sc.totalWaitTime += requiredSleep
+	sc.mu.Unlock()
+
+	return sc.sleep(ctx, requiredSleep)
+}
+
+// alignTimeOfDay returns the wall-clock time on now's date with the tick's
+// time of day, in the tick's time zone
+func alignTimeOfDay(tickTime, now time.Time) time.Time {
+	now = now.In(tickTime.Location())
+	year, month, day := now.Date()
+	return time.Date(year, month, day,
+		tickTime.Hour(), tickTime.Minute(), tickTime.Second(), tickTime.Nanosecond(),
+		tickTime.Location())
+}
+
+// reanchor restarts timestamp pacing from the next tick
+// Realtime pacing keeps its anchor: the multiplier does not apply to it
+// Caller must hold mu
+func (sc *SpeedController) reanchor() {
+	if sc.pacing == PacingRealtime {
+		return
+	}
+	sc.anchorWall = time.Time{}
+}
+
+// simulatedElapsed returns the simulated time covered so far, less the
+// idle gaps skipped
+// Caller must hold mu
+func (sc *SpeedController) simulatedElapsed() time.Duration {
+	if sc.pacing != PacingFixed {
+		if sc.firstTickTime.IsZero() {
+			return 0
+		}
+		return sc.lastTickTimestamp.Sub(sc.firstTickTime) - sc.idleSkipped
+	}
+	return time.Duration(float64(sc.ticksProcessed) * float64(sc.baseTickDuration))
+}
@@ -0,0 +1,84 @@
+package speed
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ==================== TICKS PER SECOND ====================
+
+// SetTicksPerSecond throttles to a target tick rate instead of a
+// multiplier, which is easier to reason about when tick density is
+// irregular (0 returns to the multiplier; SetSpeed does too)
+// The rate is held against a wall-clock anchor, so sleep error does not
+// accumulate; the schedule and governor, which adjust the multiplier, do
+// not apply while a rate is set
+func (sc *SpeedController) SetTicksPerSecond(rate float64) error {
+	if rate < 0 {
+		return fmt.Errorf("ticks per second cannot be negative")
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.ticksPerSecond = rate
+	sc.calculateTargetTime()
+	sc.reanchor()
+	return nil
+}
+
+// GetTicksPerSecond returns the target tick rate (0 = multiplier mode)
+func (sc *SpeedController) GetTicksPerSecond() float64 {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.ticksPerSecond
+}
+
+// waitRate waits until the next tick is due at the target rate; falling
+// behind restarts the count from now rather than bursting to catch up
+// Caller must hold mu; it is released before sleeping
+func (sc *SpeedController) waitRate(ctx context.Context, processingTime time.Duration) error {
+	if sc.paused {
+		sc.mu.Unlock()
+		return nil
+	}
+
+	now := sc.now()
+	sc.ticksProcessed++
+	sc.totalProcessTime += processingTime
+
+	if sc.anchorWall.IsZero() {
+		sc.anchorWall, sc.rateTicks = now, 0
+		sc.mu.Unlock()
+		return nil
+	}
+
+	sc.rateTicks++
+	due := sc.anchorWall.Add(time.Duration(sc.rateTicks) * sc.targetTimePerTick)
+	requiredSleep := due.Sub(now)
+	if requiredSleep <= 0 {
+		sc.skippedSleeps++
+		sc.anchorWall, sc.rateTicks = now, 0
+		sc.mu.Unlock()
+		return nil
+	}
+	if sc.deferSleep(requiredSleep) {
+		sc.mu.Unlock()
+		return nil
+	}
+	sc.totalWaitTime += requiredSleep
+	sc.mu.Unlock()
+
+	return sc.sleep(ctx, requiredSleep)
+}
+
+// getActualTicksPerSecond returns the tick rate achieved so far
+// Caller must hold mu
+func (sc *SpeedController) getActualTicksPerSecond() float64 {
+	elapsed := sc.since(sc.startTime).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(sc.ticksProcessed) / elapsed
+}
@@ -0,0 +1,136 @@
+package speed
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ==================== SPEED SCHEDULE ====================
+
+// ScheduleEntry runs the simulation at Multiplier until the simulated time
+// reaches Until (YYYY-MM-DD or RFC3339)
+type ScheduleEntry struct {
+	Until      string  `json:"until"`
+	Multiplier float64 `json:"multiplier"`
+
+	until time.Time
+}
+
+// SpeedSchedule changes the multiplier by simulated time, e.g. 10000x
+// until 2024-03-01 then 1x for the week of interest
+// Each entry applies from the end of the previous one; after the last
+// entry the controller's own multiplier applies again
+type SpeedSchedule struct {
+	entries []ScheduleEntry
+}
+
+// NewSpeedSchedule creates a schedule from entries in chronological order
+func NewSpeedSchedule(entries []ScheduleEntry) (*SpeedSchedule, error) {
+	parsed := make([]ScheduleEntry, len(entries))
+	for i, entry := range entries {
+		until, err := time.Parse(time.RFC3339, entry.Until)
+		if err != nil {
+			until, err = time.Parse("2006-01-02", entry.Until)
+			if err != nil {
+				return nil, fmt.Errorf("schedule entry %d: invalid until %q (want YYYY-MM-DD or RFC3339)", i+1, entry.Until)
+			}
+		}
+		if entry.Multiplier <= 0 {
+			return nil, fmt.Errorf("schedule entry %d: multiplier must be positive", i+1)
+		}
+		if i > 0 && !until.After(parsed[i-1].until) {
+			return nil, fmt.Errorf("schedule entry %d: until %s is not after %s", i+1, entry.Until, entries[i-1].Until)
+		}
+
+		entry.until = until
+		parsed[i] = entry
+	}
+
+	return &SpeedSchedule{entries: parsed}, nil
+}
+
+// MultiplierAt returns the scheduled multiplier at a simulated time
+// Returns false past the last entry
+func (ss *SpeedSchedule) MultiplierAt(t time.Time) (float64, bool) {
+	i := sort.Search(len(ss.entries), func(i int) bool {
+		return t.Before(ss.entries[i].until)
+	})
+	if i == len(ss.entries) {
+		return 0, false
+	}
+	return ss.entries[i].Multiplier, true
+}
+
+// GetEntries returns the schedule entries
+func (ss *SpeedSchedule) GetEntries() []ScheduleEntry {
+	return append([]ScheduleEntry(nil), ss.entries...)
+}
+
+// String returns a human-readable representation
+func (ss *SpeedSchedule) String() string {
+	s := "SpeedSchedule["
+	for i, entry := range ss.entries {
+		if i > 0 {
+			s += ", "
+		}
+		s += fmt.Sprintf("%gx until %s", entry.Multiplier, entry.Until)
+	}
+	return s + "]"
+}
+
+// ==================== CONTROLLER INTEGRATION ====================
+
+// SetSchedule attaches a speed schedule (nil removes it), applied by
+// WaitForTick from each tick's timestamp
+func (sc *SpeedController) SetSchedule(schedule *SpeedSchedule) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.schedule = schedule
+	if schedule == nil && sc.scheduled {
+		sc.scheduled = false
+		sc.setMultiplier(sc.baseMultiplier)
+	}
+}
+
+// GetSchedule returns the attached speed schedule, if any
+func (sc *SpeedController) GetSchedule() *SpeedSchedule {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.schedule
+}
+
+// applySchedule switches to the multiplier scheduled at tickTime, or back
+// to the base multiplier past the schedule
+// Caller must hold mu
+func (sc *SpeedController) applySchedule(tickTime time.Time) {
+	if sc.schedule == nil {
+		return
+	}
+
+	multiplier, scheduled := sc.schedule.MultiplierAt(tickTime)
+	if !scheduled {
+		if !sc.scheduled {
+			return
+		}
+		multiplier = sc.baseMultiplier
+	}
+	sc.scheduled = scheduled
+
+	if multiplier != sc.multiplier {
+		sc.setMultiplier(multiplier)
+		sc.scheduleChanges++
+	}
+}
+
+// setMultiplier changes the multiplier without touching the base
+// Caller must hold mu
+func (sc *SpeedController) setMultiplier(multiplier float64) {
+	sc.multiplier = multiplier
+	sc.calculateTargetTime()
+	sc.reanchor()
+	if sc.governor != nil {
+		sc.governor.SetTargetMultiplier(multiplier)
+	}
+}
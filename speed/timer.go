@@ -128,14 +128,17 @@ func (bt *BatchTimer) GetProgress() float64 {
 	return float64(bt.ticksProcessed) / float64(bt.batchSize) * 100
 }
 
-// GetEstimatedTimeRemaining estimates time remaining for batch
+// GetEstimatedTimeRemaining estimates the wall-clock time left in the
+// batch at the batch's rate so far (0 before the first tick or without a
+// batch size)
 func (bt *BatchTimer) GetEstimatedTimeRemaining() time.Duration {
-	if bt.ticksProcessed <= 0 || bt.avgTickTime <= 0 {
+	remaining := bt.batchSize - bt.ticksProcessed
+	if bt.ticksProcessed <= 0 || remaining <= 0 {
 		return 0
 	}
 
-	remaining := bt.batchSize - bt.ticksProcessed
-	return time.Duration(remaining) * (bt.avgTickTime / time.Duration(bt.controller.GetActualMultiplier()))
+	perTick := time.Since(bt.batchStartTime) / time.Duration(bt.ticksProcessed)
+	return time.Duration(remaining) * perTick
 }
 
 // ==================== BATCH STATISTICS ====================
@@ -183,7 +186,7 @@ type SessionTimer struct {
 	controller          *SpeedController
 	sessionStartTime    time.Time
 	sessionName         string
-	batches             []*BatchTimer
+	batches             []BatchStatistics
 	currentBatch        *BatchTimer
 	totalTicksProcessed int64
 }
@@ -196,7 +199,7 @@ func NewSessionTimer(controller *SpeedController, sessionName string) *SessionTi
 		controller:       controller,
 		sessionStartTime: time.Now(),
 		sessionName:      sessionName,
-		batches:          make([]*BatchTimer, 0),
+		batches:          make([]BatchStatistics, 0),
 	}
 }
 
@@ -226,27 +229,58 @@ func (st *SessionTimer) EndBatch() BatchStatistics {
 	}
 
 	stats := st.currentBatch.EndBatch()
-	st.batches = append(st.batches, st.currentBatch)
+	st.batches = append(st.batches, stats)
 	st.currentBatch = nil
 
 	return stats
 }
 
-// EndSession ends the session and returns summary statistics
+// GetTicksProcessed returns the ticks recorded in the session so far
+func (st *SessionTimer) GetTicksProcessed() int64 {
+	return st.totalTicksProcessed
+}
+
+// GetEstimatedTimeRemaining estimates the wall-clock time left to reach
+// totalTicks at the session's rate so far (0 if unknown)
+func (st *SessionTimer) GetEstimatedTimeRemaining(totalTicks int64) time.Duration {
+	remaining := totalTicks - st.totalTicksProcessed
+	if st.totalTicksProcessed <= 0 || remaining <= 0 {
+		return 0
+	}
+
+	perTick := time.Since(st.sessionStartTime) / time.Duration(st.totalTicksProcessed)
+	return time.Duration(remaining) * perTick
+}
+
+// EndSession ends the session, closing a batch in progress, and returns
+// summary statistics
 func (st *SessionTimer) EndSession() SessionStatistics {
+	if st.currentBatch != nil && st.currentBatch.ticksProcessed > 0 {
+		st.EndBatch()
+	}
 	wallClockTime := time.Since(st.sessionStartTime)
 
 	var totalBatchTime time.Duration
 	var avgBatchTime time.Duration
-	var totalAvgTickTime time.Duration
+	var totalProcessTime time.Duration
+	var avgTickTime time.Duration
+	var batchedTicks int64
 
 	if len(st.batches) > 0 {
 		for _, batch := range st.batches {
-			totalBatchTime += batch.EndBatch().WallClockTime
-			totalAvgTickTime += batch.avgTickTime
+			totalBatchTime += batch.WallClockTime
+			totalProcessTime += batch.TotalProcessTime
+			batchedTicks += batch.TicksProcessed
 		}
 		avgBatchTime = totalBatchTime / time.Duration(len(st.batches))
-		totalAvgTickTime = totalAvgTickTime / time.Duration(len(st.batches))
+	}
+	if batchedTicks > 0 {
+		avgTickTime = totalProcessTime / time.Duration(batchedTicks)
+	}
+
+	var actualSpeed float64
+	if st.controller != nil {
+		actualSpeed = st.controller.GetActualMultiplier()
 	}
 
 	return SessionStatistics{
@@ -255,8 +289,9 @@ func (st *SessionTimer) EndSession() SessionStatistics {
 		TotalTicksProcessed: st.totalTicksProcessed,
 		BatchCount:          int64(len(st.batches)),
 		AverageBatchTime:    avgBatchTime,
-		AverageTickTime:     totalAvgTickTime,
-		ActualSpeed:         st.controller.GetActualMultiplier(),
+		AverageTickTime:     avgTickTime,
+		ActualSpeed:         actualSpeed,
+		TicksPerSecond:      calculateTicksPerSecond(st.totalTicksProcessed, wallClockTime),
 	}
 }
 
@@ -271,6 +306,7 @@ type SessionStatistics struct {
 	AverageBatchTime    time.Duration
 	AverageTickTime     time.Duration
 	ActualSpeed         float64
+	TicksPerSecond      float64
 }
 
 // String returns formatted statistics string
@@ -282,6 +318,7 @@ func (ss SessionStatistics) String() string {
 			"  Batch Count:         %d\n"+
 			"  Average Batch Time:  %s\n"+
 			"  Average Tick Time:   %s\n"+
+			"  Ticks Per Second:    %.1f\n"+
 			"  Actual Speed:        %.1fx\n",
 		ss.SessionName,
 		ss.WallClockTime,
@@ -289,6 +326,7 @@ func (ss SessionStatistics) String() string {
 		ss.BatchCount,
 		ss.AverageBatchTime,
 		ss.AverageTickTime,
+		ss.TicksPerSecond,
 		ss.ActualSpeed,
 	)
 }
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -20,6 +21,19 @@ type Balance struct {
 	// Currency is the account currency (USD, EUR, etc)
 	Currency string
 
+	// Converters convert execution P&L and commission quoted in another
+	// currency into the account currency, keyed by quote currency (empty
+	// when every instrument is quoted in the account currency)
+	Converters map[string]*CurrencyConverter
+
+	// QuoteCurrencies maps each symbol quoted in another currency to that
+	// currency ("" stands for the session instrument)
+	QuoteCurrencies map[string]string
+
+	// Clock stamps updates that carry no timestamp of their own, such as
+	// mark-to-market (nil uses wall-clock time)
+	Clock Clock `json:"-"`
+
 	// TotalRealizedPnL is profit/loss from closed trades
 	TotalRealizedPnL float64
 
@@ -29,6 +43,20 @@ type Balance struct {
 	// CommissionPaid is the total fees/commissions paid
 	CommissionPaid float64
 
+	// FinancingPnL is the net of overnight swap credits and debits
+	FinancingPnL float64
+
+	// DividendPnL is the net of dividends credited to longs and charged
+	// to shorts
+	DividendPnL float64
+
+	// InterestPnL is the interest accrued on idle cash
+	InterestPnL float64
+
+	// ProtectionCredit is the deficit written off by negative balance
+	// protection
+	ProtectionCredit float64
+
 	// Leverage is the account leverage multiplier (1.0 = no leverage)
 	Leverage float64
 
@@ -38,7 +66,7 @@ type Balance struct {
 	// AvailableMargin is the margin available for new trades
 	AvailableMargin float64
 
-	// BuyingPower is the total amount that can be traded (balance * leverage)
+	// BuyingPower is the notional that can still be opened (available margin * leverage)
 	BuyingPower float64
 
 	// MaxDrawdownPercent is the maximum allowed drawdown before account blown
@@ -56,9 +84,20 @@ type Balance struct {
 	// LosingTrades is the count of losing trades
 	LosingTrades int
 
-	// BreakevenTrades is the count of trades with 0 P&L
+	// BreakevenTrades is the count of closing trades with P&L inside the
+	// breakeven band
 	BreakevenTrades int
 
+	// Breakeven is the P&L band that classifies closing trades as breakeven
+	Breakeven BreakevenBand
+
+	// EquityCurve is the sampled equity the Sharpe ratio is computed from
+	EquityCurve *EquityCurve
+
+	// Sharpe sets the Sharpe ratio's return period, risk-free rate and
+	// annualization
+	Sharpe SharpeConfig
+
 	// AccountStatus is ACTIVE, BLOWN, or AT_LIMIT
 	AccountStatus string
 
@@ -105,8 +144,21 @@ type BalanceUpdate struct {
 
 	// ReferencePnL is the P&L that caused the change
 	ReferencePnL float64
+
+	// Commission is the commission charged by the execution (account
+	// currency, execution updates only)
+	Commission float64
 }
 
+// Balance update reasons
+const (
+	BalanceReasonExecution  = "Execution"                   // Fill, followed by the order ID
+	BalanceReasonSwap       = "swap"                        // Overnight financing
+	BalanceReasonProtection = "negative balance protection" // Deficit written off
+	BalanceReasonDividend   = "dividend"                    // Corporate action cash
+	BalanceReasonInterest   = "interest"                    // Interest on idle cash
+)
+
 // ==================== BALANCE CONSTRUCTORS ====================
 
 // NewBalance creates a new balance account
@@ -124,6 +176,7 @@ func NewBalance(initialBalance float64, currency string, leverage, maxDrawdown,
 		StartTime:          now,
 		HighWaterMark:      initialBalance,
 		LowWaterMark:       initialBalance,
+		AvailableMargin:    initialBalance,
 		BuyingPower:        initialBalance * leverage,
 		UpdateHistory:      make([]*BalanceUpdate, 0),
 	}
@@ -136,9 +189,10 @@ func (b *Balance) GetTotalPnL() float64 {
 	return b.TotalRealizedPnL + b.TotalUnrealizedPnL
 }
 
-// GetNetPnL returns total P&L minus commissions
+// GetNetPnL returns total P&L minus commissions, plus financing, dividends,
+// interest and any negative balance protection credit
 func (b *Balance) GetNetPnL() float64 {
-	return b.GetTotalPnL() - b.CommissionPaid
+	return b.GetTotalPnL() - b.CommissionPaid + b.FinancingPnL + b.DividendPnL + b.InterestPnL + b.ProtectionCredit
 }
 
 // IsAccountActive returns true if account status is ACTIVE
@@ -164,6 +218,15 @@ func (b *Balance) GetDrawdownPercent() float64 {
 	return ((b.InitialBalance - b.CurrentBalance) / b.InitialBalance) * 100.0
 }
 
+// GetPeakDrawdownPercent returns the current drawdown from the high water
+// mark as a percentage
+func (b *Balance) GetPeakDrawdownPercent() float64 {
+	if b.HighWaterMark <= 0 {
+		return 0
+	}
+	return math.Max(0, (b.HighWaterMark-b.CurrentBalance)/b.HighWaterMark*100.0)
+}
+
 // GetReturnPercent returns total return as percentage
 func (b *Balance) GetReturnPercent() float64 {
 	if b.InitialBalance == 0 {
@@ -203,22 +266,13 @@ func (b *Balance) GetProfitFactor() float64 {
 	return grossWins / -grossLosses
 }
 
-// GetSharpeRatio is a simplified sharpe ratio approximation
-// (Real Sharpe needs daily returns, this is simplified)
+// GetSharpeRatio returns the annualized Sharpe ratio of the equity
+// curve's period returns (0 without an equity curve)
 func (b *Balance) GetSharpeRatio() float64 {
-	if b.TradeCount == 0 {
-		return 0
-	}
-
-	avgReturn := b.GetAverageTradePnL()
-	// Simplified: assume standard deviation is 20% of avg trade
-	stdDev := (avgReturn * 0.2)
-	if stdDev == 0 {
+	if b.EquityCurve == nil {
 		return 0
 	}
-
-	// Annualize (assuming ~250 trades per year)
-	return (avgReturn / stdDev) * (250.0 / float64(b.TradeCount))
+	return b.EquityCurve.GetSharpeRatio(b.Sharpe)
 }
 
 // IsMarginCall returns true if margin is violated
@@ -226,6 +280,15 @@ func (b *Balance) IsMarginCall() bool {
 	return b.AvailableMargin < 0
 }
 
+// GetMarginLevel returns equity as a percentage of used margin
+// Returns 0 when no margin is in use
+func (b *Balance) GetMarginLevel() float64 {
+	if b.UsedMargin <= 0 {
+		return 0
+	}
+	return b.CurrentBalance / b.UsedMargin * 100
+}
+
 // CanTrade returns true if account can trade (active and has margin)
 func (b *Balance) CanTrade() bool {
 	return b.IsAccountActive() && b.AvailableMargin > 0
@@ -245,50 +308,151 @@ func (b *Balance) UpdateFromExecution(report *ExecutionReport) error {
 
 	// Calculate P&L change
 	pnlChange := 0.0
-	if report.IsSell() && report.RealizedPnL > 0 {
-		// Position closing, add realized P&L
-		pnlChange = report.RealizedPnL
-		b.TotalRealizedPnL += report.RealizedPnL
+	if report.RealizedPnL != 0 {
+		// Position closing or reducing, add realized P&L (either side)
+		pnlChange = b.ConvertToAccount(report.Symbol, report.RealizedPnL)
+		b.TotalRealizedPnL += pnlChange
 	}
 
 	// Add unrealized P&L from open position
 	if report.IsPartial() || (report.IsFilled() && report.PositionAfter != 0) {
-		b.TotalUnrealizedPnL = report.UnrealizedPnL
+		b.TotalUnrealizedPnL = b.ConvertToAccount(report.Symbol, report.UnrealizedPnL)
 	}
 
 	// Add commission
-	b.CommissionPaid += report.Commission
+	b.CommissionPaid += b.ConvertToAccount(report.Symbol, report.Commission)
 
 	// Update trade counts
 	if report.IsFilled() || report.IsPartial() {
 		b.TradeCount++
-		if report.RealizedPnL > 0 {
+		switch b.Breakeven.Classify(report.RealizedPnL, report.FilledSize) {
+		case TradeOutcomeWin:
 			b.WinningTrades++
-		} else if report.RealizedPnL < 0 {
+		case TradeOutcomeLoss:
 			b.LosingTrades++
-		} else if report.RealizedPnL == 0 && report.IsSell() {
-			b.BreakevenTrades++
+		default:
+			if report.RealizedPnL != 0 || report.IsSell() {
+				b.BreakevenTrades++
+			}
 		}
 	}
 
 	// Recalculate balance
 	b.RecalculateBalance()
+	if !report.Timestamp.IsZero() {
+		b.LastUpdateTime = report.Timestamp
+	}
 
 	// Record update
-	b.recordUpdate(
-		fmt.Sprintf("Execution %s", report.OrderID),
+	update := b.recordUpdate(
+		fmt.Sprintf("%s %s", BalanceReasonExecution, report.OrderID),
 		report.OrderID,
 		pnlChange,
 	)
+	update.Commission = b.ConvertToAccount(report.Symbol, report.Commission)
 
 	return nil
 }
 
-// UpdateMargin updates the margin calculations
+// ConvertToAccount converts an amount in a symbol's quote currency to the
+// account currency (unchanged when the symbol is quoted in the account
+// currency)
+func (b *Balance) ConvertToAccount(symbol string, amount float64) float64 {
+	converter := b.Converters[b.QuoteCurrencies[symbol]]
+	if converter == nil {
+		return amount
+	}
+	return converter.Convert(amount)
+}
+
+// UpdateConverters takes the conversion rates a companion tick drives
+func (b *Balance) UpdateConverters(tick *Tick) {
+	for _, converter := range b.Converters {
+		converter.UpdateFromTick(tick)
+	}
+}
+
+// MarkToMarket sets the open positions' unrealized P&L (already in the
+// account currency) and recalculates equity
+func (b *Balance) MarkToMarket(unrealizedPnL float64) {
+	b.TotalUnrealizedPnL = unrealizedPnL
+	b.RecalculateBalance()
+}
+
+// ApplyDividend books a dividend credit (long) or charge (short)
+// The update is recorded at the simulated ex-date
+func (b *Balance) ApplyDividend(amount float64, reason string, timestamp time.Time) {
+	b.DividendPnL += amount
+	b.RecalculateBalance()
+	b.LastUpdateTime = timestamp
+	b.recordUpdate(reason, "", amount)
+}
+
+// GetIdleCash returns the cash not posted as margin, excluding unrealized
+// P&L (0 when fully posted)
+func (b *Balance) GetIdleCash() float64 {
+	return math.Max(0, b.CurrentBalance-b.TotalUnrealizedPnL-b.UsedMargin)
+}
+
+// ApplyInterest books interest accrued on idle cash
+// The update is recorded at the simulated end of the accrual day
+func (b *Balance) ApplyInterest(amount float64, timestamp time.Time) {
+	b.InterestPnL += amount
+	b.RecalculateBalance()
+	b.LastUpdateTime = timestamp
+	b.recordUpdate(BalanceReasonInterest, "", amount)
+}
+
+// ClampAtZero writes off a negative balance (negative balance protection)
+// and marks the account blown
+// Returns the amount written off (0 if the balance was not negative)
+func (b *Balance) ClampAtZero(timestamp time.Time) float64 {
+	if b.CurrentBalance >= 0 {
+		return 0
+	}
+
+	credit := -b.CurrentBalance
+	b.ProtectionCredit += credit
+	b.RecalculateBalance()
+	b.LastUpdateTime = timestamp
+	b.recordUpdate(BalanceReasonProtection, "", credit)
+	return credit
+}
+
+// ApplyFinancing books an overnight swap credit (positive) or debit
+// The update is recorded at the simulated time of the charge
+func (b *Balance) ApplyFinancing(amount float64, reason string, timestamp time.Time) {
+	b.FinancingPnL += amount
+	b.RecalculateBalance()
+	b.LastUpdateTime = timestamp
+	b.recordUpdate(reason, "", amount)
+}
+
+// UpdateMargin sets the margin posted for open positions
+// Available margin is the balance not posted; buying power is the
+// notional it can still open at the account's leverage
 func (b *Balance) UpdateMargin(usedMargin float64) {
 	b.UsedMargin = usedMargin
-	b.AvailableMargin = (b.CurrentBalance * b.Leverage) - b.UsedMargin
-	b.BuyingPower = b.AvailableMargin
+	b.refreshMargin()
+}
+
+// refreshMargin recalculates available margin and buying power
+func (b *Balance) refreshMargin() {
+	b.AvailableMargin = b.CurrentBalance - b.UsedMargin
+	b.BuyingPower = b.AvailableMargin * b.Leverage
+}
+
+// CalculateRequiredMargin returns the margin needed to hold a position
+// Formula: |size| × price × contractSize / leverage
+func CalculateRequiredMargin(size, price float64, instrument Instrument, leverage float64) float64 {
+	contractSize := 1.0
+	if instrument != nil && instrument.GetContractSize() > 0 {
+		contractSize = float64(instrument.GetContractSize())
+	}
+	if leverage <= 0 {
+		leverage = 1
+	}
+	return math.Abs(size) * price * contractSize / leverage
 }
 
 // RecalculateBalance recalculates the current balance
@@ -313,18 +477,58 @@ func (b *Balance) RecalculateBalance() {
 	// Update account status based on drawdown
 	b.updateAccountStatus()
 
-	// Update buying power
-	b.BuyingPower = b.CurrentBalance * b.Leverage
+	// Update available margin and buying power
+	b.refreshMargin()
 
 	// Update last update time
-	b.LastUpdateTime = time.Now()
+	if now := b.now(); !now.IsZero() {
+		b.LastUpdateTime = now
+	}
+}
+
+// now returns the balance clock's time (zero before the clock has started)
+func (b *Balance) now() time.Time {
+	if b.Clock == nil {
+		return time.Now()
+	}
+	return b.Clock.Now()
+}
+
+// SetClock sets the balance clock and, if it has started, restamps the
+// start and last update times from it, so a balance on a ManualClock
+// carries no wall-clock times
+func (b *Balance) SetClock(clock Clock) {
+	b.Clock = clock
+	if now := b.now(); !now.IsZero() {
+		b.StartTime = now
+		b.LastUpdateTime = now
+	}
+}
+
+// openTime returns the time to stamp a (re)opened account with: the
+// balance clock's time, or the wall clock's before it has started
+func (b *Balance) openTime() time.Time {
+	if now := b.now(); !now.IsZero() {
+		return now
+	}
+	return time.Now()
+}
+
+// sessionDuration returns the time since StartTime on the balance clock
+// A clock behind StartTime (the session clock, which starts at the first
+// tick's timestamp after the account opened) falls back to the wall clock
+func (b *Balance) sessionDuration() time.Duration {
+	if now := b.now(); !now.Before(b.StartTime) {
+		return now.Sub(b.StartTime)
+	}
+	return time.Since(b.StartTime)
 }
 
 // updateAccountStatus updates the account status based on drawdown
 func (b *Balance) updateAccountStatus() {
 	currentDrawdown := b.GetDrawdownPercent()
 
-	if currentDrawdown > b.MaxDrawdownPercent {
+	if b.ProtectionCredit > 0 || currentDrawdown > b.MaxDrawdownPercent {
 		b.AccountStatus = AccountStatusBlown
 	} else if currentDrawdown >= (b.MaxDrawdownPercent * 0.95) {
 		// Within 5% of limit
@@ -335,7 +539,7 @@ func (b *Balance) updateAccountStatus() {
 }
 
 // recordUpdate records a balance update event
-func (b *Balance) recordUpdate(reason, orderID string, pnlChange float64) {
+func (b *Balance) recordUpdate(reason, orderID string, pnlChange float64) *BalanceUpdate {
 	balanceBefore := b.CurrentBalance - pnlChange
 	update := &BalanceUpdate{
 		Timestamp:     b.LastUpdateTime,
@@ -347,43 +551,148 @@ func (b *Balance) recordUpdate(reason, orderID string, pnlChange float64) {
 		ReferencePnL:  pnlChange,
 	}
 	b.UpdateHistory = append(b.UpdateHistory, update)
+	return update
 }
 
 // ==================== BALANCE METRICS ====================
 
-// GetMetrics returns comprehensive balance metrics
-func (b *Balance) GetMetrics() map[string]interface{} {
-	return map[string]interface{}{
-		"initial_balance":          b.InitialBalance,
-		"current_balance":          b.CurrentBalance,
-		"currency":                 b.Currency,
-		"total_pnl":                b.GetTotalPnL(),
-		"realized_pnl":             b.TotalRealizedPnL,
-		"unrealized_pnl":           b.TotalUnrealizedPnL,
-		"net_pnl":                  b.GetNetPnL(),
-		"commission_paid":          b.CommissionPaid,
-		"return_percent":           b.GetReturnPercent(),
-		"drawdown_percent":         b.GetDrawdownPercent(),
-		"max_drawdown_percent":     b.MaxDrawdownPercent,
-		"max_drawdown_experienced": b.MaxDrawdownExperienced,
-		"leverage":                 b.Leverage,
-		"used_margin":              b.UsedMargin,
-		"available_margin":         b.AvailableMargin,
-		"buying_power":             b.BuyingPower,
-		"trade_count":              b.TradeCount,
-		"winning_trades":           b.WinningTrades,
-		"losing_trades":            b.LosingTrades,
-		"breakeven_trades":         b.BreakevenTrades,
-		"win_rate":                 b.GetWinRate(),
-		"avg_trade_pnl":            b.GetAverageTradePnL(),
-		"profit_factor":            b.GetProfitFactor(),
-		"sharpe_ratio":             b.GetSharpeRatio(),
-		"account_status":           b.AccountStatus,
-		"high_water_mark":          b.HighWaterMark,
-		"low_water_mark":           b.LowWaterMark,
-		"last_update_time":         b.LastUpdateTime,
-		"session_duration":         time.Since(b.StartTime),
+// BalanceMetrics is a typed snapshot of the balance metrics
+type BalanceMetrics struct {
+	InitialBalance         float64       `json:"initial_balance"`
+	CurrentBalance         float64       `json:"current_balance"`
+	Currency               string        `json:"currency"`
+	TotalPnL               float64       `json:"total_pnl"`
+	RealizedPnL            float64       `json:"realized_pnl"`
+	UnrealizedPnL          float64       `json:"unrealized_pnl"`
+	NetPnL                 float64       `json:"net_pnl"`
+	CommissionPaid         float64       `json:"commission_paid"`
+	FinancingPnL           float64       `json:"financing_pnl"`
+	DividendPnL            float64       `json:"dividend_pnl"`
+	InterestPnL            float64       `json:"interest_pnl"`
+	ProtectionCredit       float64       `json:"protection_credit"`
+	ReturnPercent          float64       `json:"return_percent"`
+	DrawdownPercent        float64       `json:"drawdown_percent"`
+	MaxDrawdownPercent     float64       `json:"max_drawdown_percent"`
+	MaxDrawdownExperienced float64       `json:"max_drawdown_experienced"`
+	Leverage               float64       `json:"leverage"`
+	UsedMargin             float64       `json:"used_margin"`
+	AvailableMargin        float64       `json:"available_margin"`
+	BuyingPower            float64       `json:"buying_power"`
+	MarginLevel            float64       `json:"margin_level"`
+	TradeCount             int           `json:"trade_count"`
+	WinningTrades          int           `json:"winning_trades"`
+	LosingTrades           int           `json:"losing_trades"`
+	BreakevenTrades        int           `json:"breakeven_trades"`
+	WinRate                float64       `json:"win_rate"`
+	AvgTradePnL            float64       `json:"avg_trade_pnl"`
+	ProfitFactor           float64       `json:"profit_factor"`
+	SharpeRatio            float64       `json:"sharpe_ratio"`
+	AccountStatus          string        `json:"account_status"`
+	HighWaterMark          float64       `json:"high_water_mark"`
+	LowWaterMark           float64       `json:"low_water_mark"`
+	LastUpdateTime         time.Time     `json:"last_update_time"`
+	SessionDuration        time.Duration `json:"session_duration"`
+
+	// Conversion is each currency converter's statistics keyed by quote
+	// currency, nil without converters
+	Conversion map[string]interface{} `json:"conversion,omitempty"`
+}
+
+// GetBalanceMetrics returns comprehensive balance metrics
+func (b *Balance) GetBalanceMetrics() *BalanceMetrics {
+	metrics := &BalanceMetrics{
+		InitialBalance:         b.InitialBalance,
+		CurrentBalance:         b.CurrentBalance,
+		Currency:               b.Currency,
+		TotalPnL:               b.GetTotalPnL(),
+		RealizedPnL:            b.TotalRealizedPnL,
+		UnrealizedPnL:          b.TotalUnrealizedPnL,
+		NetPnL:                 b.GetNetPnL(),
+		CommissionPaid:         b.CommissionPaid,
+		FinancingPnL:           b.FinancingPnL,
+		DividendPnL:            b.DividendPnL,
+		InterestPnL:            b.InterestPnL,
+		ProtectionCredit:       b.ProtectionCredit,
+		ReturnPercent:          b.GetReturnPercent(),
+		DrawdownPercent:        b.GetDrawdownPercent(),
+		MaxDrawdownPercent:     b.MaxDrawdownPercent,
+		MaxDrawdownExperienced: b.MaxDrawdownExperienced,
+		Leverage:               b.Leverage,
+		UsedMargin:             b.UsedMargin,
+		AvailableMargin:        b.AvailableMargin,
+		BuyingPower:            b.BuyingPower,
+		MarginLevel:            b.GetMarginLevel(),
+		TradeCount:             b.TradeCount,
+		WinningTrades:          b.WinningTrades,
+		LosingTrades:           b.LosingTrades,
+		BreakevenTrades:        b.BreakevenTrades,
+		WinRate:                b.GetWinRate(),
+		AvgTradePnL:            b.GetAverageTradePnL(),
+		ProfitFactor:           b.GetProfitFactor(),
+		SharpeRatio:            b.GetSharpeRatio(),
+		AccountStatus:          b.AccountStatus,
+		HighWaterMark:          b.HighWaterMark,
+		LowWaterMark:           b.LowWaterMark,
+		LastUpdateTime:         b.LastUpdateTime,
+		SessionDuration:        b.sessionDuration(),
+	}
+	if len(b.Converters) > 0 {
+		metrics.Conversion = make(map[string]interface{}, len(b.Converters))
+		for currency, converter := range b.Converters {
+			metrics.Conversion[currency] = converter.GetStatistics()
+		}
+	}
+	return metrics
+}
+
+// ToMap returns the metrics as a map keyed by their JSON names
+func (bm *BalanceMetrics) ToMap() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"initial_balance":          bm.InitialBalance,
+		"current_balance":          bm.CurrentBalance,
+		"currency":                 bm.Currency,
+		"total_pnl":                bm.TotalPnL,
+		"realized_pnl":             bm.RealizedPnL,
+		"unrealized_pnl":           bm.UnrealizedPnL,
+		"net_pnl":                  bm.NetPnL,
+		"commission_paid":          bm.CommissionPaid,
+		"financing_pnl":            bm.FinancingPnL,
+		"dividend_pnl":             bm.DividendPnL,
+		"interest_pnl":             bm.InterestPnL,
+		"protection_credit":        bm.ProtectionCredit,
+		"return_percent":           bm.ReturnPercent,
+		"drawdown_percent":         bm.DrawdownPercent,
+		"max_drawdown_percent":     bm.MaxDrawdownPercent,
+		"max_drawdown_experienced": bm.MaxDrawdownExperienced,
+		"leverage":                 bm.Leverage,
+		"used_margin":              bm.UsedMargin,
+		"available_margin":         bm.AvailableMargin,
+		"buying_power":             bm.BuyingPower,
+		"margin_level":             bm.MarginLevel,
+		"trade_count":              bm.TradeCount,
+		"winning_trades":           bm.WinningTrades,
+		"losing_trades":            bm.LosingTrades,
+		"breakeven_trades":         bm.BreakevenTrades,
+		"win_rate":                 bm.WinRate,
+		"avg_trade_pnl":            bm.AvgTradePnL,
+		"profit_factor":            bm.ProfitFactor,
+		"sharpe_ratio":             bm.SharpeRatio,
+		"account_status":           bm.AccountStatus,
+		"high_water_mark":          bm.HighWaterMark,
+		"low_water_mark":           bm.LowWaterMark,
+		"last_update_time":         bm.LastUpdateTime,
+		"session_duration":         bm.SessionDuration,
+	}
+	if bm.Conversion != nil {
+		metrics["conversion"] = bm.Conversion
 	}
+	return metrics
+}
+
+// GetMetrics returns comprehensive balance metrics as a map
+// Kept for backward compatibility, prefer GetBalanceMetrics
+func (b *Balance) GetMetrics() map[string]interface{} {
+	return b.GetBalanceMetrics().ToMap()
 }
 
 // ==================== BALANCE DISPLAY ====================
@@ -402,7 +711,7 @@ func (b *Balance) String() string {
 
 // DebugString returns detailed balance information
 func (b *Balance) DebugString() string {
-	sessionDuration := time.Since(b.StartTime)
+	sessionDuration := b.sessionDuration()
 
 	return fmt.Sprintf(
 		"Balance Details:\n"+
@@ -527,6 +836,10 @@ func (b *Balance) Reset() {
 	b.TotalRealizedPnL = 0
 	b.TotalUnrealizedPnL = 0
 	b.CommissionPaid = 0
+	b.FinancingPnL = 0
+	b.DividendPnL = 0
+	b.InterestPnL = 0
+	b.ProtectionCredit = 0
 	b.TradeCount = 0
 	b.WinningTrades = 0
 	b.LosingTrades = 0
@@ -536,7 +849,9 @@ func (b *Balance) Reset() {
 	b.LowWaterMark = b.InitialBalance
 	b.MaxDrawdownExperienced = 0
 	b.UpdateHistory = make([]*BalanceUpdate, 0)
-	b.StartTime = time.Now()
-	b.LastUpdateTime = time.Now()
+	b.StartTime = b.openTime()
+	b.LastUpdateTime = b.StartTime
+	b.UsedMargin = 0
+	b.AvailableMargin = b.InitialBalance
 	b.BuyingPower = b.InitialBalance * b.Leverage
 }
@@ -0,0 +1,99 @@
+package types
+
+import (
+	"fmt"
+	"math"
+)
+
+// ==================== BENCHMARK COMPARISON ====================
+
+// BenchmarkComparison compares a strategy's equity curve with a benchmark
+// curve sampled at the same timestamps
+// Alpha and beta regress the strategy's period returns on the
+// benchmark's; alpha is annualized, in percent
+type BenchmarkComparison struct {
+	StrategyReturnPercent  float64 `json:"strategy_return_percent"`
+	BenchmarkReturnPercent float64 `json:"benchmark_return_percent"`
+	RelativeReturnPercent  float64 `json:"relative_return_percent"` // Strategy minus benchmark
+	BenchmarkMaxDrawdown   float64 `json:"benchmark_max_drawdown_percent"`
+	Alpha                  float64 `json:"alpha"`
+	Beta                   float64 `json:"beta"`
+	Correlation            float64 `json:"correlation"`
+	Periods                int     `json:"periods"` // Return periods compared
+}
+
+// CompareEquityCurves compares strategy and benchmark curves over the
+// Sharpe configuration's return periods
+// The curves must be sampled at the same timestamps
+func CompareEquityCurves(strategy, benchmark *EquityCurve, config SharpeConfig) BenchmarkComparison {
+	comparison := BenchmarkComparison{
+		StrategyReturnPercent:  curveReturnPercent(strategy),
+		BenchmarkReturnPercent: curveReturnPercent(benchmark),
+		BenchmarkMaxDrawdown:   benchmark.GetMaxDrawdownPercent(),
+	}
+	comparison.RelativeReturnPercent = comparison.StrategyReturnPercent - comparison.BenchmarkReturnPercent
+
+	strategyReturns, periodsPerYear := strategy.GetPeriodReturns(config)
+	benchmarkReturns, _ := benchmark.GetPeriodReturns(config)
+	n := len(strategyReturns)
+	if len(benchmarkReturns) < n {
+		n = len(benchmarkReturns)
+	}
+	comparison.Periods = n
+	if n < 2 {
+		return comparison
+	}
+	strategyReturns, benchmarkReturns = strategyReturns[:n], benchmarkReturns[:n]
+
+	strategyMean, benchmarkMean := meanOf(strategyReturns), meanOf(benchmarkReturns)
+	covariance, strategyVariance, benchmarkVariance := 0.0, 0.0, 0.0
+	for i := 0; i < n; i++ {
+		ds := strategyReturns[i] - strategyMean
+		db := benchmarkReturns[i] - benchmarkMean
+		covariance += ds * db
+		strategyVariance += ds * ds
+		benchmarkVariance += db * db
+	}
+
+	if benchmarkVariance > 0 {
+		comparison.Beta = covariance / benchmarkVariance
+	}
+	if strategyVariance > 0 && benchmarkVariance > 0 {
+		comparison.Correlation = covariance / math.Sqrt(strategyVariance*benchmarkVariance)
+	}
+	comparison.Alpha = (strategyMean - comparison.Beta*benchmarkMean) * periodsPerYear * 100
+
+	return comparison
+}
+
+// String returns a human-readable representation
+func (bc BenchmarkComparison) String() string {
+	return fmt.Sprintf(
+		"BenchmarkComparison[Strategy:%.2f%%, Benchmark:%.2f%%, Alpha:%.2f%%, Beta:%.2f, Correlation:%.2f]",
+		bc.StrategyReturnPercent,
+		bc.BenchmarkReturnPercent,
+		bc.Alpha,
+		bc.Beta,
+		bc.Correlation,
+	)
+}
+
+// curveReturnPercent returns the curve's first-to-last return in percent
+func curveReturnPercent(curve *EquityCurve) float64 {
+	if len(curve.points) < 2 || curve.points[0].Equity <= 0 {
+		return 0
+	}
+	return (curve.points[len(curve.points)-1].Equity/curve.points[0].Equity - 1) * 100
+}
+
+// meanOf returns the average of values (0 when empty)
+func meanOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
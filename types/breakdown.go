@@ -0,0 +1,219 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// ==================== TRADING SESSIONS ====================
+
+// DefaultTradingSessions are the major FX sessions in UTC, used when the
+// instrument does not define its own session hours
+var DefaultTradingSessions = []SessionHour{
+	{Name: "Asian", OpenHour: 0, CloseHour: 9},
+	{Name: "London", OpenHour: 7, CloseHour: 16},
+	{Name: "NewYork", OpenHour: 12, CloseHour: 21},
+}
+
+// Contains returns true if a UTC hour falls in the session; sessions whose
+// close hour is before their open hour wrap past midnight
+func (sh SessionHour) Contains(hour int) bool {
+	if sh.OpenHour <= sh.CloseHour {
+		return hour >= sh.OpenHour && hour < sh.CloseHour
+	}
+	return hour >= sh.OpenHour || hour < sh.CloseHour
+}
+
+// ==================== PERFORMANCE BUCKET ====================
+
+// PerformanceBucket is the closing-trade performance of one time bucket;
+// P&L is in the account currency
+type PerformanceBucket struct {
+	Label           string  `json:"label"`
+	Trades          int     `json:"trades"`
+	WinningTrades   int     `json:"winning_trades"`
+	LosingTrades    int     `json:"losing_trades"`
+	BreakevenTrades int     `json:"breakeven_trades"`
+	TotalPnL        float64 `json:"total_pnl"`
+}
+
+// record books one closing trade
+func (pb *PerformanceBucket) record(pnl float64, outcome int) {
+	pb.Trades++
+	pb.TotalPnL += pnl
+	switch outcome {
+	case TradeOutcomeWin:
+		pb.WinningTrades++
+	case TradeOutcomeLoss:
+		pb.LosingTrades++
+	default:
+		pb.BreakevenTrades++
+	}
+}
+
+// GetWinRate returns the percent of trades that won
+func (pb *PerformanceBucket) GetWinRate() float64 {
+	if pb.Trades == 0 {
+		return 0
+	}
+	return float64(pb.WinningTrades) / float64(pb.Trades) * 100
+}
+
+// GetAveragePnL returns the average P&L per trade
+func (pb *PerformanceBucket) GetAveragePnL() float64 {
+	if pb.Trades == 0 {
+		return 0
+	}
+	return pb.TotalPnL / float64(pb.Trades)
+}
+
+// String returns a human-readable representation
+func (pb *PerformanceBucket) String() string {
+	return fmt.Sprintf(
+		"Bucket[%s Trades:%d WinRate:%.1f%% AvgPnL:%.2f]",
+		pb.Label,
+		pb.Trades,
+		pb.GetWinRate(),
+		pb.GetAveragePnL(),
+	)
+}
+
+// ==================== TIME BREAKDOWN ====================
+
+// TimeBreakdown buckets closing trades by trading session, by UTC hour and
+// by UTC weekday and hour of the fill that closed them, to show when a
+// strategy makes money
+// A trade in overlapping sessions counts toward each of them
+type TimeBreakdown struct {
+	Sessions []SessionHour
+
+	sessions []PerformanceBucket
+	hours    [24]PerformanceBucket
+	heatmap  [7][24]PerformanceBucket // By time.Weekday, then hour
+}
+
+// HeatmapCell is one weekday and UTC hour of the trade heatmap
+type HeatmapCell struct {
+	Weekday    string  `json:"weekday"`
+	Hour       int     `json:"hour"`
+	Trades     int     `json:"trades"`
+	WinRate    float64 `json:"win_rate"`
+	AveragePnL float64 `json:"average_pnl"`
+	TotalPnL   float64 `json:"total_pnl"`
+}
+
+// heatmapWeekdays is the heatmap's row order, the trading week first
+var heatmapWeekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// NewTimeBreakdown creates a breakdown over sessions, or
+// DefaultTradingSessions when none are given
+func NewTimeBreakdown(sessions []SessionHour) *TimeBreakdown {
+	if len(sessions) == 0 {
+		sessions = DefaultTradingSessions
+	}
+
+	tb := &TimeBreakdown{Sessions: sessions}
+	tb.Reset()
+	return tb
+}
+
+// RecordTrade books a closing trade's P&L and outcome (see
+// BreakevenBand.Classify) at its fill time
+func (tb *TimeBreakdown) RecordTrade(timestamp time.Time, pnl float64, outcome int) {
+	hour := timestamp.UTC().Hour()
+	tb.hours[hour].record(pnl, outcome)
+	tb.heatmap[timestamp.UTC().Weekday()][hour].record(pnl, outcome)
+	for i, session := range tb.Sessions {
+		if session.Contains(hour) {
+			tb.sessions[i].record(pnl, outcome)
+		}
+	}
+}
+
+// GetSessions returns the per-session buckets in session order
+func (tb *TimeBreakdown) GetSessions() []PerformanceBucket {
+	sessions := make([]PerformanceBucket, len(tb.sessions))
+	copy(sessions, tb.sessions)
+	return sessions
+}
+
+// GetHours returns the buckets of the UTC hours that had trades
+func (tb *TimeBreakdown) GetHours() []PerformanceBucket {
+	hours := make([]PerformanceBucket, 0)
+	for _, bucket := range tb.hours {
+		if bucket.Trades > 0 {
+			hours = append(hours, bucket)
+		}
+	}
+	return hours
+}
+
+// GetHeatmap returns the weekday x hour matrix as 168 cells, Monday 00:00
+// first, including the cells without trades
+func (tb *TimeBreakdown) GetHeatmap() []HeatmapCell {
+	cells := make([]HeatmapCell, 0, 7*24)
+	for _, weekday := range heatmapWeekdays {
+		for hour, bucket := range tb.heatmap[weekday] {
+			cells = append(cells, HeatmapCell{
+				Weekday:    weekday.String(),
+				Hour:       hour,
+				Trades:     bucket.Trades,
+				WinRate:    bucket.GetWinRate(),
+				AveragePnL: bucket.GetAveragePnL(),
+				TotalPnL:   bucket.TotalPnL,
+			})
+		}
+	}
+	return cells
+}
+
+// GetStatistics returns trades, win rate and average P&L per session and
+// per traded hour
+func (tb *TimeBreakdown) GetStatistics() map[string]interface{} {
+	summarize := func(buckets []PerformanceBucket) map[string]interface{} {
+		summary := make(map[string]interface{}, len(buckets))
+		for _, bucket := range buckets {
+			summary[bucket.Label] = map[string]interface{}{
+				"trades":      bucket.Trades,
+				"win_rate":    bucket.GetWinRate(),
+				"average_pnl": bucket.GetAveragePnL(),
+				"total_pnl":   bucket.TotalPnL,
+			}
+		}
+		return summary
+	}
+
+	return map[string]interface{}{
+		"sessions": summarize(tb.sessions),
+		"hours":    summarize(tb.GetHours()),
+	}
+}
+
+// Reset clears all buckets
+func (tb *TimeBreakdown) Reset() {
+	tb.sessions = make([]PerformanceBucket, len(tb.Sessions))
+	for i, session := range tb.Sessions {
+		tb.sessions[i].Label = session.Name
+	}
+	for hour := range tb.hours {
+		tb.hours[hour] = PerformanceBucket{Label: fmt.Sprintf("%02d:00", hour)}
+	}
+	for weekday := range tb.heatmap {
+		for hour := range tb.heatmap[weekday] {
+			tb.heatmap[weekday][hour] = PerformanceBucket{
+				Label: fmt.Sprintf("%s %02d:00", time.Weekday(weekday), hour),
+			}
+		}
+	}
+}
+
+// String returns a human-readable representation
+func (tb *TimeBreakdown) String() string {
+	trades := 0
+	for _, bucket := range tb.hours {
+		trades += bucket.Trades
+	}
+	return fmt.Sprintf("TimeBreakdown[Sessions:%d, Trades:%d]", len(tb.Sessions), trades)
+}
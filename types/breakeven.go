@@ -0,0 +1,58 @@
+package types
+
+import (
+	"fmt"
+	"math"
+)
+
+// ==================== BREAKEVEN BAND ====================
+
+// Trade outcomes returned by BreakevenBand.Classify
+const (
+	TradeOutcomeLoss      = -1
+	TradeOutcomeBreakeven = 0
+	TradeOutcomeWin       = 1
+)
+
+// BreakevenBand is the P&L band around zero within which a closed trade
+// counts as breakeven rather than a win or loss, so commission-sized noise
+// does not skew win rates
+// Amount is in P&L currency; Pips is converted per unit of closed size at
+// one P&L unit per pip, the simulator's position P&L convention. When both
+// are set the wider band applies; the zero band keeps exact classification
+type BreakevenBand struct {
+	Amount float64 `json:"amount"`
+	Pips   float64 `json:"pips"`
+}
+
+// Validate checks that the band is not negative
+func (bb BreakevenBand) Validate() error {
+	if bb.Amount < 0 || bb.Pips < 0 {
+		return fmt.Errorf("breakeven band cannot be negative")
+	}
+	return nil
+}
+
+// GetThreshold returns the band's half-width for a trade of size
+func (bb BreakevenBand) GetThreshold(size float64) float64 {
+	return math.Max(bb.Amount, bb.Pips*math.Abs(size))
+}
+
+// Classify returns TradeOutcomeWin, TradeOutcomeLoss or
+// TradeOutcomeBreakeven for a trade's realized P&L
+func (bb BreakevenBand) Classify(pnl, size float64) int {
+	threshold := bb.GetThreshold(size)
+	switch {
+	case pnl > threshold:
+		return TradeOutcomeWin
+	case pnl < -threshold:
+		return TradeOutcomeLoss
+	default:
+		return TradeOutcomeBreakeven
+	}
+}
+
+// String returns a human-readable representation
+func (bb BreakevenBand) String() string {
+	return fmt.Sprintf("BreakevenBand[Amount:%.2f, Pips:%.2f]", bb.Amount, bb.Pips)
+}
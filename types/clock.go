@@ -0,0 +1,149 @@
+package types
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ==================== CLOCKS ====================
+
+// Clock tells the current time
+// Code that takes a Clock instead of calling time.Now() works in simulated
+// time when given the session's clock, and in wall-clock time otherwise
+type Clock interface {
+	Now() time.Time
+}
+
+// WallClock is the system clock
+type WallClock struct{}
+
+// Now returns the wall-clock time
+func (WallClock) Now() time.Time {
+	return time.Now()
+}
+
+// ClockFunc adapts a function to a Clock
+type ClockFunc func() time.Time
+
+// Now calls the function
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// Sleeper is a Clock that can also wait; code that sleeps on a Clock
+// (see Sleep) waits on it instead of the wall clock
+type Sleeper interface {
+	Clock
+	Sleep(d time.Duration)
+}
+
+// Sleep waits for d on the clock: a Sleeper's own Sleep, or time.Sleep
+// for any other clock (including nil)
+func Sleep(clock Clock, d time.Duration) {
+	if sleeper, ok := clock.(Sleeper); ok {
+		sleeper.Sleep(d)
+		return
+	}
+	time.Sleep(d)
+}
+
+// SleepContext is Sleep that gives up when ctx is done, returning
+// ctx.Err(); a Sleeper's own Sleep does not block, so it only checks ctx
+// before sleeping
+func SleepContext(ctx context.Context, clock Clock, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if sleeper, ok := clock.(Sleeper); ok {
+		sleeper.Sleep(d)
+		return nil
+	}
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Since returns the time elapsed since t on the clock (wall clock if nil)
+func Since(clock Clock, t time.Time) time.Duration {
+	if clock == nil {
+		return time.Since(t)
+	}
+	return clock.Now().Sub(t)
+}
+
+// ==================== MANUAL CLOCK ====================
+
+// ManualClock is a virtual clock that only moves when told to: Set and
+// Advance move it directly, and Sleep advances it instantly instead of
+// waiting. Given to the speed controller, balance and loggers in place of
+// the wall clock, it makes tests and replays fully deterministic
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps int64
+	slept  time.Duration
+}
+
+// NewManualClock creates a manual clock reading start
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now returns the clock's time
+func (mc *ManualClock) Now() time.Time {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.now
+}
+
+// Set moves the clock to t
+func (mc *ManualClock) Set(t time.Time) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.now = t
+}
+
+// Advance moves the clock forward by d and returns the new time
+func (mc *ManualClock) Advance(d time.Duration) time.Time {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.now = mc.now.Add(d)
+	return mc.now
+}
+
+// Sleep advances the clock by d without waiting
+func (mc *ManualClock) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.now = mc.now.Add(d)
+	mc.sleeps++
+	mc.slept += d
+}
+
+// GetSleepCount returns the number of Sleep calls that advanced the clock
+func (mc *ManualClock) GetSleepCount() int64 {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.sleeps
+}
+
+// GetSleptTime returns the total time advanced by Sleep
+func (mc *ManualClock) GetSleptTime() time.Duration {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.slept
+}
@@ -32,6 +32,11 @@ const (
 	OrderStatusRejected  = "REJECTED"
 	OrderStatusPending   = "PENDING"
 	OrderStatusCancelled = "CANCELLED"
+	OrderStatusExpired   = "EXPIRED"
+
+	// OrderStatusBlown marks the record of negative balance protection
+	// writing the account off at zero
+	OrderStatusBlown = "BLOWN"
 )
 
 // ==================== ACCOUNT STATUS ====================
@@ -40,6 +45,7 @@ const (
 	AccountStatusActive  = "ACTIVE"
 	AccountStatusBlown   = "BLOWN"
 	AccountStatusAtLimit = "AT_LIMIT"
+	AccountStatusHalted  = "HALTED" // Trading halted by a risk control
 )
 
 // ==================== POSITION STATUS ====================
@@ -50,6 +56,30 @@ const (
 	PositionStatusShort = "SHORT"
 )
 
+// ==================== POSITION MODES ====================
+
+const (
+	// PositionModeNetting nets every fill into one position per instrument
+	PositionModeNetting = "netting"
+
+	// PositionModeHedging tracks each opening fill as its own ticket, so
+	// opposite positions on one instrument can be held at the same time
+	PositionModeHedging = "hedging"
+)
+
+// ==================== LOT MATCHING ====================
+
+const (
+	// LotMatchingAverage closes at the average entry price (average cost)
+	LotMatchingAverage = "average"
+
+	// LotMatchingFIFO closes the oldest open lots first
+	LotMatchingFIFO = "fifo"
+
+	// LotMatchingLIFO closes the newest open lots first
+	LotMatchingLIFO = "lifo"
+)
+
 // ==================== ERROR CODES ====================
 
 const (
@@ -57,6 +87,8 @@ const (
 	ErrorCodePositionLimitExceeded = "POSITION_LIMIT_EXCEEDED"
 	ErrorCodeInvalidOrderType      = "INVALID_ORDER_TYPE"
 	ErrorCodeInvalidLimitPrice     = "INVALID_LIMIT_PRICE"
+	ErrorCodeLimitAboveAsk         = "LIMIT_ABOVE_ASK"
+	ErrorCodeLimitBelowBid         = "LIMIT_BELOW_BID"
 	ErrorCodeInvalidOrderSize      = "INVALID_ORDER_SIZE"
 	ErrorCodeOrderRejected         = "ORDER_REJECTED"
 	ErrorCodeAccountBlown          = "ACCOUNT_BLOWN"
@@ -66,6 +98,13 @@ const (
 	ErrorCodeConfigError           = "CONFIG_ERROR"
 	ErrorCodeInstrumentNotFound    = "INSTRUMENT_NOT_FOUND"
 	ErrorCodeInvalidInstrumentType = "INVALID_INSTRUMENT_TYPE"
+	ErrorCodeNoLiquidity           = "NO_LIQUIDITY"
+	ErrorCodeSpreadTooWide         = "SPREAD_TOO_WIDE"
+	ErrorCodeStaleTick             = "STALE_TICK"
+	ErrorCodeExposureLimitExceeded = "EXPOSURE_LIMIT_EXCEEDED"
+	ErrorCodeTradingHalted         = "TRADING_HALTED"
+	ErrorCodeMetricAlert           = "METRIC_ALERT"
+	ErrorCodeSessionPaused         = "SESSION_PAUSED"
 )
 
 // ==================== COMMISSION TYPES ====================
@@ -84,6 +123,14 @@ const (
 	SlippageModelMomentum = "momentum"
 	SlippageModelFixed    = "fixed"
 	SlippageModelNone     = "none"
+	SlippageModelImpact   = "impact"
+)
+
+// ==================== SPREAD MARKUP ====================
+
+const (
+	SpreadMarkupPips    = "pips"    // Fixed markup in pips/units
+	SpreadMarkupPercent = "percent" // Markup as a percent of the mid price
 )
 
 // ==================== MOMENTUM LEVELS ====================
@@ -100,6 +147,10 @@ const (
 	PartialFillByVolumeMomentum = "volume_momentum"
 	PartialFillByDepth          = "depth"
 	PartialFillNone             = "none"
+
+	// PartialFillByParticipation caps each tick's fill at a share of the
+	// tick's traded volume, like an execution algo working a parent order
+	PartialFillByParticipation = "participation"
 )
 
 // ==================== LOGGING LEVELS ====================
@@ -250,7 +301,7 @@ func IsValidOrderType(orderType string) bool {
 // IsValidOrderStatus checks if the order status is valid
 func IsValidOrderStatus(status string) bool {
 	switch status {
-	case OrderStatusFilled, OrderStatusPartial, OrderStatusRejected, OrderStatusPending, OrderStatusCancelled:
+	case OrderStatusFilled, OrderStatusPartial, OrderStatusRejected, OrderStatusPending, OrderStatusCancelled, OrderStatusExpired, OrderStatusBlown:
 		return true
 	default:
 		return false
@@ -260,7 +311,7 @@ func IsValidOrderStatus(status string) bool {
 // IsValidAccountStatus checks if the account status is valid
 func IsValidAccountStatus(status string) bool {
 	switch status {
-	case AccountStatusActive, AccountStatusBlown, AccountStatusAtLimit:
+	case AccountStatusActive, AccountStatusBlown, AccountStatusAtLimit, AccountStatusHalted:
 		return true
 	default:
 		return false
@@ -277,6 +328,26 @@ func IsValidPositionStatus(status string) bool {
 	}
 }
 
+// IsValidPositionMode checks if the position mode is valid ("" = netting)
+func IsValidPositionMode(mode string) bool {
+	switch mode {
+	case "", PositionModeNetting, PositionModeHedging:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidLotMatching checks if the lot matching method is valid ("" = average)
+func IsValidLotMatching(method string) bool {
+	switch method {
+	case "", LotMatchingAverage, LotMatchingFIFO, LotMatchingLIFO:
+		return true
+	default:
+		return false
+	}
+}
+
 // GetPositionStatusFromSize returns position status based on size
 func GetPositionStatusFromSize(size float64) string {
 	if size == 0 {
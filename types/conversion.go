@@ -0,0 +1,141 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ==================== CURRENCY CONVERSION ====================
+
+// Conversion sources
+const (
+	ConversionSourceStatic = "static" // Fixed configured rate
+	ConversionSourceTick   = "tick"   // Mid price of a companion tick stream
+)
+
+// IsValidConversionSource checks if a conversion source is supported
+// (empty means static)
+func IsValidConversionSource(source string) bool {
+	switch source {
+	case "", ConversionSourceStatic, ConversionSourceTick:
+		return true
+	default:
+		return false
+	}
+}
+
+// CurrencyConverter converts amounts in an instrument's quote currency to
+// the account currency, e.g. JPY P&L on EURJPY into a USD account
+// The rate is fixed, or follows the mid of a companion pair such as USDJPY
+// (inverted) or JPYUSD (direct)
+type CurrencyConverter struct {
+	// From is the instrument's quote currency
+	From string
+
+	// To is the account currency
+	To string
+
+	// Rate is units of To per unit of From
+	Rate float64
+
+	// Symbol is the companion pair driving the rate (empty when static)
+	Symbol string
+
+	// Updates counts the companion ticks applied
+	Updates int64
+
+	invert bool
+}
+
+// NewStaticConverter creates a converter with a fixed rate
+func NewStaticConverter(from, to string, rate float64) (*CurrencyConverter, error) {
+	if rate <= 0 {
+		return nil, fmt.Errorf("conversion rate %s->%s must be positive", from, to)
+	}
+	return &CurrencyConverter{
+		From: from,
+		To:   to,
+		Rate: rate,
+	}, nil
+}
+
+// NewTickConverter creates a converter whose rate follows the mid price of
+// a companion pair; the pair must be From+To or To+From (separators such as
+// "/" and "_" are ignored)
+// initialRate is used until the first companion tick and must be positive,
+// so no P&L is ever booked unconverted
+func NewTickConverter(from, to, symbol string, initialRate float64) (*CurrencyConverter, error) {
+	if initialRate <= 0 {
+		return nil, fmt.Errorf("conversion rate %s->%s must be positive until the first %s tick", from, to, symbol)
+	}
+
+	pair := normalizePair(symbol)
+	invert := false
+	switch pair {
+	case strings.ToUpper(from + to):
+	case strings.ToUpper(to + from):
+		invert = true
+	default:
+		return nil, fmt.Errorf("conversion pair %s does not quote %s against %s", symbol, from, to)
+	}
+
+	return &CurrencyConverter{
+		From:   from,
+		To:     to,
+		Rate:   initialRate,
+		Symbol: symbol,
+		invert: invert,
+	}, nil
+}
+
+// normalizePair strips separators from a currency pair symbol
+func normalizePair(symbol string) string {
+	return strings.ToUpper(strings.NewReplacer("/", "", "_", "", "-", "").Replace(symbol))
+}
+
+// Convert converts an amount from the quote currency to the account currency
+func (cc *CurrencyConverter) Convert(amount float64) float64 {
+	return amount * cc.Rate
+}
+
+// UpdateFromTick takes the rate from a companion tick
+// Returns true if the tick was for the companion pair and had a price
+func (cc *CurrencyConverter) UpdateFromTick(tick *Tick) bool {
+	if cc.Symbol == "" || tick == nil || normalizePair(tick.Symbol) != normalizePair(cc.Symbol) {
+		return false
+	}
+
+	mid := tick.GetBidAskCenter()
+	if mid <= 0 {
+		return false
+	}
+
+	if cc.invert {
+		cc.Rate = 1 / mid
+	} else {
+		cc.Rate = mid
+	}
+	cc.Updates++
+	return true
+}
+
+// GetStatistics returns converter statistics
+func (cc *CurrencyConverter) GetStatistics() map[string]interface{} {
+	source := ConversionSourceStatic
+	if cc.Symbol != "" {
+		source = ConversionSourceTick
+	}
+	return map[string]interface{}{
+		"from":    cc.From,
+		"to":      cc.To,
+		"rate":    cc.Rate,
+		"source":  source,
+		"symbol":  cc.Symbol,
+		"updates": cc.Updates,
+	}
+}
+
+// String returns a human-readable representation
+func (cc *CurrencyConverter) String() string {
+	return fmt.Sprintf("Converter[%s->%s @ %.6f]", cc.From, cc.To, cc.Rate)
+}
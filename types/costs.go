@@ -0,0 +1,132 @@
+package types
+
+import "fmt"
+
+// ==================== LOT COSTS ====================
+
+// LotCosts is the friction carried by an open lot, or booked to a closed
+// one, besides commission
+// Spread and Slippage are costs (positive = paid) against the mid price,
+// in the same units as realized P&L; the spread includes any broker
+// markup. Swap is financing in the account currency (positive = credit)
+type LotCosts struct {
+	Spread   float64 `json:"spread"`
+	Slippage float64 `json:"slippage"`
+	Swap     float64 `json:"swap"`
+}
+
+// scale returns the costs of a share of the lot
+func (lc LotCosts) scale(share float64) LotCosts {
+	return LotCosts{
+		Spread:   lc.Spread * share,
+		Slippage: lc.Slippage * share,
+		Swap:     lc.Swap * share,
+	}
+}
+
+// add returns the sum of two sets of costs
+func (lc LotCosts) add(other LotCosts) LotCosts {
+	return LotCosts{
+		Spread:   lc.Spread + other.Spread,
+		Slippage: lc.Slippage + other.Slippage,
+		Swap:     lc.Swap + other.Swap,
+	}
+}
+
+// ==================== COST BREAKDOWN ====================
+
+// CostBreakdown decomposes the P&L of one or more closed trades, in the
+// account currency: the gross P&L at mid prices, less each friction term,
+// is the net P&L
+// NetPnL = GrossPnL - SpreadCost - SlippageCost - Commission + Swap
+type CostBreakdown struct {
+	Trades       int     `json:"trades"`
+	GrossPnL     float64 `json:"gross_pnl"`
+	SpreadCost   float64 `json:"spread_cost"`
+	SlippageCost float64 `json:"slippage_cost"`
+	Commission   float64 `json:"commission"`
+	Swap         float64 `json:"swap"` // Positive = credit
+	NetPnL       float64 `json:"net_pnl"`
+}
+
+// NewCostBreakdown decomposes a closed lot's P&L
+// convert turns amounts in the P&L currency into the account currency
+// (nil = no conversion)
+func NewCostBreakdown(lot ClosedLot, convert func(float64) float64) CostBreakdown {
+	if convert == nil {
+		convert = func(amount float64) float64 { return amount }
+	}
+
+	cb := CostBreakdown{
+		Trades:       1,
+		SpreadCost:   convert(lot.Costs.Spread),
+		SlippageCost: convert(lot.Costs.Slippage),
+		Commission:   convert(lot.Commission + lot.ExitCommission),
+		Swap:         lot.Costs.Swap,
+	}
+	cb.GrossPnL = convert(lot.RealizedPnL) + cb.SpreadCost + cb.SlippageCost
+	cb.NetPnL = cb.GrossPnL - cb.GetTotalCost()
+	return cb
+}
+
+// Add accumulates another breakdown
+func (cb *CostBreakdown) Add(other CostBreakdown) {
+	cb.Trades += other.Trades
+	cb.GrossPnL += other.GrossPnL
+	cb.SpreadCost += other.SpreadCost
+	cb.SlippageCost += other.SlippageCost
+	cb.Commission += other.Commission
+	cb.Swap += other.Swap
+	cb.NetPnL += other.NetPnL
+}
+
+// GetTotalCost returns the net friction (a swap credit offsets the costs)
+func (cb *CostBreakdown) GetTotalCost() float64 {
+	return cb.SpreadCost + cb.SlippageCost + cb.Commission - cb.Swap
+}
+
+// GetCostShare returns each friction term as a percent of the gross P&L's
+// magnitude (empty when the gross P&L is zero)
+func (cb *CostBreakdown) GetCostShare() map[string]float64 {
+	shares := make(map[string]float64, 4)
+	gross := cb.GrossPnL
+	if gross < 0 {
+		gross = -gross
+	}
+	if gross == 0 {
+		return shares
+	}
+	shares["spread"] = cb.SpreadCost / gross * 100
+	shares["slippage"] = cb.SlippageCost / gross * 100
+	shares["commission"] = cb.Commission / gross * 100
+	shares["swap"] = -cb.Swap / gross * 100
+	return shares
+}
+
+// GetStatistics returns the breakdown
+func (cb *CostBreakdown) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"trades":        cb.Trades,
+		"gross_pnl":     cb.GrossPnL,
+		"spread_cost":   cb.SpreadCost,
+		"slippage_cost": cb.SlippageCost,
+		"commission":    cb.Commission,
+		"swap":          cb.Swap,
+		"net_pnl":       cb.NetPnL,
+		"total_cost":    cb.GetTotalCost(),
+		"cost_share":    cb.GetCostShare(),
+	}
+}
+
+// String returns a human-readable representation
+func (cb *CostBreakdown) String() string {
+	return fmt.Sprintf(
+		"Costs[Gross:%.2f Spread:%.2f Slippage:%.2f Commission:%.2f Swap:%.2f Net:%.2f]",
+		cb.GrossPnL,
+		cb.SpreadCost,
+		cb.SlippageCost,
+		cb.Commission,
+		cb.Swap,
+		cb.NetPnL,
+	)
+}
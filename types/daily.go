@@ -0,0 +1,223 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ==================== DAILY RECORD ====================
+
+// DailyRecord is one trading day's accounting, keyed by the UTC date of
+// the tick timestamps; money values are in the account currency
+type DailyRecord struct {
+	Date string `json:"date"` // YYYY-MM-DD
+
+	OpenEquity  float64 `json:"open_equity"`
+	CloseEquity float64 `json:"close_equity"`
+	PeakEquity  float64 `json:"peak_equity"`
+
+	RealizedPnL  float64 `json:"realized_pnl"`
+	Commission   float64 `json:"commission"`
+	FinancingPnL float64 `json:"financing_pnl"`
+	DividendPnL  float64 `json:"dividend_pnl"`
+	InterestPnL  float64 `json:"interest_pnl"`
+
+	Trades        int `json:"trades"`
+	WinningTrades int `json:"winning_trades"`
+	LosingTrades  int `json:"losing_trades"`
+
+	// Largest drop from the day's running peak equity
+	MaxIntradayDrawdown        float64 `json:"max_intraday_drawdown"`
+	MaxIntradayDrawdownPercent float64 `json:"max_intraday_drawdown_percent"`
+}
+
+// GetNetPnL returns the day's change in equity (realized, unrealized,
+// commission, financing, dividends and interest)
+func (dr *DailyRecord) GetNetPnL() float64 {
+	return dr.CloseEquity - dr.OpenEquity
+}
+
+// String returns a human-readable representation
+func (dr *DailyRecord) String() string {
+	return fmt.Sprintf(
+		"Day[%s Net:%.2f Realized:%.2f Comm:%.2f Trades:%d MaxDD:%.2f%%]",
+		dr.Date,
+		dr.GetNetPnL(),
+		dr.RealizedPnL,
+		dr.Commission,
+		dr.Trades,
+		dr.MaxIntradayDrawdownPercent,
+	)
+}
+
+// ==================== DAILY LEDGER ====================
+
+// DailyLedger rolls account activity up into DailyRecords; a new day starts
+// with the first event whose timestamp falls on a new UTC date
+type DailyLedger struct {
+	days    []*DailyRecord
+	current *DailyRecord
+}
+
+// NewDailyLedger creates an empty daily ledger
+func NewDailyLedger() *DailyLedger {
+	return &DailyLedger{
+		days: make([]*DailyRecord, 0),
+	}
+}
+
+// dayKey returns the ledger date of a timestamp
+func dayKey(timestamp time.Time) string {
+	return timestamp.UTC().Format("2006-01-02")
+}
+
+// day returns the record for timestamp's date, rolling over to a new day
+// that opens at equity when the date changes
+func (dl *DailyLedger) day(timestamp time.Time, equity float64) *DailyRecord {
+	key := dayKey(timestamp)
+	if dl.current != nil && dl.current.Date == key {
+		return dl.current
+	}
+
+	open := equity
+	if dl.current != nil {
+		open = dl.current.CloseEquity
+	}
+
+	dl.current = &DailyRecord{
+		Date:        key,
+		OpenEquity:  open,
+		CloseEquity: open,
+		PeakEquity:  open,
+	}
+	dl.days = append(dl.days, dl.current)
+	return dl.current
+}
+
+// RecordEquity marks the day's equity and tracks its intraday drawdown
+func (dl *DailyLedger) RecordEquity(timestamp time.Time, equity float64) {
+	day := dl.day(timestamp, equity)
+	day.CloseEquity = equity
+	day.PeakEquity = math.Max(day.PeakEquity, equity)
+
+	drawdown := day.PeakEquity - equity
+	if drawdown > day.MaxIntradayDrawdown {
+		day.MaxIntradayDrawdown = drawdown
+		if day.PeakEquity > 0 {
+			day.MaxIntradayDrawdownPercent = drawdown / day.PeakEquity * 100
+		}
+	}
+}
+
+// RecordTrade books a fill's realized P&L and commission (account currency)
+func (dl *DailyLedger) RecordTrade(timestamp time.Time, realizedPnL, commission, equity float64) {
+	day := dl.day(timestamp, equity)
+	day.RealizedPnL += realizedPnL
+	day.Commission += commission
+	day.Trades++
+	if realizedPnL > 0 {
+		day.WinningTrades++
+	} else if realizedPnL < 0 {
+		day.LosingTrades++
+	}
+	dl.RecordEquity(timestamp, equity)
+}
+
+// RecordFinancing books an overnight swap credit or debit
+func (dl *DailyLedger) RecordFinancing(timestamp time.Time, amount, equity float64) {
+	dl.day(timestamp, equity).FinancingPnL += amount
+	dl.RecordEquity(timestamp, equity)
+}
+
+// RecordDividend books a dividend credit or charge
+func (dl *DailyLedger) RecordDividend(timestamp time.Time, amount, equity float64) {
+	dl.day(timestamp, equity).DividendPnL += amount
+	dl.RecordEquity(timestamp, equity)
+}
+
+// RecordInterest books interest accrued on idle cash
+func (dl *DailyLedger) RecordInterest(timestamp time.Time, amount, equity float64) {
+	dl.day(timestamp, equity).InterestPnL += amount
+	dl.RecordEquity(timestamp, equity)
+}
+
+// ==================== LEDGER QUERIES ====================
+
+// GetDays returns copies of the daily records, oldest first
+func (dl *DailyLedger) GetDays() []DailyRecord {
+	days := make([]DailyRecord, len(dl.days))
+	for i, day := range dl.days {
+		days[i] = *day
+	}
+	return days
+}
+
+// GetDay returns the record for a date (YYYY-MM-DD)
+func (dl *DailyLedger) GetDay(date string) (DailyRecord, bool) {
+	for _, day := range dl.days {
+		if day.Date == date {
+			return *day, true
+		}
+	}
+	return DailyRecord{}, false
+}
+
+// GetCurrent returns today's record (nil before the first event)
+func (dl *DailyLedger) GetCurrent() *DailyRecord {
+	if dl.current == nil {
+		return nil
+	}
+	day := *dl.current
+	return &day
+}
+
+// GetStatistics returns daily P&L statistics
+func (dl *DailyLedger) GetStatistics() map[string]interface{} {
+	winning, losing := 0, 0
+	best, worst, total := 0.0, 0.0, 0.0
+	maxIntraday := 0.0
+
+	for i, day := range dl.days {
+		net := day.GetNetPnL()
+		total += net
+		if net > 0 {
+			winning++
+		} else if net < 0 {
+			losing++
+		}
+		if i == 0 || net > best {
+			best = net
+		}
+		if i == 0 || net < worst {
+			worst = net
+		}
+		maxIntraday = math.Max(maxIntraday, day.MaxIntradayDrawdownPercent)
+	}
+
+	average := 0.0
+	if len(dl.days) > 0 {
+		average = total / float64(len(dl.days))
+	}
+
+	return map[string]interface{}{
+		"days":                          len(dl.days),
+		"winning_days":                  winning,
+		"losing_days":                   losing,
+		"best_day_pnl":                  best,
+		"worst_day_pnl":                 worst,
+		"average_daily_pnl":             average,
+		"max_intraday_drawdown_percent": maxIntraday,
+	}
+}
+
+// Reset clears the ledger
+func (dl *DailyLedger) Reset() {
+	dl.days = make([]*DailyRecord, 0)
+	dl.current = nil
+}
+
+// String returns a human-readable representation
+func (dl *DailyLedger) String() string {
+	return fmt.Sprintf("DailyLedger[Days:%d]", len(dl.days))
+}
@@ -0,0 +1,252 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ==================== RETURN DISTRIBUTION ====================
+
+// DistributionBins is the number of histogram buckets in a
+// ReturnDistribution
+const DistributionBins = 20
+
+// DistributionBucket is one histogram bucket, [Lower, Upper) except the
+// last, which includes its upper bound
+type DistributionBucket struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count int     `json:"count"`
+}
+
+// ReturnDistribution describes the shape of a set of trade returns: the
+// moments beyond mean and deviation show whether a strategy's results lean
+// on a few outliers
+type ReturnDistribution struct {
+	Count    int                  `json:"count"`
+	Mean     float64              `json:"mean"`
+	StdDev   float64              `json:"std_dev"`  // Sample standard deviation
+	Skewness float64              `json:"skewness"` // > 0 = long right tail
+	Kurtosis float64              `json:"kurtosis"` // Excess kurtosis, > 0 = fat tails
+	Min      float64              `json:"min"`
+	Max      float64              `json:"max"`
+	Buckets  []DistributionBucket `json:"buckets"`
+}
+
+// NewReturnDistribution computes the moments and histogram of values over
+// bins equal-width buckets (bins <= 0 uses DistributionBins)
+// Skewness needs 3 values and kurtosis 4; with fewer they stay 0
+func NewReturnDistribution(values []float64, bins int) *ReturnDistribution {
+	if bins <= 0 {
+		bins = DistributionBins
+	}
+	rd := &ReturnDistribution{Count: len(values), Buckets: make([]DistributionBucket, 0)}
+	if len(values) == 0 {
+		return rd
+	}
+
+	rd.Min, rd.Max = values[0], values[0]
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+		rd.Min = math.Min(rd.Min, v)
+		rd.Max = math.Max(rd.Max, v)
+	}
+	n := float64(len(values))
+	rd.Mean = sum / n
+
+	m2, m3, m4 := 0.0, 0.0, 0.0
+	for _, v := range values {
+		d := v - rd.Mean
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+	if len(values) > 1 {
+		rd.StdDev = math.Sqrt(m2 / (n - 1))
+	}
+
+	// Bias-corrected sample skewness and excess kurtosis, as spreadsheets
+	// report them
+	if rd.StdDev > 0 {
+		s := rd.StdDev
+		if len(values) > 2 {
+			rd.Skewness = n / ((n - 1) * (n - 2)) * m3 / (s * s * s)
+		}
+		if len(values) > 3 {
+			rd.Kurtosis = n*(n+1)/((n-1)*(n-2)*(n-3))*m4/(s*s*s*s) -
+				3*(n-1)*(n-1)/((n-2)*(n-3))
+		}
+	}
+
+	width := (rd.Max - rd.Min) / float64(bins)
+	if width == 0 {
+		rd.Buckets = append(rd.Buckets, DistributionBucket{Lower: rd.Min, Upper: rd.Max, Count: len(values)})
+		return rd
+	}
+	for i := 0; i < bins; i++ {
+		lower := rd.Min + float64(i)*width
+		rd.Buckets = append(rd.Buckets, DistributionBucket{Lower: lower, Upper: lower + width})
+	}
+	for _, v := range values {
+		bin := int((v - rd.Min) / width)
+		if bin >= bins {
+			bin = bins - 1
+		}
+		rd.Buckets[bin].Count++
+	}
+	return rd
+}
+
+// GetStatistics returns the distribution
+func (rd *ReturnDistribution) GetStatistics() map[string]interface{} {
+	return map[string]interface{}{
+		"count":    rd.Count,
+		"mean":     rd.Mean,
+		"std_dev":  rd.StdDev,
+		"skewness": rd.Skewness,
+		"kurtosis": rd.Kurtosis,
+		"min":      rd.Min,
+		"max":      rd.Max,
+		"buckets":  rd.Buckets,
+	}
+}
+
+// String returns a human-readable representation
+func (rd *ReturnDistribution) String() string {
+	return fmt.Sprintf(
+		"Distribution[N:%d Mean:%.4f StdDev:%.4f Skew:%.2f Kurt:%.2f]",
+		rd.Count,
+		rd.Mean,
+		rd.StdDev,
+		rd.Skewness,
+		rd.Kurtosis,
+	)
+}
+
+// ==================== STREAK DISTRIBUTION ====================
+
+// StreakCount is how many streaks of one length occurred
+type StreakCount struct {
+	Length int `json:"length"`
+	Count  int `json:"count"`
+}
+
+// StreakDistribution counts the winning and losing streaks of a trade
+// sequence by length, so a long losing run can be judged against how often
+// such runs occur
+// Break-even trades neither extend nor end a streak
+type StreakDistribution struct {
+	Wins        []StreakCount `json:"wins"`   // By length, shortest first
+	Losses      []StreakCount `json:"losses"` // By length, shortest first
+	MaxWin      int           `json:"max_win"`
+	MaxLoss     int           `json:"max_loss"`
+	AverageWin  float64       `json:"average_win"`  // Mean winning streak length
+	AverageLoss float64       `json:"average_loss"` // Mean losing streak length
+	CurrentWin  int           `json:"current_win"`
+	CurrentLoss int           `json:"current_loss"`
+}
+
+// NewStreakDistribution counts the streaks in a sequence of trade outcomes
+// (TradeOutcomeWin, TradeOutcomeLoss or TradeOutcomeBreakeven), oldest
+// first
+func NewStreakDistribution(outcomes []int) *StreakDistribution {
+	wins := make(map[int]int)
+	losses := make(map[int]int)
+
+	run, runOutcome := 0, TradeOutcomeBreakeven
+	closeRun := func() {
+		switch runOutcome {
+		case TradeOutcomeWin:
+			wins[run]++
+		case TradeOutcomeLoss:
+			losses[run]++
+		}
+	}
+	for _, outcome := range outcomes {
+		if outcome == TradeOutcomeBreakeven {
+			continue
+		}
+		if outcome != runOutcome {
+			closeRun()
+			run, runOutcome = 0, outcome
+		}
+		run++
+	}
+	closeRun()
+
+	sd := &StreakDistribution{
+		Wins:   sortedStreaks(wins),
+		Losses: sortedStreaks(losses),
+	}
+	sd.MaxWin, sd.AverageWin = streakSummary(sd.Wins)
+	sd.MaxLoss, sd.AverageLoss = streakSummary(sd.Losses)
+	switch runOutcome {
+	case TradeOutcomeWin:
+		sd.CurrentWin = run
+	case TradeOutcomeLoss:
+		sd.CurrentLoss = run
+	}
+	return sd
+}
+
+// sortedStreaks lists streak counts by length
+func sortedStreaks(counts map[int]int) []StreakCount {
+	streaks := make([]StreakCount, 0, len(counts))
+	for length, count := range counts {
+		streaks = append(streaks, StreakCount{Length: length, Count: count})
+	}
+	sort.Slice(streaks, func(i, j int) bool { return streaks[i].Length < streaks[j].Length })
+	return streaks
+}
+
+// streakSummary returns the longest and mean streak length
+func streakSummary(streaks []StreakCount) (longest int, average float64) {
+	total, count := 0, 0
+	for _, s := range streaks {
+		if s.Length > longest {
+			longest = s.Length
+		}
+		total += s.Length * s.Count
+		count += s.Count
+	}
+	if count > 0 {
+		average = float64(total) / float64(count)
+	}
+	return longest, average
+}
+
+// GetStatistics returns the streak figures, with each distribution keyed
+// by streak length
+func (sd *StreakDistribution) GetStatistics() map[string]interface{} {
+	byLength := func(streaks []StreakCount) map[string]int {
+		counts := make(map[string]int, len(streaks))
+		for _, s := range streaks {
+			counts[fmt.Sprintf("%d", s.Length)] = s.Count
+		}
+		return counts
+	}
+
+	return map[string]interface{}{
+		"win_streaks":         byLength(sd.Wins),
+		"loss_streaks":        byLength(sd.Losses),
+		"max_win_streak":      sd.MaxWin,
+		"max_loss_streak":     sd.MaxLoss,
+		"average_win_streak":  sd.AverageWin,
+		"average_loss_streak": sd.AverageLoss,
+		"current_win_streak":  sd.CurrentWin,
+		"current_loss_streak": sd.CurrentLoss,
+	}
+}
+
+// String returns a human-readable representation
+func (sd *StreakDistribution) String() string {
+	return fmt.Sprintf(
+		"Streaks[MaxWin:%d MaxLoss:%d AvgWin:%.1f AvgLoss:%.1f]",
+		sd.MaxWin,
+		sd.MaxLoss,
+		sd.AverageWin,
+		sd.AverageLoss,
+	)
+}
@@ -0,0 +1,324 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ==================== EQUITY POINT ====================
+
+// EquityPoint is one sample of the equity curve
+type EquityPoint struct {
+	Timestamp       time.Time `json:"timestamp"`
+	Balance         float64   `json:"balance"` // Equity excluding unrealized P&L
+	UnrealizedPnL   float64   `json:"unrealized_pnl"`
+	Equity          float64   `json:"equity"`
+	Peak            float64   `json:"peak"`
+	Drawdown        float64   `json:"drawdown"`
+	DrawdownPercent float64   `json:"drawdown_percent"`
+}
+
+// ==================== EQUITY CURVE ====================
+
+// EquityCurve samples account equity (balance + unrealized P&L) over the
+// simulated timeline; drawdown metrics are computed from the samples, so
+// they include intra-trade moves that trade-by-trade balances miss
+type EquityCurve struct {
+	// Interval is the minimum simulated time between samples (0 = every tick)
+	Interval time.Duration
+
+	points     []EquityPoint
+	lastSample time.Time
+	peak       float64
+}
+
+// NewEquityCurve creates an empty equity curve
+func NewEquityCurve(interval time.Duration) *EquityCurve {
+	return &EquityCurve{
+		Interval: interval,
+		points:   make([]EquityPoint, 0),
+	}
+}
+
+// Record samples equity at timestamp if the interval has elapsed since the
+// previous sample
+// Returns true if a sample was taken
+func (ec *EquityCurve) Record(timestamp time.Time, equity, unrealizedPnL float64) bool {
+	if len(ec.points) > 0 && timestamp.Sub(ec.lastSample) < ec.Interval {
+		return false
+	}
+	ec.add(timestamp, equity, unrealizedPnL)
+	return true
+}
+
+// Close samples the final equity regardless of the interval, so the curve
+// ends at the session's last tick
+func (ec *EquityCurve) Close(timestamp time.Time, equity, unrealizedPnL float64) {
+	if n := len(ec.points); n > 0 && ec.points[n-1].Timestamp.Equal(timestamp) {
+		return
+	}
+	ec.add(timestamp, equity, unrealizedPnL)
+}
+
+// add appends a sample and updates the running peak
+func (ec *EquityCurve) add(timestamp time.Time, equity, unrealizedPnL float64) {
+	if len(ec.points) == 0 || equity > ec.peak {
+		ec.peak = equity
+	}
+
+	drawdown := ec.peak - equity
+	drawdownPercent := 0.0
+	if ec.peak > 0 {
+		drawdownPercent = drawdown / ec.peak * 100
+	}
+
+	ec.points = append(ec.points, EquityPoint{
+		Timestamp:       timestamp,
+		Balance:         equity - unrealizedPnL,
+		UnrealizedPnL:   unrealizedPnL,
+		Equity:          equity,
+		Peak:            ec.peak,
+		Drawdown:        drawdown,
+		DrawdownPercent: drawdownPercent,
+	})
+	ec.lastSample = timestamp
+}
+
+// ==================== CURVE QUERIES ====================
+
+// GetPoints returns a copy of the samples, oldest first
+func (ec *EquityCurve) GetPoints() []EquityPoint {
+	points := make([]EquityPoint, len(ec.points))
+	copy(points, ec.points)
+	return points
+}
+
+// GetEquitySeries returns the sampled equity values
+func (ec *EquityCurve) GetEquitySeries() []float64 {
+	series := make([]float64, len(ec.points))
+	for i, p := range ec.points {
+		series[i] = p.Equity
+	}
+	return series
+}
+
+// Size returns the number of samples
+func (ec *EquityCurve) Size() int {
+	return len(ec.points)
+}
+
+// GetLatest returns the most recent sample (nil if empty)
+func (ec *EquityCurve) GetLatest() *EquityPoint {
+	if len(ec.points) == 0 {
+		return nil
+	}
+	p := ec.points[len(ec.points)-1]
+	return &p
+}
+
+// Since returns a curve of the samples from the last one at or before from
+// onward, so returns over the window start from its opening equity; its
+// peak and drawdowns restart within the window
+func (ec *EquityCurve) Since(from time.Time) *EquityCurve {
+	window := NewEquityCurve(ec.Interval)
+	start := sort.Search(len(ec.points), func(i int) bool {
+		return ec.points[i].Timestamp.After(from)
+	})
+	if start > 0 {
+		start--
+	}
+	for _, p := range ec.points[start:] {
+		window.add(p.Timestamp, p.Equity, p.UnrealizedPnL)
+	}
+	return window
+}
+
+// GetMaxDrawdown returns the largest peak-to-trough drop in equity
+func (ec *EquityCurve) GetMaxDrawdown() float64 {
+	worst := 0.0
+	for _, p := range ec.points {
+		worst = math.Max(worst, p.Drawdown)
+	}
+	return worst
+}
+
+// GetMaxDrawdownPercent returns the largest drawdown as a percent of its peak
+func (ec *EquityCurve) GetMaxDrawdownPercent() float64 {
+	worst := 0.0
+	for _, p := range ec.points {
+		worst = math.Max(worst, p.DrawdownPercent)
+	}
+	return worst
+}
+
+// GetCurrentDrawdownPercent returns the latest sample's drawdown percent
+func (ec *EquityCurve) GetCurrentDrawdownPercent() float64 {
+	if latest := ec.GetLatest(); latest != nil {
+		return latest.DrawdownPercent
+	}
+	return 0
+}
+
+// GetMaxDrawdownDuration returns the longest time equity spent below a
+// previous peak (an unrecovered drawdown runs to the last sample)
+func (ec *EquityCurve) GetMaxDrawdownDuration() time.Duration {
+	longest := time.Duration(0)
+	var start time.Time
+	underwater := false
+
+	for _, p := range ec.points {
+		if p.Drawdown > 0 {
+			if !underwater {
+				underwater = true
+				start = p.Timestamp
+			}
+			if d := p.Timestamp.Sub(start); d > longest {
+				longest = d
+			}
+		} else if underwater {
+			underwater = false
+			if d := p.Timestamp.Sub(start); d > longest {
+				longest = d
+			}
+		}
+	}
+
+	return longest
+}
+
+// ==================== RETURN RATIOS ====================
+
+// CalmarWindow is the trailing period the Calmar ratio is measured over
+const CalmarWindow = 36 // Months
+
+// DefaultTradingDaysPerYear annualizes returns when the instrument does
+// not say
+const DefaultTradingDaysPerYear = 252
+
+// GetTradingDays returns the number of distinct UTC dates sampled
+func (ec *EquityCurve) GetTradingDays() int {
+	return countTradingDays(ec.points)
+}
+
+// GetAnnualizedReturn returns the compound annual return in percent over
+// the whole curve, with a year of tradingDaysPerYear sampled dates
+func (ec *EquityCurve) GetAnnualizedReturn(tradingDaysPerYear int) float64 {
+	annualized, _ := annualizedPerformance(ec.points, tradingDaysPerYear)
+	return annualized
+}
+
+// GetMARRatio returns the annualized return over the max drawdown percent
+// across the whole curve (0 without a drawdown)
+func (ec *EquityCurve) GetMARRatio(tradingDaysPerYear int) float64 {
+	return returnOverDrawdown(ec.points, tradingDaysPerYear)
+}
+
+// GetCalmarRatio returns the annualized return over the max drawdown
+// percent across the trailing CalmarWindow months (0 without a drawdown)
+// Sessions shorter than the window give the same value as the MAR ratio
+func (ec *EquityCurve) GetCalmarRatio(tradingDaysPerYear int) float64 {
+	if len(ec.points) == 0 {
+		return 0
+	}
+
+	cutoff := ec.points[len(ec.points)-1].Timestamp.AddDate(0, -CalmarWindow, 0)
+	start := 0
+	for start < len(ec.points)-1 && ec.points[start].Timestamp.Before(cutoff) {
+		start++
+	}
+	return returnOverDrawdown(ec.points[start:], tradingDaysPerYear)
+}
+
+// returnOverDrawdown divides the annualized return of points by their max
+// drawdown percent
+func returnOverDrawdown(points []EquityPoint, tradingDaysPerYear int) float64 {
+	annualized, maxDrawdown := annualizedPerformance(points, tradingDaysPerYear)
+	if maxDrawdown <= 0 {
+		return 0
+	}
+	return annualized / maxDrawdown
+}
+
+// annualizedPerformance returns the compound annual return and the max
+// drawdown (both percent) of points, with drawdown measured from the
+// first point's equity so a window does not inherit an earlier peak
+func annualizedPerformance(points []EquityPoint, tradingDaysPerYear int) (float64, float64) {
+	if len(points) < 2 || points[0].Equity <= 0 {
+		return 0, 0
+	}
+	if tradingDaysPerYear <= 0 {
+		tradingDaysPerYear = DefaultTradingDaysPerYear
+	}
+
+	peak := points[0].Equity
+	maxDrawdown := 0.0
+	for _, p := range points {
+		peak = math.Max(peak, p.Equity)
+		if peak > 0 {
+			maxDrawdown = math.Max(maxDrawdown, (peak-p.Equity)/peak*100)
+		}
+	}
+
+	growth := points[len(points)-1].Equity / points[0].Equity
+	if growth <= 0 {
+		return -100, maxDrawdown
+	}
+	years := float64(countTradingDays(points)) / float64(tradingDaysPerYear)
+	annualized := (math.Pow(growth, 1/years) - 1) * 100
+	if math.IsInf(annualized, 0) || math.IsNaN(annualized) {
+		// Too short a session to annualize
+		return 0, maxDrawdown
+	}
+	return annualized, maxDrawdown
+}
+
+// countTradingDays returns the number of distinct UTC dates among points
+func countTradingDays(points []EquityPoint) int {
+	days := 0
+	last := ""
+	for _, p := range points {
+		if date := p.Timestamp.UTC().Format("2006-01-02"); date != last {
+			days++
+			last = date
+		}
+	}
+	return days
+}
+
+// ==================== STATISTICS ====================
+
+// GetStatistics returns equity curve statistics
+func (ec *EquityCurve) GetStatistics() map[string]interface{} {
+	stats := map[string]interface{}{
+		"samples":                  len(ec.points),
+		"interval":                 ec.Interval.String(),
+		"peak_equity":              ec.peak,
+		"max_drawdown":             ec.GetMaxDrawdown(),
+		"max_drawdown_percent":     ec.GetMaxDrawdownPercent(),
+		"current_drawdown_percent": ec.GetCurrentDrawdownPercent(),
+		"max_drawdown_duration":    ec.GetMaxDrawdownDuration().String(),
+	}
+	if latest := ec.GetLatest(); latest != nil {
+		stats["equity"] = latest.Equity
+	}
+	return stats
+}
+
+// Reset clears the samples
+func (ec *EquityCurve) Reset() {
+	ec.points = make([]EquityPoint, 0)
+	ec.lastSample = time.Time{}
+	ec.peak = 0
+}
+
+// String returns a human-readable representation
+func (ec *EquityCurve) String() string {
+	return fmt.Sprintf(
+		"EquityCurve[Samples:%d, Peak:%.2f, MaxDD:%.2f%%]",
+		len(ec.points),
+		ec.peak,
+		ec.GetMaxDrawdownPercent(),
+	)
+}
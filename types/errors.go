@@ -70,6 +70,48 @@ func NewPositionLimitError(requested, maxAllowed float64) *HolodeckError {
 	return err
 }
 
+// NewExposureLimitError creates an EXPOSURE_LIMIT_EXCEEDED error
+// kind is "gross" or "net"; exposure is the notional after the order
+func NewExposureLimitError(kind string, exposure, maxAllowed float64) *HolodeckError {
+	err := NewHolodeckError(
+		ErrorCodeExposureLimitExceeded,
+		fmt.Sprintf("%s exposure limit exceeded: %.2f after order, max %.2f", kind, exposure, maxAllowed),
+	)
+	err.Details["kind"] = kind
+	err.Details["exposure"] = exposure
+	err.Details["max_allowed"] = maxAllowed
+	err.Details["excess"] = exposure - maxAllowed
+	return err
+}
+
+// NewTradingHaltedError creates a TRADING_HALTED error
+func NewTradingHaltedError(reason string) *HolodeckError {
+	err := NewHolodeckError(
+		ErrorCodeTradingHalted,
+		fmt.Sprintf("trading halted: %s", reason),
+	)
+	err.Details["reason"] = reason
+	return err
+}
+
+// NewSessionPausedError creates a SESSION_PAUSED error for an order
+// submitted while the session is paused
+func NewSessionPausedError() *HolodeckError {
+	return NewHolodeckError(ErrorCodeSessionPaused, "session paused: order rejected")
+}
+
+// NewMetricAlertError creates a METRIC_ALERT error for a breached alert
+// rule, e.g. "drawdown_percent > 10"
+func NewMetricAlertError(rule string, value float64) *HolodeckError {
+	err := NewHolodeckError(
+		ErrorCodeMetricAlert,
+		fmt.Sprintf("alert: %s (value %.2f)", rule, value),
+	)
+	err.Details["rule"] = rule
+	err.Details["value"] = value
+	return err
+}
+
 // NewInvalidOrderTypeError creates an INVALID_ORDER_TYPE error
 func NewInvalidOrderTypeError(orderType string) *HolodeckError {
 	err := NewHolodeckError(
@@ -92,6 +134,28 @@ func NewInvalidLimitPriceError(limitPrice float64, reason string) *HolodeckError
 	return err
 }
 
+// NewLimitAboveAskError creates a LIMIT_ABOVE_ASK error for a marketable BUY LIMIT
+func NewLimitAboveAskError(limitPrice, ask float64) *HolodeckError {
+	err := NewHolodeckError(
+		ErrorCodeLimitAboveAsk,
+		fmt.Sprintf("buy limit %.8f is above the ask %.8f", limitPrice, ask),
+	)
+	err.Details["limit_price"] = limitPrice
+	err.Details["ask"] = ask
+	return err
+}
+
+// NewLimitBelowBidError creates a LIMIT_BELOW_BID error for a marketable SELL LIMIT
+func NewLimitBelowBidError(limitPrice, bid float64) *HolodeckError {
+	err := NewHolodeckError(
+		ErrorCodeLimitBelowBid,
+		fmt.Sprintf("sell limit %.8f is below the bid %.8f", limitPrice, bid),
+	)
+	err.Details["limit_price"] = limitPrice
+	err.Details["bid"] = bid
+	return err
+}
+
 // NewInvalidOrderSizeError creates an INVALID_ORDER_SIZE error
 func NewInvalidOrderSizeError(size float64, minSize float64) *HolodeckError {
 	err := NewHolodeckError(
@@ -135,6 +199,17 @@ func NewAccountBlownError(currentDrawdown, maxDrawdown float64) *HolodeckError {
 	return err
 }
 
+// NewBalanceProtectionError creates the ACCOUNT_BLOWN error returned once
+// negative balance protection has closed the account
+func NewBalanceProtectionError(writtenOff float64) *HolodeckError {
+	err := NewHolodeckError(
+		ErrorCodeAccountBlown,
+		fmt.Sprintf("account blown: negative balance protection wrote off %.2f", writtenOff),
+	)
+	err.Details["written_off"] = writtenOff
+	return err
+}
+
 // NewInvalidOperationError creates an INVALID_OPERATION error
 func NewInvalidOperationError(operation, reason string) *HolodeckError {
 	err := NewHolodeckError(
@@ -13,6 +13,19 @@ type ExecutionReport struct {
 	// OrderID is the unique identifier for this order
 	OrderID string
 
+	// ExecutionID is the sequential audit trail ID, assigned when the
+	// simulator reports the execution
+	ExecutionID string
+
+	// Ticket is the hedging-mode position the fill opened or closed
+	Ticket string
+
+	// Symbol is the instrument the order traded
+	Symbol string
+
+	// StopLoss is the order's initial stop price (0 = no stop)
+	StopLoss float64
+
 	// Timestamp is when the order was executed
 	Timestamp time.Time
 
@@ -33,9 +46,18 @@ type ExecutionReport struct {
 	// SlippageUnits is the slippage in decimal units (pips for forex, cents for stocks, etc)
 	SlippageUnits float64
 
+	// SpreadMarkup is the broker markup added to the fill price (price units)
+	SpreadMarkup float64
+
 	// Commission is the trading fee paid
 	Commission float64
 
+	// SpreadCost and SlippageCost split the fill's cost against the mid
+	// price into the spread crossed (including any broker markup) and the
+	// slippage, in the same units as RealizedPnL (set by the simulator)
+	SpreadCost   float64
+	SlippageCost float64
+
 	// PositionAfter is the position size after this execution
 	// Positive = LONG, negative = SHORT, 0 = FLAT
 	PositionAfter float64
@@ -49,6 +71,12 @@ type ExecutionReport struct {
 	// RealizedPnL is the profit/loss from closed trades
 	RealizedPnL float64
 
+	// MaxFavorableExcursion (>= 0) and MaxAdverseExcursion (<= 0) are the
+	// best and worst open P&L of the size this fill closed, while it was
+	// held (0 for opening fills)
+	MaxFavorableExcursion float64
+	MaxAdverseExcursion   float64
+
 	// TotalPnL is realized + unrealized - cumulative commissions
 	TotalPnL float64
 
@@ -137,6 +165,21 @@ func NewPartialExecution(
 	return report
 }
 
+// NewBlownExecution creates the BLOWN report recorded when negative balance
+// protection clamps the account at zero; RealizedPnL carries the written
+// off deficit (positive)
+func NewBlownExecution(orderID string, timestamp time.Time, writtenOff float64) *ExecutionReport {
+	return &ExecutionReport{
+		OrderID:      orderID,
+		Timestamp:    timestamp,
+		Action:       OrderActionHold,
+		Status:       OrderStatusBlown,
+		RealizedPnL:  writtenOff,
+		ErrorCode:    ErrorCodeAccountBlown,
+		ErrorMessage: fmt.Sprintf("negative balance protection: %.2f written off, account closed at zero", writtenOff),
+	}
+}
+
 // ==================== EXECUTION REPORT METHODS ====================
 
 // IsFilled returns true if the order was fully filled
@@ -149,11 +192,21 @@ func (er *ExecutionReport) IsPartial() bool {
 	return er.Status == OrderStatusPartial
 }
 
+// IsExpired returns true if the order expired before filling completely
+func (er *ExecutionReport) IsExpired() bool {
+	return er.Status == OrderStatusExpired
+}
+
 // IsRejected returns true if the order was rejected
 func (er *ExecutionReport) IsRejected() bool {
 	return er.Status == OrderStatusRejected
 }
 
+// IsBlown returns true for a negative balance protection record
+func (er *ExecutionReport) IsBlown() bool {
+	return er.Status == OrderStatusBlown
+}
+
 // WasExecuted returns true if order was filled (fully or partially)
 func (er *ExecutionReport) WasExecuted() bool {
 	return er.IsPartial() || er.IsFilled()
@@ -0,0 +1,371 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ==================== HEDGE POSITION ====================
+
+// HedgePosition is one independently tracked position (ticket) in hedging
+// mode; closing it never nets against other tickets
+type HedgePosition struct {
+	// Ticket identifies the position for close-by-ticket
+	Ticket string
+
+	// Action is the opening side: BUY (long) or SELL (short)
+	Action string
+
+	// Size is the remaining open size (always positive)
+	Size float64
+
+	// OpenSize is the size originally opened
+	OpenSize float64
+
+	// EntryPrice is the opening fill price
+	EntryPrice float64
+
+	// EntryTime is when the ticket was opened
+	EntryTime time.Time
+
+	// Commission is the commission paid on the ticket's fills
+	Commission float64
+
+	// RealizedPnL is the P&L booked by closes against this ticket
+	RealizedPnL float64
+
+	// CloseTime is when the ticket was fully closed (zero while open)
+	CloseTime time.Time
+}
+
+// IsLong returns true for a BUY ticket
+func (hp *HedgePosition) IsLong() bool {
+	return hp.Action == OrderActionBuy
+}
+
+// IsOpen returns true while the ticket has size left
+func (hp *HedgePosition) IsOpen() bool {
+	return hp.Size > 0
+}
+
+// GetSignedSize returns the remaining size, negative for short tickets
+func (hp *HedgePosition) GetSignedSize() float64 {
+	if hp.IsLong() {
+		return hp.Size
+	}
+	return -hp.Size
+}
+
+// CalculateUnrealizedPnL calculates the ticket's mark-to-market P&L
+// price is the bid for long tickets and the ask for short tickets
+func (hp *HedgePosition) CalculateUnrealizedPnL(price, pipValue float64) float64 {
+	return hedgePnL(hp, price, hp.Size, pipValue)
+}
+
+// hedgePnL returns the P&L of closing size of a ticket at price
+func hedgePnL(hp *HedgePosition, price, size, pipValue float64) float64 {
+	priceDiff := price - hp.EntryPrice
+	if !hp.IsLong() {
+		priceDiff = -priceDiff
+	}
+	pnl := priceDiff * size
+	if pipValue > 0 {
+		pnl /= pipValue
+	}
+	return pnl
+}
+
+// String returns a human-readable representation
+func (hp *HedgePosition) String() string {
+	return fmt.Sprintf(
+		"Ticket[%s %s %.4f @ %.5f, Realized:%.2f]",
+		hp.Ticket,
+		hp.Action,
+		hp.Size,
+		hp.EntryPrice,
+		hp.RealizedPnL,
+	)
+}
+
+// ==================== HEDGE BOOK ====================
+
+// HedgeBook tracks the tickets of a hedging-mode account
+// Fills open a new ticket unless their order was registered as a close of
+// an existing ticket
+type HedgeBook struct {
+	open       []*HedgePosition
+	closed     []*HedgePosition
+	nextTicket int64
+
+	// Ticket each close order (by order ID) applies to
+	pendingCloses map[string]string
+	closeOrders   int64
+}
+
+// NewHedgeBook creates an empty hedge book
+func NewHedgeBook() *HedgeBook {
+	return &HedgeBook{
+		open:          make([]*HedgePosition, 0),
+		closed:        make([]*HedgePosition, 0),
+		pendingCloses: make(map[string]string),
+	}
+}
+
+// Get retrieves an open ticket
+func (hb *HedgeBook) Get(ticket string) (*HedgePosition, bool) {
+	for _, hp := range hb.open {
+		if hp.Ticket == ticket {
+			return hp, true
+		}
+	}
+	return nil, false
+}
+
+// NewCloseOrder creates a MARKET order that closes size of a ticket
+// (size <= 0 closes all of it) and registers it against the ticket
+func (hb *HedgeBook) NewCloseOrder(ticket string, size float64, timestamp time.Time) (*Order, error) {
+	hp, ok := hb.Get(ticket)
+	if !ok {
+		return nil, fmt.Errorf("no open position with ticket %s", ticket)
+	}
+	if size <= 0 {
+		size = hp.Size
+	}
+	if size > hp.Size+1e-9 {
+		return nil, fmt.Errorf("close size %.4f exceeds ticket %s size %.4f", size, ticket, hp.Size)
+	}
+
+	action := OrderActionSell
+	if !hp.IsLong() {
+		action = OrderActionBuy
+	}
+
+	hb.closeOrders++
+	order := NewMarketOrder(action, size, timestamp)
+	order.OrderID = fmt.Sprintf("CLOSE-%s-%d", ticket, hb.closeOrders)
+	order.CloseTicket = ticket
+	order.Description = fmt.Sprintf("close ticket %s", ticket)
+
+	hb.pendingCloses[order.OrderID] = ticket
+	return order, nil
+}
+
+// RegisterClose routes fills of an order to an existing ticket
+func (hb *HedgeBook) RegisterClose(orderID, ticket string) {
+	hb.pendingCloses[orderID] = ticket
+}
+
+// ApplyFill books a fill: a registered close reduces its ticket (any excess
+// opens a new ticket), anything else opens a new ticket
+// Returns the realized P&L and the ticket the fill was booked to
+func (hb *HedgeBook) ApplyFill(exec *ExecutionReport, pipValue float64) (float64, string) {
+	remaining := exec.FilledSize
+	realized := 0.0
+	ticket := ""
+
+	if closeTicket, ok := hb.pendingCloses[exec.OrderID]; ok {
+		if hp, open := hb.Get(closeTicket); open && hp.IsLong() != exec.IsBuy() {
+			closed := math.Min(remaining, hp.Size)
+			pnl := hedgePnL(hp, exec.FillPrice, closed, pipValue)
+
+			hp.Size -= closed
+			hp.RealizedPnL += pnl
+			hp.Commission += exec.Commission * closed / exec.FilledSize
+			realized += pnl
+			remaining -= closed
+			ticket = hp.Ticket
+
+			if hp.Size < 1e-9 {
+				hp.Size = 0
+				hp.CloseTime = exec.Timestamp
+				hb.removeOpen(hp.Ticket)
+				hb.closed = append(hb.closed, hp)
+			}
+		}
+	}
+
+	if remaining > 1e-9 {
+		hb.nextTicket++
+		hp := &HedgePosition{
+			Ticket:     fmt.Sprintf("T-%06d", hb.nextTicket),
+			Action:     exec.Action,
+			Size:       remaining,
+			OpenSize:   remaining,
+			EntryPrice: exec.FillPrice,
+			EntryTime:  exec.Timestamp,
+			Commission: exec.Commission * remaining / exec.FilledSize,
+		}
+		hb.open = append(hb.open, hp)
+		if ticket == "" {
+			ticket = hp.Ticket
+		}
+	}
+
+	return realized, ticket
+}
+
+// removeOpen drops a ticket from the open list
+func (hb *HedgeBook) removeOpen(ticket string) {
+	for i, hp := range hb.open {
+		if hp.Ticket == ticket {
+			hb.open = append(hb.open[:i], hb.open[i+1:]...)
+			return
+		}
+	}
+}
+
+// ApplySplit adjusts every open ticket for a stock split
+func (hb *HedgeBook) ApplySplit(ratio float64) {
+	if ratio <= 0 {
+		return
+	}
+	for _, hp := range hb.open {
+		hp.Size *= ratio
+		hp.OpenSize *= ratio
+		hp.EntryPrice /= ratio
+	}
+}
+
+// ==================== AGGREGATES ====================
+
+// GetOpenPositions returns copies of the open tickets, oldest first
+func (hb *HedgeBook) GetOpenPositions() []HedgePosition {
+	positions := make([]HedgePosition, len(hb.open))
+	for i, hp := range hb.open {
+		positions[i] = *hp
+	}
+	return positions
+}
+
+// GetClosedPositions returns copies of the fully closed tickets, in the
+// order they closed
+func (hb *HedgeBook) GetClosedPositions() []HedgePosition {
+	positions := make([]HedgePosition, len(hb.closed))
+	for i, hp := range hb.closed {
+		positions[i] = *hp
+	}
+	return positions
+}
+
+// GetLongSize returns the total size of open long tickets
+func (hb *HedgeBook) GetLongSize() float64 {
+	total := 0.0
+	for _, hp := range hb.open {
+		if hp.IsLong() {
+			total += hp.Size
+		}
+	}
+	return total
+}
+
+// GetShortSize returns the total size of open short tickets (positive)
+func (hb *HedgeBook) GetShortSize() float64 {
+	total := 0.0
+	for _, hp := range hb.open {
+		if !hp.IsLong() {
+			total += hp.Size
+		}
+	}
+	return total
+}
+
+// GetNetSize returns long minus short size
+func (hb *HedgeBook) GetNetSize() float64 {
+	return hb.GetLongSize() - hb.GetShortSize()
+}
+
+// GetNetEntryPrice returns the size-weighted entry price of the tickets on
+// the net side (0 when the book is flat or fully hedged)
+func (hb *HedgeBook) GetNetEntryPrice() float64 {
+	net := hb.GetNetSize()
+	if math.Abs(net) < 1e-9 {
+		return 0
+	}
+
+	notional, size := 0.0, 0.0
+	for _, hp := range hb.open {
+		if hp.IsLong() == (net > 0) {
+			notional += hp.EntryPrice * hp.Size
+			size += hp.Size
+		}
+	}
+	if size == 0 {
+		return 0
+	}
+	return notional / size
+}
+
+// GetUnrealizedPnL marks long tickets at the bid and short tickets at the ask
+func (hb *HedgeBook) GetUnrealizedPnL(bid, ask, pipValue float64) float64 {
+	total := 0.0
+	for _, hp := range hb.open {
+		price := bid
+		if !hp.IsLong() {
+			price = ask
+		}
+		total += hp.CalculateUnrealizedPnL(price, pipValue)
+	}
+	return total
+}
+
+// CalculateUsedMargin sums the margin of every open ticket at its entry
+// price; opposite tickets are margined gross, not netted
+// With leverage tiers the tickets' summed notional is margined band by band
+func (hb *HedgeBook) CalculateUsedMargin(instrument Instrument, tiers LeverageTiers, leverage float64) float64 {
+	if len(tiers) > 0 {
+		gross := 0.0
+		for _, hp := range hb.open {
+			gross += math.Abs(hp.Size) * hp.EntryPrice
+		}
+		return CalculateTieredMargin(gross, 1, instrument, tiers, leverage)
+	}
+
+	total := 0.0
+	for _, hp := range hb.open {
+		total += CalculateRequiredMargin(hp.Size, hp.EntryPrice, instrument, leverage)
+	}
+	return total
+}
+
+// ==================== STATISTICS ====================
+
+// GetStatistics returns hedge book statistics
+func (hb *HedgeBook) GetStatistics() map[string]interface{} {
+	realized := 0.0
+	for _, hp := range hb.closed {
+		realized += hp.RealizedPnL
+	}
+	for _, hp := range hb.open {
+		realized += hp.RealizedPnL
+	}
+
+	return map[string]interface{}{
+		"open_tickets":   len(hb.open),
+		"closed_tickets": len(hb.closed),
+		"long_size":      hb.GetLongSize(),
+		"short_size":     hb.GetShortSize(),
+		"net_size":       hb.GetNetSize(),
+		"realized_pnl":   realized,
+	}
+}
+
+// Reset clears all tickets
+func (hb *HedgeBook) Reset() {
+	hb.open = make([]*HedgePosition, 0)
+	hb.closed = make([]*HedgePosition, 0)
+	hb.nextTicket = 0
+	hb.pendingCloses = make(map[string]string)
+	hb.closeOrders = 0
+}
+
+// String returns a human-readable representation
+func (hb *HedgeBook) String() string {
+	return fmt.Sprintf(
+		"HedgeBook[Open:%d, Long:%.4f, Short:%.4f, Net:%.4f]",
+		len(hb.open),
+		hb.GetLongSize(),
+		hb.GetShortSize(),
+		hb.GetNetSize(),
+	)
+}
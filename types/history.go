@@ -0,0 +1,24 @@
+package types
+
+// ==================== HISTORY BOUNDS ====================
+
+// TrimHistory drops the oldest entries of a history that has grown past
+// max and returns the kept and the evicted entries (max <= 0 = unbounded)
+// A tenth of max is dropped beyond the excess, so the kept entries are
+// compacted once per that many appends rather than on every append
+func TrimHistory[T any](history []T, max int) (kept, evicted []T) {
+	if max <= 0 || len(history) <= max {
+		return history, nil
+	}
+
+	drop := len(history) - max + max/10
+	evicted = make([]T, drop)
+	copy(evicted, history[:drop])
+
+	n := copy(history, history[drop:])
+	var zero T
+	for i := n; i < len(history); i++ {
+		history[i] = zero
+	}
+	return history[:n], evicted
+}
@@ -129,6 +129,8 @@ type Instrument interface {
 	ValidateOrderSize(size float64) error
 
 	// ValidateLimitPrice checks if limit price is valid
+	// currentPrice is the ask for BUY and the bid for SELL; a BUY LIMIT must
+	// be at or below it and a SELL LIMIT at or above it (0 skips the check)
 	ValidateLimitPrice(limitPrice, currentPrice float64, action string) error
 
 	// FormatPrice formats a price with correct decimals
@@ -250,10 +252,7 @@ func (f *ForexInstrument) ValidateOrderSize(size float64) error {
 }
 
 func (f *ForexInstrument) ValidateLimitPrice(limitPrice, currentPrice float64, action string) error {
-	if limitPrice <= 0 {
-		return NewInvalidLimitPriceError(limitPrice, "price must be positive")
-	}
-	return nil
+	return validateLimitPriceForSide(limitPrice, currentPrice, action)
 }
 
 func (f *ForexInstrument) FormatPrice(price float64) string {
@@ -323,10 +322,7 @@ func (s *StocksInstrument) ValidateOrderSize(size float64) error {
 }
 
 func (s *StocksInstrument) ValidateLimitPrice(limitPrice, currentPrice float64, action string) error {
-	if limitPrice <= 0 {
-		return NewInvalidLimitPriceError(limitPrice, "price must be positive")
-	}
-	return nil
+	return validateLimitPriceForSide(limitPrice, currentPrice, action)
 }
 
 func (s *StocksInstrument) FormatPrice(price float64) string {
@@ -396,10 +392,7 @@ func (c *CommoditiesInstrument) ValidateOrderSize(size float64) error {
 }
 
 func (c *CommoditiesInstrument) ValidateLimitPrice(limitPrice, currentPrice float64, action string) error {
-	if limitPrice <= 0 {
-		return NewInvalidLimitPriceError(limitPrice, "price must be positive")
-	}
-	return nil
+	return validateLimitPriceForSide(limitPrice, currentPrice, action)
 }
 
 func (c *CommoditiesInstrument) FormatPrice(price float64) string {
@@ -470,10 +463,7 @@ func (cr *CryptoInstrument) ValidateOrderSize(size float64) error {
 }
 
 func (cr *CryptoInstrument) ValidateLimitPrice(limitPrice, currentPrice float64, action string) error {
-	if limitPrice <= 0 {
-		return NewInvalidLimitPriceError(limitPrice, "price must be positive")
-	}
-	return nil
+	return validateLimitPriceForSide(limitPrice, currentPrice, action)
 }
 
 func (cr *CryptoInstrument) FormatPrice(price float64) string {
@@ -481,6 +471,33 @@ func (cr *CryptoInstrument) FormatPrice(price float64) string {
 	return fmt.Sprintf(format, price)
 }
 
+// ==================== LIMIT PRICE VALIDATION ====================
+
+// validateLimitPriceForSide checks a limit price against the side of the
+// market it would rest on
+func validateLimitPriceForSide(limitPrice, currentPrice float64, action string) error {
+	if limitPrice <= 0 {
+		return NewInvalidLimitPriceError(limitPrice, "price must be positive")
+	}
+	if currentPrice <= 0 {
+		return nil
+	}
+
+	switch action {
+	case OrderActionBuy:
+		if limitPrice > currentPrice {
+			return NewLimitAboveAskError(limitPrice, currentPrice)
+		}
+	case OrderActionSell:
+		if limitPrice < currentPrice {
+			return NewLimitBelowBidError(limitPrice, currentPrice)
+		}
+	default:
+		return NewInvalidLimitPriceError(limitPrice, fmt.Sprintf("no limit side for action %s", action))
+	}
+	return nil
+}
+
 // ==================== REGISTRY ====================
 
 // InstrumentRegistry manages available instruments
@@ -0,0 +1,100 @@
+package types
+
+import (
+	"fmt"
+	"math"
+)
+
+// ==================== LEVERAGE TIERS ====================
+
+// LeverageTier is one band of a tiered leverage schedule: notional up to
+// UpToNotional is margined at Leverage (0 on the last tier = no cap)
+type LeverageTier struct {
+	UpToNotional float64 `json:"up_to_notional"`
+	Leverage     float64 `json:"leverage"`
+}
+
+// LeverageTiers margins a position's notional band by band, like a
+// broker's tiered margin: e.g. 30:1 up to 50k notional and 10:1 beyond
+// means 100k needs 50k/30 + 50k/10 of margin
+type LeverageTiers []LeverageTier
+
+// Validate checks that bands ascend, leverages are at least 1 and only
+// the last band is uncapped
+func (lt LeverageTiers) Validate() error {
+	previous := 0.0
+	for i, tier := range lt {
+		if tier.Leverage < 1 {
+			return fmt.Errorf("tier %d: leverage must be >= 1", i+1)
+		}
+		last := i == len(lt)-1
+		if tier.UpToNotional == 0 && !last {
+			return fmt.Errorf("tier %d: only the last tier may omit up_to_notional", i+1)
+		}
+		if tier.UpToNotional < 0 || (tier.UpToNotional != 0 && tier.UpToNotional <= previous) {
+			return fmt.Errorf("tier %d: up_to_notional must be above the previous tier's", i+1)
+		}
+		previous = tier.UpToNotional
+	}
+	return nil
+}
+
+// CalculateMargin returns the margin for a notional (absolute value taken)
+// Notional beyond a capped last tier is margined at that tier's leverage
+func (lt LeverageTiers) CalculateMargin(notional float64) float64 {
+	remaining := math.Abs(notional)
+	margin := 0.0
+	floor := 0.0
+
+	for i, tier := range lt {
+		band := remaining
+		if tier.UpToNotional > 0 && i < len(lt)-1 {
+			band = math.Min(remaining, tier.UpToNotional-floor)
+		}
+		margin += band / tier.Leverage
+		remaining -= band
+		floor = tier.UpToNotional
+		if remaining <= 0 {
+			break
+		}
+	}
+
+	return margin
+}
+
+// GetEffectiveLeverage returns notional / margin at a notional
+func (lt LeverageTiers) GetEffectiveLeverage(notional float64) float64 {
+	margin := lt.CalculateMargin(notional)
+	if margin <= 0 {
+		if len(lt) > 0 {
+			return lt[0].Leverage
+		}
+		return 1
+	}
+	return math.Abs(notional) / margin
+}
+
+// CalculateTieredMargin returns the margin needed to hold a position,
+// using tiers when given and the flat leverage otherwise
+func CalculateTieredMargin(size, price float64, instrument Instrument, tiers LeverageTiers, leverage float64) float64 {
+	if len(tiers) == 0 {
+		return CalculateRequiredMargin(size, price, instrument, leverage)
+	}
+	contractSize := 1.0
+	if instrument != nil && instrument.GetContractSize() > 0 {
+		contractSize = float64(instrument.GetContractSize())
+	}
+	return tiers.CalculateMargin(size * price * contractSize)
+}
+
+// GetLeverageTiers looks up tiers by symbol first and then by instrument
+// type (nil when neither is configured)
+func GetLeverageTiers(tiers map[string]LeverageTiers, instrument Instrument) LeverageTiers {
+	if instrument == nil || len(tiers) == 0 {
+		return nil
+	}
+	if t, ok := tiers[instrument.GetSymbol()]; ok {
+		return t
+	}
+	return tiers[instrument.GetType()]
+}
@@ -0,0 +1,399 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ==================== LOT RECORDS ====================
+
+// Lot is an open slice of a position bought or sold at one price
+type Lot struct {
+	// LotID identifies the lot within its position
+	LotID string
+
+	// Size is the remaining open size (always positive)
+	Size float64
+
+	// Price is the fill price the lot was opened at
+	Price float64
+
+	// OpenTime is when the lot was opened
+	OpenTime time.Time
+
+	// Commission is the remaining share of the opening commission
+	Commission float64
+
+	// HighPrice and LowPrice are the extreme marks since the lot opened,
+	// for its excursions
+	HighPrice float64
+	LowPrice  float64
+
+	// StopPrice is the initial stop the lot was opened with (0 = no stop)
+	StopPrice float64
+
+	// Costs is the remaining share of the opening fill's spread and
+	// slippage, and the swap booked while the lot was held
+	Costs LotCosts
+}
+
+// ClosedLot is the part of a lot closed by one fill, with the P&L it
+// realized; the records line up with a broker's closed-lot statement
+type ClosedLot struct {
+	LotID       string
+	Side        string // LONG or SHORT
+	Size        float64
+	EntryPrice  float64
+	ExitPrice   float64
+	OpenTime    time.Time
+	CloseTime   time.Time
+	Commission  float64 // Opening commission attributed to the closed size
+	RealizedPnL float64
+
+	// MaxFavorableExcursion (>= 0) and MaxAdverseExcursion (<= 0) are the
+	// best and worst open P&L of the closed size while it was held
+	MaxFavorableExcursion float64
+	MaxAdverseExcursion   float64
+
+	// InitialRisk (>= 0) is what the closed size stood to lose at its
+	// initial stop, in the same units as RealizedPnL (0 = no stop)
+	InitialRisk float64
+
+	// ExitCommission is the closing fill's commission attributed to the
+	// closed size
+	ExitCommission float64
+
+	// Costs is the opening and closing fills' spread and slippage
+	// attributed to the closed size, and the swap it booked while held
+	Costs LotCosts
+}
+
+// GetHoldingPeriod returns how long the lot was held
+func (cl *ClosedLot) GetHoldingPeriod() time.Duration {
+	return cl.CloseTime.Sub(cl.OpenTime)
+}
+
+// GetEdgeRatio returns MFE / |MAE|, how far trades ran for versus against
+// (0 when the lot never went against)
+func (cl *ClosedLot) GetEdgeRatio() float64 {
+	if cl.MaxAdverseExcursion >= 0 {
+		return 0
+	}
+	return cl.MaxFavorableExcursion / -cl.MaxAdverseExcursion
+}
+
+// GetReturnPercent returns the price move captured, as a percent of the
+// entry price
+func (cl *ClosedLot) GetReturnPercent() float64 {
+	if cl.EntryPrice == 0 {
+		return 0
+	}
+	direction := 1.0
+	if cl.Side == PositionStatusShort {
+		direction = -1.0
+	}
+	return (cl.ExitPrice - cl.EntryPrice) / cl.EntryPrice * direction * 100
+}
+
+// HasInitialRisk checks if the lot was opened with a stop
+func (cl *ClosedLot) HasInitialRisk() bool {
+	return cl.InitialRisk > 0
+}
+
+// GetRMultiple returns the realized P&L in units of the initial risk
+// (0 when the lot had no stop)
+func (cl *ClosedLot) GetRMultiple() float64 {
+	if !cl.HasInitialRisk() {
+		return 0
+	}
+	return cl.RealizedPnL / cl.InitialRisk
+}
+
+// String returns a human-readable representation
+func (cl *ClosedLot) String() string {
+	return fmt.Sprintf(
+		"ClosedLot[%s %s %.4f %.5f -> %.5f, P&L:%.2f, MFE:%.2f, MAE:%.2f]",
+		cl.LotID,
+		cl.Side,
+		cl.Size,
+		cl.EntryPrice,
+		cl.ExitPrice,
+		cl.RealizedPnL,
+		cl.MaxFavorableExcursion,
+		cl.MaxAdverseExcursion,
+	)
+}
+
+// ==================== LOT MATCHING ====================
+
+// openLot adds a lot to the position
+// stop is the lot's initial stop price (0 = no stop)
+func (p *Position) openLot(size, price, commission, stop float64, openTime time.Time) {
+	p.lotCount++
+	p.Lots = append(p.Lots, &Lot{
+		LotID:      fmt.Sprintf("L-%d", p.lotCount),
+		Size:       size,
+		Price:      price,
+		OpenTime:   openTime,
+		Commission: commission,
+		HighPrice:  price,
+		LowPrice:   price,
+		StopPrice:  stop,
+	})
+}
+
+// markLots extends each open lot's extreme marks to price
+func (p *Position) markLots(price float64) {
+	for _, lot := range p.Lots {
+		lot.HighPrice = math.Max(lot.HighPrice, price)
+		lot.LowPrice = math.Min(lot.LowPrice, price)
+	}
+}
+
+// excursions returns the best and worst open P&L of size of a lot held
+// in direction, in the same units as realized P&L
+func (lot *Lot) excursions(size, direction, pipValue float64) (favorable, adverse float64) {
+	best, worst := lot.HighPrice, lot.LowPrice
+	if direction < 0 {
+		best, worst = worst, best
+	}
+	favorable = math.Max((best-lot.Price)*direction*size, 0)
+	adverse = math.Min((worst-lot.Price)*direction*size, 0)
+	if pipValue > 0 {
+		favorable /= pipValue
+		adverse /= pipValue
+	}
+	return favorable, adverse
+}
+
+// risk returns what size of the lot stands to lose at its initial stop, in
+// the same units as realized P&L (0 = no stop)
+func (lot *Lot) risk(size, pipValue float64) float64 {
+	if lot.StopPrice <= 0 {
+		return 0
+	}
+	risk := math.Abs(lot.Price-lot.StopPrice) * size
+	if pipValue > 0 {
+		risk /= pipValue
+	}
+	return risk
+}
+
+// closeLots closes size of the open lots at price using the position's lot
+// matching method and returns the realized P&L
+// FIFO closes the oldest lots first and LIFO the newest; average cost
+// closes every lot pro rata at the average entry price
+func (p *Position) closeLots(size, price float64, closeTime time.Time, pipValue float64) float64 {
+	side := p.GetStatus()
+	direction := float64(p.GetDirection())
+
+	// The exit price counts toward the excursions
+	p.markLots(price)
+
+	pnl := func(entry, closed float64) float64 {
+		realized := (price - entry) * direction * closed
+		if pipValue > 0 {
+			realized /= pipValue
+		}
+		return realized
+	}
+
+	if p.LotMatching != LotMatchingFIFO && p.LotMatching != LotMatchingLIFO {
+		average := p.EntryPrice
+		open := p.GetAbsoluteSize()
+		share := math.Min(size/open, 1)
+
+		// The averaged close's excursions and risk sum those of each lot's
+		// share; the risk is only known when every lot has a stop
+		commission, favorable, adverse, risk := 0.0, 0.0, 0.0, 0.0
+		costs := LotCosts{}
+		stopped := true
+		for _, lot := range p.Lots {
+			mfe, mae := lot.excursions(lot.Size*share, direction, pipValue)
+			favorable += mfe
+			adverse += mae
+			risk += lot.risk(lot.Size*share, pipValue)
+			stopped = stopped && lot.StopPrice > 0
+			commission += lot.Commission * share
+			lot.Commission -= lot.Commission * share
+			costs = costs.add(lot.Costs.scale(share))
+			lot.Costs = lot.Costs.scale(1 - share)
+			lot.Size -= lot.Size * share
+		}
+		p.pruneLots()
+		if !stopped {
+			risk = 0
+		}
+
+		realized := pnl(average, size)
+		p.ClosedLots = append(p.ClosedLots, &ClosedLot{
+			LotID:                 "AVG",
+			Side:                  side,
+			Size:                  size,
+			EntryPrice:            average,
+			ExitPrice:             price,
+			OpenTime:              p.EntryTime,
+			CloseTime:             closeTime,
+			Commission:            commission,
+			RealizedPnL:           realized,
+			MaxFavorableExcursion: favorable,
+			MaxAdverseExcursion:   adverse,
+			InitialRisk:           risk,
+			Costs:                 costs,
+		})
+		return realized
+	}
+
+	realized := 0.0
+	remaining := size
+	for remaining > 1e-9 && len(p.Lots) > 0 {
+		index := 0
+		if p.LotMatching == LotMatchingLIFO {
+			index = len(p.Lots) - 1
+		}
+		lot := p.Lots[index]
+
+		closed := math.Min(remaining, lot.Size)
+		commission := lot.Commission * closed / lot.Size
+		lotPnL := pnl(lot.Price, closed)
+		favorable, adverse := lot.excursions(closed, direction, pipValue)
+
+		p.ClosedLots = append(p.ClosedLots, &ClosedLot{
+			LotID:                 lot.LotID,
+			Side:                  side,
+			Size:                  closed,
+			EntryPrice:            lot.Price,
+			ExitPrice:             price,
+			OpenTime:              lot.OpenTime,
+			CloseTime:             closeTime,
+			Commission:            commission,
+			RealizedPnL:           lotPnL,
+			MaxFavorableExcursion: favorable,
+			MaxAdverseExcursion:   adverse,
+			InitialRisk:           lot.risk(closed, pipValue),
+			Costs:                 lot.Costs.scale(closed / lot.Size),
+		})
+
+		lot.Costs = lot.Costs.scale(1 - closed/lot.Size)
+		lot.Size -= closed
+		lot.Commission -= commission
+		realized += lotPnL
+		remaining -= closed
+		p.pruneLots()
+	}
+
+	return realized
+}
+
+// chargeEntryCosts books share of an opening fill's spread and slippage
+// to the newest lot
+func (p *Position) chargeEntryCosts(exec *ExecutionReport, share float64) {
+	if len(p.Lots) == 0 {
+		return
+	}
+	lot := p.Lots[len(p.Lots)-1]
+	lot.Costs.Spread += exec.SpreadCost * share
+	lot.Costs.Slippage += exec.SlippageCost * share
+}
+
+// chargeExitCosts spreads a closing fill's commission, spread and slippage
+// over the closed lots it produced, by size; filled is the fill's size
+func chargeExitCosts(closed []*ClosedLot, exec *ExecutionReport, filled float64) {
+	if filled <= 0 {
+		return
+	}
+	for _, lot := range closed {
+		share := lot.Size / filled
+		lot.ExitCommission += exec.Commission * share
+		lot.Costs.Spread += exec.SpreadCost * share
+		lot.Costs.Slippage += exec.SlippageCost * share
+	}
+}
+
+// ChargeSwap books a financing amount (positive = credit) to the open
+// lots by size
+func (p *Position) ChargeSwap(amount float64) {
+	open := p.GetAbsoluteSize()
+	if open <= 0 {
+		return
+	}
+	for _, lot := range p.Lots {
+		lot.Costs.Swap += amount * lot.Size / open
+	}
+}
+
+// pruneLots drops fully closed lots
+func (p *Position) pruneLots() {
+	open := p.Lots[:0]
+	for _, lot := range p.Lots {
+		if lot.Size > 1e-9 {
+			open = append(open, lot)
+		}
+	}
+	p.Lots = open
+}
+
+// refreshFromLots sets the entry price and entry commission from the open lots
+func (p *Position) refreshFromLots() {
+	notional, size, commission := 0.0, 0.0, 0.0
+	for _, lot := range p.Lots {
+		notional += lot.Price * lot.Size
+		size += lot.Size
+		commission += lot.Commission
+	}
+
+	p.EntryCommission = commission
+	if size > 0 {
+		p.EntryPrice = notional / size
+	} else {
+		p.EntryPrice = 0
+	}
+}
+
+// RestoreLots replaces the open lots with lots carried over from an
+// earlier session, oldest first; side is LONG or SHORT
+// Size, entry price and entry commission follow from the lots, and new
+// lots are numbered after the restored ones
+func (p *Position) RestoreLots(side string, lots []Lot, entryTime time.Time) {
+	p.Lots = make([]*Lot, 0, len(lots))
+	size := 0.0
+	for _, lot := range lots {
+		restored := lot
+		if restored.HighPrice == 0 && restored.LowPrice == 0 {
+			restored.HighPrice, restored.LowPrice = lot.Price, lot.Price
+		}
+		p.Lots = append(p.Lots, &restored)
+		size += lot.Size
+
+		var n int
+		if _, err := fmt.Sscanf(lot.LotID, "L-%d", &n); err == nil && n > p.lotCount {
+			p.lotCount = n
+		}
+	}
+
+	if side == PositionStatusShort {
+		size = -size
+	}
+	p.Size = size
+	p.EntryTime = entryTime
+	p.refreshFromLots()
+}
+
+// GetOpenLots returns copies of the open lots, oldest first
+func (p *Position) GetOpenLots() []Lot {
+	lots := make([]Lot, len(p.Lots))
+	for i, lot := range p.Lots {
+		lots[i] = *lot
+	}
+	return lots
+}
+
+// GetClosedLots returns copies of the closed lot records, in close order
+func (p *Position) GetClosedLots() []ClosedLot {
+	lots := make([]ClosedLot, len(p.ClosedLots))
+	for i, lot := range p.ClosedLots {
+		lots[i] = *lot
+	}
+	return lots
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -32,6 +33,35 @@ type Order struct {
 
 	// Description is a human-readable note about the order
 	Description string
+
+	// ExpiresAt is when a working order stops working, in simulated (tick)
+	// time (zero = good until cancelled)
+	ExpiresAt time.Time
+
+	// CommissionOverride replaces the executor's commission for this order
+	// (nil = use the configured commission, 0 = commission-free)
+	CommissionOverride *float64
+
+	// AllowMarketable lets a LIMIT order cross the spread (a BUY above the
+	// ask or a SELL below the bid), filling immediately like a market order
+	// capped at the limit
+	AllowMarketable bool
+
+	// CloseTicket closes (part of) this hedging-mode ticket instead of
+	// opening a new one; ignored in netting mode
+	CloseTicket string
+
+	// Symbol is the instrument to trade (empty = the session instrument)
+	Symbol string
+
+	// StopLoss is the initial protective stop price the opening fill is
+	// risked to, the 1R for R-multiple analytics (0 = no stop)
+	StopLoss float64
+
+	// Forced marks a liquidation (stop-out, negative balance protection,
+	// daily loss flatten) that only closes what is open: the executor
+	// skips its size limits, margin, exposure and spread/tick-age gates
+	Forced bool
 }
 
 // ==================== ORDER CONSTRUCTORS ====================
@@ -87,8 +117,79 @@ func NewSellLimitOrder(size, limitPrice float64, timestamp time.Time) *Order {
 	return NewLimitOrder(OrderActionSell, size, limitPrice, timestamp)
 }
 
+// NewClosePositionOrder creates the MARKET order that flattens a position
+// Longs are sold and shorts bought back at the full position size
+// A flat (or nil) position yields a HOLD order
+func NewClosePositionOrder(position *Position, timestamp time.Time) *Order {
+	if position == nil || position.IsFlat() {
+		return NewHoldOrder(timestamp)
+	}
+
+	action := OrderActionSell
+	if position.IsShort() {
+		action = OrderActionBuy
+	}
+
+	order := NewMarketOrder(action, position.GetAbsoluteSize(), timestamp)
+	order.Description = "close position"
+	return order
+}
+
+// NewPartialCloseOrder creates a MARKET order that closes size of the
+// position (size must be positive and no larger than the position)
+// Returns nil for a flat (or nil) position or an invalid size
+func NewPartialCloseOrder(position *Position, size float64, timestamp time.Time) *Order {
+	if position == nil || position.IsFlat() {
+		return nil
+	}
+	if size <= 0 || size > position.GetAbsoluteSize()+1e-9 {
+		return nil
+	}
+
+	order := NewClosePositionOrder(position, timestamp)
+	order.Size = math.Min(size, position.GetAbsoluteSize())
+	order.Description = "partial close"
+	return order
+}
+
+// NewReversePositionOrders creates the two MARKET orders that flip a position:
+// the close leg for the full current size, then the open leg in the opposite
+// direction at the requested size (size <= 0 reuses the current size)
+// Returns nil for a flat (or nil) position
+func NewReversePositionOrders(position *Position, size float64, timestamp time.Time) []*Order {
+	if position == nil || position.IsFlat() {
+		return nil
+	}
+
+	closeOrder := NewClosePositionOrder(position, timestamp)
+	closeOrder.Description = "reverse position: close leg"
+
+	if size <= 0 {
+		size = position.GetAbsoluteSize()
+	}
+	openOrder := NewMarketOrder(closeOrder.Action, size, timestamp)
+	openOrder.Description = "reverse position: open leg"
+
+	return []*Order{closeOrder, openOrder}
+}
+
 // ==================== ORDER METHODS ====================
 
+// IsExpired checks if the order has expired at the given simulated time
+func (o *Order) IsExpired(now time.Time) bool {
+	return !o.ExpiresAt.IsZero() && !now.Before(o.ExpiresAt)
+}
+
+// SetCommissionOverride fixes the total commission charged for this order
+func (o *Order) SetCommissionOverride(commission float64) {
+	o.CommissionOverride = &commission
+}
+
+// HasCommissionOverride checks if the order carries its own commission
+func (o *Order) HasCommissionOverride() bool {
+	return o.CommissionOverride != nil
+}
+
 // IsBuy returns true if this is a BUY action
 func (o *Order) IsBuy() bool {
 	return o.Action == OrderActionBuy
@@ -159,6 +260,18 @@ func (o *Order) DebugString() string {
 	if o.Description != "" {
 		description = fmt.Sprintf("\n  Description: %s", o.Description)
 	}
+	if !o.ExpiresAt.IsZero() {
+		description += fmt.Sprintf("\n  Expires At:  %s", o.ExpiresAt.Format("2006-01-02T15:04:05.000000"))
+	}
+	if o.HasCommissionOverride() {
+		description += fmt.Sprintf("\n  Commission:  %.2f (override)", *o.CommissionOverride)
+	}
+	if o.AllowMarketable {
+		description += "\n  Marketable:  allowed"
+	}
+	if o.CloseTicket != "" {
+		description += fmt.Sprintf("\n  Closes:      %s", o.CloseTicket)
+	}
 
 	return fmt.Sprintf(
 		"Order Details:\n"+
@@ -361,6 +474,51 @@ func (ob *OrderBuilder) WithDescription(desc string) *OrderBuilder {
 	return ob
 }
 
+// WithExpiry sets when the order expires (simulated time)
+func (ob *OrderBuilder) WithExpiry(expiresAt time.Time) *OrderBuilder {
+	if ob.err != nil {
+		return ob
+	}
+	ob.order.ExpiresAt = expiresAt
+	return ob
+}
+
+// WithTimeInForce sets the order to expire a duration after its timestamp
+// (call after WithTimestamp)
+func (ob *OrderBuilder) WithTimeInForce(d time.Duration) *OrderBuilder {
+	if ob.err != nil {
+		return ob
+	}
+	if d <= 0 {
+		ob.err = fmt.Errorf("time in force must be positive")
+		return ob
+	}
+	ob.order.ExpiresAt = ob.order.Timestamp.Add(d)
+	return ob
+}
+
+// WithCommission sets a commission override (0 for a commission-free order)
+func (ob *OrderBuilder) WithCommission(commission float64) *OrderBuilder {
+	if ob.err != nil {
+		return ob
+	}
+	if commission < 0 {
+		ob.err = fmt.Errorf("commission override cannot be negative")
+		return ob
+	}
+	ob.order.SetCommissionOverride(commission)
+	return ob
+}
+
+// WithMarketable allows a LIMIT order to be priced through the spread
+func (ob *OrderBuilder) WithMarketable() *OrderBuilder {
+	if ob.err != nil {
+		return ob
+	}
+	ob.order.AllowMarketable = true
+	return ob
+}
+
 // Buy shortcut for BUY action
 func (ob *OrderBuilder) Buy() *OrderBuilder {
 	return ob.WithAction(OrderActionBuy)
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -16,13 +17,15 @@ type Position struct {
 	// 0 = FLAT (no position)
 	Size float64
 
-	// EntryPrice is the average entry price for the current position
+	// EntryPrice is the size-weighted average entry price for the current
+	// position
 	EntryPrice float64
 
 	// EntryTime is when the position was opened
 	EntryTime time.Time
 
-	// EntryCommission is the commission paid when opening the position
+	// EntryCommission is the commission paid to open the current size,
+	// including scale-ins and less the share of any partial closes
 	EntryCommission float64
 
 	// CurrentPrice is the latest market price (updated each tick)
@@ -54,6 +57,19 @@ type Position struct {
 
 	// MaxFavorableExcursion is the best mark-to-market during position
 	MaxFavorableExcursion float64
+
+	// LotMatching is how closes are matched to open lots: average (default),
+	// fifo or lifo
+	LotMatching string
+
+	// Lots are the open lots that make up Size, oldest first
+	Lots []*Lot
+
+	// ClosedLots records every lot closed, with its realized P&L
+	ClosedLots []*ClosedLot
+
+	// lotCount numbers lots for their IDs
+	lotCount int
 }
 
 // ==================== TRADE RECORD ====================
@@ -104,6 +120,8 @@ func NewPosition() *Position {
 		TradeHistory:   make([]*Trade, 0),
 		PeakProfit:     0,
 		PeakLoss:       0,
+		Lots:           make([]*Lot, 0),
+		ClosedLots:     make([]*ClosedLot, 0),
 	}
 }
 
@@ -116,6 +134,7 @@ func NewLongPosition(size, entryPrice float64, entryTime time.Time, commission f
 	pos.EntryCommission = commission
 	pos.CommissionPaid = commission
 	pos.TradeCount = 1
+	pos.openLot(size, entryPrice, commission, 0, entryTime)
 	return pos
 }
 
@@ -128,6 +147,7 @@ func NewShortPosition(size, entryPrice float64, entryTime time.Time, commission
 	pos.EntryCommission = commission
 	pos.CommissionPaid = commission
 	pos.TradeCount = 1
+	pos.openLot(size, entryPrice, commission, 0, entryTime)
 	return pos
 }
 
@@ -194,6 +214,7 @@ func (p *Position) UpdatePrice(newPrice float64, pipValue float64) {
 		p.UnrealizedPnL = 0
 		return
 	}
+	p.markLots(newPrice)
 
 	// Calculate unrealized P&L based on position direction
 	if p.IsLong() {
@@ -215,6 +236,24 @@ func (p *Position) UpdatePrice(newPrice float64, pipValue float64) {
 	}
 }
 
+// ApplySplit adjusts the position for a stock split: size is multiplied
+// by ratio and prices divided by it, so notional and P&L are unchanged
+func (p *Position) ApplySplit(ratio float64) {
+	if ratio <= 0 {
+		return
+	}
+
+	p.Size *= ratio
+	p.EntryPrice /= ratio
+	p.CurrentPrice /= ratio
+	for _, lot := range p.Lots {
+		lot.Size *= ratio
+		lot.Price /= ratio
+		lot.HighPrice /= ratio
+		lot.LowPrice /= ratio
+	}
+}
+
 // AddTrade adds a trade to the position history and updates position
 func (p *Position) AddTrade(trade *Trade) {
 	p.TradeHistory = append(p.TradeHistory, trade)
@@ -235,6 +274,106 @@ func (p *Position) AddTrade(trade *Trade) {
 	p.CommissionPaid += trade.Commission
 }
 
+// ApplyFill nets a fill into the signed position and returns the P&L it
+// realizes
+// Each opening fill (including scale-ins) becomes a lot, and the entry
+// price is the size-weighted average of the open lots; reducing fills close
+// lots by the position's LotMatching method; a fill larger than the
+// position flips it and opens the excess as a new lot at the fill price
+// pipValue is the smallest price unit (0.0001 for Forex, 0.01 for stocks, etc)
+func (p *Position) ApplyFill(exec *ExecutionReport, pipValue float64) float64 {
+	signed := exec.FilledSize
+	if exec.IsSell() {
+		signed = -signed
+	}
+	prev := p.Size
+	realized := 0.0
+
+	trade := &Trade{
+		TradeID:    exec.OrderID,
+		Timestamp:  exec.Timestamp,
+		Action:     exec.Action,
+		Size:       exec.FilledSize,
+		Price:      exec.FillPrice,
+		Commission: exec.Commission,
+		Slippage:   exec.SlippageUnits,
+	}
+
+	if prev == 0 || (prev > 0) == (signed > 0) {
+		// Opening or scaling in
+		if prev == 0 {
+			p.EntryTime = exec.Timestamp
+			trade.IsEntry = true
+		}
+		p.openLot(math.Abs(signed), exec.FillPrice, exec.Commission, exec.StopLoss, exec.Timestamp)
+		p.chargeEntryCosts(exec, 1)
+	} else {
+		// Reducing, closing or flipping
+		closed := math.Min(math.Abs(signed), math.Abs(prev))
+		closedBefore := len(p.ClosedLots)
+		realized = p.closeLots(closed, exec.FillPrice, exec.Timestamp, pipValue)
+		chargeExitCosts(p.ClosedLots[closedBefore:], exec, math.Abs(signed))
+		p.RealizedPnL += realized
+
+		trade.IsExit = true
+		trade.PnLAtClose = realized
+
+		if math.Abs(signed) > math.Abs(prev) {
+			// Flip: the excess opens a new position carrying its share of
+			// the fill's commission
+			opened := math.Abs(signed) - closed
+			p.openLot(opened, exec.FillPrice, exec.Commission*opened/math.Abs(signed), exec.StopLoss, exec.Timestamp)
+			p.chargeEntryCosts(exec, opened/math.Abs(signed))
+			p.EntryTime = exec.Timestamp
+			trade.IsEntry = true
+		}
+	}
+
+	p.Size = prev + signed
+	if math.Abs(p.Size) < 1e-9 {
+		p.Size = 0
+		p.Lots = p.Lots[:0]
+		p.UnrealizedPnL = 0
+	}
+	p.refreshFromLots()
+
+	p.CommissionPaid += exec.Commission
+	p.TradeHistory = append(p.TradeHistory, trade)
+	p.TradeCount++
+
+	return realized
+}
+
+// ClosePartial closes size of the position at price and returns the P&L
+// realized on the closed slice; the rest stays open at the same entry and
+// is marked to price
+// The close is recorded as an exit trade like any other reducing fill
+func (p *Position) ClosePartial(size, price, commission float64, timestamp time.Time, pipValue float64) (float64, error) {
+	if p.IsFlat() {
+		return 0, fmt.Errorf("no open position to close")
+	}
+	if size <= 0 || size > p.GetAbsoluteSize()+1e-9 {
+		return 0, fmt.Errorf("close size %.4f must be between 0 and the position size %.4f", size, p.GetAbsoluteSize())
+	}
+
+	action := OrderActionSell
+	if p.IsShort() {
+		action = OrderActionBuy
+	}
+
+	realized := p.ApplyFill(&ExecutionReport{
+		Timestamp:  timestamp,
+		Action:     action,
+		FilledSize: math.Min(size, p.GetAbsoluteSize()),
+		FillPrice:  price,
+		Commission: commission,
+		Status:     OrderStatusFilled,
+	}, pipValue)
+	p.UpdatePrice(price, pipValue)
+
+	return realized, nil
+}
+
 // ==================== POSITION CALCULATIONS ====================
 
 // CalculateUnrealizedPnL calculates unrealized P&L based on current price
@@ -301,11 +440,11 @@ func (p *Position) GetBreakevenPrice() float64 {
 		return 0
 	}
 
-	// Adjust entry price by commission impact
+	// Adjust entry price by the commission paid to build the open size
 	if p.IsLong() {
-		return p.EntryPrice + (p.CommissionPaid / p.Size)
+		return p.EntryPrice + (p.EntryCommission / p.Size)
 	} else {
-		return p.EntryPrice - (p.CommissionPaid / p.GetAbsoluteSize())
+		return p.EntryPrice - (p.EntryCommission / p.GetAbsoluteSize())
 	}
 }
 
@@ -419,6 +558,9 @@ func (p *Position) DebugString() string {
 // PositionHistory tracks position changes over time
 type PositionHistory struct {
 	Snapshots []*PositionSnapshot
+
+	// Clock stamps snapshots (wall clock if nil)
+	Clock Clock `json:"-"`
 }
 
 // PositionSnapshot captures position state at a point in time
@@ -447,7 +589,7 @@ func (ph *PositionHistory) AddSnapshot(snapshot *PositionSnapshot) {
 // TakeSnapshot creates a snapshot from current position
 func (ph *PositionHistory) TakeSnapshot(pos *Position) {
 	snapshot := &PositionSnapshot{
-		Timestamp:     time.Now(),
+		Timestamp:     ph.now(),
 		Size:          pos.Size,
 		EntryPrice:    pos.EntryPrice,
 		CurrentPrice:  pos.CurrentPrice,
@@ -458,6 +600,14 @@ func (ph *PositionHistory) TakeSnapshot(pos *Position) {
 	ph.AddSnapshot(snapshot)
 }
 
+// now returns the history clock's time
+func (ph *PositionHistory) now() time.Time {
+	if ph.Clock == nil {
+		return time.Now()
+	}
+	return ph.Clock.Now()
+}
+
 // Size returns number of snapshots
 func (ph *PositionHistory) Size() int {
 	return len(ph.Snapshots)
@@ -0,0 +1,89 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ==================== MONEY PRECISION ====================
+
+// Default decimals for monetary values per instrument class
+// Crypto trades tiny sizes, so cents hide most of its P&L
+const (
+	ForexMoneyDecimals       = 2
+	StocksMoneyDecimals      = 2
+	CommoditiesMoneyDecimals = 2
+	CryptoMoneyDecimals      = 4
+	DefaultMoneyDecimals     = 2
+	MaxMoneyDecimals         = 10
+)
+
+// PrecisionPolicy decides how many decimals monetary values (balances,
+// P&L, costs) are reported with for each instrument class
+// It is the single source of formatting precision for loggers, reports
+// and exports
+type PrecisionPolicy struct {
+	decimals map[string]int
+}
+
+// DefaultPrecisionPolicy returns the built-in per-class precision
+func DefaultPrecisionPolicy() *PrecisionPolicy {
+	return &PrecisionPolicy{
+		decimals: map[string]int{
+			InstrumentTypeForex:       ForexMoneyDecimals,
+			InstrumentTypeStocks:      StocksMoneyDecimals,
+			InstrumentTypeCommodities: CommoditiesMoneyDecimals,
+			InstrumentTypeCrypto:      CryptoMoneyDecimals,
+		},
+	}
+}
+
+// NewPrecisionPolicy returns the default policy with per-class overrides
+func NewPrecisionPolicy(overrides map[string]int) (*PrecisionPolicy, error) {
+	policy := DefaultPrecisionPolicy()
+	for instrumentType, decimals := range overrides {
+		if !IsValidInstrumentType(instrumentType) {
+			return nil, NewInvalidInstrumentTypeError(instrumentType)
+		}
+		if decimals < 0 || decimals > MaxMoneyDecimals {
+			return nil, fmt.Errorf("%s money precision must be between 0 and %d", instrumentType, MaxMoneyDecimals)
+		}
+		policy.decimals[instrumentType] = decimals
+	}
+	return policy, nil
+}
+
+// GetMoneyDecimals returns the decimals used for an instrument class
+func (pp *PrecisionPolicy) GetMoneyDecimals(instrumentType string) int {
+	if pp == nil {
+		return DefaultMoneyDecimals
+	}
+	if decimals, ok := pp.decimals[instrumentType]; ok {
+		return decimals
+	}
+	return DefaultMoneyDecimals
+}
+
+// FormatMoney formats an amount with a class's decimals (see
+// GetMoneyDecimals), for CSV and text exports
+func FormatMoney(amount float64, decimals int) string {
+	return strconv.FormatFloat(amount, 'f', decimals, 64)
+}
+
+// RoundMoney rounds an amount to a class's decimals, for JSON exports
+func RoundMoney(amount float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(amount*scale) / scale
+}
+
+// String returns a human-readable representation
+func (pp *PrecisionPolicy) String() string {
+	return fmt.Sprintf(
+		"PrecisionPolicy[FOREX:%d, STOCKS:%d, COMMODITIES:%d, CRYPTO:%d]",
+		pp.GetMoneyDecimals(InstrumentTypeForex),
+		pp.GetMoneyDecimals(InstrumentTypeStocks),
+		pp.GetMoneyDecimals(InstrumentTypeCommodities),
+		pp.GetMoneyDecimals(InstrumentTypeCrypto),
+	)
+}
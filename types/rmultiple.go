@@ -0,0 +1,139 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ==================== R-MULTIPLES ====================
+
+// RMultipleBucketEdges are the R values splitting the R distribution into
+// buckets: below -2R, -2R to -1R, ..., 2R to 3R, and 3R and above
+var RMultipleBucketEdges = []float64{-2, -1, 0, 1, 2, 3}
+
+// RBucket is the number of trades in one range of R
+type RBucket struct {
+	Label  string `json:"label"`
+	Trades int    `json:"trades"`
+}
+
+// RMultipleStats summarizes the closed trades opened with an initial stop
+// in units of their initial risk (1R)
+// Trades without a stop are counted but left out of the R figures
+type RMultipleStats struct {
+	Trades         int       `json:"trades"`           // Trades with an initial stop
+	Unstopped      int       `json:"unstopped"`        // Trades without one
+	AverageR       float64   `json:"average_r"`        // Mean R-multiple
+	MedianR        float64   `json:"median_r"`         // Median R-multiple
+	BestR          float64   `json:"best_r"`           // Largest R-multiple
+	WorstR         float64   `json:"worst_r"`          // Smallest R-multiple
+	TotalR         float64   `json:"total_r"`          // Sum of the R-multiples
+	Above1RPercent float64   `json:"above_1r_percent"` // Percent of trades reaching 1R or more
+	Above2RPercent float64   `json:"above_2r_percent"` // Percent of trades reaching 2R or more
+	Distribution   []RBucket `json:"distribution"`
+}
+
+// NewRMultipleStats computes the R figures of closed lots
+func NewRMultipleStats(lots []ClosedLot) *RMultipleStats {
+	stats := &RMultipleStats{Distribution: newRBuckets()}
+
+	multiples := make([]float64, 0, len(lots))
+	for i := range lots {
+		if !lots[i].HasInitialRisk() {
+			stats.Unstopped++
+			continue
+		}
+		// Rounded so a trade stopped out exactly at its stop counts as -1R
+		// despite float noise in the prices
+		multiples = append(multiples, math.Round(lots[i].GetRMultiple()*1e6)/1e6)
+	}
+	if len(multiples) == 0 {
+		return stats
+	}
+
+	stats.Trades = len(multiples)
+	stats.BestR, stats.WorstR = math.Inf(-1), math.Inf(1)
+	above1, above2 := 0, 0
+	for _, r := range multiples {
+		stats.TotalR += r
+		stats.BestR = math.Max(stats.BestR, r)
+		stats.WorstR = math.Min(stats.WorstR, r)
+		if r >= 1 {
+			above1++
+		}
+		if r >= 2 {
+			above2++
+		}
+		stats.Distribution[rBucketIndex(r)].Trades++
+	}
+
+	n := float64(len(multiples))
+	stats.AverageR = stats.TotalR / n
+	stats.Above1RPercent = float64(above1) / n * 100
+	stats.Above2RPercent = float64(above2) / n * 100
+
+	sort.Float64s(multiples)
+	mid := len(multiples) / 2
+	if len(multiples)%2 == 0 {
+		stats.MedianR = (multiples[mid-1] + multiples[mid]) / 2
+	} else {
+		stats.MedianR = multiples[mid]
+	}
+
+	return stats
+}
+
+// newRBuckets creates the empty R distribution
+func newRBuckets() []RBucket {
+	edges := RMultipleBucketEdges
+	buckets := make([]RBucket, 0, len(edges)+1)
+	buckets = append(buckets, RBucket{Label: fmt.Sprintf("<%gR", edges[0])})
+	for i := 1; i < len(edges); i++ {
+		buckets = append(buckets, RBucket{Label: fmt.Sprintf("%gR to %gR", edges[i-1], edges[i])})
+	}
+	buckets = append(buckets, RBucket{Label: fmt.Sprintf(">=%gR", edges[len(edges)-1])})
+	return buckets
+}
+
+// rBucketIndex returns the distribution bucket of an R-multiple
+func rBucketIndex(r float64) int {
+	for i, edge := range RMultipleBucketEdges {
+		if r < edge {
+			return i
+		}
+	}
+	return len(RMultipleBucketEdges)
+}
+
+// GetStatistics returns the R figures
+func (rs *RMultipleStats) GetStatistics() map[string]interface{} {
+	distribution := make(map[string]int, len(rs.Distribution))
+	for _, bucket := range rs.Distribution {
+		distribution[bucket.Label] = bucket.Trades
+	}
+
+	return map[string]interface{}{
+		"trades":           rs.Trades,
+		"unstopped":        rs.Unstopped,
+		"average_r":        rs.AverageR,
+		"median_r":         rs.MedianR,
+		"best_r":           rs.BestR,
+		"worst_r":          rs.WorstR,
+		"total_r":          rs.TotalR,
+		"above_1r_percent": rs.Above1RPercent,
+		"above_2r_percent": rs.Above2RPercent,
+		"distribution":     distribution,
+	}
+}
+
+// String returns a human-readable representation
+func (rs *RMultipleStats) String() string {
+	return fmt.Sprintf(
+		"RMultiples[Trades:%d AvgR:%.2f >=1R:%.1f%% >=2R:%.1f%%]",
+		rs.Trades,
+		rs.AverageR,
+		rs.Above1RPercent,
+		rs.Above2RPercent,
+	)
+}
@@ -0,0 +1,154 @@
+package types
+
+import (
+	"fmt"
+	"math"
+)
+
+// ==================== SHARPE RATIO ====================
+
+// Sharpe return periods
+const (
+	SharpePeriodDaily = "daily" // Close-to-close returns of each UTC date
+	SharpePeriodBar   = "bar"   // Returns between consecutive equity samples
+)
+
+// SharpeConfig sets how the Sharpe ratio is computed from the sampled
+// equity curve
+type SharpeConfig struct {
+	// Period is "daily" (default) or "bar"
+	Period string `json:"period"`
+
+	// RiskFreeRate is the annual risk-free rate in percent, deducted from
+	// each period's return
+	RiskFreeRate float64 `json:"risk_free_rate"`
+
+	// AnnualizationFactor is the number of periods per year; 0 uses
+	// TradingDaysPerYear for daily returns and TradingDaysPerYear times
+	// the average samples per day for bar returns
+	AnnualizationFactor float64 `json:"annualization_factor"`
+
+	// TradingDaysPerYear defaults to the instrument's trading year
+	TradingDaysPerYear int `json:"trading_days_per_year"`
+}
+
+// Validate checks the period and that the rates are not negative
+func (sc SharpeConfig) Validate() error {
+	if sc.Period != "" && sc.Period != SharpePeriodDaily && sc.Period != SharpePeriodBar {
+		return fmt.Errorf("period must be %s or %s", SharpePeriodDaily, SharpePeriodBar)
+	}
+	if sc.RiskFreeRate < 0 || sc.AnnualizationFactor < 0 || sc.TradingDaysPerYear < 0 {
+		return fmt.Errorf("risk-free rate, annualization factor and trading days cannot be negative")
+	}
+	return nil
+}
+
+// GetPeriod returns the return period, daily by default
+func (sc SharpeConfig) GetPeriod() string {
+	if sc.Period == "" {
+		return SharpePeriodDaily
+	}
+	return sc.Period
+}
+
+// GetTradingDaysPerYear returns the trading year, DefaultTradingDaysPerYear
+// when not set
+func (sc SharpeConfig) GetTradingDaysPerYear() int {
+	if sc.TradingDaysPerYear > 0 {
+		return sc.TradingDaysPerYear
+	}
+	return DefaultTradingDaysPerYear
+}
+
+// CalculateSharpeRatio returns the annualized Sharpe ratio of period
+// returns (fractions): the mean excess return over the risk-free rate's
+// per-period share, divided by the sample standard deviation and scaled
+// by the square root of periodsPerYear
+// riskFreeRate is annual, in percent; returns 0 with fewer than two
+// returns or no volatility
+func CalculateSharpeRatio(returns []float64, riskFreeRate, periodsPerYear float64) float64 {
+	n := len(returns)
+	if n < 2 || periodsPerYear <= 0 {
+		return 0
+	}
+
+	riskFree := riskFreeRate / 100 / periodsPerYear
+	mean := 0.0
+	for _, r := range returns {
+		mean += r - riskFree
+	}
+	mean /= float64(n)
+
+	variance := 0.0
+	for _, r := range returns {
+		diff := r - riskFree - mean
+		variance += diff * diff
+	}
+	stdDev := math.Sqrt(variance / float64(n-1))
+	if stdDev == 0 {
+		return 0
+	}
+
+	return mean / stdDev * math.Sqrt(periodsPerYear)
+}
+
+// ==================== EQUITY CURVE RETURNS ====================
+
+// GetPeriodReturns returns the curve's returns for a Sharpe period and the
+// number of such periods per year
+// Daily returns run from the first sample to each date's last sample
+func (ec *EquityCurve) GetPeriodReturns(config SharpeConfig) ([]float64, float64) {
+	returns := make([]float64, 0)
+	if len(ec.points) < 2 {
+		return returns, 0
+	}
+
+	periodsPerYear := config.AnnualizationFactor
+	tradingDays := float64(config.GetTradingDaysPerYear())
+
+	if config.GetPeriod() == SharpePeriodBar {
+		for i := 1; i < len(ec.points); i++ {
+			if previous := ec.points[i-1].Equity; previous > 0 {
+				returns = append(returns, ec.points[i].Equity/previous-1)
+			}
+		}
+		if periodsPerYear == 0 {
+			days := countTradingDays(ec.points)
+			periodsPerYear = tradingDays * float64(len(ec.points)-1) / float64(days)
+		}
+		return returns, periodsPerYear
+	}
+
+	previous := ec.points[0].Equity
+	for i, p := range ec.points {
+		last := i == len(ec.points)-1
+		if !last && sameDate(p, ec.points[i+1]) {
+			continue
+		}
+		if i == 0 {
+			continue
+		}
+		if previous > 0 {
+			returns = append(returns, p.Equity/previous-1)
+		}
+		previous = p.Equity
+	}
+	if periodsPerYear == 0 {
+		periodsPerYear = tradingDays
+	}
+	return returns, periodsPerYear
+}
+
+// GetSharpeRatio returns the annualized Sharpe ratio of the curve's
+// period returns
+func (ec *EquityCurve) GetSharpeRatio(config SharpeConfig) float64 {
+	returns, periodsPerYear := ec.GetPeriodReturns(config)
+	return CalculateSharpeRatio(returns, config.RiskFreeRate, periodsPerYear)
+}
+
+// sameDate returns true if two samples fall on the same UTC date
+func sameDate(a, b EquityPoint) bool {
+	ay, am, ad := a.Timestamp.UTC().Date()
+	by, bm, bd := b.Timestamp.UTC().Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,186 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ==================== SYMBOL METRICS ====================
+
+// SymbolMetrics is one instrument's performance within a session that
+// trades several symbols; money values are in the account currency
+type SymbolMetrics struct {
+	Symbol string `json:"symbol"`
+
+	Executions      int `json:"executions"`
+	Trades          int `json:"trades"` // Fills that realized P&L
+	WinningTrades   int `json:"winning_trades"`
+	LosingTrades    int `json:"losing_trades"`
+	BreakevenTrades int `json:"breakeven_trades"`
+
+	RealizedPnL   float64 `json:"realized_pnl"`
+	UnrealizedPnL float64 `json:"unrealized_pnl"`
+	Commission    float64 `json:"commission"`
+
+	// PeakPnL is the highest net P&L reached; MaxDrawdown is the largest
+	// drop of the net P&L from that peak
+	PeakPnL     float64 `json:"peak_pnl"`
+	MaxDrawdown float64 `json:"max_drawdown"`
+
+	// DrawdownContribution is this symbol's share of the summed drawdown
+	// of every symbol, in percent (set by SymbolMetricsBook.GetAll)
+	DrawdownContribution float64 `json:"drawdown_contribution"`
+}
+
+// GetNetPnL returns realized plus unrealized P&L, net of commission
+func (sm *SymbolMetrics) GetNetPnL() float64 {
+	return sm.RealizedPnL + sm.UnrealizedPnL - sm.Commission
+}
+
+// GetWinRate returns the percent of trades that won
+func (sm *SymbolMetrics) GetWinRate() float64 {
+	if sm.Trades == 0 {
+		return 0
+	}
+	return float64(sm.WinningTrades) / float64(sm.Trades) * 100
+}
+
+// updateDrawdown moves the peak and the max drawdown to the net P&L
+func (sm *SymbolMetrics) updateDrawdown() {
+	net := sm.GetNetPnL()
+	if net > sm.PeakPnL {
+		sm.PeakPnL = net
+	}
+	if drawdown := sm.PeakPnL - net; drawdown > sm.MaxDrawdown {
+		sm.MaxDrawdown = drawdown
+	}
+}
+
+// String returns a human-readable representation
+func (sm *SymbolMetrics) String() string {
+	return fmt.Sprintf(
+		"SymbolMetrics[%s Trades:%d WinRate:%.1f%% Net:%.2f MaxDD:%.2f]",
+		sm.Symbol,
+		sm.Trades,
+		sm.GetWinRate(),
+		sm.GetNetPnL(),
+		sm.MaxDrawdown,
+	)
+}
+
+// ==================== SYMBOL METRICS BOOK ====================
+
+// SymbolMetricsBook keys SymbolMetrics by symbol
+type SymbolMetricsBook struct {
+	symbols map[string]*SymbolMetrics
+}
+
+// NewSymbolMetricsBook creates an empty book
+func NewSymbolMetricsBook() *SymbolMetricsBook {
+	return &SymbolMetricsBook{
+		symbols: make(map[string]*SymbolMetrics),
+	}
+}
+
+// get returns a symbol's metrics, creating them on first use
+func (smb *SymbolMetricsBook) get(symbol string) *SymbolMetrics {
+	metrics, ok := smb.symbols[symbol]
+	if !ok {
+		metrics = &SymbolMetrics{Symbol: symbol}
+		smb.symbols[symbol] = metrics
+	}
+	return metrics
+}
+
+// RecordFill books a fill's realized P&L and commission; fills that
+// realize P&L count as trades with their outcome (see BreakevenBand.Classify)
+func (smb *SymbolMetricsBook) RecordFill(symbol string, realizedPnL, commission float64, outcome int) {
+	metrics := smb.get(symbol)
+	metrics.Executions++
+	metrics.RealizedPnL += realizedPnL
+	metrics.Commission += commission
+
+	if realizedPnL != 0 {
+		metrics.Trades++
+		switch outcome {
+		case TradeOutcomeWin:
+			metrics.WinningTrades++
+		case TradeOutcomeLoss:
+			metrics.LosingTrades++
+		default:
+			metrics.BreakevenTrades++
+		}
+	}
+	metrics.updateDrawdown()
+}
+
+// MarkToMarket sets a symbol's unrealized P&L
+func (smb *SymbolMetricsBook) MarkToMarket(symbol string, unrealizedPnL float64) {
+	metrics := smb.get(symbol)
+	metrics.UnrealizedPnL = unrealizedPnL
+	metrics.updateDrawdown()
+}
+
+// Get returns a copy of a symbol's metrics, nil if it never traded
+func (smb *SymbolMetricsBook) Get(symbol string) *SymbolMetrics {
+	metrics, ok := smb.symbols[symbol]
+	if !ok {
+		return nil
+	}
+	copied := *metrics
+	return &copied
+}
+
+// GetAll returns every symbol's metrics sorted by symbol, with their
+// drawdown contributions
+func (smb *SymbolMetricsBook) GetAll() []SymbolMetrics {
+	all := make([]SymbolMetrics, 0, len(smb.symbols))
+	totalDrawdown := 0.0
+	for _, metrics := range smb.symbols {
+		all = append(all, *metrics)
+		totalDrawdown += metrics.MaxDrawdown
+	}
+	for i := range all {
+		all[i].DrawdownContribution = 0
+		if totalDrawdown > 0 {
+			all[i].DrawdownContribution = all[i].MaxDrawdown / totalDrawdown * 100
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Symbol < all[j].Symbol
+	})
+	return all
+}
+
+// Len returns the number of symbols with metrics
+func (smb *SymbolMetricsBook) Len() int {
+	return len(smb.symbols)
+}
+
+// GetStatistics returns win rate, P&L and drawdown contribution by symbol
+func (smb *SymbolMetricsBook) GetStatistics() map[string]interface{} {
+	stats := make(map[string]interface{}, len(smb.symbols))
+	for _, metrics := range smb.GetAll() {
+		stats[metrics.Symbol] = map[string]interface{}{
+			"trades":                metrics.Trades,
+			"win_rate":              metrics.GetWinRate(),
+			"realized_pnl":          metrics.RealizedPnL,
+			"unrealized_pnl":        metrics.UnrealizedPnL,
+			"commission":            metrics.Commission,
+			"net_pnl":               metrics.GetNetPnL(),
+			"max_drawdown":          metrics.MaxDrawdown,
+			"drawdown_contribution": metrics.DrawdownContribution,
+		}
+	}
+	return stats
+}
+
+// Reset clears every symbol
+func (smb *SymbolMetricsBook) Reset() {
+	smb.symbols = make(map[string]*SymbolMetrics)
+}
+
+// String returns a human-readable representation
+func (smb *SymbolMetricsBook) String() string {
+	return fmt.Sprintf("SymbolMetricsBook[Symbols:%d]", len(smb.symbols))
+}
@@ -39,6 +39,10 @@ type Tick struct {
 
 	// Mid price (calculated as (Bid + Ask) / 2)
 	MidPrice float64
+
+	// Symbol identifies the instrument in a multi-symbol stream
+	// (empty = the session instrument)
+	Symbol string
 }
 
 // ==================== TICK METHODS ====================
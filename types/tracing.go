@@ -0,0 +1,53 @@
+package types
+
+import "context"
+
+// ==================== TRACING ====================
+
+// Tracer starts spans around stages of the tick and order pipeline
+// It mirrors OpenTelemetry's trace.Tracer, so an OTel tracer plugs in with
+// a small adapter; Holodeck takes no OTel dependency itself
+type Tracer interface {
+	// Start starts a span as a child of the span in ctx, returning a
+	// context carrying the new span
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is one timed stage of the pipeline
+type Span interface {
+	// SetAttribute records a key/value on the span
+	SetAttribute(key string, value interface{})
+
+	// RecordError marks the span as failed
+	RecordError(err error)
+
+	// End ends the span
+	End()
+}
+
+// Span names
+const (
+	SpanTick            = "holodeck.tick"
+	SpanTickRead        = "holodeck.tick.read"
+	SpanTickUpdate      = "holodeck.tick.update"
+	SpanOrder           = "holodeck.order"
+	SpanOrderExecute    = "holodeck.order.execute"
+	SpanOrderValidate   = "holodeck.order.validate"
+	SpanOrderSlippage   = "holodeck.order.slippage"
+	SpanOrderStateApply = "holodeck.order.state_update"
+)
+
+// StartSpan starts a span with tracer, or a no-op span when tracer is nil
+func StartSpan(ctx context.Context, tracer Tracer, name string) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return tracer.Start(ctx, name)
+}
+
+// noopSpan is the span used when tracing is off
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) RecordError(err error)                      {}
+func (noopSpan) End()                                       {}
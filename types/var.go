@@ -0,0 +1,69 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ==================== VALUE AT RISK ====================
+
+// VaRConfidenceLevels are the confidence levels reported, in percent
+var VaRConfidenceLevels = []float64{95, 99}
+
+// ValueAtRisk is a historical one-period Value-at-Risk estimate: at
+// Confidence percent, a period loses no more than VaR percent of equity,
+// and CVaR is the average loss on the periods beyond that
+// Losses are positive; a tail made only of gains reports 0
+type ValueAtRisk struct {
+	Confidence   float64 `json:"confidence"`
+	VaR          float64 `json:"var_percent"`
+	CVaR         float64 `json:"cvar_percent"`
+	Observations int     `json:"observations"`
+}
+
+// CalculateValueAtRisk estimates VaR and CVaR from period returns
+// (fractions) by historical simulation: the tail is the worst
+// ceil((100 - confidence)% of n) returns, VaR the best of them and CVaR
+// their mean
+func CalculateValueAtRisk(returns []float64, confidence float64) ValueAtRisk {
+	v := ValueAtRisk{Confidence: confidence, Observations: len(returns)}
+	if len(returns) == 0 || confidence <= 0 || confidence >= 100 {
+		return v
+	}
+
+	sorted := make([]float64, len(returns))
+	copy(sorted, returns)
+	sort.Float64s(sorted)
+
+	tail := int(math.Ceil((100 - confidence) / 100 * float64(len(sorted))))
+	if tail < 1 {
+		tail = 1
+	}
+
+	sum := 0.0
+	for _, r := range sorted[:tail] {
+		sum += r
+	}
+	v.VaR = math.Max(-sorted[tail-1]*100, 0)
+	v.CVaR = math.Max(-sum/float64(tail)*100, 0)
+	return v
+}
+
+// GetValueAtRisk returns the curve's daily VaR and CVaR at a confidence
+// level, from its close-to-close daily returns
+func (ec *EquityCurve) GetValueAtRisk(confidence float64) ValueAtRisk {
+	returns, _ := ec.GetPeriodReturns(SharpeConfig{Period: SharpePeriodDaily})
+	return CalculateValueAtRisk(returns, confidence)
+}
+
+// String returns a human-readable representation
+func (v ValueAtRisk) String() string {
+	return fmt.Sprintf(
+		"VaR[%.0f%% VaR:%.2f%% CVaR:%.2f%% N:%d]",
+		v.Confidence,
+		v.VaR,
+		v.CVaR,
+		v.Observations,
+	)
+}